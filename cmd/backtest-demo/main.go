@@ -13,8 +13,8 @@ import (
 func main() {
 	fmt.Println("🚀 Executando Demo do Backtest com dados simulados...")
 
-	// Create a fake client with predefined data
-	client := external.NewBinanceClientFake()
+	// Create a fake exchange with predefined data
+	client := external.NewFakeExchange()
 	
 	// Configure with whipsaw data to generate some trades
 	client.SetPredefinedKlines(external.CreateWhipsawKlines())
@@ -2,6 +2,7 @@ package main
 
 import (
 	"crypgo-machine/src/application/usecase"
+	"crypgo-machine/src/domain/entity"
 	"crypgo-machine/src/infra/external"
 	"encoding/json"
 	"flag"
@@ -32,11 +33,19 @@ func main() {
 		tradeAmount            = flag.Float64("amount", 5000.0, "Trade amount per operation in BRL")
 		tradingFees            = flag.Float64("fees", 0.01, "Trading fees percentage (0.01 = 0.01%)")
 		minimumProfitThreshold = flag.Float64("min-profit", 2.0, "Minimum profit threshold percentage")
+		trailingStop           = flag.Float64("trailing-stop", 0, "Trailing stop percentage from the highest price since entry (0 = disabled)")
+		takeProfit             = flag.Float64("take-profit", 0, "Take profit percentage (0 = disabled)")
+		maxHoldingSeconds      = flag.Int("max-holding", 0, "Max holding time in seconds (0 = disabled)")
+		riskPerTrade           = flag.Float64("risk-per-trade", 0, "ATR sizing: percentage of capital risked per trade (0 = size by -amount)")
+		atrPeriod              = flag.Int("atr-period", 14, "ATR sizing: number of klines in the ATR")
+		atrMultiplier          = flag.Float64("atr-multiplier", 2.0, "ATR sizing: stop distance in ATRs")
 		interval               = flag.String("interval", "30m", "Kline interval (1m, 5m, 15m, 30m, 1h, 4h, 1d)")
 		currency               = flag.String("currency", "BRL", "Currency for calculations")
 		quantity               = flag.Float64("quantity", 0.001, "Quantity per trade (for crypto pairs)")
 		intervalSeconds        = flag.Int("interval-seconds", 1800, "Interval in seconds for bot operations")
 		minimumSpread          = flag.Float64("min-spread", 0.1, "Minimum spread percentage for anti-whipsaw")
+		strategyParamsJSON     = flag.String("params", "", "Strategy parameters as JSON (e.g. '{\"Period\":14}'), overrides -fast/-slow/-min-spread")
+		listStrategies         = flag.Bool("list-strategies", false, "List available strategies and their parameters")
 		outputFile             = flag.String("output", "", "Output file for results (optional)")
 		apiKey                 = flag.String("api-key", "", "Binance API key (or use BINANCE_API_KEY env var)")
 		secretKey              = flag.String("secret-key", "", "Binance secret key (or use BINANCE_SECRET_KEY env var)")
@@ -45,6 +54,11 @@ func main() {
 	)
 	flag.Parse()
 
+	if *listStrategies {
+		printStrategies()
+		return
+	}
+
 	// Validate required parameters
 	if *startDateStr == "" || *endDateStr == "" {
 		fmt.Println("❌ Error: start and end dates are required")
@@ -58,6 +72,24 @@ func main() {
 		fmt.Println("    -capital=10000 -amount=5000 \\")
 		fmt.Println("    -min-profit=2.5 -min-spread=0.7 \\")
 		fmt.Println("    -interval=1h -output=results.json")
+		fmt.Println("\n  # RSI backtest with custom parameters")
+		fmt.Println("  go run cmd/backtest/main.go -start=2024-01-01 -end=2024-01-31 \\")
+		fmt.Println("    -strategy=RSI -params='{\"Period\":14,\"OversoldThreshold\":25}'")
+		fmt.Println("\n  # Bollinger Bands backtest entering on band touch")
+		fmt.Println("  go run cmd/backtest/main.go -start=2024-01-01 -end=2024-01-31 \\")
+		fmt.Println("    -strategy=BollingerBands -params='{\"Period\":20,\"StdDevMultiplier\":2,\"EntryMode\":\"touch\"}'")
+		fmt.Println("\n  # Composite backtest: entries from MovingAverage, exits from RSI")
+		fmt.Println("  go run cmd/backtest/main.go -start=2024-01-01 -end=2024-01-31 \\")
+		fmt.Println("    -strategy=Composite -params='{\"Rule\":\"entry_exit\",\"Strategies\":[{\"Name\":\"MovingAverage\",\"Params\":{\"FastWindow\":7,\"SlowWindow\":40}},{\"Name\":\"RSI\"}]}'")
+		fmt.Println("\n  # Bot-level exit rules: 3% trailing stop, 8% take profit, max 2 days holding")
+		fmt.Println("  go run cmd/backtest/main.go -start=2024-01-01 -end=2024-01-31 \\")
+		fmt.Println("    -trailing-stop=3 -take-profit=8 -max-holding=172800")
+		fmt.Println("\n  # ATR position sizing: risk 1% of capital per trade with a 2 ATR stop distance")
+		fmt.Println("  go run cmd/backtest/main.go -start=2024-01-01 -end=2024-01-31 \\")
+		fmt.Println("    -risk-per-trade=1 -atr-period=14 -atr-multiplier=2")
+		fmt.Println("\n  # Multi-timeframe: 30m MovingAverage entries only while the 4h trend is up")
+		fmt.Println("  go run cmd/backtest/main.go -start=2024-01-01 -end=2024-01-31 -interval=30m \\")
+		fmt.Println("    -params='{\"FastWindow\":7,\"SlowWindow\":40,\"TrendIntervalSeconds\":14400,\"TrendWindow\":50}'")
 		fmt.Println("\nParameters:")
 		flag.PrintDefaults()
 		os.Exit(1)
@@ -95,20 +127,37 @@ func main() {
 
 	// Create Binance client
 	binanceClient := binance.NewClient(binanceAPIKey, binanceSecretKey)
-	client := external.NewBinanceClientWrapper(binanceClient)
+	client := external.NewBinanceExchange(binanceClient)
 
 	// Create backtest use case
 	useCase := usecase.NewBacktestTradingBotUseCase(client)
 
+	// Resolve strategy parameters: MovingAverage flags first, then -params overrides
+	strategyParams := map[string]interface{}{}
+	if *strategy == "MovingAverage" {
+		strategyParams["FastWindow"] = float64(*fastWindow)
+		strategyParams["SlowWindow"] = float64(*slowWindow)
+		strategyParams["MinimumSpread"] = *minimumSpread
+	}
+	if *strategyParamsJSON != "" {
+		var overrides map[string]interface{}
+		if err := json.Unmarshal([]byte(*strategyParamsJSON), &overrides); err != nil {
+			log.Fatalf("❌ Error parsing -params JSON: %v", err)
+		}
+		for name, value := range overrides {
+			strategyParams[name] = value
+		}
+	}
+
+	if !entity.IsStrategyRegistered(*strategy) {
+		log.Fatalf("❌ Error: unknown strategy %s (use -list-strategies to see the available ones)", *strategy)
+	}
+
 	// Prepare input with all dynamic parameters
 	input := usecase.BacktestTradingBotInput{
-		Symbol:   *symbol,
-		Strategy: *strategy,
-		StrategyParams: map[string]interface{}{
-			"FastWindow":    float64(*fastWindow),
-			"SlowWindow":    float64(*slowWindow),
-			"MinimumSpread": *minimumSpread,
-		},
+		Symbol:                 *symbol,
+		Strategy:               *strategy,
+		StrategyParams:         strategyParams,
 		StartDate:              startDate,
 		EndDate:                endDate,
 		InitialCapital:         *initialCapital,
@@ -119,20 +168,31 @@ func main() {
 		Currency:               *currency,
 		Quantity:               *quantity,
 		IntervalSeconds:        *intervalSeconds,
+		TrailingStopPercent:    *trailingStop,
+		TakeProfitPercent:      *takeProfit,
+		MaxHoldingSeconds:      *maxHoldingSeconds,
+		RiskPerTradePercent:    *riskPerTrade,
+		ATRPeriod:              *atrPeriod,
+		ATRMultiplier:          *atrMultiplier,
 	}
 
 	// Print configuration unless quiet mode
 	if !*quiet {
 		fmt.Printf("🚀 Starting backtest with configuration:\n")
 		fmt.Printf("   Symbol: %s\n", *symbol)
-		fmt.Printf("   Strategy: %s (Fast: %d, Slow: %d)\n", *strategy, *fastWindow, *slowWindow)
+		fmt.Printf("   Strategy: %s %v\n", *strategy, strategyParams)
 		fmt.Printf("   Period: %s to %s\n", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
 		fmt.Printf("   Initial Capital: %.2f %s\n", *initialCapital, *currency)
 		fmt.Printf("   Trade Amount: %.2f %s\n", *tradeAmount, *currency)
 		fmt.Printf("   Quantity per Trade: %.6f\n", *quantity)
 		fmt.Printf("   Trading Fees: %.3f%%\n", *tradingFees)
 		fmt.Printf("   Minimum Profit Threshold: %.2f%%\n", *minimumProfitThreshold)
-		fmt.Printf("   Minimum Spread: %.2f%%\n", *minimumSpread)
+		if *trailingStop > 0 || *takeProfit > 0 || *maxHoldingSeconds > 0 {
+			fmt.Printf("   Exit Rules: trailing stop %.2f%%, take profit %.2f%%, max holding %ds\n", *trailingStop, *takeProfit, *maxHoldingSeconds)
+		}
+		if *riskPerTrade > 0 {
+			fmt.Printf("   Position Sizing: ATR risk %.2f%% of capital (ATR %d x %.1f, capped at trade amount)\n", *riskPerTrade, *atrPeriod, *atrMultiplier)
+		}
 		fmt.Printf("   Interval: %s (%d seconds)\n", *interval, *intervalSeconds)
 		if *verbose {
 			fmt.Printf("   Currency: %s\n", *currency)
@@ -182,6 +242,17 @@ func main() {
 	fmt.Printf("\n✅ Backtest completed successfully!\n")
 }
 
+// printStrategies prints every registered strategy with its parameter schema
+func printStrategies() {
+	fmt.Println("📚 Available strategies:")
+	for _, definition := range entity.ListStrategyDefinitions() {
+		fmt.Printf("\n  %s - %s\n", definition.Name, definition.Description)
+		for _, param := range definition.Params {
+			fmt.Printf("    %-22s %-7s default: %-8v %s\n", param.Name, param.Type, param.Default, param.Description)
+		}
+	}
+}
+
 // minInt returns the minimum of two integers
 func minInt(a, b int) int {
 	if a < b {
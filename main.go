@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypgo-machine/src/application/repository"
 	"crypgo-machine/src/application/service"
 	"crypgo-machine/src/application/usecase"
@@ -16,21 +17,36 @@ import (
 	infraRepository "crypgo-machine/src/infra/repository"
 	"crypgo-machine/src/infra/scheduler"
 	"fmt"
-	"github.com/adshao/go-binance/v2"
 	"github.com/joho/godotenv"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 )
 
 func main() {
 	fmt.Println("🚀 Starting Binance Trading Bot...")
 	loadEnv()
 
-	client := binance.NewClient(
-		os.Getenv("BINANCE_API_KEY"),
-		os.Getenv("BINANCE_SECRET_KEY"),
-	)
+	// Exchange accounts the bots trade on, the default production one first
+	exchangeProfiles, err := external.LoadExchangeProfiles(os.Getenv)
+	if err != nil {
+		log.Fatalf("❌ Error loading exchange profiles: %v", err)
+	}
+	// The bots only talk to the exchange through its port, Binance being its adapter
+	exchangeClient := external.NewBinanceExchangeForProfile(exchangeProfiles[0])
+	profileExchanges := make(map[string]*external.BinanceExchange)
+	profileNames := make([]string, 0, len(exchangeProfiles))
+	for _, profile := range exchangeProfiles {
+		profileNames = append(profileNames, profile.Name)
+		fmt.Printf("🔑 Exchange profile %s on %s (%s)\n", profile.Name, profile.Environment, profile.APIBaseURL())
+		if profile.Name != entity.DefaultExchangeProfile {
+			profileExchanges[profile.Name] = external.NewBinanceExchangeForProfile(profile)
+		}
+	}
 
 	dbConnection, err := database.NewDatabaseConnection(
 		os.Getenv("DB_HOST"),
@@ -50,6 +66,8 @@ func main() {
 		log.Fatal(err)
 	}
 	decisionLogRepository := infraRepository.NewTradingDecisionLogRepositoryDatabase(dbConnection.DB)
+	orderRepository := infraRepository.NewOrderRepositoryDatabase(dbConnection.DB)
+	tradeRepository := infraRepository.NewTradeRepositoryDatabase(dbConnection.DB)
 
 	// Email service setup
 	emailService := notification.NewEmailService()
@@ -115,33 +133,88 @@ func main() {
 	http.HandleFunc("/api/v1/auth/validate", authController.ValidateToken)
 
 	// Protected trading routes
-	createTradingBotUseCase := usecase.NewCreateTradingBotUseCase(tradingBotRepository, *client, rabbit, "trading_bot")
+	createTradingBotUseCase := usecase.NewCreateTradingBotUseCase(tradingBotRepository, exchangeClient, rabbit, "trading_bot").
+		WithExchangeProfiles(profileNames...)
 	createTradingBotController := api.NewCreateTradingBotController(createTradingBotUseCase)
 	http.HandleFunc("/api/v1/trading/create_trading_bot", authMiddleware.RequireAuth(createTradingBotController.Handle))
 
+	listStrategiesUseCase := usecase.NewListStrategiesUseCase()
+	listStrategiesController := api.NewListStrategiesController(listStrategiesUseCase)
+	http.HandleFunc("/api/v1/strategies", authMiddleware.RequireAuth(listStrategiesController.Handle))
+
 	listAllTradingBotsUseCase := usecase.NewListAllTradingBotsUseCase(tradingBotRepository)
 	listAllTradingBotsController := api.NewListAllTradingBotsController(listAllTradingBotsUseCase)
 	http.HandleFunc("/api/v1/trading/list", authMiddleware.RequireAuth(listAllTradingBotsController.Handle))
 
-	binanceWrapper := external.NewBinanceClientWrapper(client)
-	startTradingBotUseCase := usecase.NewStartTradingBotUseCaseWithMessaging(tradingBotRepository, decisionLogRepository, binanceWrapper, rabbit, "trading_bot")
+	klineCache := service.NewKlineCache(exchangeClient)
+	// Paper bots fill at the live price moved by PAPER_SLIPPAGE_PERCENT against them
+	paperSlippagePercent := service.DefaultPaperSlippagePercent
+	if value, err := strconv.ParseFloat(os.Getenv("PAPER_SLIPPAGE_PERCENT"), 64); err == nil {
+		paperSlippagePercent = value
+	}
+	paperExecutionContext := service.NewPaperTradingExecutionContext(tradingBotRepository, decisionLogRepository, rabbit, "trading_bot", paperSlippagePercent)
+	// Risk limits shared by the live bots, checked between their decisions and their orders
+	riskLimitsRepository := infraRepository.NewRiskLimitsRepositoryDatabase(dbConnection.DB)
+	riskManager := service.NewRiskManager(riskLimitsRepository, tradingBotRepository, tradeRepository, rabbit, "trading_bot")
+	// One goroutine per running bot, stopped through its context and drained on shutdown
+	botSupervisor := service.NewBotSupervisor(rabbit, "trading_bot")
+	startTradingBotUseCase := usecase.NewStartTradingBotUseCaseWithMessaging(tradingBotRepository, decisionLogRepository, orderRepository, tradeRepository, exchangeClient, klineCache, external.NewBinanceKlineStream(), rabbit, "trading_bot").
+		WithPaperExecutionContext(paperExecutionContext).
+		WithRiskManager(riskManager).
+		WithSupervisor(botSupervisor)
+	for name, profileExchange := range profileExchanges {
+		profileExecutionContext := service.NewLiveTradingExecutionContext(profileExchange, tradingBotRepository, decisionLogRepository, rabbit, "trading_bot").
+			WithLedger(orderRepository, tradeRepository).
+			WithCapitalAllocator(service.NewCapitalAllocator(profileExchange))
+		startTradingBotUseCase.WithExchangeProfile(name, service.NewLiveMarketDataSource(profileExchange), profileExecutionContext)
+	}
 	startTradingBotController := api.NewStartTradingBotController(startTradingBotUseCase)
 	http.HandleFunc("/api/v1/trading/start", authMiddleware.RequireAuth(startTradingBotController.Handle))
 
 	// Auto-recovery: restart all running bots after server restart
 	fmt.Println("🔧 About to start auto-recovery...")
-	if err := recoverRunningBots(tradingBotRepository, startTradingBotUseCase); err != nil {
+	reconcilePositionsUseCase := usecase.NewReconcilePositionsUseCase(tradingBotRepository, orderRepository, tradeRepository, exchangeClient, rabbit, "trading_bot")
+	for name, profileExchange := range profileExchanges {
+		reconcilePositionsUseCase.WithExchangeProfile(name, profileExchange)
+	}
+	if err := recoverRunningBots(tradingBotRepository, reconcilePositionsUseCase, startTradingBotUseCase); err != nil {
 		fmt.Printf("⚠️ Auto-recovery completed with some errors: %v\n", err)
 	} else {
 		fmt.Println("🔧 Auto-recovery finished successfully")
 	}
 
-	stopTradingBotUseCase := usecase.NewStopTradingBotUseCase(tradingBotRepository)
+	getKlineCacheStatsUseCase := usecase.NewGetKlineCacheStatsUseCase(klineCache)
+	klineCacheStatsController := api.NewKlineCacheStatsController(getKlineCacheStatsUseCase)
+	http.HandleFunc("/api/v1/market-data/cache/stats", authMiddleware.RequireAuth(klineCacheStatsController.Handle))
+
+	stopTradingBotUseCase := usecase.NewStopTradingBotUseCase(tradingBotRepository).WithSupervisor(botSupervisor)
 	stopTradingBotController := api.NewStopTradingBotController(stopTradingBotUseCase)
 	http.HandleFunc("/api/v1/trading/stop", authMiddleware.RequireAuth(stopTradingBotController.Handle))
 
+	// Pause, resume, close then stop and liquidate, each restarting the bot loop on the new status
+	pauseTradingBotUseCase := usecase.NewPauseTradingBotUseCase(startTradingBotUseCase)
+	resumeTradingBotUseCase := usecase.NewResumeTradingBotUseCase(startTradingBotUseCase)
+	closeTradingBotPositionUseCase := usecase.NewCloseTradingBotPositionUseCase(startTradingBotUseCase)
+	liquidateTradingBotUseCase := usecase.NewLiquidateTradingBotUseCase(startTradingBotUseCase)
+	botLifecycleController := api.NewBotLifecycleController(pauseTradingBotUseCase, resumeTradingBotUseCase, closeTradingBotPositionUseCase, liquidateTradingBotUseCase)
+	http.HandleFunc("/api/v1/trading/pause", authMiddleware.RequireAuth(botLifecycleController.Pause))
+	http.HandleFunc("/api/v1/trading/resume", authMiddleware.RequireAuth(botLifecycleController.Resume))
+	http.HandleFunc("/api/v1/trading/close", authMiddleware.RequireAuth(botLifecycleController.Close))
+	http.HandleFunc("/api/v1/trading/liquidate", authMiddleware.RequireAuth(botLifecycleController.Liquidate))
+
+	// Edit the config of a bot in place, its loop restarting on the new version
+	botConfigHistoryRepository := infraRepository.NewBotConfigHistoryRepositoryDatabase(dbConnection.DB)
+	updateTradingBotUseCase := usecase.NewUpdateTradingBotUseCase(startTradingBotUseCase, botConfigHistoryRepository)
+	getBotConfigHistoryUseCase := usecase.NewGetBotConfigHistoryUseCase(tradingBotRepository, botConfigHistoryRepository)
+	botConfigController := api.NewBotConfigController(updateTradingBotUseCase, getBotConfigHistoryUseCase)
+	http.HandleFunc("/api/v1/trading/bots/{id}", authMiddleware.RequireAuth(botConfigController.Update))
+	http.HandleFunc("/api/v1/trading/bots/{id}/config-history", authMiddleware.RequireAuth(botConfigController.History))
+
+	botRuntimeController := api.NewBotRuntimeController(usecase.NewGetBotRuntimeUseCase(botSupervisor))
+	http.HandleFunc("/api/v1/trading/runtime", authMiddleware.RequireAuth(botRuntimeController.Handle))
+
 	backtestStrategyUseCase := usecase.NewBacktestStrategyUseCase()
-	historicalDataService := external.NewBinanceHistoricalDataService(binanceWrapper)
+	historicalDataService := external.NewBinanceHistoricalDataService(exchangeClient)
 	backtestStrategyController := api.NewBacktestStrategyController(backtestStrategyUseCase, historicalDataService)
 	http.HandleFunc("/api/v1/trading/backtest", authMiddleware.RequireAuth(backtestStrategyController.Handle))
 
@@ -149,6 +222,17 @@ func main() {
 	tradingLogsController := api.NewTradingLogsController(listTradingLogsUseCase)
 	http.HandleFunc("/api/v1/trading/logs", authMiddleware.RequireAuth(tradingLogsController.ListLogs))
 
+	listOrdersUseCase := usecase.NewListOrdersUseCase(orderRepository)
+	listTradesUseCase := usecase.NewListTradesUseCase(tradeRepository)
+	tradingLedgerController := api.NewTradingLedgerController(listOrdersUseCase, listTradesUseCase)
+	http.HandleFunc("/api/v1/trading/orders", authMiddleware.RequireAuth(tradingLedgerController.ListOrders))
+	http.HandleFunc("/api/v1/trading/trades", authMiddleware.RequireAuth(tradingLedgerController.ListTrades))
+
+	riskController := api.NewRiskController(usecase.NewGetRiskStatusUseCase(riskManager), usecase.NewUpdateRiskLimitsUseCase(riskManager), usecase.NewSetKillSwitchUseCase(riskManager))
+	http.HandleFunc("/api/v1/risk/status", authMiddleware.RequireAuth(riskController.Status))
+	http.HandleFunc("/api/v1/risk/limits", authMiddleware.RequireAuth(riskController.UpdateLimits))
+	http.HandleFunc("/api/v1/risk/kill-switch", authMiddleware.RequireAuth(riskController.KillSwitch))
+
 	// Sentiment Analysis System
 	sentimentSuggestionRepository := infraRepository.NewSentimentSuggestionRepositoryDatabase(dbConnection.DB)
 	generateSentimentUseCase := usecase.NewGenerateSentimentSuggestionUseCase(sentimentSuggestionRepository)
@@ -160,7 +244,8 @@ func main() {
 	sentimentScheduler := scheduler.NewSentimentScheduler(marketSentimentService, sentimentSuggestionRepository, rabbit)
 
 	// Telegram Bot Handler for interactive commands
-	telegramBotHandler := notification.NewTelegramBotHandler(telegramService, marketSentimentService)
+	telegramBotHandler := notification.NewTelegramBotHandler(telegramService, marketSentimentService).
+		WithBotLifecycle(pauseTradingBotUseCase, resumeTradingBotUseCase, closeTradingBotPositionUseCase, liquidateTradingBotUseCase)
 	go func() {
 		err := telegramBotHandler.Start()
 		if err != nil {
@@ -199,12 +284,37 @@ func main() {
 	// Serve static files for dashboard
 	http.Handle("/", http.FileServer(http.Dir("./web/")))
 
-	fmt.Println("🚀 Listening on :8080")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		log.Fatalf("❌ Error starting server: %v", err)
+	server := &http.Server{Addr: ":8080"}
+	go func() {
+		fmt.Println("🚀 Listening on :8080")
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("❌ Error starting server: %v", err)
+		}
+	}()
+
+	// Graceful shutdown: the bots keep their RUNNING, PAUSED or CLOSING status, so auto-recovery restarts them on the next boot
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	sig := <-signals
+	fmt.Printf("🛑 Received %v, shutting down...\n", sig)
+
+	httpCtx, cancelHttp := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelHttp()
+	if err := server.Shutdown(httpCtx); err != nil {
+		fmt.Printf("⚠️ HTTP server shutdown: %v\n", err)
+	}
+
+	// Limit orders may still be resting and repricing, give the ticks in flight time to finish
+	botsCtx, cancelBots := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancelBots()
+	if err := botSupervisor.Shutdown(botsCtx); err != nil {
+		fmt.Printf("⚠️ Trading bots shutdown: %v\n", err)
+	} else {
+		fmt.Println("✅ All trading bot loops stopped")
 	}
 
-	defer dbConnection.DB.Close()
+	dbConnection.DB.Close()
+	fmt.Println("👋 Shutdown complete")
 }
 
 func loadEnv() {
@@ -214,15 +324,20 @@ func loadEnv() {
 	}
 }
 
-// recoverRunningBots finds all trading bots with RUNNING status and restarts their trading loops
-func recoverRunningBots(tradingBotRepository repository.TradingBotRepository, startTradingBotUseCase *usecase.StartTradingBotUseCase) error {
+// recoverRunningBots finds all trading bots with RUNNING, PAUSED or CLOSING status and restarts their trading loops,
+// once their positions were reconciled with the exchange. Bots whose position does not match are left in NEEDS_ATTENTION.
+func recoverRunningBots(tradingBotRepository repository.TradingBotRepository, reconcilePositionsUseCase *usecase.ReconcilePositionsUseCase, startTradingBotUseCase *usecase.StartTradingBotUseCase) error {
 	fmt.Println("🔄 Starting auto-recovery process...")
 
-	// Get all bots with RUNNING status
-	runningBots, err := tradingBotRepository.GetTradingBotsByStatus(entity.StatusRunning)
-	if err != nil {
-		fmt.Printf("❌ Error querying running bots: %v\n", err)
-		return err
+	// Get all bots whose loop has to run
+	var runningBots []*entity.TradingBot
+	for _, status := range []entity.Status{entity.StatusRunning, entity.StatusPaused, entity.StatusClosing} {
+		bots, err := tradingBotRepository.GetTradingBotsByStatus(status)
+		if err != nil {
+			fmt.Printf("❌ Error querying %s bots: %v\n", status, err)
+			return err
+		}
+		runningBots = append(runningBots, bots...)
 	}
 
 	if len(runningBots) == 0 {
@@ -232,17 +347,41 @@ func recoverRunningBots(tradingBotRepository repository.TradingBotRepository, st
 
 	fmt.Printf("🔍 Found %d running trading bot(s) to recover\n", len(runningBots))
 
+	// Check the positions in the database against the exchange balances before trading again
+	if _, err := reconcilePositionsUseCase.Execute(runningBots); err != nil {
+		fmt.Printf("⚠️ Position reconciliation skipped: %v\n", err)
+	}
+
 	// Track recovery results
 	successCount := 0
 	errorCount := 0
+	attentionCount := 0
 
 	// Restart each running bot
 	for _, bot := range runningBots {
 		botId := bot.Id.GetValue()
 		symbol := bot.GetSymbol().GetValue()
 
+		if bot.GetStatus() == entity.StatusNeedsAttention {
+			fmt.Printf("⏸️ Not recovering bot %s (%s): its position needs attention\n", botId, symbol)
+			attentionCount++
+			continue
+		}
+
 		fmt.Printf("⚡ Recovering bot %s (%s)...\n", botId, symbol)
 
+		// Paused and closing bots keep their status, only their loop is gone
+		if bot.GetStatus() != entity.StatusRunning {
+			if err := startTradingBotUseCase.RunLoop(bot); err != nil {
+				fmt.Printf("❌ Failed to recover bot %s (%s): %v\n", botId, symbol, err)
+				errorCount++
+			} else {
+				fmt.Printf("✅ Successfully recovered %s bot %s (%s)\n", bot.GetStatus(), botId, symbol)
+				successCount++
+			}
+			continue
+		}
+
 		// For auto-recovery, we need to reset the bot status to STOPPED first
 		// because the server restart killed the actual trading loops but left the status as RUNNING
 		if err := bot.Stop(); err != nil {
@@ -273,7 +412,7 @@ func recoverRunningBots(tradingBotRepository repository.TradingBotRepository, st
 	}
 
 	// Summary
-	fmt.Printf("📊 Auto-recovery completed: %d successful, %d failed\n", successCount, errorCount)
+	fmt.Printf("📊 Auto-recovery completed: %d successful, %d failed, %d need attention\n", successCount, errorCount, attentionCount)
 
 	if errorCount > 0 {
 		return fmt.Errorf("auto-recovery completed with %d errors", errorCount)
@@ -0,0 +1,9 @@
+package repository
+
+import "crypgo-machine/src/domain/entity"
+
+type BotConfigHistoryRepository interface {
+	Save(version *entity.BotConfigVersion) error                                    // Replaces the bot's version with the same number
+	SaveWithBot(bot *entity.TradingBot, versions ...*entity.BotConfigVersion) error // Saves the versions and updates the bot atomically
+	GetByTradingBotId(tradingBotId string) ([]*entity.BotConfigVersion, error)      // Oldest version first
+}
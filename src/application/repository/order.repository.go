@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"time"
+)
+
+// OrderFilter selects orders of the ledger, empty fields match everything
+type OrderFilter struct {
+	TradingBotId    string
+	Symbol          string
+	Side            string
+	Status          string
+	ExchangeOrderId int64
+	ClientOrderId   string
+	From            time.Time // Created at or after
+	To              time.Time // Created before
+	Limit           int
+	Offset          int
+}
+
+type OrderRepository interface {
+	Save(order *entity.Order) error
+	Update(order *entity.Order) error
+	GetOrdersWithFilters(filter OrderFilter) ([]*entity.Order, int, error)
+}
@@ -0,0 +1,8 @@
+package repository
+
+import "crypgo-machine/src/domain/entity"
+
+type RiskLimitsRepository interface {
+	Get() (entity.RiskLimits, error) // No limits until they are saved
+	Save(limits entity.RiskLimits) error
+}
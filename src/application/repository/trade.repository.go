@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"time"
+)
+
+// TradeFilter selects closed trades of the ledger, empty fields match everything
+type TradeFilter struct {
+	TradingBotId string
+	Symbol       string
+	From         time.Time // Closed at or after
+	To           time.Time // Closed before
+	Limit        int
+	Offset       int
+}
+
+type TradeRepository interface {
+	Save(trade *entity.Trade) error
+	GetTradesWithFilters(filter TradeFilter) ([]*entity.Trade, int, error)
+}
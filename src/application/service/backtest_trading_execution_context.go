@@ -39,7 +39,6 @@ type BacktestTrade struct {
 type BacktestTradingExecutionContext struct {
 	result            *BacktestResult
 	currentTrade      *BacktestTrade
-	shouldContinue    bool
 	highWaterMark     float64 // For drawdown calculation
 }
 
@@ -53,7 +52,6 @@ func NewBacktestTradingExecutionContext(symbol string, initialCapital float64) *
 			Decisions:      make([]*entity.TradingDecisionLog, 0),
 			Trades:         make([]BacktestTrade, 0),
 		},
-		shouldContinue: true,
 		highWaterMark:  initialCapital,
 	}
 }
@@ -69,21 +67,23 @@ func (ctx *BacktestTradingExecutionContext) ExecuteTrade(decision entity.Trading
 		// Simulate buy order
 		fmt.Printf("🟢 [BACKTEST] BUY at %.2f on %s\n", currentPrice, timestamp.Format("2006-01-02 15:04"))
 		
-		// Calculate fees
-		tradeValue := bot.GetTradeAmount()
+		// Calculate fees on the order value of the bot's sizing mode
+		quantity := bot.CalculateBuyQuantity(currentPrice)
+		tradeValue := quantity * currentPrice
 		fees := tradeValue * (bot.GetTradingFees() / 100)
 		
 		// Start a new trade
 		ctx.currentTrade = &BacktestTrade{
 			EntryPrice: currentPrice,
 			EntryTime:  timestamp,
-			Quantity:   bot.GetQuantity(),
+			Quantity:   quantity,
 			Fees:       fees,
 		}
 		
 		// Update bot state
 		bot.SetEntryPrice(currentPrice)
 		_ = bot.GetIntoPosition()
+		bot.StartPositionTracking(currentPrice, timestamp)
 		
 		// Update capital (deduct fees)
 		ctx.result.FinalCapital -= fees
@@ -98,9 +98,9 @@ func (ctx *BacktestTradingExecutionContext) ExecuteTrade(decision entity.Trading
 			return fmt.Errorf("no current trade to close")
 		}
 
-		// Calculate profit/loss
+		// Calculate profit/loss on the value of the position at entry
 		entryPrice := bot.GetEntryPrice()
-		tradeValue := bot.GetTradeAmount()
+		tradeValue := ctx.currentTrade.Quantity * ctx.currentTrade.EntryPrice
 		fees := tradeValue * (bot.GetTradingFees() / 100)
 		
 		pnlPercentage := ((currentPrice - entryPrice) / entryPrice) * 100
@@ -162,16 +162,6 @@ func (ctx *BacktestTradingExecutionContext) OnDecisionMade(decisionLog *entity.T
 	return nil
 }
 
-// ShouldContinue returns whether the backtest should continue
-func (ctx *BacktestTradingExecutionContext) ShouldContinue() bool {
-	return ctx.shouldContinue
-}
-
-// Stop stops the backtest
-func (ctx *BacktestTradingExecutionContext) Stop() {
-	ctx.shouldContinue = false
-}
-
 // GetResult finalizes and returns the backtest results
 func (ctx *BacktestTradingExecutionContext) GetResult() *BacktestResult {
 	// Calculate final metrics
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypgo-machine/src/infra/queue"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BotRuntimeState is the live state of a bot's trading loop, as opposed to the status persisted with the bot
+type BotRuntimeState struct {
+	BotId               string    `json:"bot_id"`
+	Symbol              string    `json:"symbol"`
+	Running             bool      `json:"running"`
+	StartedAt           time.Time `json:"started_at"`
+	StoppedAt           time.Time `json:"stopped_at"`
+	LastTickAt          time.Time `json:"last_tick_at"`
+	Ticks               int       `json:"ticks"`
+	LastError           string    `json:"last_error"`
+	LastErrorAt         time.Time `json:"last_error_at"`
+	ConsecutiveFailures int       `json:"consecutive_failures"` // Failed ticks since the last successful one
+	Panics              int       `json:"panics"`
+}
+
+// BotLoop runs the trading loop of a bot until ctx is cancelled, each analysis going through tick
+type BotLoop func(ctx context.Context, tick func(analysis func() error))
+
+// BotSupervisor owns the goroutine of every running bot. Stopping a bot cancels its context, so the loop exits
+// without waiting for its next tick. A panic fails the tick it happened in and is reported instead of taking the
+// process down, and a shutdown waits for the ticks in flight, i.e. for the orders being placed.
+type BotSupervisor struct {
+	messageBroker queue.MessageBroker
+	exchangeName  string
+	now           func() time.Time
+
+	mu           sync.Mutex
+	bots         map[string]*supervisedBot
+	shuttingDown bool
+}
+
+type supervisedBot struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	state  BotRuntimeState
+}
+
+func NewBotSupervisor(messageBroker queue.MessageBroker, exchangeName string) *BotSupervisor {
+	return &BotSupervisor{
+		messageBroker: messageBroker,
+		exchangeName:  exchangeName,
+		now:           time.Now,
+		bots:          make(map[string]*supervisedBot),
+	}
+}
+
+// Start runs the loop of a bot in a goroutine of its own. A bot has at most one loop running.
+func (s *BotSupervisor) Start(botId, symbol string, loop BotLoop) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shuttingDown {
+		return fmt.Errorf("bot supervisor is shutting down")
+	}
+	if existing, ok := s.bots[botId]; ok && existing.state.Running {
+		return fmt.Errorf("trading bot %s is already running", botId)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bot := &supervisedBot{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		state:  BotRuntimeState{BotId: botId, Symbol: symbol, Running: true, StartedAt: s.now()},
+	}
+	s.bots[botId] = bot
+	go s.run(ctx, bot, loop)
+	return nil
+}
+
+// Stop cancels the loop of a bot and waits for the tick in flight to finish. It reports whether the loop was running.
+func (s *BotSupervisor) Stop(botId string) bool {
+	s.mu.Lock()
+	bot, ok := s.bots[botId]
+	running := ok && bot.state.Running
+	s.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	bot.cancel()
+	<-bot.done
+	return running
+}
+
+// Shutdown stops every loop, waiting for the ticks in flight until ctx is done. No bot starts afterwards.
+func (s *BotSupervisor) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.shuttingDown = true
+	bots := make([]*supervisedBot, 0, len(s.bots))
+	for _, bot := range s.bots {
+		bots = append(bots, bot)
+	}
+	s.mu.Unlock()
+
+	for _, bot := range bots {
+		bot.cancel()
+	}
+	for _, bot := range bots {
+		select {
+		case <-bot.done:
+		case <-ctx.Done():
+			return fmt.Errorf("trading bots still running: %v", s.runningBots())
+		}
+	}
+	return nil
+}
+
+// States returns the runtime state of the bots started since the process began, sorted by bot ID
+func (s *BotSupervisor) States() []BotRuntimeState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]BotRuntimeState, 0, len(s.bots))
+	for _, bot := range s.bots {
+		states = append(states, bot.state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].BotId < states[j].BotId })
+	return states
+}
+
+// State returns the runtime state of a bot, false when it was not started since the process began
+func (s *BotSupervisor) State(botId string) (BotRuntimeState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bot, ok := s.bots[botId]
+	if !ok {
+		return BotRuntimeState{}, false
+	}
+	return bot.state, true
+}
+
+func (s *BotSupervisor) run(ctx context.Context, bot *supervisedBot, loop BotLoop) {
+	defer close(bot.done)
+	defer func() {
+		// The loop itself panicked outside of a tick, it cannot go on
+		if r := recover(); r != nil {
+			s.recoverPanic(bot, r)
+		}
+		s.mu.Lock()
+		bot.state.Running = false
+		bot.state.StoppedAt = s.now()
+		s.mu.Unlock()
+	}()
+
+	loop(ctx, func(analysis func() error) {
+		s.tick(bot, analysis)
+	})
+}
+
+// tick runs one analysis of the bot, a panic failing only this tick
+func (s *BotSupervisor) tick(bot *supervisedBot, analysis func() error) {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = s.recoverPanic(bot, r)
+			}
+		}()
+		return analysis()
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	bot.state.LastTickAt = now
+	bot.state.Ticks++
+	if err == nil {
+		bot.state.ConsecutiveFailures = 0
+		return
+	}
+	bot.state.LastError = err.Error()
+	bot.state.LastErrorAt = now
+	bot.state.ConsecutiveFailures++
+	fmt.Printf("❌ Error in analysis and trade for bot %s: %v (%d in a row)\n", bot.state.BotId, err, bot.state.ConsecutiveFailures)
+}
+
+// recoverPanic logs a panic of the bot with its stack and publishes it as a trading_bot.panic_recovered event
+func (s *BotSupervisor) recoverPanic(bot *supervisedBot, recovered interface{}) error {
+	err := fmt.Errorf("panic: %v", recovered)
+
+	s.mu.Lock()
+	bot.state.Panics++
+	botId, symbol := bot.state.BotId, bot.state.Symbol
+	s.mu.Unlock()
+
+	fmt.Printf("💥 [%s] Recovered panic in bot %s: %v\n%s", symbol, botId, recovered, debug.Stack())
+	if s.messageBroker == nil {
+		return err
+	}
+
+	timestamp := s.now()
+	payloadBytes, errMarshal := json.Marshal(map[string]interface{}{
+		"id":        botId,
+		"symbol":    symbol,
+		"error":     err.Error(),
+		"timestamp": timestamp,
+	})
+	if errMarshal != nil {
+		fmt.Printf("⚠️ Failed to marshal panic event payload: %v\n", errMarshal)
+		return err
+	}
+	message := queue.Message{
+		RoutingKey: "trading_bot.panic_recovered",
+		Payload:    payloadBytes,
+		Headers: map[string]string{
+			"timestamp": timestamp.Format(time.RFC3339),
+			"bot_id":    botId,
+		},
+	}
+	if errPublish := s.messageBroker.Publish(s.exchangeName, message); errPublish != nil {
+		fmt.Printf("⚠️ Failed to emit panic event: %v\n", errPublish)
+	}
+	return err
+}
+
+func (s *BotSupervisor) runningBots() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var running []string
+	for botId, bot := range s.bots {
+		if bot.state.Running {
+			running = append(running, botId)
+		}
+	}
+	sort.Strings(running)
+	return running
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// tickingLoop runs a tick for every analysis sent on ticks until ctx is cancelled, signaling done after each tick
+func tickingLoop(ticks <-chan func() error, done chan<- struct{}) BotLoop {
+	return func(ctx context.Context, tick func(func() error)) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case analysis := <-ticks:
+				tick(analysis)
+				done <- struct{}{}
+			}
+		}
+	}
+}
+
+func TestBotSupervisor_TracksTicksAndRecoversPanics(t *testing.T) {
+	broker := &recordingMessageBroker{}
+	supervisor := NewBotSupervisor(broker, "test_exchange")
+	ticks := make(chan func() error)
+	done := make(chan struct{})
+	run := func(analysis func() error) {
+		ticks <- analysis
+		<-done
+	}
+
+	if err := supervisor.Start("bot-1", "SOLBRL", tickingLoop(ticks, done)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := supervisor.Start("bot-1", "SOLBRL", tickingLoop(ticks, done)); err == nil {
+		t.Fatal("expected a bot to run a single loop")
+	}
+
+	run(func() error { return errors.New("exchange unavailable") })
+	run(func() error { panic("nil strategy") })
+
+	state, ok := supervisor.State("bot-1")
+	if !ok || !state.Running || state.Ticks != 2 || state.Panics != 1 {
+		t.Fatalf("expected the loop to survive the panic, got %+v", state)
+	}
+	if state.ConsecutiveFailures != 2 || state.LastError != "panic: nil strategy" {
+		t.Errorf("expected two failures in a row ending with the panic, got %+v", state)
+	}
+	if len(broker.messages) != 1 || broker.messages[0].RoutingKey != "trading_bot.panic_recovered" {
+		t.Errorf("expected the panic to be published, got %v", broker.messages)
+	}
+
+	run(func() error { return nil })
+	if !supervisor.Stop("bot-1") {
+		t.Fatal("expected the loop to be running")
+	}
+	state, _ = supervisor.State("bot-1")
+	if state.Running || state.Ticks != 3 || state.ConsecutiveFailures != 0 || state.StoppedAt.IsZero() {
+		t.Errorf("expected a stopped loop after a successful tick, got %+v", state)
+	}
+	if supervisor.Stop("bot-1") {
+		t.Error("expected a stopped loop not to be reported as running")
+	}
+}
+
+func TestBotSupervisor_ShutdownWaitsForTicksInFlight(t *testing.T) {
+	supervisor := NewBotSupervisor(nil, "")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := false
+
+	err := supervisor.Start("bot-1", "SOLBRL", func(ctx context.Context, tick func(func() error)) {
+		tick(func() error {
+			close(started)
+			<-release // An order being placed
+			finished = true
+			return nil
+		})
+		<-ctx.Done()
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	<-started
+
+	// The tick outlives a short deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := supervisor.Shutdown(ctx); err == nil {
+		t.Fatal("expected the shutdown to time out while the tick is in flight")
+	}
+	if err := supervisor.Start("bot-2", "BTCBRL", tickingLoop(nil, nil)); err == nil {
+		t.Error("expected no bot to start during the shutdown")
+	}
+
+	close(release)
+	if err := supervisor.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected the shutdown to complete, got %v", err)
+	}
+	if !finished {
+		t.Error("expected the tick in flight to finish before the shutdown")
+	}
+}
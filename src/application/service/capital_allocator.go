@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"fmt"
+	"sync"
+)
+
+// InsufficientCapitalError tells why a buy was refused by the CapitalAllocator
+type InsufficientCapitalError struct {
+	Reason    string
+	Required  float64
+	Available float64
+	Currency  string
+}
+
+func (e *InsufficientCapitalError) Error() string {
+	return fmt.Sprintf("%s: %.2f %s required, %.2f %s available", e.Reason, e.Required, e.Currency, e.Available, e.Currency)
+}
+
+// capitalReservation is the quote currency set aside for a bot from its buy until its sell. It is pending while the
+// buy is being placed and settled once the exchange debited the account for the fill.
+type capitalReservation struct {
+	currency string
+	amount   float64
+	settled  bool
+	spent    float64 // Settled by the bot's earlier buys, still held in its position
+}
+
+// debited returns what the bot spent on its position, counted against its budget until its sell
+func (r capitalReservation) debited() float64 {
+	if r.settled {
+		return r.spent + r.amount
+	}
+	return r.spent
+}
+
+// CapitalAllocator shares the account's quote currency between the live bots: each buy reserves its cost, which must
+// fit both the bot's budget (its initial capital less what its position already cost) and the account's free balance
+// less what other bots are about to spend, and each sell releases it
+type CapitalAllocator struct {
+	exchange exchange.Exchange
+
+	mu           sync.Mutex
+	reservations map[string]capitalReservation // Per bot ID
+}
+
+// NewCapitalAllocator creates a new CapitalAllocator
+func NewCapitalAllocator(exchangeClient exchange.Exchange) *CapitalAllocator {
+	return &CapitalAllocator{
+		exchange:     exchangeClient,
+		reservations: make(map[string]capitalReservation),
+	}
+}
+
+// Reserve sets amount of the bot's currency aside for a buy, or returns an *InsufficientCapitalError when the bot's
+// budget or the account's free balance does not cover it
+func (a *CapitalAllocator) Reserve(bot *entity.TradingBot, amount float64) error {
+	currency := bot.GetCurrency()
+	a.mu.Lock()
+	spent := a.reservations[bot.Id.GetValue()].debited()
+	a.mu.Unlock()
+	if budget := bot.GetInitialCapital() - spent; amount > budget {
+		return &InsufficientCapitalError{Reason: "bot budget exceeded", Required: amount, Available: budget, Currency: currency}
+	}
+
+	free, err := a.freeBalance(currency)
+	if err != nil {
+		return fmt.Errorf("failed to check %s balance: %v", currency, err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Pending reservations of other bots are not debited from the account yet
+	available := free
+	for botId, reservation := range a.reservations {
+		if botId != bot.Id.GetValue() && reservation.currency == currency && !reservation.settled {
+			available -= reservation.amount
+		}
+	}
+	if amount > available {
+		return &InsufficientCapitalError{Reason: "insufficient account balance", Required: amount, Available: available, Currency: currency}
+	}
+
+	a.reservations[bot.Id.GetValue()] = capitalReservation{currency: currency, amount: amount, spent: a.reservations[bot.Id.GetValue()].debited()}
+	return nil
+}
+
+// Settle records what the bot's buy actually spent, the exchange having debited it from the account
+func (a *CapitalAllocator) Settle(bot *entity.TradingBot, spent float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	previous := a.reservations[bot.Id.GetValue()]
+	a.reservations[bot.Id.GetValue()] = capitalReservation{currency: bot.GetCurrency(), amount: spent, settled: true, spent: previous.spent}
+}
+
+// Restore settles the cost of a position the allocator does not track, e.g. one opened before a restart, so it
+// counts against the bot's budget until its sell. The cost is estimated from the entry price and fees.
+func (a *CapitalAllocator) Restore(bot *entity.TradingBot) {
+	if !bot.GetIsPositioned() {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, tracked := a.reservations[bot.Id.GetValue()]; tracked {
+		return
+	}
+	cost := bot.GetEntryPrice()*bot.GetActualQuantityHeld() + bot.GetEntryFees()
+	a.reservations[bot.Id.GetValue()] = capitalReservation{currency: bot.GetCurrency(), amount: cost, settled: true}
+	fmt.Printf("💰 [%s] Restored the %.2f %s the position cost against the bot's budget\n", bot.GetSymbol().GetValue(), cost, bot.GetCurrency())
+}
+
+// Release gives the bot's reservation back, after its sell or a buy that did not fill
+func (a *CapitalAllocator) Release(bot *entity.TradingBot) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.reservations, bot.Id.GetValue())
+}
+
+// freeBalance returns the free balance of asset on the account, locked funds being held by open orders
+func (a *CapitalAllocator) freeBalance(asset string) (float64, error) {
+	balances, err := a.exchange.GetBalances(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	for _, balance := range balances {
+		if balance.Asset == asset {
+			return balance.Free, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/infra/external"
+	"encoding/json"
+	"testing"
+	"time"
+
+)
+
+func TestCapitalAllocator_ChecksBudgetAndFreeBalance(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBalance("BRL", 500)
+	allocator := NewCapitalAllocator(client)
+	first := newLimitOrderTestBot(t, entity.ExecutionModeMarket, "", 0)
+	second := newLimitOrderTestBot(t, entity.ExecutionModeMarket, "", 0)
+
+	// The bots have 1000 BRL of initial capital each
+	err := allocator.Reserve(first, 1200)
+	if refusal, ok := err.(*InsufficientCapitalError); !ok || refusal.Reason != "bot budget exceeded" || refusal.Available != 1000 {
+		t.Fatalf("expected the budget to refuse the buy, got %v", err)
+	}
+
+	if err := allocator.Reserve(first, 300); err != nil {
+		t.Fatalf("expected the reservation to fit, got %v", err)
+	}
+	// The first bot's buy is not debited yet, leaving 200 BRL
+	err = allocator.Reserve(second, 300)
+	if refusal, ok := err.(*InsufficientCapitalError); !ok || refusal.Reason != "insufficient account balance" || refusal.Available != 200 {
+		t.Fatalf("expected the pending reservation to refuse the buy, got %v", err)
+	}
+
+	// Once settled the account balance accounts for it
+	allocator.Settle(first, 299)
+	if err := allocator.Reserve(second, 300); err != nil {
+		t.Errorf("expected the settled reservation to leave the balance to the other bot, got %v", err)
+	}
+
+	allocator.Release(first)
+	if _, ok := allocator.reservations[first.Id.GetValue()]; ok {
+		t.Error("expected the reservation to be released")
+	}
+}
+
+func TestLiveTradingExecutionContext_RefusesBuyWithoutCapital(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBalance("BRL", 150)
+	broker := &recordingMessageBroker{}
+	allocator := NewCapitalAllocator(client)
+	ctx := NewLiveTradingExecutionContext(client, &MockTradeBotRepository{}, &MockTradingDecisionLogRepository{}, broker, "test_exchange").
+		WithCapitalAllocator(allocator)
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeMarket, "", 0)
+
+	// 2 SOL at 100 BRL cost 200 BRL
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("expected the refusal not to be an error, got %v", err)
+	}
+	if bot.GetIsPositioned() || len(client.GetPlacedOrders()) != 0 {
+		t.Fatal("expected no order to be placed")
+	}
+	if len(broker.messages) != 1 || broker.messages[0].RoutingKey != "trading.buy_refused" {
+		t.Fatalf("expected a buy refused event, got %v", broker.messages)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(broker.messages[0].Payload, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload["reason"] != "insufficient account balance" || payload["required"] != 200.0 || payload["available"] != 150.0 {
+		t.Errorf("expected the reason and amounts of the refusal, got %v", payload)
+	}
+
+	// With enough balance the buy reserves its cost until the sell
+	client.SetBalance("BRL", 1000)
+	client.AddOrderFills(entity.Fill{Price: 100.0, Quantity: 2.0, Commission: 0.002, CommissionAsset: "SOL"})
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+	if reservation := allocator.reservations[bot.Id.GetValue()]; !reservation.settled || reservation.amount != 200 {
+		t.Fatalf("expected the buy's cost to be reserved, got %+v", reservation)
+	}
+	if err := ctx.ExecuteTrade(entity.Sell, bot, 110.0, time.Now()); err != nil {
+		t.Fatalf("sell failed: %v", err)
+	}
+	if _, ok := allocator.reservations[bot.Id.GetValue()]; ok {
+		t.Error("expected the sell to release the reservation")
+	}
+}
+
+func TestCapitalAllocator_RestoresPositionOpenedBeforeRestart(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBalance("BRL", 5000)
+	allocator := NewCapitalAllocator(client)
+	ctx := NewLiveTradingExecutionContext(client, &MockTradeBotRepository{}, &MockTradingDecisionLogRepository{}, &MockMessageBroker{}, "test_exchange").
+		WithCapitalAllocator(allocator)
+
+	// Position of 8 SOL at 100 BRL opened before the restart, unknown to the new allocator
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeMarket, "", 0)
+	bot.RecordEntryFill(entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 8.0, NetQuantity: 8.0, FeesInQuote: 0.8})
+	_ = bot.GetIntoPosition()
+
+	if err := ctx.ReconcilePosition(bot, time.Now()); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	reservation, ok := allocator.reservations[bot.Id.GetValue()]
+	if !ok || !reservation.settled {
+		t.Fatalf("expected the position's cost to be restored, got %+v", reservation)
+	}
+	assertAlmostEqual(t, "restored cost", 800.8, reservation.amount)
+
+	// What the position cost counts against the bot's 1000 BRL budget
+	err := allocator.Reserve(bot, 300)
+	refusal, refused := err.(*InsufficientCapitalError)
+	if !refused || refusal.Reason != "bot budget exceeded" {
+		t.Fatalf("expected the budget left to refuse the buy, got %v", err)
+	}
+	assertAlmostEqual(t, "budget left", 199.2, refusal.Available)
+
+	// Restoring again does not count the position twice
+	allocator.Restore(bot)
+	if err := allocator.Reserve(bot, 150); err != nil {
+		t.Fatalf("expected the budget left to cover the buy, got %v", err)
+	}
+	allocator.Settle(bot, 150)
+	assertAlmostEqual(t, "spent on both buys", 950.8, allocator.reservations[bot.Id.GetValue()].debited())
+
+	allocator.Release(bot)
+	if err := allocator.Reserve(bot, 900); err != nil {
+		t.Errorf("expected the sell to give the whole budget back, got %v", err)
+	}
+}
@@ -8,27 +8,42 @@ import (
 
 // HistoricalMarketDataSource implements MarketDataSource using pre-loaded historical data
 type HistoricalMarketDataSource struct {
-	historicalData []vo.Kline
-	currentIndex   int
-	windowSize     int // Number of klines to return (like the limit in live data)
+	historicalData      []vo.Kline
+	currentIndex        int
+	windowSize          int                // Number of klines to return (like the limit in live data)
+	baseIntervalSeconds int                // Interval of historicalData, inferred from the close times
+	timeframes          map[int][]vo.Kline // Pre-loaded klines of higher intervals, keyed by interval in seconds
 }
 
 // NewHistoricalMarketDataSource creates a new HistoricalMarketDataSource
 func NewHistoricalMarketDataSource(historicalData []vo.Kline, windowSize int) *HistoricalMarketDataSource {
 	return &HistoricalMarketDataSource{
-		historicalData: historicalData,
-		currentIndex:   0,
-		windowSize:     windowSize,
+		historicalData:      historicalData,
+		currentIndex:        0,
+		windowSize:          windowSize,
+		baseIntervalSeconds: InferIntervalSeconds(historicalData),
+		timeframes:          make(map[int][]vo.Kline),
 	}
 }
 
+// AddTimeframeData pre-loads klines of a higher interval. Without it, higher intervals are resampled from the base data.
+func (s *HistoricalMarketDataSource) AddTimeframeData(intervalSeconds int, klines []vo.Kline) {
+	s.timeframes[intervalSeconds] = klines
+}
+
 // GetMarketData returns a window of historical klines ending at the current index
-// Note: intervalSeconds is ignored for historical data as the data is already filtered by interval
+// Intervals up to the base interval return the base data as is, since it is already filtered by interval.
+// Higher intervals never look ahead: they only contain klines that closed by the current kline's close time,
+// see ClosedKlines.
 func (s *HistoricalMarketDataSource) GetMarketData(symbol string, intervalSeconds int) ([]vo.Kline, error) {
 	if s.currentIndex >= len(s.historicalData) {
 		return nil, fmt.Errorf("no more historical data available")
 	}
 
+	if intervalSeconds > s.baseIntervalSeconds && s.baseIntervalSeconds > 0 {
+		return s.getHigherTimeframe(intervalSeconds)
+	}
+
 	// Calculate the start index for the window
 	startIndex := s.currentIndex - s.windowSize + 1
 	if startIndex < 0 {
@@ -44,10 +59,25 @@ func (s *HistoricalMarketDataSource) GetMarketData(symbol string, intervalSecond
 	return s.historicalData[startIndex:endIndex], nil
 }
 
+// getHigherTimeframe aligns pre-loaded klines to the current close time, or resamples the base data up to it,
+// keeping the closed klines only either way
+func (s *HistoricalMarketDataSource) getHigherTimeframe(intervalSeconds int) ([]vo.Kline, error) {
+	if klines, exists := s.timeframes[intervalSeconds]; exists {
+		closed := ClosedKlines(klines, s.historicalData[s.currentIndex].CloseTime())
+		startIndex := len(closed) - s.windowSize
+		if startIndex < 0 {
+			startIndex = 0
+		}
+		return closed[startIndex:], nil
+	}
+
+	return ResampleClosedKlines(s.historicalData[:s.currentIndex+1], s.baseIntervalSeconds, intervalSeconds, s.windowSize)
+}
+
 // GetCurrentTime returns the timestamp of the current kline being processed
 func (s *HistoricalMarketDataSource) GetCurrentTime() time.Time {
 	if s.currentIndex < len(s.historicalData) {
-		return time.Unix(s.historicalData[s.currentIndex].CloseTime()/1000, 0)
+		return time.UnixMilli(s.historicalData[s.currentIndex].CloseTime())
 	}
 	return time.Now()
 }
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"testing"
+	"time"
+)
+
+// createHourlyKlines creates hourly klines with Binance close times (open time + interval - 1ms), closing at 1, 2, 3...
+func createHourlyKlines(count int) []vo.Kline {
+	klines := make([]vo.Kline, count)
+	for i := range klines {
+		price := float64(i + 1)
+		klines[i], _ = vo.NewKline(price, price, price+0.5, price-0.5, 10.0, int64(i+1)*3600000-1)
+	}
+	return klines
+}
+
+func TestResampleKlines(t *testing.T) {
+	klines := createHourlyKlines(10)
+
+	resampled, err := ResampleKlines(klines, 3600, 14400, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resampled) != 3 {
+		t.Fatalf("expected 3 four-hour klines (the last one still forming), got %d", len(resampled))
+	}
+
+	first := resampled[0]
+	if first.Open() != 1 || first.Close() != 4 || first.High() != 4.5 || first.Low() != 0.5 || first.Volume() != 40 {
+		t.Errorf("unexpected first 4h kline: open %.1f close %.1f high %.1f low %.1f volume %.1f",
+			first.Open(), first.Close(), first.High(), first.Low(), first.Volume())
+	}
+	if first.CloseTime() != 4*3600000-1 {
+		t.Errorf("expected first 4h kline to close at %d, got %d", 4*3600000-1, first.CloseTime())
+	}
+
+	// The forming kline only aggregates the klines seen so far
+	if last := resampled[2]; last.Open() != 9 || last.Close() != 10 {
+		t.Errorf("expected forming 4h kline from 9 to 10, got %.1f to %.1f", last.Open(), last.Close())
+	}
+
+	limited, err := ResampleKlines(klines, 3600, 14400, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(limited) != 2 || limited[0].Open() != 5 || limited[0].Close() != 8 {
+		t.Errorf("expected the latest 2 4h klines, got %d starting at %.1f", len(limited), limited[0].Open())
+	}
+
+	// Only the buckets closed by the last kline are kept
+	closed, err := ResampleClosedKlines(klines, 3600, 14400, 1)
+	if err != nil || len(closed) != 1 || closed[0].Open() != 5 || closed[0].Close() != 8 {
+		t.Errorf("expected the latest closed 4h kline from 5 to 8, got %d klines (%v)", len(closed), err)
+	}
+	if closed, _ := ResampleClosedKlines(klines[:8], 3600, 14400, 0); len(closed) != 2 || closed[1].Close() != 8 {
+		t.Errorf("expected the 4h kline closed by the last hourly kline to be kept, got %d klines", len(closed))
+	}
+
+	if _, err := ResampleKlines(klines, 3600, 5400, 0); err == nil {
+		t.Error("expected error for a target interval that is not a multiple of the base interval")
+	}
+	if _, err := ResampleKlines(klines, 3600, 1800, 0); err == nil {
+		t.Error("expected error for a target interval lower than the base interval")
+	}
+}
+
+func TestHistoricalMarketDataSource_HigherTimeframeHasNoLookAhead(t *testing.T) {
+	dataSource := NewHistoricalMarketDataSource(createHourlyKlines(10), 100)
+	for i := 0; i < 5; i++ {
+		dataSource.AdvanceToNext()
+	}
+	currentCloseTime := dataSource.GetCurrentTime().Unix() * 1000
+
+	// The bot's own interval is served as before
+	base, err := dataSource.GetMarketData("SOLBRL", 3600)
+	if err != nil || len(base) != 6 {
+		t.Fatalf("expected 6 hourly klines, got %d (%v)", len(base), err)
+	}
+
+	resampled, err := dataSource.GetMarketData("SOLBRL", 14400)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resampled) != 1 || resampled[0].CloseTime() > currentCloseTime {
+		t.Fatalf("expected only the closed 4h kline, got %d klines", len(resampled))
+	}
+
+	// Pre-loaded klines still open at the current time are not returned either
+	fourHour, _ := ResampleKlines(createHourlyKlines(10), 3600, 14400, 0)
+	dataSource.AddTimeframeData(14400, fourHour)
+	aligned, err := dataSource.GetMarketData("SOLBRL", 14400)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(aligned) != 1 {
+		t.Fatalf("expected only the closed 4h kline, got %d", len(aligned))
+	}
+	for _, kline := range aligned {
+		if kline.CloseTime() > currentCloseTime {
+			t.Errorf("kline closing at %d looks ahead of the current time %d", kline.CloseTime(), currentCloseTime)
+		}
+	}
+}
+
+// liveMarketDataSource serves fixed klines at a fixed time, like the exchange returning its forming kline
+type liveMarketDataSource struct {
+	klines map[int][]vo.Kline
+	now    time.Time
+}
+
+func (s *liveMarketDataSource) GetMarketData(symbol string, intervalSeconds int) ([]vo.Kline, error) {
+	return s.klines[intervalSeconds], nil
+}
+
+func (s *liveMarketDataSource) GetCurrentTime() time.Time {
+	return s.now
+}
+
+func TestFetchTimeframes_LeavesOutTheFormingKline(t *testing.T) {
+	fourHour, _ := ResampleKlines(createHourlyKlines(10), 3600, 14400, 0)
+	source := &liveMarketDataSource{
+		klines: map[int][]vo.Kline{14400: fourHour},
+		now:    time.UnixMilli(9*3600000 + 1800000), // Half an hour into the third 4h kline
+	}
+
+	timeframes, err := FetchTimeframes(source, "SOLBRL", []int{14400})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if klines := timeframes[14400]; len(klines) != 2 || klines[1].CloseTime() != 8*3600000-1 {
+		t.Errorf("expected the 2 closed 4h klines, got %d", len(klines))
+	}
+}
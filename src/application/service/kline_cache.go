@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultKlineCacheLimit  = 100             // Klines kept per symbol and interval, same window the bots always used
+	DefaultKlineCacheMaxAge = 5 * time.Second // Klines refreshed within this window are served without calling Binance
+)
+
+// KlineCache is an in-process kline store shared by every bot, keyed by symbol and interval.
+// Bots on the same pair read the same klines, and refreshes only fetch the candles that are new
+// since the last one (plus the still forming candle), which keeps the API weight low.
+type KlineCache struct {
+	exchange exchange.Exchange
+	limit    int
+	maxAge   time.Duration
+	now      func() time.Time
+	mu       sync.Mutex
+	entries  map[string]*klineCacheEntry
+
+	hits               atomic.Int64
+	misses             atomic.Int64
+	incrementalFetches atomic.Int64
+}
+
+type klineCacheEntry struct {
+	mu              sync.Mutex // Serializes refreshes, so concurrent bots on the same pair share one fetch
+	symbol          string
+	intervalSeconds int
+	klines          []vo.Kline
+	refreshedAt     time.Time
+}
+
+// KlineCacheStats reports how often bots were served without a full fetch from Binance
+type KlineCacheStats struct {
+	Hits               int64                  `json:"hits"`                // Served from memory, no API call
+	Misses             int64                  `json:"misses"`              // Full window fetched from Binance
+	IncrementalFetches int64                  `json:"incremental_fetches"` // Only the new candles fetched from Binance
+	HitRate            float64                `json:"hit_rate"`            // Hits as a percentage of all requests
+	Entries            []KlineCacheEntryStats `json:"entries"`
+}
+
+// KlineCacheEntryStats describes one cached symbol and interval
+type KlineCacheEntryStats struct {
+	Symbol          string    `json:"symbol"`
+	IntervalSeconds int       `json:"interval_seconds"`
+	Klines          int       `json:"klines"`
+	RefreshedAt     time.Time `json:"refreshed_at"`
+}
+
+// NewKlineCache creates a kline cache with the default window and max age
+func NewKlineCache(exchangeClient exchange.Exchange) *KlineCache {
+	return NewKlineCacheWithOptions(exchangeClient, DefaultKlineCacheLimit, DefaultKlineCacheMaxAge, time.Now)
+}
+
+// NewKlineCacheWithOptions creates a kline cache with a custom window, max age and clock
+func NewKlineCacheWithOptions(exchangeClient exchange.Exchange, limit int, maxAge time.Duration, now func() time.Time) *KlineCache {
+	return &KlineCache{
+		exchange: exchangeClient,
+		limit:    limit,
+		maxAge:   maxAge,
+		now:      now,
+		entries:  make(map[string]*klineCacheEntry),
+	}
+}
+
+// GetKlines returns the latest klines of the symbol and interval, refreshing them from Binance when stale
+func (c *KlineCache) GetKlines(symbol string, intervalSeconds int) ([]vo.Kline, error) {
+	interval, err := exchange.SecondsToInterval(intervalSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval %d seconds: %v", intervalSeconds, err)
+	}
+
+	entry := c.getEntry(symbol, intervalSeconds)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	now := c.now()
+	if len(entry.klines) > 0 && now.Sub(entry.refreshedAt) < c.maxAge {
+		c.hits.Add(1)
+		return copyKlines(entry.klines), nil
+	}
+
+	if len(entry.klines) == 0 || c.isTooFarBehind(entry, now) {
+		klines, err := c.fetch(symbol, interval, 0)
+		if err != nil {
+			return nil, err
+		}
+		c.misses.Add(1)
+		entry.klines = klines
+	} else {
+		// Start at the last cached candle: it may have been still forming when it was fetched
+		intervalMs := int64(intervalSeconds) * 1000
+		lastOpenTime := entry.klines[len(entry.klines)-1].CloseTime() - intervalMs + 1
+		klines, err := c.fetch(symbol, interval, lastOpenTime)
+		if err != nil {
+			return nil, err
+		}
+		c.incrementalFetches.Add(1)
+		entry.klines = mergeKlines(entry.klines, klines, c.limit)
+	}
+	entry.refreshedAt = now
+
+	return copyKlines(entry.klines), nil
+}
+
+// Stats returns the cache metrics
+func (c *KlineCache) Stats() KlineCacheStats {
+	stats := KlineCacheStats{
+		Hits:               c.hits.Load(),
+		Misses:             c.misses.Load(),
+		IncrementalFetches: c.incrementalFetches.Load(),
+		Entries:            []KlineCacheEntryStats{},
+	}
+	if total := stats.Hits + stats.Misses + stats.IncrementalFetches; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total) * 100
+	}
+
+	c.mu.Lock()
+	entries := make([]*klineCacheEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, entry)
+	}
+	c.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.mu.Lock()
+		stats.Entries = append(stats.Entries, KlineCacheEntryStats{
+			Symbol:          entry.symbol,
+			IntervalSeconds: entry.intervalSeconds,
+			Klines:          len(entry.klines),
+			RefreshedAt:     entry.refreshedAt,
+		})
+		entry.mu.Unlock()
+	}
+	return stats
+}
+
+func (c *KlineCache) getEntry(symbol string, intervalSeconds int) *klineCacheEntry {
+	key := klineKey(symbol, intervalSeconds)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, exists := c.entries[key]
+	if !exists {
+		entry = &klineCacheEntry{symbol: symbol, intervalSeconds: intervalSeconds}
+		c.entries[key] = entry
+	}
+	return entry
+}
+
+func klineKey(symbol string, intervalSeconds int) string {
+	return fmt.Sprintf("%s:%d", symbol, intervalSeconds)
+}
+
+// isTooFarBehind reports whether more candles closed since the last refresh than one fetch returns
+func (c *KlineCache) isTooFarBehind(entry *klineCacheEntry, now time.Time) bool {
+	intervalMs := int64(entry.intervalSeconds) * 1000
+	lastCloseTime := entry.klines[len(entry.klines)-1].CloseTime()
+	return (now.UnixMilli()-lastCloseTime)/intervalMs >= int64(c.limit-1)
+}
+
+// mergeKlines replaces the cached candles from the first fetched one onwards and keeps the latest limit klines
+func mergeKlines(cached, fetched []vo.Kline, limit int) []vo.Kline {
+	if len(fetched) == 0 {
+		return cached
+	}
+
+	keep := len(cached)
+	for keep > 0 && cached[keep-1].CloseTime() >= fetched[0].CloseTime() {
+		keep--
+	}
+	merged := append(copyKlines(cached[:keep]), fetched...)
+	if len(merged) > limit {
+		merged = merged[len(merged)-limit:]
+	}
+	return merged
+}
+
+func (c *KlineCache) fetch(symbol, interval string, startTime int64) ([]vo.Kline, error) {
+	return c.exchange.GetKlines(context.Background(), exchange.KlineQuery{
+		Symbol:    symbol,
+		Interval:  interval,
+		StartTime: startTime,
+		Limit:     c.limit,
+	})
+}
+
+func copyKlines(klines []vo.Kline) []vo.Kline {
+	return append([]vo.Kline(nil), klines...)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/external"
+	"sync"
+	"testing"
+	"time"
+)
+
+// exchangeKlinesClient serves one candle per minute up to the current time and honours StartTime and Limit
+type exchangeKlinesClient struct {
+	*external.FakeExchange
+	mu        sync.Mutex
+	now       time.Time
+	calls     int
+	startTime []int64
+}
+
+func (c *exchangeKlinesClient) GetKlines(ctx context.Context, query exchange.KlineQuery) ([]vo.Kline, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	c.startTime = append(c.startTime, query.StartTime)
+
+	// The forming candle closes at the end of the current minute, like on the exchange
+	nowMs := c.now.UnixMilli()
+	formingOpenTime := nowMs - nowMs%60000
+	openTime := formingOpenTime - int64(query.Limit-1)*60000
+	if query.StartTime > 0 {
+		openTime = query.StartTime
+	}
+
+	var klines []vo.Kline
+	for ; openTime <= formingOpenTime && len(klines) < query.Limit; openTime += 60000 {
+		price := float64(openTime/60000%1000 + 1)
+		kline, err := vo.NewKline(price, price, price, price, 1.0, openTime+59999)
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, kline)
+	}
+	return klines, nil
+}
+
+func TestKlineCache_ServesBotsOnTheSamePairFromOneFetch(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	client := &exchangeKlinesClient{FakeExchange: external.NewFakeExchange(), now: now}
+	cache := NewKlineCacheWithOptions(client, 100, 5*time.Second, func() time.Time { return now })
+
+	for bot := 0; bot < 3; bot++ {
+		klines, err := cache.GetKlines("SOLBRL", 60)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(klines) != 100 {
+			t.Fatalf("expected 100 klines, got %d", len(klines))
+		}
+	}
+
+	if client.calls != 1 {
+		t.Errorf("expected a single Binance call for 3 bots, got %d", client.calls)
+	}
+	stats := cache.Stats()
+	if stats.Misses != 1 || stats.Hits != 2 || len(stats.Entries) != 1 {
+		t.Errorf("expected 1 miss and 2 hits on 1 entry, got %+v", stats)
+	}
+
+	// Another interval is a separate entry
+	if _, err := cache.GetKlines("SOLBRL", 300); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats := cache.Stats(); stats.Misses != 2 || len(stats.Entries) != 2 {
+		t.Errorf("expected a miss for the new interval, got %+v", stats)
+	}
+}
+
+func TestKlineCache_FetchesOnlyNewCandles(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	client := &exchangeKlinesClient{FakeExchange: external.NewFakeExchange(), now: now}
+	cache := NewKlineCacheWithOptions(client, 100, 5*time.Second, func() time.Time { return now })
+
+	first, _ := cache.GetKlines("SOLBRL", 60)
+
+	// Three minutes later: the old forming candle closed and three more candles opened
+	now = now.Add(3 * time.Minute)
+	client.now = now
+	klines, err := cache.GetKlines("SOLBRL", 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lastCached := first[len(first)-1]
+	if client.startTime[1] != lastCached.CloseTime()-59999 {
+		t.Errorf("expected the refresh to start at the last cached candle, got start time %d", client.startTime[1])
+	}
+	if len(klines) != 100 {
+		t.Fatalf("expected the window to stay at 100 klines, got %d", len(klines))
+	}
+	if klines[96].CloseTime() != lastCached.CloseTime() || klines[0].CloseTime() != first[3].CloseTime() {
+		t.Error("expected the window to slide by the three new candles")
+	}
+	for i := 1; i < len(klines); i++ {
+		if klines[i].CloseTime()-klines[i-1].CloseTime() != 60000 {
+			t.Fatalf("expected contiguous candles, gap at %d", i)
+		}
+	}
+	if stats := cache.Stats(); stats.IncrementalFetches != 1 || stats.Misses != 1 {
+		t.Errorf("expected 1 incremental fetch after the first miss, got %+v", stats)
+	}
+
+	// After more candles than one fetch returns, the whole window is fetched again
+	now = now.Add(3 * time.Hour)
+	client.now = now
+	if _, err := cache.GetKlines("SOLBRL", 60); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.startTime[2] != 0 {
+		t.Errorf("expected a full fetch after a long gap, got start time %d", client.startTime[2])
+	}
+	if stats := cache.Stats(); stats.Misses != 2 {
+		t.Errorf("expected a second miss after a long gap, got %+v", stats)
+	}
+}
+
+func TestKlineCache_InvalidInterval(t *testing.T) {
+	client := &exchangeKlinesClient{FakeExchange: external.NewFakeExchange(), now: time.Now()}
+	cache := NewKlineCache(client)
+
+	if _, err := cache.GetKlines("SOLBRL", 120); err == nil {
+		t.Error("expected error for an unsupported interval")
+	}
+	if client.calls != 0 {
+		t.Errorf("expected no Binance call for an unsupported interval, got %d", client.calls)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+	"sort"
+)
+
+// InferIntervalSeconds returns the interval of the klines from the distance between their close times, or 0 if unknown
+func InferIntervalSeconds(klines []vo.Kline) int {
+	if len(klines) < 2 {
+		return 0
+	}
+	return int((klines[1].CloseTime() - klines[0].CloseTime()) / 1000)
+}
+
+// ClosedKlines returns the klines closed by closeTime (Unix milliseconds), leaving out the latest ones still forming.
+// Higher timeframes only hold closed klines, in backtests and live trading alike, so a strategy never decides on the
+// partial OHLC of a higher timeframe kline.
+func ClosedKlines(klines []vo.Kline, closeTime int64) []vo.Kline {
+	end := sort.Search(len(klines), func(i int) bool {
+		return klines[i].CloseTime() > closeTime
+	})
+	return klines[:end]
+}
+
+// ResampleClosedKlines resamples klines like ResampleKlines, leaving out the last bucket while it is still forming
+// at the close of the last kline
+func ResampleClosedKlines(klines []vo.Kline, baseIntervalSeconds, targetIntervalSeconds, limit int) ([]vo.Kline, error) {
+	readLimit := limit
+	if limit > 0 {
+		readLimit = limit + 1
+	}
+	resampled, err := ResampleKlines(klines, baseIntervalSeconds, targetIntervalSeconds, readLimit)
+	if err != nil || len(klines) == 0 {
+		return resampled, err
+	}
+
+	resampled = ClosedKlines(resampled, klines[len(klines)-1].CloseTime())
+	if limit > 0 && len(resampled) > limit {
+		resampled = resampled[len(resampled)-limit:]
+	}
+	return resampled, nil
+}
+
+// ResampleKlines aggregates klines of baseIntervalSeconds into klines of targetIntervalSeconds.
+// Buckets are aligned to the target interval like exchange candles (a 4h kline opens at 00:00, 04:00, ...).
+// The last bucket may still be forming, exactly like the latest kline returned by the exchange, but it only
+// aggregates the klines it was given, so it never looks ahead. limit keeps only the latest buckets (0 = all).
+func ResampleKlines(klines []vo.Kline, baseIntervalSeconds, targetIntervalSeconds, limit int) ([]vo.Kline, error) {
+	if baseIntervalSeconds <= 0 || targetIntervalSeconds < baseIntervalSeconds || targetIntervalSeconds%baseIntervalSeconds != 0 {
+		return nil, fmt.Errorf("cannot resample %ds klines into %ds klines", baseIntervalSeconds, targetIntervalSeconds)
+	}
+	if len(klines) == 0 {
+		return []vo.Kline{}, nil
+	}
+
+	baseMs := int64(baseIntervalSeconds) * 1000
+	targetMs := int64(targetIntervalSeconds) * 1000
+
+	// Only aggregate the tail that can produce the requested buckets; the first bucket may then be cut
+	// in half, so one extra bucket is read and dropped below
+	start := 0
+	if limit > 0 {
+		ratio := targetIntervalSeconds / baseIntervalSeconds
+		if tail := (limit + 1) * ratio; len(klines) > tail {
+			start = len(klines) - tail
+		}
+	}
+
+	var resampled []vo.Kline
+	var bucketStart int64 = -1
+	var open, closePrice, high, low, volume float64
+
+	flush := func() error {
+		kline, err := vo.NewKline(open, closePrice, high, low, volume, bucketStart+targetMs-1)
+		if err != nil {
+			return err
+		}
+		resampled = append(resampled, kline)
+		return nil
+	}
+
+	for _, kline := range klines[start:] {
+		openTime := kline.CloseTime() - baseMs + 1
+		currentBucket := openTime - openTime%targetMs
+
+		if currentBucket != bucketStart {
+			if bucketStart >= 0 {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+			bucketStart = currentBucket
+			open, high, low, volume = kline.Open(), kline.High(), kline.Low(), 0
+		}
+
+		closePrice = kline.Close()
+		volume += kline.Volume()
+		if kline.High() > high {
+			high = kline.High()
+		}
+		if kline.Low() < low {
+			low = kline.Low()
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if start > 0 && len(resampled) > 1 {
+		resampled = resampled[1:]
+	}
+	if limit > 0 && len(resampled) > limit {
+		resampled = resampled[len(resampled)-limit:]
+	}
+	return resampled, nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"fmt"
+	"time"
+)
+
+// placeProtectiveOCO places the exchange-side OCO sell of a bot that just entered a position, so the stop loss
+// and take profit hold between ticks and while the bot is down. A failure is logged and leaves the position
+// protected by the strategy only.
+func (ctx *LiveTradingExecutionContext) placeProtectiveOCO(bot *entity.TradingBot) {
+	oco := bot.GetExchangeOCO()
+	if !oco.IsEnabled() {
+		return
+	}
+	symbol := bot.GetSymbol().GetValue()
+
+	takeProfitPrice, stopPrice, stopLimitPrice := oco.Prices(bot.GetEntryPrice())
+	_, formattedTakeProfit, errTP := ctx.orderValidator.AdjustLimitPrice(symbol, takeProfitPrice, false)
+	_, formattedStop, errStop := ctx.orderValidator.AdjustLimitPrice(symbol, stopPrice, true)
+	adjustedStopLimit, formattedStopLimit, errStopLimit := ctx.orderValidator.AdjustLimitPrice(symbol, stopLimitPrice, true)
+	if errTP != nil || errStop != nil || errStopLimit != nil {
+		fmt.Printf("⚠️ [%s] Could not price the OCO (tp: %v, stop: %v, stop limit: %v), position protected by the strategy only\n",
+			symbol, errTP, errStop, errStopLimit)
+		return
+	}
+
+	// Both legs must be sellable at the lowest price, the stop limit
+	formattedQty, tradable := ctx.tradableQuantity(symbol, bot.CalculateQuantityForSell(), adjustedStopLimit)
+	if !tradable {
+		fmt.Printf("⚠️ [%s] Position too small for an OCO at %s, protected by the strategy only\n", symbol, formattedStopLimit)
+		return
+	}
+
+	response, err := ctx.exchange.PlaceOCO(context.Background(), exchange.OCORequest{
+		Symbol:               symbol,
+		Side:                 entity.OrderSideSell,
+		Quantity:             formattedQty,
+		Price:                formattedTakeProfit,
+		StopPrice:            formattedStop,
+		StopLimitPrice:       formattedStopLimit,
+		StopLimitTimeInForce: exchange.TimeInForceGTC,
+	})
+	if err != nil {
+		fmt.Printf("⚠️ [%s] Failed to place the OCO: %v, position protected by the strategy only\n", symbol, err)
+		return
+	}
+
+	bot.SetActiveOCO(entity.ActiveOCO{
+		OrderListID:       response.OrderListID,
+		TakeProfitOrderID: response.TakeProfitOrderID,
+		StopLossOrderID:   response.StopLossOrderID,
+	})
+	fmt.Printf("🛡️ [%s] OCO placed: OrderListID=%d, Qty=%s, take profit %s, stop %s (limit %s)\n",
+		symbol, response.OrderListID, formattedQty, formattedTakeProfit, formattedStop, formattedStopLimit)
+}
+
+// cancelProtectiveOCO cancels the OCO of a bot before the strategy sells, as it holds the position's quantity on
+// the exchange. When the OCO can no longer be canceled it was probably filled, then the position is reconciled
+// and closed reports whether the OCO closed it.
+func (ctx *LiveTradingExecutionContext) cancelProtectiveOCO(bot *entity.TradingBot, timestamp time.Time) (closed bool, err error) {
+	active := bot.GetActiveOCO()
+	if !active.IsActive() {
+		return false, nil
+	}
+	symbol := bot.GetSymbol().GetValue()
+
+	errCancel := ctx.exchange.CancelOCO(context.Background(), symbol, active.OrderListID)
+	if errCancel == nil {
+		fmt.Printf("↩️ [%s] OCO %d canceled, the strategy exits first\n", symbol, active.OrderListID)
+		bot.ClearActiveOCO()
+		return false, nil
+	}
+
+	if err := ctx.ReconcilePosition(bot, timestamp); err != nil {
+		return false, fmt.Errorf("failed to cancel OCO %d: %v (%v)", active.OrderListID, errCancel, err)
+	}
+	if !bot.GetIsPositioned() {
+		return true, nil
+	}
+	if bot.GetActiveOCO().IsActive() {
+		return false, fmt.Errorf("failed to cancel OCO %d: %v", active.OrderListID, errCancel)
+	}
+	return false, nil
+}
+
+// restoreProtectiveOCO places the OCO again over a position whose sell did not go through, e.g. one the exchange
+// rejected, so the position is not left unprotected once its OCO was canceled for the sell
+func (ctx *LiveTradingExecutionContext) restoreProtectiveOCO(bot *entity.TradingBot) {
+	if !bot.GetIsPositioned() || !bot.GetExchangeOCO().IsEnabled() || bot.GetActiveOCO().IsActive() {
+		return
+	}
+
+	ctx.placeProtectiveOCO(bot)
+	if !bot.GetActiveOCO().IsActive() {
+		return
+	}
+	if err := ctx.tradingBotRepository.Update(bot); err != nil {
+		fmt.Printf("⚠️ [%s] Failed to save the restored OCO: %v\n", bot.GetSymbol().GetValue(), err)
+	}
+}
+
+// ReconcilePosition syncs the bot with the OCO protecting its position: when a leg filled, e.g. while the bot was
+// down, the position is closed and recorded as a trade; when both legs ended unfilled the OCO is forgotten. A
+// position opened before a restart is first restored in the capital allocator.
+func (ctx *LiveTradingExecutionContext) ReconcilePosition(bot *entity.TradingBot, timestamp time.Time) error {
+	if ctx.capitalAllocator != nil {
+		ctx.capitalAllocator.Restore(bot)
+	}
+
+	active := bot.GetActiveOCO()
+	if !active.IsActive() {
+		return nil
+	}
+	symbol := bot.GetSymbol().GetValue()
+
+	takeProfit, err := ctx.exchange.GetOrder(context.Background(), symbol, active.TakeProfitOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to query the take profit of OCO %d: %v", active.OrderListID, err)
+	}
+	stopLoss, err := ctx.exchange.GetOrder(context.Background(), symbol, active.StopLossOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to query the stop loss of OCO %d: %v", active.OrderListID, err)
+	}
+
+	switch {
+	case takeProfit.Status == exchange.OrderStatusFilled:
+		return ctx.closePositionByOCO(bot, takeProfit, entity.ExitReasonOCOTakeProfit, timestamp)
+	case stopLoss.Status == exchange.OrderStatusFilled:
+		return ctx.closePositionByOCO(bot, stopLoss, entity.ExitReasonOCOStopLoss, timestamp)
+	case isOrderDone(takeProfit.Status) && isOrderDone(stopLoss.Status):
+		// Both legs ended without filling, e.g. canceled by hand on the exchange
+		fmt.Printf("⚠️ [%s] OCO %d ended unfilled (%s/%s), position protected by the strategy only\n",
+			symbol, active.OrderListID, takeProfit.Status, stopLoss.Status)
+		bot.ClearActiveOCO()
+		return ctx.tradingBotRepository.Update(bot)
+	}
+	return nil
+}
+
+// closePositionByOCO records the OCO leg that filled as the bot's sell order and closes the position
+func (ctx *LiveTradingExecutionContext) closePositionByOCO(bot *entity.TradingBot, leg *exchange.Order, reason string, timestamp time.Time) error {
+	symbol := bot.GetSymbol().GetValue()
+	if !bot.GetIsPositioned() {
+		bot.ClearActiveOCO()
+		return ctx.tradingBotRepository.Update(bot)
+	}
+
+	order := entity.NewOrder(bot.Id, "", symbol, entity.OrderSideSell, string(leg.Type), leg.Quantity, leg.Price)
+	fill := ctx.executedFillOf(bot, leg)
+	if !leg.UpdatedAt.IsZero() {
+		fill.ExecutedAt = leg.UpdatedAt
+	}
+	order.MarkFilled(fill)
+	ctx.saveOrder(order)
+
+	fmt.Printf("🛡️ [%s] OCO %d closed the position (%s): OrderID=%d, filled %.8f @ %.4f\n",
+		symbol, bot.GetActiveOCO().OrderListID, reason, leg.OrderID, fill.ExecutedQuantity, fill.AveragePrice)
+	bot.RecordExit(reason, timestamp)
+	return ctx.closePosition(bot, order, timestamp)
+}
+
+// isOrderDone reports whether an order left the book without filling
+func isOrderDone(status exchange.OrderStatus) bool {
+	switch status {
+	case exchange.OrderStatusCanceled, exchange.OrderStatusExpired, exchange.OrderStatusRejected:
+		return true
+	}
+	return false
+}
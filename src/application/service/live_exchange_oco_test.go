@@ -0,0 +1,149 @@
+package service
+
+import (
+	appRepository "crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/external"
+	"crypgo-machine/src/infra/repository"
+	"testing"
+	"time"
+
+)
+
+// newOCOTestBot returns a bot with an open SOLBRL position of 1.998 entered at 100, protected by an OCO with a
+// 2% stop loss and a 5% take profit
+func newOCOTestBot(t *testing.T, client *external.FakeExchange) (*LiveTradingExecutionContext, *entity.TradingBot, *repository.TradeRepositoryInMemory) {
+	tradeRepo := repository.NewTradeRepositoryInMemory()
+	ctx := NewLiveTradingExecutionContext(client, &MockTradeBotRepository{}, &MockTradingDecisionLogRepository{}, &MockMessageBroker{}, "test_exchange").
+		WithLedger(repository.NewOrderRepositoryInMemory(), tradeRepo)
+
+	symbol, _ := vo.NewSymbol("SOLBRL")
+	bot := entity.NewTradingBot(symbol, 2.0, entity.NewMovingAverageStrategy(5, 20), 60, 1000, 300, "BRL", 0.1, 2.0, true)
+	oco, err := entity.NewExchangeOCO(2.0, 5.0, 0.5)
+	if err != nil {
+		t.Fatalf("invalid OCO: %v", err)
+	}
+	bot.SetExchangeOCO(oco)
+
+	client.AddOrderFills(entity.Fill{Price: 100.0, Quantity: 2.0, Commission: 0.002, CommissionAsset: "SOL"})
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+	return ctx, bot, tradeRepo
+}
+
+func TestLiveTradingExecutionContext_PlacesOCOAfterBuy(t *testing.T) {
+	client := external.NewFakeExchange()
+	_, bot, _ := newOCOTestBot(t, client)
+
+	placed := client.GetPlacedOCOs()
+	if len(placed) != 1 {
+		t.Fatalf("expected one OCO after the buy, got %+v", placed)
+	}
+	oco := placed[0]
+	// Take profit 5% above the entry, stop 2% below it and its limit 0.5% under the stop, on the 0.1 tick
+	if oco.Side != entity.OrderSideSell || oco.Quantity != "1.99" || oco.Price != "105.0" || oco.StopPrice != "98.0" || oco.StopLimitPrice != "97.5" {
+		t.Errorf("unexpected OCO order %+v", oco)
+	}
+
+	active := bot.GetActiveOCO()
+	if active.OrderListID != oco.OrderListID || active.TakeProfitOrderID != oco.TakeProfitOrderID || active.StopLossOrderID != oco.StopLossOrderID {
+		t.Errorf("expected the bot to track OCO %+v, got %+v", oco, active)
+	}
+}
+
+func TestLiveTradingExecutionContext_ReconcilesOCOFilledWhileDown(t *testing.T) {
+	client := external.NewFakeExchange()
+	ctx, bot, tradeRepo := newOCOTestBot(t, client)
+
+	// Nothing to reconcile while both legs rest on the book
+	if err := ctx.ReconcilePosition(bot, time.Now()); err != nil || !bot.GetIsPositioned() {
+		t.Fatalf("expected the position to stay open, err: %v", err)
+	}
+
+	client.TriggerOCO(bot.GetActiveOCO().OrderListID, true)
+	if err := ctx.ReconcilePosition(bot, time.Now()); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	if bot.GetIsPositioned() || bot.GetActiveOCO().IsActive() || bot.GetActualQuantityHeld() != 0 {
+		t.Fatal("expected the take profit fill to close the position and clear the OCO")
+	}
+	trades, total, _ := tradeRepo.GetTradesWithFilters(appRepository.TradeFilter{TradingBotId: bot.Id.GetValue()})
+	if total != 1 {
+		t.Fatalf("expected the OCO exit to be recorded as a trade, got %d", total)
+	}
+	assertAlmostEqual(t, "exit price", 105.0, trades[0].GetExitPrice())
+	// (105 - 100) * 1.99 - 0.2 buy fees - 0.1% sell fees
+	assertAlmostEqual(t, "profit_loss", 5*1.99-0.2-1.99*105*0.001, trades[0].GetProfitLoss())
+}
+
+func TestLiveTradingExecutionContext_StrategySellCancelsOCO(t *testing.T) {
+	client := external.NewFakeExchange()
+	ctx, bot, _ := newOCOTestBot(t, client)
+	orderListID := bot.GetActiveOCO().OrderListID
+
+	client.AddOrderFills(entity.Fill{Price: 101.0, Quantity: 1.99, Commission: 0.20099, CommissionAsset: "BRL"})
+	if err := ctx.ExecuteTrade(entity.Sell, bot, 101.0, time.Now()); err != nil {
+		t.Fatalf("sell failed: %v", err)
+	}
+
+	if canceled := client.GetCanceledOCOIDs(); len(canceled) != 1 || canceled[0] != orderListID {
+		t.Errorf("expected OCO %d to be canceled before selling, got %v", orderListID, canceled)
+	}
+	if placed := client.GetPlacedOrders(); len(placed) != 2 || placed[1].Side != entity.OrderSideSell {
+		t.Errorf("expected the strategy sell to reach the exchange, got %+v", placed)
+	}
+	if bot.GetIsPositioned() || bot.GetActiveOCO().IsActive() {
+		t.Error("expected the position to be closed without an OCO")
+	}
+}
+
+func TestLiveTradingExecutionContext_StrategySellAfterOCOStopLossFilled(t *testing.T) {
+	client := external.NewFakeExchange()
+	ctx, bot, tradeRepo := newOCOTestBot(t, client)
+
+	client.TriggerOCO(bot.GetActiveOCO().OrderListID, false)
+	if err := ctx.ExecuteTrade(entity.Sell, bot, 97.0, time.Now()); err != nil {
+		t.Fatalf("sell failed: %v", err)
+	}
+
+	if placed := client.GetPlacedOrders(); len(placed) != 1 {
+		t.Errorf("expected no sell order once the stop loss closed the position, got %+v", placed)
+	}
+	if bot.GetIsPositioned() {
+		t.Fatal("expected the stop loss fill to close the position")
+	}
+	trades, _, _ := tradeRepo.GetTradesWithFilters(appRepository.TradeFilter{TradingBotId: bot.Id.GetValue()})
+	if len(trades) != 1 {
+		t.Fatalf("expected the stop loss exit to be recorded as a trade, got %d", len(trades))
+	}
+	assertAlmostEqual(t, "exit price", 97.5, trades[0].GetExitPrice())
+}
+
+func TestLiveTradingExecutionContext_RejectedSellRestoresOCO(t *testing.T) {
+	client := external.NewFakeExchange()
+	ctx, bot, _ := newOCOTestBot(t, client)
+	orderListID := bot.GetActiveOCO().OrderListID
+
+	client.AddOrderFailures(external.FakeOrderFailure{Err: &exchange.RejectedError{Code: -2010, Message: "Account has insufficient balance for requested action."}})
+	if err := ctx.ExecuteTrade(entity.Sell, bot, 101.0, time.Now()); err != nil {
+		t.Fatalf("expected the rejected sell to be recorded without error, got %v", err)
+	}
+
+	if canceled := client.GetCanceledOCOIDs(); len(canceled) != 1 || canceled[0] != orderListID {
+		t.Fatalf("expected OCO %d to be canceled before selling, got %v", orderListID, canceled)
+	}
+	if !bot.GetIsPositioned() {
+		t.Fatal("expected the position to stay open after the rejected sell")
+	}
+	placed := client.GetPlacedOCOs()
+	if len(placed) != 2 || placed[1].Quantity != placed[0].Quantity || placed[1].StopPrice != placed[0].StopPrice {
+		t.Fatalf("expected the OCO to be placed again over the position, got %+v", placed)
+	}
+	if active := bot.GetActiveOCO(); active.OrderListID != placed[1].OrderListID {
+		t.Errorf("expected the bot to track the restored OCO %d, got %+v", placed[1].OrderListID, active)
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 func TestLiveMarketDataSource_GetMarketData_WithDifferentIntervals(t *testing.T) {
 	// Use fake client for testing
-	client := external.NewBinanceClientFake()
+	client := external.NewFakeExchange()
 	dataSource := NewLiveMarketDataSource(client)
 	
 	testCases := []struct {
@@ -48,7 +48,7 @@ func TestLiveMarketDataSource_GetMarketData_WithDifferentIntervals(t *testing.T)
 }
 
 func TestLiveMarketDataSource_GetCurrentTime(t *testing.T) {
-	client := external.NewBinanceClientFake()
+	client := external.NewFakeExchange()
 	dataSource := NewLiveMarketDataSource(client)
 	
 	currentTime := dataSource.GetCurrentTime()
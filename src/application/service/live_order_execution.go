@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"encoding/hex"
+	"crypgo-machine/src/application/repository"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Retries of order requests whose outcome is unknown, e.g. after a network error or a timeout
+const (
+	DefaultOrderRetries      = 3
+	DefaultOrderRetryBackoff = time.Second // Doubled after every retry
+)
+
+// OrderStatusUnknownError is an order the exchange could neither confirm nor deny having placed after every lookup.
+// It may still be live, so it is looked up again by its client order ID before the bot sends another order.
+type OrderStatusUnknownError struct {
+	ClientOrderID string
+	Attempts      int
+	Err           error
+}
+
+func (e *OrderStatusUnknownError) Error() string {
+	return fmt.Sprintf("order %s status unknown after %d attempts: %v", e.ClientOrderID, e.Attempts, e.Err)
+}
+
+func (e *OrderStatusUnknownError) Unwrap() error {
+	return e.Err
+}
+
+// orderTypeOf returns the exchange order type placed by an execution mode
+func orderTypeOf(execution entity.OrderExecution) exchange.OrderType {
+	switch {
+	case execution.IsMarket():
+		return exchange.OrderTypeMarket
+	case execution.IsPostOnly():
+		return exchange.OrderTypeLimitMaker
+	default:
+		return exchange.OrderTypeLimit
+	}
+}
+
+// exchangeOrdersOf returns how many exchange orders a bot order sent with the execution mode may become: a single
+// market order, or the limit attempts and their market fallback
+func exchangeOrdersOf(execution entity.OrderExecution) int {
+	if execution.IsMarket() {
+		return 1
+	}
+	return execution.MaxAttempts + 1
+}
+
+// clientOrderIDOf derives the client order ID of the order a bot sends for a decision, so the same decision always
+// maps to the same ID. Without a decision log the ID is unique to the call. It fits the exchange's 36 characters
+// with room for sequenceClientOrderID suffixes.
+func clientOrderIDOf(botId, decisionLogId string, side entity.OrderSide) string {
+	if decisionLogId == "" {
+		decisionLogId = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	sum := sha256.Sum256([]byte(botId + ":" + decisionLogId + ":" + string(side)))
+	return "cg" + hex.EncodeToString(sum[:])[:24]
+}
+
+// sequenceClientOrderID returns the client order ID of the attempt-th exchange order sent for one bot order, e.g.
+// repriced limit orders. The first one uses the bot order's ID.
+func sequenceClientOrderID(clientOrderID string, attempt int) string {
+	if attempt <= 1 {
+		return clientOrderID
+	}
+	return fmt.Sprintf("%s-%d", clientOrderID, attempt)
+}
+
+// executeOrder places a validated order according to the execution mode, usually the bot's, and returns what was filled
+func (ctx *LiveTradingExecutionContext) executeOrder(bot *entity.TradingBot, execution entity.OrderExecution, clientOrderID string, side entity.OrderSide, quantity float64, formattedQty string, price float64) (entity.OrderFill, error) {
+	if execution.IsMarket() {
+		response, err := ctx.placeMarketOrder(bot.GetSymbol().GetValue(), clientOrderID, side, formattedQty)
+		if err != nil {
+			return entity.OrderFill{}, err
+		}
+		return ctx.orderFillOf(response, bot, price), nil
+	}
+	return ctx.executeLimitOrder(bot, clientOrderID, side, quantity, price)
+}
+
+func (ctx *LiveTradingExecutionContext) placeMarketOrder(symbol, clientOrderID string, side entity.OrderSide, formattedQty string) (*exchange.Order, error) {
+	return ctx.submitOrder(exchange.OrderRequest{
+		Symbol:        symbol,
+		ClientOrderID: clientOrderID,
+		Side:          side,
+		Type:          exchange.OrderTypeMarket,
+		Quantity:      formattedQty,
+	})
+}
+
+// submitOrder sends the order with its client order ID. When the request fails without telling whether the
+// exchange got it, the order is looked up by its client order ID after a backoff: an order found is returned as
+// placed, and only an order the exchange does not know is sent again, so a decision is never executed twice. Up to
+// orderRetries lookups are made, doubling the backoff every time, before giving up with an *OrderStatusUnknownError.
+func (ctx *LiveTradingExecutionContext) submitOrder(request exchange.OrderRequest) (*exchange.Order, error) {
+	symbol, clientOrderID := request.Symbol, request.ClientOrderID
+	response, err := ctx.exchange.PlaceOrder(context.Background(), request)
+	backoff := ctx.orderRetryBackoff
+	for attempt := 1; err != nil && isTransientOrderError(err); attempt++ {
+		if attempt > ctx.orderRetries {
+			return nil, &OrderStatusUnknownError{ClientOrderID: clientOrderID, Attempts: ctx.orderRetries, Err: err}
+		}
+		fmt.Printf("🔁 [%s] Order %s failed (%v), looking it up in %s (%d/%d)\n", symbol, clientOrderID, err, backoff, attempt, ctx.orderRetries)
+		ctx.sleep(backoff)
+		backoff *= 2
+
+		order, errLookup := ctx.exchange.GetOrderByClientID(context.Background(), symbol, clientOrderID)
+		switch {
+		case errLookup == nil:
+			fmt.Printf("🔎 [%s] Order %s reached the exchange: OrderID=%d, %s\n", symbol, clientOrderID, order.OrderID, order.Status)
+			return order, nil
+		case isUnknownOrderError(errLookup):
+			// The request never reached the exchange, it is safe to send it again
+			response, err = ctx.exchange.PlaceOrder(context.Background(), request)
+		default:
+			// Still unknown, look it up again rather than risk a duplicate
+			err = errLookup
+		}
+	}
+	return response, err
+}
+
+// isTransientOrderError reports whether an order request failed without the exchange refusing it, so the order
+// may or may not have been placed: network errors, timeouts and the exchange's internal or rate limit errors
+func isTransientOrderError(err error) bool {
+	return !exchange.IsRejected(err)
+}
+
+// isOrderStatusUnknown reports whether an order request gave up without knowing whether the order was placed
+func isOrderStatusUnknown(err error) bool {
+	var unknown *OrderStatusUnknownError
+	return errors.As(err, &unknown)
+}
+
+// isUnknownOrderError reports whether the exchange answered that the order does not exist
+func isUnknownOrderError(err error) bool {
+	return errors.Is(err, exchange.ErrOrderNotFound)
+}
+
+// executeLimitOrder places limit (or post-only) orders at the best bid for buys and the best ask for sells. An
+// order still resting on the book after RepriceAfterSeconds is canceled and the rest placed again at the new best
+// price. Whatever is left after MaxAttempts orders, or when there is no book price, is sent as a market order. An
+// order whose status is unknown stops the attempts: it may still fill, so nothing else is sent until it is looked up.
+func (ctx *LiveTradingExecutionContext) executeLimitOrder(bot *entity.TradingBot, clientOrderID string, side entity.OrderSide, quantity, price float64) (entity.OrderFill, error) {
+	symbol := bot.GetSymbol().GetValue()
+	execution := bot.GetOrderExecution()
+	orderType := orderTypeOf(execution)
+
+	var fills []entity.OrderFill
+	var lastErr error
+	remaining := quantity
+
+	for attempt := 1; attempt <= execution.MaxAttempts; attempt++ {
+		formattedQty, tradable := ctx.tradableQuantity(symbol, remaining, price)
+		if !tradable {
+			break
+		}
+
+		limitPrice, formattedPrice, err := ctx.bestLimitPrice(symbol, side)
+		if err != nil {
+			fmt.Printf("⚠️ [%s] No book price for the %s limit order: %v\n", symbol, side, err)
+			lastErr = err
+			break
+		}
+
+		request := exchange.OrderRequest{
+			Symbol:        symbol,
+			ClientOrderID: sequenceClientOrderID(clientOrderID, attempt),
+			Side:          side,
+			Type:          orderType,
+			Quantity:      formattedQty,
+			Price:         formattedPrice,
+		}
+		if !execution.IsPostOnly() {
+			request.TimeInForce = exchange.TimeInForce(execution.TimeInForce)
+		}
+		response, err := ctx.submitOrder(request)
+		if isOrderStatusUnknown(err) {
+			return entity.OrderFill{}, err
+		}
+		if err != nil {
+			// Post-only orders that would take liquidity are refused, try again at the next best price
+			fmt.Printf("⚠️ [%s] %s order %d/%d at %s refused: %v\n", symbol, orderType, attempt, execution.MaxAttempts, formattedPrice, err)
+			lastErr = err
+			continue
+		}
+		fmt.Printf("📝 [%s] %s %s order %d/%d placed: OrderID=%d, Qty=%s @ %s\n",
+			symbol, side, orderType, attempt, execution.MaxAttempts, response.OrderID, formattedQty, formattedPrice)
+
+		fill := ctx.settleLimitOrder(bot, response, limitPrice)
+		if fill.ExecutedQuantity > 0 {
+			fills = append(fills, fill)
+			remaining -= fill.ExecutedQuantity
+		}
+		if fill.Status == string(exchange.OrderStatusFilled) {
+			remaining = 0
+			break
+		}
+	}
+
+	// Send the rest at market once the limit attempts are exhausted
+	if formattedQty, tradable := ctx.tradableQuantity(symbol, remaining, price); tradable {
+		fmt.Printf("⏩ [%s] Sending the remaining %s at market after the limit attempts\n", symbol, formattedQty)
+		response, err := ctx.placeMarketOrder(symbol, sequenceClientOrderID(clientOrderID, execution.MaxAttempts+1), side, formattedQty)
+		if isOrderStatusUnknown(err) {
+			return entity.OrderFill{}, err
+		}
+		if err != nil {
+			lastErr = err
+		} else {
+			fill := ctx.orderFillOf(response, bot, price)
+			if fill.ExecutedQuantity > 0 {
+				fills = append(fills, fill)
+				remaining -= fill.ExecutedQuantity
+			}
+		}
+	}
+
+	if len(fills) == 0 {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no quantity of the %s limit order was filled", side)
+		}
+		return entity.OrderFill{}, lastErr
+	}
+
+	fill := MergeOrderFills(fills)
+	fill.Status = string(exchange.OrderStatusFilled)
+	if _, tradable := ctx.tradableQuantity(symbol, remaining, price); tradable {
+		fill.Status = string(exchange.OrderStatusPartiallyFilled)
+	}
+	return fill, nil
+}
+
+// resolveUnknownOrders looks up the bot's orders whose status is unknown by their client order IDs and records what
+// they executed: a buy opens the position and a sell closes it. It reports whether an order was resolved, and fails
+// while the exchange still cannot tell, so the bot sends no other order in the meantime.
+func (ctx *LiveTradingExecutionContext) resolveUnknownOrders(bot *entity.TradingBot, timestamp time.Time) (bool, error) {
+	if ctx.orderRepository == nil {
+		return false, nil
+	}
+	orders, _, err := ctx.orderRepository.GetOrdersWithFilters(repository.OrderFilter{
+		TradingBotId: bot.Id.GetValue(),
+		Status:       string(entity.OrderStatusUnknown),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list the orders of unknown status: %v", err)
+	}
+
+	// Oldest first, the way they were sent
+	for i := len(orders) - 1; i >= 0; i-- {
+		order := orders[i]
+		fill, err := ctx.lookUpOrder(bot, order)
+		if err != nil {
+			return false, fmt.Errorf("order %s status still unknown, not sending new orders: %v", order.GetClientOrderId(), err)
+		}
+		if fill.ExecutedQuantity > 0 {
+			order.MarkFilled(fill)
+		} else {
+			order.MarkFailed(errors.New("not executed on the exchange"))
+		}
+		fmt.Printf("🔎 [%s] %s order %s of unknown status resolved: %s, filled %.8f\n",
+			order.GetSymbol(), order.GetSide(), order.GetClientOrderId(), order.GetStatus(), fill.ExecutedQuantity)
+		if err := ctx.orderRepository.Update(order); err != nil {
+			fmt.Printf("⚠️ Failed to update order %s in the ledger: %v\n", order.GetId().GetValue(), err)
+		}
+		if err := ctx.applyResolvedOrder(bot, order, timestamp); err != nil {
+			return true, err
+		}
+	}
+	return len(orders) > 0, nil
+}
+
+// lookUpOrder returns what the exchange executed of a bot order: the order sent with its client order ID and, for
+// limit orders, its repriced attempts and market fallback. Orders still on the book are canceled first.
+func (ctx *LiveTradingExecutionContext) lookUpOrder(bot *entity.TradingBot, order *entity.Order) (entity.OrderFill, error) {
+	symbol := order.GetSymbol()
+	attempts := order.GetMaxAttempts()
+	if attempts == 0 {
+		// Recorded without its attempts, assume those of the bot's order execution
+		attempts = 1
+		if order.GetOrderType() != string(exchange.OrderTypeMarket) {
+			attempts = bot.GetOrderExecution().MaxAttempts + 1
+		}
+	}
+
+	var fills []entity.OrderFill
+	for attempt := 1; attempt <= attempts; attempt++ {
+		placed, err := ctx.exchange.GetOrderByClientID(context.Background(), symbol, sequenceClientOrderID(order.GetClientOrderId(), attempt))
+		if isUnknownOrderError(err) {
+			continue
+		}
+		if err != nil {
+			return entity.OrderFill{}, err
+		}
+		if placed.Status == exchange.OrderStatusNew || placed.Status == exchange.OrderStatusPartiallyFilled {
+			if placed, err = ctx.exchange.CancelOrder(context.Background(), symbol, placed.OrderID); err != nil {
+				return entity.OrderFill{}, err
+			}
+		}
+		if placed.ExecutedQuantity > 0 {
+			fills = append(fills, ctx.executedFillOf(bot, placed))
+		}
+	}
+	if len(fills) == 0 {
+		return entity.OrderFill{}, nil
+	}
+
+	fill := MergeOrderFills(fills)
+	fill.Status = string(exchange.OrderStatusFilled)
+	if _, tradable := ctx.tradableQuantity(symbol, order.GetRequestedQuantity()-fill.ExecutedQuantity, fill.AveragePrice); tradable {
+		fill.Status = string(exchange.OrderStatusPartiallyFilled)
+	}
+	return fill, nil
+}
+
+// applyResolvedOrder updates the bot with a resolved order: an executed buy opens the position, an executed sell
+// closes it, a buy that did not execute gives its capital back and a sell that did not execute protects the position
+// with its OCO again
+func (ctx *LiveTradingExecutionContext) applyResolvedOrder(bot *entity.TradingBot, order *entity.Order, timestamp time.Time) error {
+	switch {
+	case order.GetSide() == entity.OrderSideBuy && order.IsExecuted() && !bot.GetIsPositioned():
+		return ctx.openPosition(bot, order, timestamp)
+	case order.GetSide() == entity.OrderSideBuy && !order.IsExecuted():
+		ctx.releaseCapital(bot)
+	case order.GetSide() == entity.OrderSideSell && order.IsExecuted() && bot.GetIsPositioned():
+		return ctx.closePosition(bot, order, timestamp)
+	case order.GetSide() == entity.OrderSideSell && !order.IsExecuted():
+		ctx.restoreProtectiveOCO(bot)
+	}
+	return nil
+}
+
+// settleLimitOrder waits for an order resting on the book to fill, canceling it after the reprice interval,
+// and returns what it filled
+func (ctx *LiveTradingExecutionContext) settleLimitOrder(bot *entity.TradingBot, response *exchange.Order, limitPrice float64) entity.OrderFill {
+	symbol := bot.GetSymbol().GetValue()
+	execution := bot.GetOrderExecution()
+
+	if response.Status == exchange.OrderStatusFilled || !execution.RestsOnBook() {
+		if len(response.Fills) > 0 {
+			return ctx.orderFillOf(response, bot, limitPrice)
+		}
+		return ctx.executedFillOf(bot, response)
+	}
+
+	ctx.sleep(time.Duration(execution.RepriceAfterSeconds) * time.Second)
+
+	order, err := ctx.exchange.GetOrder(context.Background(), symbol, response.OrderID)
+	if err == nil && order.Status == exchange.OrderStatusFilled {
+		return ctx.executedFillOf(bot, order)
+	}
+
+	canceled, err := ctx.exchange.CancelOrder(context.Background(), symbol, response.OrderID)
+	if err != nil {
+		// The order may have filled in the meantime, ask the exchange what it executed
+		order, errGet := ctx.exchange.GetOrder(context.Background(), symbol, response.OrderID)
+		if errGet != nil {
+			fmt.Printf("⚠️ [%s] Could not cancel or query order %d: %v / %v\n", symbol, response.OrderID, err, errGet)
+			return entity.OrderFill{}
+		}
+		return ctx.executedFillOf(bot, order)
+	}
+
+	fmt.Printf("↩️ [%s] Order %d canceled after %ds with %.8f filled, repricing the rest\n",
+		symbol, canceled.OrderID, execution.RepriceAfterSeconds, canceled.ExecutedQuantity)
+	return ctx.executedFillOf(bot, canceled)
+}
+
+// executedFillOf estimates the fill of an order from its executed totals, with the bot's fee percentage
+func (ctx *LiveTradingExecutionContext) executedFillOf(bot *entity.TradingBot, order *exchange.Order) entity.OrderFill {
+	return EstimateExecutedFill(order.OrderID, order.Side, order.Status, order.ExecutedQuantity, order.QuoteQuantity, bot.GetTradingFees())
+}
+
+// bestLimitPrice returns the best bid for buys and the best ask for sells, rounded to the symbol's tick size
+func (ctx *LiveTradingExecutionContext) bestLimitPrice(symbol string, side entity.OrderSide) (float64, string, error) {
+	ticker, err := ctx.exchange.GetBookTicker(context.Background(), symbol)
+	if err != nil {
+		return 0, "", err
+	}
+
+	price := ticker.BidPrice
+	if side == entity.OrderSideSell {
+		price = ticker.AskPrice
+	}
+	if price <= 0 {
+		return 0, "", fmt.Errorf("invalid book price %v for %s", price, symbol)
+	}
+
+	return ctx.orderValidator.AdjustLimitPrice(symbol, price, side == entity.OrderSideBuy)
+}
+
+// tradableQuantity formats quantity for the exchange and reports whether it is still above the symbol minimums
+func (ctx *LiveTradingExecutionContext) tradableQuantity(symbol string, quantity, price float64) (string, bool) {
+	if quantity <= 0 {
+		return "", false
+	}
+	result, err := ctx.orderValidator.ValidateOrder(symbol, quantity, price)
+	if err != nil || !result.IsValid || result.AdjustedQuantity > quantity {
+		return "", false
+	}
+	return result.FormattedQuantity, true
+}
@@ -0,0 +1,400 @@
+package service
+
+import (
+	appRepository "crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/external"
+	"crypgo-machine/src/infra/repository"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+)
+
+// newLimitOrderTestContext returns a context whose resting orders are settled without waiting, recording the waits
+func newLimitOrderTestContext(client *external.FakeExchange) (*LiveTradingExecutionContext, *[]time.Duration) {
+	ctx := NewLiveTradingExecutionContext(client, &MockTradeBotRepository{}, &MockTradingDecisionLogRepository{}, &MockMessageBroker{}, "test_exchange")
+	waits := &[]time.Duration{}
+	ctx.sleep = func(d time.Duration) { *waits = append(*waits, d) }
+	return ctx, waits
+}
+
+func newLimitOrderTestBot(t *testing.T, mode, timeInForce string, maxAttempts int) *entity.TradingBot {
+	symbol, _ := vo.NewSymbol("SOLBRL")
+	bot := entity.NewTradingBot(symbol, 2.0, entity.NewMovingAverageStrategy(5, 20), 60, 1000, 300, "BRL", 0.1, 2.0, true)
+	execution, err := entity.NewOrderExecution(mode, timeInForce, 15, maxAttempts)
+	if err != nil {
+		t.Fatalf("invalid order execution: %v", err)
+	}
+	bot.SetOrderExecution(execution)
+	return bot
+}
+
+func TestLiveTradingExecutionContext_PostOnlyBuyFilledOnTheBook(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBookTicker("SOLBRL", 99.5, 99.7)
+	client.AddLimitFillRatios(1.0)
+	ctx, waits := newLimitOrderTestContext(client)
+	bot := newLimitOrderTestBot(t, entity.ExecutionModePostOnly, "", 3)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+
+	placed := client.GetPlacedOrders()
+	if len(placed) != 1 || placed[0].Type != exchange.OrderTypeLimitMaker || placed[0].Price != "99.5" || placed[0].TimeInForce != "" {
+		t.Fatalf("expected one post-only order at the best bid, got %+v", placed)
+	}
+	if len(client.GetCanceledOrderIDs()) != 0 {
+		t.Error("expected the filled order not to be canceled")
+	}
+	if len(*waits) != 1 || (*waits)[0] != 15*time.Second {
+		t.Errorf("expected to wait the reprice interval once, got %v", *waits)
+	}
+	assertAlmostEqual(t, "entry price", 99.5, bot.GetEntryPrice())
+	assertAlmostEqual(t, "quantity held", 2.0*(1-0.001), bot.GetActualQuantityHeld())
+	assertAlmostEqual(t, "entry fees", 2.0*99.5*0.001, bot.GetEntryFees())
+}
+
+func TestLiveTradingExecutionContext_LimitSellRepricesThenFallsBackToMarket(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBookTicker("SOLBRL", 109.8, 110.0)
+	// Half of the first order fills, the repriced one does not fill at all
+	client.AddLimitFillRatios(0.5, 0)
+	client.AddOrderFills(entity.Fill{Price: 109.5, Quantity: 1.0, Commission: 0.1095, CommissionAsset: "BRL"})
+	ctx, _ := newLimitOrderTestContext(client)
+
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeLimit, "", 2)
+	bot.RecordEntryFill(entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 2.0, NetQuantity: 2.0})
+	bot.GetIntoPosition()
+
+	if err := ctx.ExecuteTrade(entity.Sell, bot, 110.0, time.Now()); err != nil {
+		t.Fatalf("sell failed: %v", err)
+	}
+
+	placed := client.GetPlacedOrders()
+	if len(placed) != 3 {
+		t.Fatalf("expected two limit orders and a market fallback, got %+v", placed)
+	}
+	if placed[0].Type != exchange.OrderTypeLimit || placed[0].Price != "110.0" || placed[0].Quantity != "2.00" || placed[0].TimeInForce != exchange.TimeInForceGTC {
+		t.Errorf("expected a GTC limit sell of 2.00 at the best ask, got %+v", placed[0])
+	}
+	if placed[1].Type != exchange.OrderTypeLimit || placed[1].Quantity != "1.00" {
+		t.Errorf("expected the unfilled half to be repriced, got %+v", placed[1])
+	}
+	if placed[2].Type != exchange.OrderTypeMarket || placed[2].Quantity != "1.00" {
+		t.Errorf("expected the rest to be sent at market, got %+v", placed[2])
+	}
+	if canceled := client.GetCanceledOrderIDs(); len(canceled) != 2 || canceled[0] != placed[0].OrderID || canceled[1] != placed[1].OrderID {
+		t.Errorf("expected both limit orders to be canceled, got %v", canceled)
+	}
+	if bot.GetIsPositioned() {
+		t.Error("expected the position to be closed")
+	}
+}
+
+func TestLiveTradingExecutionContext_LimitOrderWithoutBookPriceUsesMarket(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.AddOrderFills(entity.Fill{Price: 100.0, Quantity: 2.0, Commission: 0.002, CommissionAsset: "SOL"})
+	ctx, _ := newLimitOrderTestContext(client)
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeLimit, entity.TimeInForceIOC, 3)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+
+	placed := client.GetPlacedOrders()
+	if len(placed) != 1 || placed[0].Type != exchange.OrderTypeMarket {
+		t.Fatalf("expected a market order when there is no book price, got %+v", placed)
+	}
+	assertAlmostEqual(t, "quantity held", 1.998, bot.GetActualQuantityHeld())
+}
+
+func TestLiveTradingExecutionContext_IOCLimitBuyPartiallyFilledIsRepriced(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBookTicker("SOLBRL", 99.5, 99.7)
+	client.AddLimitFillRatios(0.5, 1.0)
+	ctx, waits := newLimitOrderTestContext(client)
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeLimit, entity.TimeInForceIOC, 3)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+
+	placed := client.GetPlacedOrders()
+	if len(placed) != 2 || placed[0].TimeInForce != exchange.TimeInForceIOC || placed[1].Quantity != "1.00" {
+		t.Fatalf("expected an IOC order and one for the expired rest, got %+v", placed)
+	}
+	if len(*waits) != 0 || len(client.GetCanceledOrderIDs()) != 0 {
+		t.Error("expected IOC orders not to wait on the book nor be canceled")
+	}
+	if !bot.GetIsPositioned() {
+		t.Fatal("expected the position to be opened")
+	}
+	assertAlmostEqual(t, "entry price", 99.5, bot.GetEntryPrice())
+}
+
+// decideFor saves a decision of the bot so its orders are sent with the decision's client order ID
+func decideFor(t *testing.T, ctx *LiveTradingExecutionContext, bot *entity.TradingBot, decision entity.TradingDecision) string {
+	decisionLog := entity.NewTradingDecisionLog(bot.Id, decision, "MovingAverage", map[string]interface{}{}, nil, 100.0, 0)
+	if err := ctx.OnDecisionMade(decisionLog); err != nil {
+		t.Fatalf("failed to save decision: %v", err)
+	}
+	return decisionLog.GetId().GetValue()
+}
+
+func TestLiveTradingExecutionContext_RecoversOrderPlacedDespiteTimeout(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.AddOrderFills(entity.Fill{Price: 100.0, Quantity: 2.0, Commission: 0.002, CommissionAsset: "SOL"})
+	client.AddOrderFailures(external.FakeOrderFailure{Err: errors.New("context deadline exceeded"), Placed: true})
+	ctx, waits := newLimitOrderTestContext(client)
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeMarket, "", 0)
+	decisionLogId := decideFor(t, ctx, bot, entity.Buy)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+
+	placed := client.GetPlacedOrders()
+	if len(placed) != 1 || placed[0].ClientOrderID != clientOrderIDOf(bot.Id.GetValue(), decisionLogId, entity.OrderSideBuy) {
+		t.Fatalf("expected the timed out order to be found instead of sent again, got %+v", placed)
+	}
+	if len(*waits) != 1 || (*waits)[0] != DefaultOrderRetryBackoff {
+		t.Errorf("expected one backoff before the lookup, got %v", *waits)
+	}
+	if !bot.GetIsPositioned() {
+		t.Fatal("expected the order found on the exchange to open the position")
+	}
+	assertAlmostEqual(t, "entry price", 100.0, bot.GetEntryPrice())
+	assertAlmostEqual(t, "quantity held", 2.0*(1-0.001), bot.GetActualQuantityHeld())
+}
+
+func TestLiveTradingExecutionContext_ResendsOrderThatNeverReachedTheExchange(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.AddOrderFailures(external.FakeOrderFailure{Err: errors.New("connection reset by peer")})
+	ctx, waits := newLimitOrderTestContext(client)
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeMarket, "", 0)
+	decideFor(t, ctx, bot, entity.Buy)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+	if placed := client.GetPlacedOrders(); len(placed) != 1 || !bot.GetIsPositioned() {
+		t.Fatalf("expected the order to be sent again once, got %+v", placed)
+	}
+	if len(*waits) != 1 {
+		t.Errorf("expected one backoff before sending again, got %v", *waits)
+	}
+}
+
+func TestLiveTradingExecutionContext_GivesUpWhenOrderKeepsFailing(t *testing.T) {
+	client := external.NewFakeExchange()
+	networkError := external.FakeOrderFailure{Err: errors.New("i/o timeout")}
+	client.AddOrderFailures(networkError, networkError, networkError, networkError)
+	ctx, waits := newLimitOrderTestContext(client)
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeMarket, "", 0)
+	decideFor(t, ctx, bot, entity.Buy)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("expected the failed order to be recorded without error, got %v", err)
+	}
+	if bot.GetIsPositioned() || len(client.GetPlacedOrders()) != 0 {
+		t.Fatal("expected no position without an order on the exchange")
+	}
+	expected := []time.Duration{DefaultOrderRetryBackoff, 2 * DefaultOrderRetryBackoff, 4 * DefaultOrderRetryBackoff}
+	if len(*waits) != len(expected) || (*waits)[2] != expected[2] {
+		t.Errorf("expected exponential backoff %v, got %v", expected, *waits)
+	}
+
+	// Orders refused by the exchange are not retried
+	refused := external.NewFakeExchange()
+	refused.SetShouldFailOrder(true)
+	ctx, waits = newLimitOrderTestContext(refused)
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(*waits) != 0 {
+		t.Errorf("expected a refused order not to be retried, got %v", *waits)
+	}
+}
+
+func TestLiveTradingExecutionContext_DoesNotExecuteDecisionTwice(t *testing.T) {
+	client := external.NewFakeExchange()
+	ctx, _ := newLimitOrderTestContext(client)
+	ctx.WithLedger(repository.NewOrderRepositoryInMemory(), repository.NewTradeRepositoryInMemory())
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeMarket, "", 0)
+	decideFor(t, ctx, bot, entity.Buy)
+
+	client.AddOrderFills(entity.Fill{Price: 100.0, Quantity: 2.0, Commission: 0.002, CommissionAsset: "SOL"})
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+
+	// The bot lost track of its position, e.g. its update failed, and handles the same decision again
+	_ = bot.ClosePosition()
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 101.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+	if placed := client.GetPlacedOrders(); len(placed) != 1 {
+		t.Fatalf("expected the decision to be sent to the exchange once, got %+v", placed)
+	}
+	if !bot.GetIsPositioned() || bot.GetEntryPrice() != 100.0 {
+		t.Errorf("expected the position of the first order, got entry %.2f", bot.GetEntryPrice())
+	}
+}
+
+func TestLiveTradingExecutionContext_LiquidatesAtMarketWhateverTheExecutionMode(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBookTicker("SOLBRL", 94.8, 95.0)
+	client.AddOrderFills(entity.Fill{Price: 94.7, Quantity: 2.0, Commission: 0.1894, CommissionAsset: "BRL"})
+	ctx, waits := newLimitOrderTestContext(client)
+	tradeRepo := repository.NewTradeRepositoryInMemory()
+	ctx.WithLedger(repository.NewOrderRepositoryInMemory(), tradeRepo)
+
+	bot := newLimitOrderTestBot(t, entity.ExecutionModePostOnly, "", 3)
+	bot.RecordEntryFill(entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 2.0, NetQuantity: 2.0})
+	bot.GetIntoPosition()
+
+	if err := ctx.LiquidatePosition(bot, 95.0, time.Now()); err != nil {
+		t.Fatalf("liquidation failed: %v", err)
+	}
+
+	placed := client.GetPlacedOrders()
+	if len(placed) != 1 || placed[0].Type != exchange.OrderTypeMarket || placed[0].Side != entity.OrderSideSell || placed[0].Quantity != "2.00" {
+		t.Fatalf("expected a single market sell of the position, got %+v", placed)
+	}
+	if len(*waits) != 0 {
+		t.Errorf("expected the liquidation not to wait on the book, got %v", *waits)
+	}
+	if bot.GetIsPositioned() || bot.GetCooldownState().LastExitReason != entity.ExitReasonLiquidated {
+		t.Errorf("expected the position to be closed by the liquidation, got reason %q", bot.GetCooldownState().LastExitReason)
+	}
+	if _, total, _ := tradeRepo.GetTradesWithFilters(appRepository.TradeFilter{TradingBotId: bot.Id.GetValue()}); total != 1 {
+		t.Errorf("expected the liquidation to be recorded as a trade, got %d", total)
+	}
+
+	if err := ctx.LiquidatePosition(bot, 95.0, time.Now()); err == nil {
+		t.Error("expected a bot without position not to be liquidated")
+	}
+}
+
+// unknownOrderFailures makes an order request give up without knowing the outcome although the order was placed:
+// the exchange does not know the order at each lookup, then places the last retry without answering
+func unknownOrderFailures() []external.FakeOrderFailure {
+	networkError := external.FakeOrderFailure{Err: errors.New("i/o timeout")}
+	return []external.FakeOrderFailure{networkError, networkError, networkError, {Err: errors.New("i/o timeout"), Placed: true}}
+}
+
+func TestLiveTradingExecutionContext_ResolvesBuyOfUnknownStatusBeforeTheNextOrder(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBalance("BRL", 1000)
+	client.AddOrderFailures(unknownOrderFailures()...)
+	client.AddOrderFills(entity.Fill{Price: 100.0, Quantity: 2.0, Commission: 0.002, CommissionAsset: "SOL"})
+	ctx, _ := newLimitOrderTestContext(client)
+	orderRepo := repository.NewOrderRepositoryInMemory()
+	ctx.WithLedger(orderRepo, repository.NewTradeRepositoryInMemory())
+	allocator := NewCapitalAllocator(client)
+	ctx.WithCapitalAllocator(allocator)
+	bot := newLimitOrderTestBot(t, entity.ExecutionModeMarket, "", 0)
+	decideFor(t, ctx, bot, entity.Buy)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("expected the unknown order to be recorded without error, got %v", err)
+	}
+	orders, _, _ := orderRepo.GetOrdersWithFilters(appRepository.OrderFilter{TradingBotId: bot.Id.GetValue()})
+	if len(orders) != 1 || orders[0].GetStatus() != entity.OrderStatusUnknown || bot.GetIsPositioned() {
+		t.Fatalf("expected one order of unknown status and no position yet, got %+v", orders)
+	}
+	if _, reserved := allocator.reservations[bot.Id.GetValue()]; !reserved {
+		t.Error("expected the capital of the unknown buy to stay reserved")
+	}
+
+	// The next decision looks the order up instead of buying again
+	decideFor(t, ctx, bot, entity.Buy)
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 101.0, time.Now()); err != nil {
+		t.Fatalf("expected the unknown order to be resolved, got %v", err)
+	}
+	if placed := client.GetPlacedOrders(); len(placed) != 1 {
+		t.Fatalf("expected no second buy, got %+v", placed)
+	}
+	if !bot.GetIsPositioned() || orders[0].GetStatus() != entity.OrderStatusFilled {
+		t.Fatalf("expected the order found on the exchange to open the position, got status %s", orders[0].GetStatus())
+	}
+	assertAlmostEqual(t, "entry price", 100.0, bot.GetEntryPrice())
+	if reservation := allocator.reservations[bot.Id.GetValue()]; !reservation.settled {
+		t.Error("expected the reservation to be settled by the resolved buy")
+	}
+}
+
+func TestLiveTradingExecutionContext_LimitOrderOfUnknownStatusIsNotRepriced(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBookTicker("SOLBRL", 99.5, 99.7)
+	client.AddOrderFailures(unknownOrderFailures()...)
+	client.AddLimitFillRatios(0.5)
+	ctx, _ := newLimitOrderTestContext(client)
+	orderRepo := repository.NewOrderRepositoryInMemory()
+	ctx.WithLedger(orderRepo, repository.NewTradeRepositoryInMemory())
+	bot := newLimitOrderTestBot(t, entity.ExecutionModePostOnly, "", 3)
+	decideFor(t, ctx, bot, entity.Buy)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("expected the unknown order to be recorded without error, got %v", err)
+	}
+	if placed := client.GetPlacedOrders(); len(placed) != 1 || bot.GetIsPositioned() {
+		t.Fatalf("expected neither a repriced order nor a market fallback, got %+v", placed)
+	}
+
+	// Still on the book half filled, the order is canceled and its fill taken as the position
+	if err := ctx.ExecuteTrade(entity.Hold, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("expected the unknown order to be resolved, got %v", err)
+	}
+	if canceled := client.GetCanceledOrderIDs(); len(canceled) != 1 || len(client.GetPlacedOrders()) != 1 {
+		t.Fatalf("expected the resting order to be canceled and nothing else sent, got %v", canceled)
+	}
+	orders, _, _ := orderRepo.GetOrdersWithFilters(appRepository.OrderFilter{TradingBotId: bot.Id.GetValue()})
+	if !bot.GetIsPositioned() || orders[0].GetStatus() != entity.OrderStatus(exchange.OrderStatusPartiallyFilled) {
+		t.Fatalf("expected the partial fill to open the position, got status %s", orders[0].GetStatus())
+	}
+	assertAlmostEqual(t, "quantity held", 1.0*(1-0.001), bot.GetActualQuantityHeld())
+}
+
+func TestLiveTradingExecutionContext_LooksUpUnknownOrderByTheAttemptsItWasSentWith(t *testing.T) {
+	client := external.NewFakeExchange()
+	client.SetBookTicker("SOLBRL", 99.5, 99.7)
+	wouldTakeLiquidity := external.FakeOrderFailure{Err: &exchange.RejectedError{Code: -2010, Message: "Order would immediately match and take."}}
+	client.AddOrderFailures(append([]external.FakeOrderFailure{wouldTakeLiquidity, wouldTakeLiquidity}, unknownOrderFailures()...)...)
+	client.AddLimitFillRatios(0.5)
+	ctx, _ := newLimitOrderTestContext(client)
+	orderRepo := repository.NewOrderRepositoryInMemory()
+	ctx.WithLedger(orderRepo, repository.NewTradeRepositoryInMemory())
+	bot := newLimitOrderTestBot(t, entity.ExecutionModePostOnly, "", 3)
+	decideFor(t, ctx, bot, entity.Buy)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("expected the unknown order to be recorded without error, got %v", err)
+	}
+	orders, _, _ := orderRepo.GetOrdersWithFilters(appRepository.OrderFilter{TradingBotId: bot.Id.GetValue()})
+	if len(orders) != 1 || orders[0].GetMaxAttempts() != 4 {
+		t.Fatalf("expected one order recorded with its 3 limit attempts and market fallback, got %+v", orders)
+	}
+	if !strings.Contains(orders[0].GetErrorMessage(), "after 3 attempts") {
+		t.Errorf("expected the error to report the 3 lookups, got %q", orders[0].GetErrorMessage())
+	}
+
+	// The bot is reconfigured with fewer attempts while its third attempt rests on the book
+	execution, _ := entity.NewOrderExecution(entity.ExecutionModePostOnly, "", 15, 1)
+	bot.SetOrderExecution(execution)
+
+	if err := ctx.ExecuteTrade(entity.Hold, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("expected the unknown order to be resolved, got %v", err)
+	}
+	if canceled := client.GetCanceledOrderIDs(); len(canceled) != 1 {
+		t.Fatalf("expected the third attempt to be found and canceled, got %v", canceled)
+	}
+	if !bot.GetIsPositioned() || orders[0].GetStatus() != entity.OrderStatus(exchange.OrderStatusPartiallyFilled) {
+		t.Fatalf("expected the partial fill of the third attempt to open the position, got status %s", orders[0].GetStatus())
+	}
+}
@@ -4,94 +4,113 @@ import (
 	"context"
 	"crypgo-machine/src/application/repository"
 	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
 	"crypgo-machine/src/domain/service"
-	"crypgo-machine/src/infra/external"
 	"crypgo-machine/src/infra/queue"
 	"encoding/json"
 	"fmt"
-	"github.com/adshao/go-binance/v2"
+	"strings"
+	"sync"
 	"time"
 )
 
 // LiveTradingExecutionContext implements TradingExecutionContext for real trading
 type LiveTradingExecutionContext struct {
-	client                       external.BinanceClientInterface
+	exchange                     exchange.Exchange
 	tradingBotRepository         repository.TradingBotRepository
 	tradingDecisionLogRepository repository.TradingDecisionLogRepository
 	messageBroker                queue.MessageBroker
 	orderValidator              *service.OrderValidatorService
+	orderRepository              repository.OrderRepository
+	tradeRepository              repository.TradeRepository
 	exchangeName                 string
-	shouldContinue               bool
+	sleep                        func(time.Duration) // Waits for resting limit orders to fill and order retries, replaced in tests
+	orderRetries                 int
+	orderRetryBackoff            time.Duration
+	capitalAllocator             *CapitalAllocator // Checks the capital of buys when set
+
+	decisionLogMu  sync.Mutex
+	decisionLogIds map[string]string // Latest decision log saved per bot, linked to the orders it causes
 }
 
 // NewLiveTradingExecutionContext creates a new LiveTradingExecutionContext
 func NewLiveTradingExecutionContext(
-	client external.BinanceClientInterface,
+	exchangeClient exchange.Exchange,
 	tradingBotRepo repository.TradingBotRepository,
 	decisionLogRepo repository.TradingDecisionLogRepository,
 	messageBroker queue.MessageBroker,
 	exchangeName string,
 ) *LiveTradingExecutionContext {
-	// Create exchange info service and order validator
-	exchangeInfoService := external.NewExchangeInfoService(client)
-	orderValidator := service.NewOrderValidatorService(exchangeInfoService)
+	// Create the order validator on the cached symbol rules of the exchange
+	orderValidator := service.NewOrderValidatorService(exchange.NewSymbolRulesCache(exchangeClient))
 	
 	return &LiveTradingExecutionContext{
-		client:                       client,
+		exchange:                     exchangeClient,
 		tradingBotRepository:         tradingBotRepo,
 		tradingDecisionLogRepository: decisionLogRepo,
 		messageBroker:                messageBroker,
 		orderValidator:              orderValidator,
 		exchangeName:                 exchangeName,
-		shouldContinue:               true,
+		sleep:                        time.Sleep,
+		orderRetries:                 DefaultOrderRetries,
+		orderRetryBackoff:            DefaultOrderRetryBackoff,
+		decisionLogIds:               make(map[string]string),
 	}
 }
 
+// WithLedger records every order placement attempt and every closed trade in the given repositories
+func (ctx *LiveTradingExecutionContext) WithLedger(orderRepo repository.OrderRepository, tradeRepo repository.TradeRepository) *LiveTradingExecutionContext {
+	ctx.orderRepository = orderRepo
+	ctx.tradeRepository = tradeRepo
+	return ctx
+}
+
+// WithOrderRetries sets how many times an order whose request failed on the network is looked up and sent again,
+// waiting backoff before the first retry and doubling it after each one
+func (ctx *LiveTradingExecutionContext) WithOrderRetries(retries int, backoff time.Duration) *LiveTradingExecutionContext {
+	ctx.orderRetries = retries
+	ctx.orderRetryBackoff = backoff
+	return ctx
+}
+
+// WithCapitalAllocator reserves the cost of each buy with allocator, refusing the buys it does not cover
+func (ctx *LiveTradingExecutionContext) WithCapitalAllocator(allocator *CapitalAllocator) *LiveTradingExecutionContext {
+	ctx.capitalAllocator = allocator
+	return ctx
+}
+
 // ExecuteTrade executes real trading orders via Binance API
 func (ctx *LiveTradingExecutionContext) ExecuteTrade(decision entity.TradingDecision, bot *entity.TradingBot, currentPrice float64, timestamp time.Time) error {
 	symbol := bot.GetSymbol().GetValue()
-	
-	// Calculate quantity based on useFixedQuantity flag
-	var quantity float64
-	if bot.GetUseFixedQuantity() {
-		// Use fixed quantity from bot configuration
-		quantity = bot.GetQuantity()
-	} else {
-		// Calculate dynamic quantity based on trade amount and current price
-		quantity = bot.GetTradeAmount() / currentPrice
+
+	// An order of unknown status may still fill, settle it before the bot decides on another one
+	if resolved, err := ctx.resolveUnknownOrders(bot, timestamp); err != nil || resolved {
+		return err
 	}
+	
+	// Calculate quantity based on the bot's sizing mode (fixed quantity, trade amount or ATR risk)
+	quantity := bot.CalculateBuyQuantity(currentPrice)
 
 	switch decision {
 	case entity.Buy:
 		if bot.GetIsPositioned() {
 			return fmt.Errorf("this trading bot already has an open position")
 		}
+		if refused, err := ctx.reserveCapital(bot, quantity*currentPrice, timestamp); err != nil || refused {
+			return err
+		}
 		fmt.Printf("🟢 [%s] BUY order (qty: %.6f, price: %.2f)\n", symbol, quantity, currentPrice)
 
-		isOrderPlaced := ctx.placeBuyOrder(symbol, quantity, currentPrice)
-		if isOrderPlaced {
-			// Set entry price when entering position
-			bot.SetEntryPrice(currentPrice)
-			// Calculate actual quantity held after fees
-			feePercentage := bot.GetTradingFees() / 100.0
-			actualQuantity := quantity * (1.0 - feePercentage)
-			bot.SetActualQuantityHeld(actualQuantity)
-			fmt.Printf("📈 [%s] Position opened at %.2f (actual qty: %.6f after %.2f%% fees)\n", 
-				symbol, currentPrice, actualQuantity, bot.GetTradingFees())
-
-			errPosition := bot.GetIntoPosition()
-			if errPosition != nil {
-				return errPosition
-			}
-			errUpdate := ctx.tradingBotRepository.Update(bot)
-			if errUpdate != nil {
-				return errUpdate
-			}
-
-			// Emit buy event
-			if err := ctx.emitTradingEvent("trading.buy_executed", bot, currentPrice, quantity, 0, 0, timestamp); err != nil {
-				fmt.Printf("⚠️ Failed to emit buy event: %v\n", err)
-			}
+		order := ctx.placeBuyOrder(bot, quantity, currentPrice)
+		switch {
+		case order.IsExecuted():
+			return ctx.openPosition(bot, order, timestamp)
+		case order.IsUnknown():
+			// The buy may still fill, its capital stays reserved until it is looked up
+			fmt.Printf("❓ [%s] Buy order %s status unknown, looking it up before the next order\n", symbol, order.GetClientOrderId())
+		default:
+			// Nothing was spent
+			ctx.releaseCapital(bot)
 		}
 		return nil
 
@@ -100,34 +119,24 @@ func (ctx *LiveTradingExecutionContext) ExecuteTrade(decision entity.TradingDeci
 			return fmt.Errorf("this trading bot don't have an open position")
 		}
 
+		// The OCO holds the quantity on the exchange, cancel it first unless it already closed the position
+		closed, errOCO := ctx.cancelProtectiveOCO(bot, timestamp)
+		if errOCO != nil || closed {
+			return errOCO
+		}
+
 		// Calculate quantity for sell considering fees
 		sellQuantity := bot.CalculateQuantityForSell()
 		actualProfit := ((currentPrice - bot.GetEntryPrice()) / bot.GetEntryPrice()) * 100
 		fmt.Printf("🔴 [%s] SELL order (qty: %.6f, profit: %.2f%%, entry: %.2f, current: %.2f)\n", 
 			symbol, sellQuantity, actualProfit, bot.GetEntryPrice(), currentPrice)
 
-		isOrderPlaced := ctx.placeSellOrder(symbol, sellQuantity, currentPrice)
-		if isOrderPlaced {
-			// Clear entry price and actual quantity when exiting position
-			entryPrice := bot.GetEntryPrice()
-			bot.ClearEntryPrice()
-			bot.ClearActualQuantityHeld()
-			fmt.Printf("📉 [%s] Position closed\n", symbol)
-
-			errPosition := bot.GetOutOfPosition()
-			if errPosition != nil {
-				return errPosition
-			}
-			errUpdate := ctx.tradingBotRepository.Update(bot)
-			if errUpdate != nil {
-				return errUpdate
-			}
-
-			// Emit sell event
-			if err := ctx.emitTradingEvent("trading.sell_executed", bot, currentPrice, sellQuantity, entryPrice, actualProfit, timestamp); err != nil {
-				fmt.Printf("⚠️ Failed to emit sell event: %v\n", err)
-			}
+		order := ctx.placeSellOrder(bot, sellQuantity, currentPrice)
+		if order.IsExecuted() {
+			return ctx.closePosition(bot, order, timestamp)
 		}
+		// Still in position, protect it again until the next sell
+		ctx.restoreProtectiveOCO(bot)
 		return nil
 
 	case entity.Hold:
@@ -148,33 +157,187 @@ func (ctx *LiveTradingExecutionContext) ExecuteTrade(decision entity.TradingDeci
 	return nil
 }
 
+// openPosition takes the bot into the position opened by the executed buy order
+func (ctx *LiveTradingExecutionContext) openPosition(bot *entity.TradingBot, order *entity.Order, timestamp time.Time) error {
+	symbol := bot.GetSymbol().GetValue()
+	fill := order.GetFill()
+	if ctx.capitalAllocator != nil {
+		ctx.capitalAllocator.Settle(bot, fill.QuoteQuantity)
+	}
+
+	// Entry price, quantity held and fees come from what the exchange actually filled
+	if err := bot.OpenPosition(fill, timestamp); err != nil {
+		return err
+	}
+	fmt.Printf("📈 [%s] Position opened at %.2f (actual qty: %.6f, fees: %.4f %s)\n", 
+		symbol, fill.AveragePrice, fill.NetQuantity, fill.FeesInQuote, bot.GetCurrency())
+
+	ctx.placeProtectiveOCO(bot)
+	errUpdate := ctx.tradingBotRepository.Update(bot)
+	if errUpdate != nil {
+		return errUpdate
+	}
+
+	// Emit buy event
+	if err := ctx.emitTradingEvent("trading.buy_executed", bot, fill, 0, 0, 0, timestamp); err != nil {
+		fmt.Printf("⚠️ Failed to emit buy event: %v\n", err)
+	}
+	return nil
+}
+
+// closePosition records the trade closed by the executed sell order and takes the bot out of its position
+func (ctx *LiveTradingExecutionContext) closePosition(bot *entity.TradingBot, order *entity.Order, timestamp time.Time) error {
+	symbol := bot.GetSymbol().GetValue()
+	fill := order.GetFill()
+
+	// Realized P&L of the actual fills, net of the buy and sell fees
+	trade := entity.NewClosedTrade(bot, ctx.entryOrderIdOf(bot), order)
+	ctx.saveTrade(trade)
+	entryPrice := trade.GetEntryPrice()
+	realizedProfit := trade.GetProfitLoss()
+	realizedProfitPercent := trade.GetProfitLossPercent()
+
+	// Clear entry price, actual quantity and fees when exiting position
+	if err := bot.ClosePosition(); err != nil {
+		return err
+	}
+	ctx.releaseCapital(bot)
+	fmt.Printf("📉 [%s] Position closed at %.2f (P&L: %.2f %s, %.2f%% after fees)\n",
+		symbol, fill.AveragePrice, realizedProfit, bot.GetCurrency(), realizedProfitPercent)
+
+	errUpdate := ctx.tradingBotRepository.Update(bot)
+	if errUpdate != nil {
+		return errUpdate
+	}
+
+	// Emit sell event
+	if err := ctx.emitTradingEvent("trading.sell_executed", bot, fill, entryPrice, realizedProfit, realizedProfitPercent, timestamp); err != nil {
+		fmt.Printf("⚠️ Failed to emit sell event: %v\n", err)
+	}
+	return nil
+}
+
+// LiquidatePosition sells the bot's whole position at market, whatever its order execution mode. The order is not
+// linked to a decision, it is sent once and not retried as the strategy's sells are.
+func (ctx *LiveTradingExecutionContext) LiquidatePosition(bot *entity.TradingBot, currentPrice float64, timestamp time.Time) error {
+	resolved, err := ctx.resolveUnknownOrders(bot, timestamp)
+	if err != nil {
+		return err
+	}
+	if !bot.GetIsPositioned() {
+		if resolved {
+			return nil
+		}
+		return fmt.Errorf("this trading bot don't have an open position")
+	}
+	symbol := bot.GetSymbol().GetValue()
+
+	closed, errOCO := ctx.cancelProtectiveOCO(bot, timestamp)
+	if errOCO != nil || closed {
+		return errOCO
+	}
+
+	sellQuantity := bot.CalculateQuantityForSell()
+	fmt.Printf("🚨 [%s] LIQUIDATION market sell (qty: %.6f, entry: %.2f, current: %.2f)\n",
+		symbol, sellQuantity, bot.GetEntryPrice(), currentPrice)
+
+	market := entity.OrderExecution{Mode: entity.ExecutionModeMarket}
+	order := entity.NewOrder(bot.Id, "", symbol, entity.OrderSideSell, string(orderTypeOf(market)), sellQuantity, currentPrice)
+	order.SetClientOrderId(clientOrderIDOf(bot.Id.GetValue(), "", entity.OrderSideSell))
+	order = ctx.sendSellOrder(bot, order, market, sellQuantity, currentPrice)
+	if !order.IsExecuted() {
+		ctx.restoreProtectiveOCO(bot)
+		return fmt.Errorf("liquidation order was not executed: %s", order.GetErrorMessage())
+	}
+
+	bot.RecordExit(entity.ExitReasonLiquidated, timestamp)
+	return ctx.closePosition(bot, order, timestamp)
+}
+
+// reserveCapital reserves the cost of a buy with the capital allocator. A buy the allocator does not cover is
+// refused: the reason is logged and published as a trading.buy_refused event.
+func (ctx *LiveTradingExecutionContext) reserveCapital(bot *entity.TradingBot, cost float64, timestamp time.Time) (bool, error) {
+	if ctx.capitalAllocator == nil {
+		return false, nil
+	}
+
+	err := ctx.capitalAllocator.Reserve(bot, cost)
+	refusal, ok := err.(*InsufficientCapitalError)
+	if !ok {
+		return false, err
+	}
+
+	fmt.Printf("🚫 [%s] BUY refused: %v\n", bot.GetSymbol().GetValue(), refusal)
+	if ctx.messageBroker == nil {
+		return true, nil
+	}
+	payload, errMarshal := json.Marshal(map[string]interface{}{
+		"bot_id":    bot.Id.GetValue(),
+		"symbol":    bot.GetSymbol().GetValue(),
+		"reason":    refusal.Reason,
+		"required":  refusal.Required,
+		"available": refusal.Available,
+		"currency":  refusal.Currency,
+		"timestamp": timestamp,
+	})
+	if errMarshal != nil {
+		return true, nil
+	}
+	message := queue.Message{
+		RoutingKey: "trading.buy_refused",
+		Payload:    payload,
+		Headers: map[string]string{
+			"timestamp": timestamp.Format(time.RFC3339),
+			"bot_id":    bot.Id.GetValue(),
+		},
+	}
+	if errPublish := ctx.messageBroker.Publish(ctx.exchangeName, message); errPublish != nil {
+		fmt.Printf("⚠️ Failed to emit buy refused event: %v\n", errPublish)
+	}
+	return true, nil
+}
+
+// releaseCapital gives the bot's reserved capital back to the capital allocator
+func (ctx *LiveTradingExecutionContext) releaseCapital(bot *entity.TradingBot) {
+	if ctx.capitalAllocator != nil {
+		ctx.capitalAllocator.Release(bot)
+	}
+}
+
 // OnDecisionMade logs trading decisions to the repository
 func (ctx *LiveTradingExecutionContext) OnDecisionMade(decisionLog *entity.TradingDecisionLog) error {
+	botId := decisionLog.GetTradingBotId().GetValue()
+	ctx.decisionLogMu.Lock()
+	delete(ctx.decisionLogIds, botId)
+	ctx.decisionLogMu.Unlock()
+
 	if err := ctx.tradingDecisionLogRepository.Save(decisionLog); err != nil {
 		fmt.Printf("⚠️ Failed to save decision log: %v\n", err)
 		return err
 	}
-	return nil
-}
 
-// ShouldContinue returns whether the trading loop should continue
-func (ctx *LiveTradingExecutionContext) ShouldContinue() bool {
-	return ctx.shouldContinue
+	ctx.decisionLogMu.Lock()
+	ctx.decisionLogIds[botId] = decisionLog.GetId().GetValue()
+	ctx.decisionLogMu.Unlock()
+	return nil
 }
 
-// Stop stops the trading execution context
-func (ctx *LiveTradingExecutionContext) Stop() {
-	ctx.shouldContinue = false
-}
+// placeBuyOrder places a real buy order via Binance API with validation, recording the attempt in the order ledger
+func (ctx *LiveTradingExecutionContext) placeBuyOrder(bot *entity.TradingBot, quantity, price float64) *entity.Order {
+	symbol := bot.GetSymbol().GetValue()
+	order := ctx.newOrder(bot, entity.OrderSideBuy, quantity, price)
+	if executed := ctx.executedOrderOf(order); executed != nil {
+		return executed
+	}
+	defer ctx.saveOrder(order)
 
-// placeBuyOrder places a real buy order via Binance API with validation
-func (ctx *LiveTradingExecutionContext) placeBuyOrder(symbol string, quantity, price float64) bool {
 	// Validate and adjust quantity
 	adjustedQty, formattedQty, shouldProceed, warnings := ctx.orderValidator.ValidateOrderBeforePlacement(symbol, quantity, price)
 	
 	if !shouldProceed {
 		fmt.Printf("❌ Buy order validation failed for %s\n", symbol)
-		return false
+		order.MarkRejected(validationFailure(warnings))
+		return order
 	}
 
 	// Log warnings if any
@@ -182,30 +345,46 @@ func (ctx *LiveTradingExecutionContext) placeBuyOrder(symbol string, quantity, p
 		fmt.Printf("⚠️ [%s] %s\n", symbol, warning)
 	}
 
-	order, err := ctx.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(binance.SideTypeBuy).
-		Type(binance.OrderTypeMarket).
-		Quantity(formattedQty).
-		Do(context.Background())
-
+	order.SetMaxAttempts(exchangeOrdersOf(bot.GetOrderExecution()))
+	fill, err := ctx.executeOrder(bot, bot.GetOrderExecution(), order.GetClientOrderId(), entity.OrderSideBuy, adjustedQty, formattedQty, price)
+	if isOrderStatusUnknown(err) {
+		fmt.Printf("❓ Buy order status unknown: %v\n", err)
+		order.MarkUnknown(err)
+		return order
+	}
 	if err != nil {
 		fmt.Printf("❌ Error placing buy order: %v\n", err)
-		return false
+		order.MarkFailed(err)
+		return order
 	}
 
-	fmt.Printf("✅ Buy order placed: OrderID=%d, Qty=%s (adj: %.8f)\n", order.OrderID, formattedQty, adjustedQty)
-	return true
+	order.MarkFilled(fill)
+	fmt.Printf("✅ Buy order placed: OrderID=%d, Qty=%s (adj: %.8f), filled %.8f @ %.4f, commission %.8f %s\n",
+		fill.ExchangeOrderID, formattedQty, adjustedQty, fill.ExecutedQuantity, fill.AveragePrice, fill.Commission, fill.CommissionAsset)
+	return order
 }
 
-// placeSellOrder places a real sell order via Binance API with validation
-func (ctx *LiveTradingExecutionContext) placeSellOrder(symbol string, quantity, price float64) bool {
+// placeSellOrder places a real sell order via Binance API with validation, recording the attempt in the order ledger
+func (ctx *LiveTradingExecutionContext) placeSellOrder(bot *entity.TradingBot, quantity, price float64) *entity.Order {
+	order := ctx.newOrder(bot, entity.OrderSideSell, quantity, price)
+	if executed := ctx.executedOrderOf(order); executed != nil {
+		return executed
+	}
+	return ctx.sendSellOrder(bot, order, bot.GetOrderExecution(), quantity, price)
+}
+
+// sendSellOrder validates and sends the sell order with the given execution mode, recording it in the order ledger
+func (ctx *LiveTradingExecutionContext) sendSellOrder(bot *entity.TradingBot, order *entity.Order, execution entity.OrderExecution, quantity, price float64) *entity.Order {
+	symbol := bot.GetSymbol().GetValue()
+	defer ctx.saveOrder(order)
+
 	// Validate and adjust quantity
 	adjustedQty, formattedQty, shouldProceed, warnings := ctx.orderValidator.ValidateOrderBeforePlacement(symbol, quantity, price)
 	
 	if !shouldProceed {
 		fmt.Printf("❌ Sell order validation failed for %s\n", symbol)
-		return false
+		order.MarkRejected(validationFailure(warnings))
+		return order
 	}
 
 	// Log warnings if any
@@ -213,52 +392,199 @@ func (ctx *LiveTradingExecutionContext) placeSellOrder(symbol string, quantity,
 		fmt.Printf("⚠️ [%s] %s\n", symbol, warning)
 	}
 
-	order, err := ctx.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(binance.SideTypeSell).
-		Type(binance.OrderTypeMarket).
-		Quantity(formattedQty).
-		Do(context.Background())
-
+	order.SetMaxAttempts(exchangeOrdersOf(execution))
+	fill, err := ctx.executeOrder(bot, execution, order.GetClientOrderId(), entity.OrderSideSell, adjustedQty, formattedQty, price)
+	if isOrderStatusUnknown(err) {
+		fmt.Printf("❓ Sell order status unknown: %v\n", err)
+		order.MarkUnknown(err)
+		return order
+	}
 	if err != nil {
 		fmt.Printf("❌ Error placing sell order: %v\n", err)
-		return false
+		order.MarkFailed(err)
+		return order
+	}
+
+	order.MarkFilled(fill)
+	fmt.Printf("✅ Sell order placed: OrderID=%d, Qty=%s (adj: %.8f), filled %.8f @ %.4f, commission %.8f %s\n",
+		fill.ExchangeOrderID, formattedQty, adjustedQty, fill.ExecutedQuantity, fill.AveragePrice, fill.Commission, fill.CommissionAsset)
+	return order
+}
+
+// newOrder starts the ledger record of an order, linked to the decision that caused it
+func (ctx *LiveTradingExecutionContext) newOrder(bot *entity.TradingBot, side entity.OrderSide, quantity, price float64) *entity.Order {
+	ctx.decisionLogMu.Lock()
+	decisionLogId := ctx.decisionLogIds[bot.Id.GetValue()]
+	ctx.decisionLogMu.Unlock()
+
+	orderType := string(orderTypeOf(bot.GetOrderExecution()))
+	order := entity.NewOrder(bot.Id, decisionLogId, bot.GetSymbol().GetValue(), side, orderType, quantity, price)
+	order.SetClientOrderId(clientOrderIDOf(bot.Id.GetValue(), decisionLogId, side))
+	return order
+}
+
+// executedOrderOf returns the order of the ledger that already executed the same decision, nil if there is none,
+// so a decision handled twice is not sent to the exchange again
+func (ctx *LiveTradingExecutionContext) executedOrderOf(order *entity.Order) *entity.Order {
+	if ctx.orderRepository == nil || order.GetDecisionLogId() == "" {
+		return nil
+	}
+
+	orders, _, err := ctx.orderRepository.GetOrdersWithFilters(repository.OrderFilter{
+		TradingBotId:  order.GetTradingBotId().GetValue(),
+		ClientOrderId: order.GetClientOrderId(),
+	})
+	if err != nil {
+		return nil
+	}
+	for _, previous := range orders {
+		if previous.IsExecuted() {
+			fmt.Printf("⚠️ [%s] Decision %s was already executed by order %s, not sending it again\n",
+				order.GetSymbol(), order.GetDecisionLogId(), previous.GetId().GetValue())
+			return previous
+		}
+	}
+	return nil
+}
+
+// saveOrder writes the order to the ledger, a failure is logged and does not undo the trade
+func (ctx *LiveTradingExecutionContext) saveOrder(order *entity.Order) {
+	if ctx.orderRepository == nil {
+		return
+	}
+	if err := ctx.orderRepository.Save(order); err != nil {
+		fmt.Printf("⚠️ Failed to save order %s to the ledger: %v\n", order.GetId().GetValue(), err)
+	}
+}
+
+// saveTrade writes the closed trade to the ledger, a failure is logged and does not undo the trade
+func (ctx *LiveTradingExecutionContext) saveTrade(trade *entity.Trade) {
+	if ctx.tradeRepository == nil {
+		return
+	}
+	if err := ctx.tradeRepository.Save(trade); err != nil {
+		fmt.Printf("⚠️ Failed to save trade %s to the ledger: %v\n", trade.GetId().GetValue(), err)
+	}
+}
+
+// entryOrderIdOf returns the ledger ID of the buy order that opened the bot's position, empty if unknown
+func (ctx *LiveTradingExecutionContext) entryOrderIdOf(bot *entity.TradingBot) string {
+	if ctx.orderRepository == nil {
+		return ""
+	}
+
+	orders, _, err := ctx.orderRepository.GetOrdersWithFilters(repository.OrderFilter{
+		TradingBotId: bot.Id.GetValue(),
+		Side:         string(entity.OrderSideBuy),
+		Status:       string(entity.OrderStatusFilled),
+		Limit:        1,
+	})
+	if err != nil || len(orders) == 0 {
+		return ""
+	}
+	return orders[0].GetId().GetValue()
+}
+
+// validationFailure describes why order validation refused an order
+func validationFailure(reasons []string) string {
+	if len(reasons) == 0 {
+		return "order validation failed"
+	}
+	return "order validation failed: " + strings.Join(reasons, "; ")
+}
+
+// orderFillOf parses the fills of a placed order. Without fills the executed totals of the order are used, its fees
+// estimated, and its price too when the exchange did not report what it cost. An order that executed nothing, e.g. an
+// expired one, returns an empty fill: the quantity is never assumed.
+func (ctx *LiveTradingExecutionContext) orderFillOf(order *exchange.Order, bot *entity.TradingBot, price float64) entity.OrderFill {
+	symbol := bot.GetSymbol().GetValue()
+	quoteAsset := bot.GetCurrency()
+
+	if len(order.Fills) == 0 && order.ExecutedQuantity > 0 {
+		if order.QuoteQuantity <= 0 {
+			fmt.Printf("⚠️ [%s] Order %d reported no cost, estimating its price at %.4f\n", symbol, order.OrderID, price)
+			return EstimateExecutedFill(order.OrderID, order.Side, order.Status, order.ExecutedQuantity, order.ExecutedQuantity*price, bot.GetTradingFees())
+		}
+		// Orders looked up after their response was lost only report the executed totals, their fees are estimated
+		return ctx.executedFillOf(bot, order)
+	}
+
+	fill, err := ParseOrderFill(order, baseAssetOf(symbol, quoteAsset), quoteAsset, bot.GetTradingFees(), ctx.assetPriceIn(quoteAsset))
+	if err != nil {
+		fmt.Printf("⚠️ [%s] %v (%s), nothing executed\n", symbol, err, order.Status)
+		return entity.OrderFill{ExchangeOrderID: order.OrderID, Side: string(order.Side), Status: string(order.Status)}
 	}
+	return fill
+}
 
-	fmt.Printf("✅ Sell order placed: OrderID=%d, Qty=%s (adj: %.8f)\n", order.OrderID, formattedQty, adjustedQty)
-	return true
+// assetPriceIn returns a function pricing assets in quoteAsset from their latest 1m kline, e.g. BNBBRL for BNB
+func (ctx *LiveTradingExecutionContext) assetPriceIn(quoteAsset string) AssetPriceFunc {
+	return func(asset string) (float64, error) {
+		klines, err := ctx.exchange.GetKlines(context.Background(), exchange.KlineQuery{
+			Symbol:   asset + quoteAsset,
+			Interval: "1m",
+			Limit:    1,
+		})
+		if err != nil {
+			return 0, err
+		}
+		if len(klines) == 0 {
+			return 0, fmt.Errorf("no price for %s%s", asset, quoteAsset)
+		}
+		return klines[len(klines)-1].Close(), nil
+	}
 }
 
 // emitTradingEvent emits trading events to the message broker
 func (ctx *LiveTradingExecutionContext) emitTradingEvent(
 	eventType string,
 	bot *entity.TradingBot,
-	price float64,
-	quantity float64,
+	fill entity.OrderFill,
 	entryPrice float64,
 	profitLoss float64,
+	profitLossPerc float64,
 	timestamp time.Time,
 ) error {
-	totalValue := price * quantity
-	
+	return publishTradingEvent(ctx.messageBroker, ctx.exchangeName, eventType, bot, fill, entryPrice, profitLoss, profitLossPerc, timestamp)
+}
+
+// publishTradingEvent publishes a trading.buy_executed or trading.sell_executed event, tagged with the bot's
+// trading mode so paper fills are told apart from live ones
+func publishTradingEvent(
+	messageBroker queue.MessageBroker,
+	exchangeName string,
+	eventType string,
+	bot *entity.TradingBot,
+	fill entity.OrderFill,
+	entryPrice float64,
+	profitLoss float64,
+	profitLossPerc float64,
+	timestamp time.Time,
+) error {
+	if messageBroker == nil {
+		return nil
+	}
+
 	payload := map[string]interface{}{
 		"bot_id":           bot.Id.GetValue(),
 		"symbol":           bot.GetSymbol().GetValue(),
 		"action":           eventType[8:], // Remove "trading." prefix to get "buy_executed" or "sell_executed"
-		"price":            price,
-		"quantity":         quantity,
-		"total_value":      totalValue,
+		"price":            fill.AveragePrice,
+		"quantity":         fill.ExecutedQuantity,
+		"total_value":      fill.QuoteQuantity,
 		"strategy":         bot.GetStrategy().GetName(),
 		"timestamp":        timestamp,
 		"trading_fees":     bot.GetTradingFees(),
 		"currency":         bot.GetCurrency(),
+		"mode":             bot.GetMode(),
+		"order":            fill,
 	}
 
 	// Add extra fields for sell events
 	if eventType == "trading.sell_executed" {
 		payload["entry_price"] = entryPrice
-		payload["profit_loss"] = (price - entryPrice) * quantity
-		payload["profit_loss_perc"] = profitLoss
+		payload["profit_loss"] = profitLoss
+		payload["profit_loss_perc"] = profitLossPerc
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -272,8 +598,9 @@ func (ctx *LiveTradingExecutionContext) emitTradingEvent(
 		Headers:    map[string]string{
 			"timestamp": timestamp.Format(time.RFC3339),
 			"bot_id":    bot.Id.GetValue(),
+			"mode":      bot.GetMode(),
 		},
 	}
 
-	return ctx.messageBroker.Publish(ctx.exchangeName, message)
-}
\ No newline at end of file
+	return messageBroker.Publish(exchangeName, message)
+}
@@ -39,7 +39,7 @@ func (m *MockMessageBroker) Close() error { return nil }
 func TestLiveTradingExecutionContext_FeeCalculation(t *testing.T) {
 	// Setup
 	repo := &MockTradeBotRepository{}
-	client := external.NewBinanceClientFake()
+	client := external.NewFakeExchange()
 	broker := &MockMessageBroker{}
 	decisionRepo := &MockTradingDecisionLogRepository{}
 	
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"crypgo-machine/src/domain/entity"
 	"crypgo-machine/src/domain/vo"
+	"fmt"
 	"time"
 )
 
@@ -15,4 +17,23 @@ type MarketDataSource interface {
 	// For live data, this is the current system time
 	// For historical data, this is the timestamp of the current kline being processed
 	GetCurrentTime() time.Time
-}
\ No newline at end of file
+}
+
+// FetchTimeframes retrieves the klines of each additional interval a multi-timeframe strategy needs, leaving out the
+// kline still forming at the source's current time like backtests do, see ClosedKlines
+func FetchTimeframes(source MarketDataSource, symbol string, intervals []int) (entity.Timeframes, error) {
+	if len(intervals) == 0 {
+		return nil, nil
+	}
+
+	currentTime := source.GetCurrentTime().UnixMilli()
+	timeframes := make(entity.Timeframes, len(intervals))
+	for _, intervalSeconds := range intervals {
+		klines, err := source.GetMarketData(symbol, intervalSeconds)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching %ds timeframe: %v", intervalSeconds, err)
+		}
+		timeframes[intervalSeconds] = ClosedKlines(klines, currentTime)
+	}
+	return timeframes, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AssetPriceFunc returns the price of an asset in the quote currency, used to value commission paid in other assets like BNB
+type AssetPriceFunc func(asset string) (float64, error)
+
+// ParseOrderFill builds the OrderFill of an order response from its fills: executed quantity, volume-weighted
+// average price and commission. Commission paid in the base asset reduces the quantity held, and commission in
+// any other asset than the quote one is valued with assetPrice, falling back to estimatedFeePercent of the fill.
+func ParseOrderFill(order *exchange.Order, baseAsset, quoteAsset string, estimatedFeePercent float64, assetPrice AssetPriceFunc) (entity.OrderFill, error) {
+	fill := entity.OrderFill{
+		ExchangeOrderID: order.OrderID,
+		Side:            string(order.Side),
+		Status:          string(order.Status),
+		ExecutedAt:      time.Now(),
+	}
+	if !order.UpdatedAt.IsZero() {
+		fill.ExecutedAt = order.UpdatedAt
+	}
+
+	var baseCommission float64
+	for _, orderFill := range order.Fills {
+		price, qty, commission := orderFill.Price, orderFill.Quantity, orderFill.Commission
+
+		fill.ExecutedQuantity += qty
+		fill.QuoteQuantity += price * qty
+		fill.Fills = append(fill.Fills, orderFill)
+
+		if fill.CommissionAsset == "" || fill.CommissionAsset == orderFill.CommissionAsset {
+			fill.CommissionAsset = orderFill.CommissionAsset
+			fill.Commission += commission
+		}
+
+		switch orderFill.CommissionAsset {
+		case quoteAsset:
+			fill.FeesInQuote += commission
+		case baseAsset:
+			baseCommission += commission
+			fill.FeesInQuote += commission * price
+		default:
+			assetValue, err := assetPrice(orderFill.CommissionAsset)
+			if err != nil || assetValue <= 0 {
+				fmt.Printf("⚠️ Could not price %s commission in %s, estimating %.2f%% fees: %v\n",
+					orderFill.CommissionAsset, quoteAsset, estimatedFeePercent, err)
+				fill.FeesInQuote += price * qty * estimatedFeePercent / 100.0
+				continue
+			}
+			fill.FeesInQuote += commission * assetValue
+		}
+	}
+
+	// Without fills, fall back to the order totals
+	if len(order.Fills) == 0 {
+		fill.ExecutedQuantity = order.ExecutedQuantity
+		fill.QuoteQuantity = order.QuoteQuantity
+	}
+	if fill.ExecutedQuantity <= 0 {
+		return entity.OrderFill{}, fmt.Errorf("order %d has no executed quantity", order.OrderID)
+	}
+
+	fill.AveragePrice = fill.QuoteQuantity / fill.ExecutedQuantity
+	fill.NetQuantity = fill.ExecutedQuantity - baseCommission
+	return fill, nil
+}
+
+// EstimateExecutedFill builds the OrderFill of an order from its executed totals, for orders whose fills are not
+// known like limit orders filled while resting on the book. It pays feePercent of the quote quantity: in the base
+// asset on buys and in the quote one on sells.
+func EstimateExecutedFill(orderID int64, side entity.OrderSide, status exchange.OrderStatus, executedQuantity, quoteQuantity, feePercent float64) entity.OrderFill {
+	fees := quoteQuantity * feePercent / 100.0
+	netQuantity := executedQuantity
+	if side == entity.OrderSideBuy {
+		netQuantity = executedQuantity * (1.0 - feePercent/100.0)
+	}
+	averagePrice := 0.0
+	if executedQuantity > 0 {
+		averagePrice = quoteQuantity / executedQuantity
+	}
+
+	return entity.OrderFill{
+		ExchangeOrderID:  orderID,
+		Side:             string(side),
+		Status:           string(status),
+		ExecutedQuantity: executedQuantity,
+		AveragePrice:     averagePrice,
+		QuoteQuantity:    quoteQuantity,
+		FeesInQuote:      fees,
+		NetQuantity:      netQuantity,
+		Estimated:        true,
+		ExecutedAt:       time.Now(),
+	}
+}
+
+// MergeOrderFills combines the fills of the orders that executed one bot order, e.g. repriced limit orders and
+// a market order for the rest, into one fill at their volume-weighted average price. It keeps the exchange order
+// ID and status of the last one.
+func MergeOrderFills(fills []entity.OrderFill) entity.OrderFill {
+	if len(fills) == 0 {
+		return entity.OrderFill{}
+	}
+
+	merged := entity.OrderFill{Side: fills[0].Side}
+	for _, fill := range fills {
+		merged.ExchangeOrderID = fill.ExchangeOrderID
+		merged.Status = fill.Status
+		merged.ExecutedAt = fill.ExecutedAt
+		merged.ExecutedQuantity += fill.ExecutedQuantity
+		merged.QuoteQuantity += fill.QuoteQuantity
+		merged.FeesInQuote += fill.FeesInQuote
+		merged.NetQuantity += fill.NetQuantity
+		merged.Estimated = merged.Estimated || fill.Estimated
+		merged.Fills = append(merged.Fills, fill.Fills...)
+
+		if merged.CommissionAsset == "" || merged.CommissionAsset == fill.CommissionAsset {
+			merged.CommissionAsset = fill.CommissionAsset
+			merged.Commission += fill.Commission
+		}
+	}
+
+	if merged.ExecutedQuantity > 0 {
+		merged.AveragePrice = merged.QuoteQuantity / merged.ExecutedQuantity
+	}
+	return merged
+}
+
+// baseAssetOf returns the base asset of a symbol quoted in currency, e.g. SOL for SOLBRL and BRL
+func baseAssetOf(symbol, currency string) string {
+	if currency == "" || !strings.HasSuffix(symbol, currency) {
+		return ""
+	}
+	return strings.TrimSuffix(symbol, currency)
+}
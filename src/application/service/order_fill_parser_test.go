@@ -0,0 +1,246 @@
+package service
+
+import (
+	appRepository "crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/external"
+	"crypgo-machine/src/infra/queue"
+	"crypgo-machine/src/infra/repository"
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+)
+
+func assertAlmostEqual(t *testing.T, name string, expected, actual float64) {
+	t.Helper()
+	if math.Abs(expected-actual) > 1e-9 {
+		t.Errorf("expected %s %.8f, got %.8f", name, expected, actual)
+	}
+}
+
+func TestParseOrderFill(t *testing.T) {
+	noPrice := func(asset string) (float64, error) { return 0, fmt.Errorf("no price for %s", asset) }
+
+	t.Run("volume-weighted price and commission in the base asset", func(t *testing.T) {
+		order := &exchange.Order{
+			OrderID: 7,
+			Side:    entity.OrderSideBuy,
+			Status:  exchange.OrderStatusFilled,
+			Fills: []entity.Fill{
+				{Price: 100.0, Quantity: 1.0, Commission: 0.001, CommissionAsset: "SOL"},
+				{Price: 103.0, Quantity: 2.0, Commission: 0.002, CommissionAsset: "SOL"},
+			},
+		}
+
+		fill, err := ParseOrderFill(order, "SOL", "BRL", 0.1, noPrice)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertAlmostEqual(t, "executed quantity", 3.0, fill.ExecutedQuantity)
+		assertAlmostEqual(t, "average price", 102.0, fill.AveragePrice)
+		assertAlmostEqual(t, "quote quantity", 306.0, fill.QuoteQuantity)
+		assertAlmostEqual(t, "commission", 0.003, fill.Commission)
+		assertAlmostEqual(t, "net quantity", 2.997, fill.NetQuantity)
+		assertAlmostEqual(t, "fees in quote", 0.001*100+0.002*103, fill.FeesInQuote)
+		if fill.CommissionAsset != "SOL" || fill.ExchangeOrderID != 7 || fill.Side != "BUY" || fill.Estimated {
+			t.Errorf("unexpected fill %+v", fill)
+		}
+	})
+
+	t.Run("commission in BNB is priced in the quote currency", func(t *testing.T) {
+		order := &exchange.Order{
+			Side: entity.OrderSideSell,
+			Fills: []entity.Fill{
+				{Price: 110.0, Quantity: 1.0, Commission: 0.0002, CommissionAsset: "BNB"},
+			},
+		}
+		bnbPrice := func(asset string) (float64, error) {
+			if asset != "BNB" {
+				return 0, fmt.Errorf("unexpected asset %s", asset)
+			}
+			return 3000.0, nil
+		}
+
+		fill, err := ParseOrderFill(order, "SOL", "BRL", 0.1, bnbPrice)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertAlmostEqual(t, "fees in quote", 0.6, fill.FeesInQuote)
+		assertAlmostEqual(t, "net quantity", 1.0, fill.NetQuantity)
+		if fill.CommissionAsset != "BNB" {
+			t.Errorf("expected BNB commission, got %s", fill.CommissionAsset)
+		}
+	})
+
+	t.Run("unpriced commission is estimated from the fee percentage", func(t *testing.T) {
+		order := &exchange.Order{
+			Fills: []entity.Fill{
+				{Price: 200.0, Quantity: 1.0, Commission: 0.0002, CommissionAsset: "BNB"},
+			},
+		}
+
+		fill, err := ParseOrderFill(order, "SOL", "BRL", 0.1, noPrice)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertAlmostEqual(t, "fees in quote", 0.2, fill.FeesInQuote)
+	})
+
+	t.Run("order without executed quantity", func(t *testing.T) {
+		if _, err := ParseOrderFill(&exchange.Order{OrderID: 9}, "SOL", "BRL", 0.1, noPrice); err == nil {
+			t.Error("expected error for an order without fills")
+		}
+	})
+}
+
+// capturingMessageBroker records the published messages
+type capturingMessageBroker struct {
+	MockMessageBroker
+	messages []queue.Message
+}
+
+func (b *capturingMessageBroker) Publish(exchangeName string, message queue.Message) error {
+	b.messages = append(b.messages, message)
+	return nil
+}
+
+func TestLiveTradingExecutionContext_RecordsActualFills(t *testing.T) {
+	client := external.NewFakeExchange()
+	broker := &capturingMessageBroker{}
+	ctx := NewLiveTradingExecutionContext(client, &MockTradeBotRepository{}, &MockTradingDecisionLogRepository{}, broker, "test_exchange")
+
+	symbol, _ := vo.NewSymbol("SOLBRL")
+	bot := entity.NewTradingBot(symbol, 2.0, entity.NewMovingAverageStrategy(5, 20), 60, 1000, 300, "BRL", 0.1, 2.0, true)
+
+	// Bought in two fills with commission taken in SOL, sold with commission paid in BRL
+	client.AddOrderFills(
+		entity.Fill{Price: 100.0, Quantity: 1.0, Commission: 0.001, CommissionAsset: "SOL"},
+		entity.Fill{Price: 102.0, Quantity: 1.0, Commission: 0.001, CommissionAsset: "SOL"},
+	)
+	client.AddOrderFills(
+		entity.Fill{Price: 110.0, Quantity: 1.99, Commission: 0.2189, CommissionAsset: "BRL"},
+	)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 99.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+	assertAlmostEqual(t, "entry price", 101.0, bot.GetEntryPrice())
+	assertAlmostEqual(t, "quantity held", 1.998, bot.GetActualQuantityHeld())
+	assertAlmostEqual(t, "entry fees", 0.202, bot.GetEntryFees())
+
+	if err := ctx.ExecuteTrade(entity.Sell, bot, 109.0, time.Now()); err != nil {
+		t.Fatalf("sell failed: %v", err)
+	}
+	if bot.GetEntryFees() != 0 || bot.GetActualQuantityHeld() != 0 || bot.GetEntryPrice() != 0 {
+		t.Error("expected the position bookkeeping to be cleared after the sell")
+	}
+
+	if len(broker.messages) != 2 {
+		t.Fatalf("expected buy and sell events, got %d", len(broker.messages))
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(broker.messages[1].Payload, &payload); err != nil {
+		t.Fatalf("invalid sell payload: %v", err)
+	}
+
+	// (110 - 101) * 1.99 - 0.202 buy fees - 0.2189 sell fees
+	assertAlmostEqual(t, "profit_loss", 9*1.99-0.202-0.2189, payload["profit_loss"].(float64))
+	assertAlmostEqual(t, "price", 110.0, payload["price"].(float64))
+	order := payload["order"].(map[string]interface{})
+	if order["commission_asset"] != "BRL" || order["exchange_order_id"].(float64) == 0 {
+		t.Errorf("expected the order record in the sell event, got %v", order)
+	}
+}
+
+func TestLiveTradingExecutionContext_RecordsOrderAndTradeLedger(t *testing.T) {
+	client := external.NewFakeExchange()
+	orderRepo := repository.NewOrderRepositoryInMemory()
+	tradeRepo := repository.NewTradeRepositoryInMemory()
+	ctx := NewLiveTradingExecutionContext(client, &MockTradeBotRepository{}, &MockTradingDecisionLogRepository{}, &MockMessageBroker{}, "test_exchange").
+		WithLedger(orderRepo, tradeRepo)
+
+	symbol, _ := vo.NewSymbol("SOLBRL")
+	bot := entity.NewTradingBot(symbol, 2.0, entity.NewMovingAverageStrategy(5, 20), 60, 1000, 300, "BRL", 0.1, 2.0, true)
+
+	client.AddOrderFills(entity.Fill{Price: 100.0, Quantity: 2.0, Commission: 0.002, CommissionAsset: "SOL"})
+	client.AddOrderFills(entity.Fill{Price: 110.0, Quantity: 1.99, Commission: 0.2189, CommissionAsset: "BRL"})
+
+	buyLog := entity.NewTradingDecisionLog(bot.Id, entity.Buy, "MovingAverage", nil, nil, 100.0, 0)
+	if err := ctx.OnDecisionMade(buyLog); err != nil {
+		t.Fatalf("failed to record decision: %v", err)
+	}
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+	if err := ctx.ExecuteTrade(entity.Sell, bot, 110.0, time.Now()); err != nil {
+		t.Fatalf("sell failed: %v", err)
+	}
+
+	// A quantity below the exchange minimums never reaches the exchange but is still recorded
+	tinyBot := entity.NewTradingBot(symbol, 0.00001, entity.NewMovingAverageStrategy(5, 20), 60, 1000, 300, "BRL", 0.1, 2.0, true)
+	if err := ctx.ExecuteTrade(entity.Buy, tinyBot, 100.0, time.Now()); err != nil {
+		t.Fatalf("rejected buy should not fail the trade loop: %v", err)
+	}
+
+	orders, total, err := orderRepo.GetOrdersWithFilters(appRepository.OrderFilter{TradingBotId: bot.Id.GetValue()})
+	if err != nil || total != 2 {
+		t.Fatalf("expected 2 orders for the bot, got %d (err: %v)", total, err)
+	}
+	sellOrder, buyOrder := orders[0], orders[1]
+	if buyOrder.GetSide() != entity.OrderSideBuy || buyOrder.GetStatus() != entity.OrderStatusFilled {
+		t.Errorf("expected a filled buy order, got %s %s", buyOrder.GetSide(), buyOrder.GetStatus())
+	}
+	if buyOrder.GetDecisionLogId() != buyLog.GetId().GetValue() {
+		t.Errorf("expected the buy order to reference its decision log, got %q", buyOrder.GetDecisionLogId())
+	}
+	if sellOrder.GetSide() != entity.OrderSideSell || len(sellOrder.GetFill().Fills) != 1 {
+		t.Errorf("expected a sell order with its fill, got %s with %d fills", sellOrder.GetSide(), len(sellOrder.GetFill().Fills))
+	}
+
+	rejected, _, _ := orderRepo.GetOrdersWithFilters(appRepository.OrderFilter{Status: string(entity.OrderStatusRejected)})
+	if len(rejected) != 1 || rejected[0].GetTradingBotId() != tinyBot.Id || rejected[0].GetErrorMessage() == "" {
+		t.Fatalf("expected the tiny order to be recorded as rejected with a reason, got %v", rejected)
+	}
+
+	trades, total, err := tradeRepo.GetTradesWithFilters(appRepository.TradeFilter{TradingBotId: bot.Id.GetValue()})
+	if err != nil || total != 1 {
+		t.Fatalf("expected 1 closed trade, got %d (err: %v)", total, err)
+	}
+	trade := trades[0]
+	if trade.GetEntryOrderId() != buyOrder.GetId().GetValue() || trade.GetExitOrderId() != sellOrder.GetId().GetValue() {
+		t.Error("expected the trade to reference its entry and exit orders")
+	}
+	// (110 - 100) * 1.99 - 0.2 buy fees - 0.2189 sell fees
+	assertAlmostEqual(t, "trade profit_loss", 10*1.99-0.2-0.2189, trade.GetProfitLoss())
+	assertAlmostEqual(t, "trade entry_fees", 0.2, trade.GetEntryFees())
+}
+
+func TestLiveTradingExecutionContext_OrderFillNeverAssumesTheQuantity(t *testing.T) {
+	ctx := NewLiveTradingExecutionContext(external.NewFakeExchange(), &MockTradeBotRepository{}, &MockTradingDecisionLogRepository{}, &MockMessageBroker{}, "test_exchange")
+	symbol, _ := vo.NewSymbol("SOLBRL")
+	bot := entity.NewTradingBot(symbol, 2.0, entity.NewMovingAverageStrategy(5, 20), 60, 1000, 300, "BRL", 0.1, 2.0, true)
+
+	// An expired market order or one looked up while still new executed nothing
+	for _, status := range []exchange.OrderStatus{exchange.OrderStatusExpired, exchange.OrderStatusNew} {
+		fill := ctx.orderFillOf(&exchange.Order{OrderID: 7, Side: entity.OrderSideBuy, Status: status, Quantity: 2.0}, bot, 100.0)
+		if fill.ExecutedQuantity != 0 || fill.Status != string(status) {
+			t.Errorf("expected the %s order to execute nothing, got %+v", status, fill)
+		}
+	}
+
+	// Without fills nor cost only the price and fees are estimated, the quantity is the executed one
+	fill := ctx.orderFillOf(&exchange.Order{OrderID: 8, Side: entity.OrderSideBuy, Status: exchange.OrderStatusFilled, Quantity: 2.0, ExecutedQuantity: 1.5}, bot, 100.0)
+	if !fill.Estimated {
+		t.Error("expected the fill to be estimated")
+	}
+	assertAlmostEqual(t, "executed quantity", 1.5, fill.ExecutedQuantity)
+	assertAlmostEqual(t, "average price", 100.0, fill.AveragePrice)
+	assertAlmostEqual(t, "fees", 150.0*0.001, fill.FeesInQuote)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/infra/queue"
+	"fmt"
+	"time"
+)
+
+// DefaultPaperSlippagePercent is how far from the live price paper orders fill when no slippage is configured
+const DefaultPaperSlippagePercent = 0.05
+
+// PaperTradingExecutionContext implements TradingExecutionContext for paper trading: decisions are taken on live
+// market data, but fills are simulated at the current price moved against the bot by the slippage and charged the
+// bot's trading fees. Each bot trades against its own virtual balance, nothing is sent to the exchange nor
+// recorded in the order and trade ledger.
+type PaperTradingExecutionContext struct {
+	tradingBotRepository         repository.TradingBotRepository
+	tradingDecisionLogRepository repository.TradingDecisionLogRepository
+	messageBroker                queue.MessageBroker
+	exchangeName                 string
+	slippagePercent              float64
+}
+
+// NewPaperTradingExecutionContext creates a new PaperTradingExecutionContext, a negative slippage uses the default
+func NewPaperTradingExecutionContext(
+	tradingBotRepo repository.TradingBotRepository,
+	decisionLogRepo repository.TradingDecisionLogRepository,
+	messageBroker queue.MessageBroker,
+	exchangeName string,
+	slippagePercent float64,
+) *PaperTradingExecutionContext {
+	if slippagePercent < 0 {
+		slippagePercent = DefaultPaperSlippagePercent
+	}
+
+	return &PaperTradingExecutionContext{
+		tradingBotRepository:         tradingBotRepo,
+		tradingDecisionLogRepository: decisionLogRepo,
+		messageBroker:                messageBroker,
+		exchangeName:                 exchangeName,
+		slippagePercent:              slippagePercent,
+	}
+}
+
+// ExecuteTrade simulates the fill of the decision against the bot's virtual balance
+func (ctx *PaperTradingExecutionContext) ExecuteTrade(decision entity.TradingDecision, bot *entity.TradingBot, currentPrice float64, timestamp time.Time) error {
+	symbol := bot.GetSymbol().GetValue()
+
+	switch decision {
+	case entity.Buy:
+		if bot.GetIsPositioned() {
+			return fmt.Errorf("this trading bot already has an open position")
+		}
+
+		price := currentPrice * (1 + ctx.slippagePercent/100.0)
+		quantity := bot.CalculateBuyQuantity(price)
+		cost := quantity * price
+		if quantity <= 0 || cost > bot.GetPaperBalance() {
+			fmt.Printf("🧪 [%s] PAPER BUY skipped: %.2f %s needed, %.2f %s available\n",
+				symbol, cost, bot.GetCurrency(), bot.GetPaperBalance(), bot.GetCurrency())
+			return nil
+		}
+
+		fill := ctx.simulateFill(bot, entity.OrderSideBuy, quantity, price, timestamp)
+		if err := bot.OpenPosition(fill, timestamp); err != nil {
+			return err
+		}
+		bot.AdjustPaperBalance(-fill.QuoteQuantity)
+		fmt.Printf("🧪 [%s] PAPER BUY filled %.6f @ %.4f (fees: %.4f %s, balance: %.2f %s)\n",
+			symbol, fill.ExecutedQuantity, fill.AveragePrice, fill.FeesInQuote, bot.GetCurrency(), bot.GetPaperBalance(), bot.GetCurrency())
+
+		if err := ctx.tradingBotRepository.Update(bot); err != nil {
+			return err
+		}
+		if err := publishTradingEvent(ctx.messageBroker, ctx.exchangeName, "trading.buy_executed", bot, fill, 0, 0, 0, timestamp); err != nil {
+			fmt.Printf("⚠️ Failed to emit buy event: %v\n", err)
+		}
+		return nil
+
+	case entity.Sell:
+		if !bot.GetIsPositioned() {
+			return fmt.Errorf("this trading bot don't have an open position")
+		}
+
+		price := currentPrice * (1 - ctx.slippagePercent/100.0)
+		fill := ctx.simulateFill(bot, entity.OrderSideSell, bot.CalculateQuantityForSell(), price, timestamp)
+		entryPrice := bot.GetEntryPrice()
+		profitLoss := bot.CalculateRealizedProfitLoss(fill)
+		profitLossPercent := 0.0
+		if costBasis := entryPrice * fill.ExecutedQuantity; costBasis > 0 {
+			profitLossPercent = profitLoss / costBasis * 100
+		}
+
+		if err := bot.ClosePosition(); err != nil {
+			return err
+		}
+		bot.AdjustPaperBalance(fill.QuoteQuantity - fill.FeesInQuote)
+		fmt.Printf("🧪 [%s] PAPER SELL filled %.6f @ %.4f (P&L: %.2f %s, %.2f%% after fees, balance: %.2f %s)\n",
+			symbol, fill.ExecutedQuantity, fill.AveragePrice, profitLoss, bot.GetCurrency(), profitLossPercent, bot.GetPaperBalance(), bot.GetCurrency())
+
+		if err := ctx.tradingBotRepository.Update(bot); err != nil {
+			return err
+		}
+		if err := publishTradingEvent(ctx.messageBroker, ctx.exchangeName, "trading.sell_executed", bot, fill, entryPrice, profitLoss, profitLossPercent, timestamp); err != nil {
+			fmt.Printf("⚠️ Failed to emit sell event: %v\n", err)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// LiquidatePosition sells the bot's whole position at once, as every paper order fills right away
+func (ctx *PaperTradingExecutionContext) LiquidatePosition(bot *entity.TradingBot, currentPrice float64, timestamp time.Time) error {
+	if !bot.GetIsPositioned() {
+		return fmt.Errorf("this trading bot don't have an open position")
+	}
+	bot.RecordExit(entity.ExitReasonLiquidated, timestamp)
+	return ctx.ExecuteTrade(entity.Sell, bot, currentPrice, timestamp)
+}
+
+// simulateFill fills the whole quantity at price, charging the bot's trading fees like the exchange does: in the
+// base asset on buys and in the quote one on sells
+func (ctx *PaperTradingExecutionContext) simulateFill(bot *entity.TradingBot, side entity.OrderSide, quantity, price float64, timestamp time.Time) entity.OrderFill {
+	fill := EstimateExecutedFill(0, side, exchange.OrderStatusFilled, quantity, quantity*price, bot.GetTradingFees())
+	fill.Estimated = false
+	if side == entity.OrderSideBuy {
+		fill.Commission = fill.ExecutedQuantity - fill.NetQuantity
+		fill.CommissionAsset = baseAssetOf(bot.GetSymbol().GetValue(), bot.GetCurrency())
+	} else {
+		fill.Commission = fill.FeesInQuote
+		fill.CommissionAsset = bot.GetCurrency()
+	}
+	fill.ExecutedAt = timestamp
+	return fill
+}
+
+// OnDecisionMade logs trading decisions to the repository
+func (ctx *PaperTradingExecutionContext) OnDecisionMade(decisionLog *entity.TradingDecisionLog) error {
+	if err := ctx.tradingDecisionLogRepository.Save(decisionLog); err != nil {
+		fmt.Printf("⚠️ Failed to save decision log: %v\n", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/queue"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// recordingMessageBroker keeps the published messages
+type recordingMessageBroker struct {
+	MockMessageBroker
+	messages []queue.Message
+}
+
+func (m *recordingMessageBroker) Publish(exchangeName string, message queue.Message) error {
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+// newPaperTestBot returns a paper bot trading 300 BRL of SOLBRL per entry with 0.1% fees out of 1000 BRL
+func newPaperTestBot() *entity.TradingBot {
+	symbol, _ := vo.NewSymbol("SOLBRL")
+	bot := entity.NewTradingBot(symbol, 2.0, entity.NewMovingAverageStrategy(5, 20), 60, 1000, 300, "BRL", 0.1, 2.0, false)
+	bot.SetMode(entity.TradingModePaper)
+	return bot
+}
+
+func TestPaperTradingExecutionContext_RoundTrip(t *testing.T) {
+	broker := &recordingMessageBroker{}
+	ctx := NewPaperTradingExecutionContext(&MockTradeBotRepository{}, &MockTradingDecisionLogRepository{}, broker, "test_exchange", 0.5)
+	bot := newPaperTestBot()
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+	// 0.5% slippage against the buyer, 300 BRL spent and 0.1% of the coins charged as fees
+	buyQuantity := 300.0 / 100.5
+	assertAlmostEqual(t, "entry price", 100.5, bot.GetEntryPrice())
+	assertAlmostEqual(t, "quantity held", buyQuantity*0.999, bot.GetActualQuantityHeld())
+	assertAlmostEqual(t, "entry fees", 0.3, bot.GetEntryFees())
+	assertAlmostEqual(t, "balance after buy", 700.0, bot.GetPaperBalance())
+
+	if err := ctx.ExecuteTrade(entity.Sell, bot, 110.0, time.Now()); err != nil {
+		t.Fatalf("sell failed: %v", err)
+	}
+	if bot.GetIsPositioned() {
+		t.Fatal("expected the sell to close the paper position")
+	}
+	// 0.5% slippage against the seller and 0.1% of the proceeds charged as fees
+	proceeds := buyQuantity * 0.999 * 109.45
+	assertAlmostEqual(t, "balance after sell", 700.0+proceeds*0.999, bot.GetPaperBalance())
+
+	if len(broker.messages) != 2 || broker.messages[0].RoutingKey != "trading.buy_executed" || broker.messages[1].RoutingKey != "trading.sell_executed" {
+		t.Fatalf("expected buy and sell events, got %v", broker.messages)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(broker.messages[1].Payload, &payload); err != nil {
+		t.Fatalf("invalid sell payload: %v", err)
+	}
+	if payload["mode"] != entity.TradingModePaper || broker.messages[1].Headers["mode"] != entity.TradingModePaper {
+		t.Errorf("expected the sell event tagged as paper, got %v", payload["mode"])
+	}
+	assertAlmostEqual(t, "profit_loss", proceeds*0.999-300.0, payload["profit_loss"].(float64))
+}
+
+func TestPaperTradingExecutionContext_SkipsBuyBeyondBalance(t *testing.T) {
+	broker := &recordingMessageBroker{}
+	ctx := NewPaperTradingExecutionContext(&MockTradeBotRepository{}, &MockTradingDecisionLogRepository{}, broker, "test_exchange", 0)
+	bot := newPaperTestBot()
+	bot.AdjustPaperBalance(-800)
+
+	if err := ctx.ExecuteTrade(entity.Buy, bot, 100.0, time.Now()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if bot.GetIsPositioned() || bot.GetPaperBalance() != 200 || len(broker.messages) != 0 {
+		t.Errorf("expected the buy to be skipped with 200 BRL left, got positioned=%v balance=%.2f", bot.GetIsPositioned(), bot.GetPaperBalance())
+	}
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/infra/queue"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RiskBreach describes a risk limit that blocks an entry or flattens the positions
+type RiskBreach struct {
+	Limit     string // One of the entity.RiskLimit* names
+	Asset     string // Base asset of an exposure breach
+	Profile   string // Exchange profile whose bots breached the limit, empty for the kill switch
+	Value     float64
+	Threshold float64
+	Flatten   bool // The open positions are sold
+}
+
+func (b *RiskBreach) Error() string {
+	switch b.Limit {
+	case entity.RiskLimitKillSwitch:
+		return "kill switch is on"
+	case entity.RiskLimitDailyLoss:
+		return fmt.Sprintf("daily loss %.2f reached the limit of %.2f", b.Value, b.Threshold)
+	case entity.RiskLimitOpenPositions:
+		return fmt.Sprintf("%.0f open positions reached the limit of %.0f", b.Value, b.Threshold)
+	case entity.RiskLimitAssetExposure:
+		return fmt.Sprintf("%s exposure %.2f exceeds the limit of %.2f", b.Asset, b.Value, b.Threshold)
+	}
+	return fmt.Sprintf("%s limit breached", b.Limit)
+}
+
+// RiskStatus is the state of the risk limits across the live bots of an exchange profile
+type RiskStatus struct {
+	Profile          string
+	Limits           entity.RiskLimits
+	DailyProfitLoss  float64 // Realized since midnight UTC
+	OpenPositions    int
+	ExposurePerAsset map[string]float64 // Cost of the open positions per base asset
+	Breaches         []RiskBreach
+}
+
+// PositionFlattener sells right away the open positions of the live bots a breach flattens
+type PositionFlattener interface {
+	// FlattenPositions liquidates the positions of the live bots of the breach's profile, of every profile for the
+	// kill switch
+	FlattenPositions(breach *RiskBreach)
+}
+
+// RiskManager enforces the risk limits shared by the live bots between their decisions and their execution. Paper
+// bots are ignored, their positions and trades being simulated. The daily loss, open positions and exposure are
+// counted per exchange profile, so the losses of a testnet account never block the production bots, while the kill
+// switch stops the bots of every profile. The first entry blocked by a limit publishes a risk.limit_breached event,
+// the next ones are only logged until the limit is no longer breached.
+type RiskManager struct {
+	limitsRepository     repository.RiskLimitsRepository
+	tradingBotRepository repository.TradingBotRepository
+	tradeRepository      repository.TradeRepository
+	messageBroker        queue.MessageBroker
+	exchangeName         string
+	now                  func() time.Time
+	flattener            PositionFlattener // Liquidates the positions when a limit flattening them trips (nil = on the next tick of each bot)
+
+	mu       sync.Mutex
+	limits   *entity.RiskLimits // Loaded on first use
+	breached map[string]bool    // Limits whose breach was already published
+}
+
+// NewRiskManager creates a new RiskManager
+func NewRiskManager(
+	limitsRepo repository.RiskLimitsRepository,
+	tradingBotRepo repository.TradingBotRepository,
+	tradeRepo repository.TradeRepository,
+	messageBroker queue.MessageBroker,
+	exchangeName string,
+) *RiskManager {
+	return &RiskManager{
+		limitsRepository:     limitsRepo,
+		tradingBotRepository: tradingBotRepo,
+		tradeRepository:      tradeRepo,
+		messageBroker:        messageBroker,
+		exchangeName:         exchangeName,
+		now:                  time.Now,
+		breached:             make(map[string]bool),
+	}
+}
+
+// WithPositionFlattener liquidates the positions through flattener as soon as a limit flattening them is breached,
+// rather than on the next tick of each bot
+func (m *RiskManager) WithPositionFlattener(flattener PositionFlattener) *RiskManager {
+	m.flattener = flattener
+	return m
+}
+
+// Limits returns the current risk limits
+func (m *RiskManager) Limits() (entity.RiskLimits, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.loadLimits()
+}
+
+// UpdateLimits saves new risk limits, keeping the kill switch as it is
+func (m *RiskManager) UpdateLimits(limits entity.RiskLimits) (entity.RiskLimits, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, err := m.loadLimits()
+	if err != nil {
+		return entity.RiskLimits{}, err
+	}
+	limits.KillSwitch = current.KillSwitch
+	return limits, m.saveLimits(limits)
+}
+
+// SetKillSwitch turns the kill switch on or off, publishing a risk.limit_breached event when it is turned on
+func (m *RiskManager) SetKillSwitch(active bool) (entity.RiskLimits, error) {
+	m.mu.Lock()
+	limits, err := m.loadLimits()
+	if err == nil {
+		limits.KillSwitch = active
+		err = m.saveLimits(limits)
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return entity.RiskLimits{}, err
+	}
+
+	if active {
+		m.notifyBreach(&RiskBreach{Limit: entity.RiskLimitKillSwitch, Flatten: limits.FlattenOnBreach}, nil)
+	} else {
+		fmt.Println("✅ Risk kill switch turned off")
+		m.clearBreach(breachKey(entity.RiskLimitKillSwitch, "", ""))
+	}
+	return limits, nil
+}
+
+// CheckEntry returns the breach that blocks the bot from opening a position costing cost, nil when it may enter
+func (m *RiskManager) CheckEntry(bot *entity.TradingBot, cost float64) (*RiskBreach, error) {
+	if bot.IsPaper() {
+		return nil, nil
+	}
+	limits, err := m.Limits()
+	if err != nil {
+		return nil, err
+	}
+
+	breach, err := m.entryBreach(limits, bot.GetExchangeProfile(), bot, cost)
+	if err != nil {
+		return nil, err
+	}
+	if breach != nil {
+		m.notifyBreach(breach, bot)
+	}
+	return breach, nil
+}
+
+// entryBreach checks the limits in order of severity on the bots of profile, clearing the notified breaches of the
+// limits that pass
+func (m *RiskManager) entryBreach(limits entity.RiskLimits, profile string, bot *entity.TradingBot, cost float64) (*RiskBreach, error) {
+	if limits.KillSwitch {
+		return &RiskBreach{Limit: entity.RiskLimitKillSwitch, Flatten: limits.FlattenOnBreach}, nil
+	}
+
+	if breach, err := m.dailyLossBreach(limits, profile); err != nil || breach != nil {
+		return breach, err
+	}
+	m.clearBreach(breachKey(entity.RiskLimitDailyLoss, profile, ""))
+
+	if limits.MaxOpenPositions == 0 && limits.MaxExposurePerAsset == 0 {
+		return nil, nil
+	}
+	openPositions, exposure, err := m.openPositions(profile)
+	if err != nil {
+		return nil, err
+	}
+	if limits.MaxOpenPositions > 0 && openPositions >= limits.MaxOpenPositions {
+		return &RiskBreach{Limit: entity.RiskLimitOpenPositions, Profile: profile, Value: float64(openPositions), Threshold: float64(limits.MaxOpenPositions)}, nil
+	}
+	m.clearBreach(breachKey(entity.RiskLimitOpenPositions, profile, ""))
+
+	asset := baseAssetOf(bot.GetSymbol().GetValue(), bot.GetCurrency())
+	if limits.MaxExposurePerAsset > 0 && exposure[asset]+cost > limits.MaxExposurePerAsset {
+		return &RiskBreach{Limit: entity.RiskLimitAssetExposure, Asset: asset, Profile: profile, Value: exposure[asset] + cost, Threshold: limits.MaxExposurePerAsset}, nil
+	}
+	m.clearBreach(breachKey(entity.RiskLimitAssetExposure, profile, asset))
+	return nil, nil
+}
+
+// FlattenBreach returns the breach for which the open positions of the bots of profile must be sold, nil when they
+// may be kept
+func (m *RiskManager) FlattenBreach(profile string) (*RiskBreach, error) {
+	limits, err := m.Limits()
+	if err != nil || !limits.FlattenOnBreach {
+		return nil, err
+	}
+	if limits.KillSwitch {
+		return &RiskBreach{Limit: entity.RiskLimitKillSwitch, Flatten: true}, nil
+	}
+
+	breach, err := m.dailyLossBreach(limits, profile)
+	if breach != nil {
+		m.notifyBreach(breach, nil)
+	}
+	return breach, err
+}
+
+// Status returns the limits and how far the live bots of profile are from them
+func (m *RiskManager) Status(profile string) (*RiskStatus, error) {
+	limits, err := m.Limits()
+	if err != nil {
+		return nil, err
+	}
+	dailyProfitLoss, err := m.dailyProfitLoss(profile)
+	if err != nil {
+		return nil, err
+	}
+	openPositions, exposure, err := m.openPositions(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &RiskStatus{
+		Profile:          profile,
+		Limits:           limits,
+		DailyProfitLoss:  dailyProfitLoss,
+		OpenPositions:    openPositions,
+		ExposurePerAsset: exposure,
+		Breaches:         []RiskBreach{},
+	}
+	if limits.KillSwitch {
+		status.Breaches = append(status.Breaches, RiskBreach{Limit: entity.RiskLimitKillSwitch, Flatten: limits.FlattenOnBreach})
+	}
+	if limits.MaxDailyLoss > 0 && -dailyProfitLoss >= limits.MaxDailyLoss {
+		status.Breaches = append(status.Breaches, RiskBreach{Limit: entity.RiskLimitDailyLoss, Profile: profile, Value: -dailyProfitLoss, Threshold: limits.MaxDailyLoss, Flatten: limits.FlattenOnBreach})
+	}
+	if limits.MaxOpenPositions > 0 && openPositions >= limits.MaxOpenPositions {
+		status.Breaches = append(status.Breaches, RiskBreach{Limit: entity.RiskLimitOpenPositions, Profile: profile, Value: float64(openPositions), Threshold: float64(limits.MaxOpenPositions)})
+	}
+	for asset, cost := range exposure {
+		if limits.MaxExposurePerAsset > 0 && cost >= limits.MaxExposurePerAsset {
+			status.Breaches = append(status.Breaches, RiskBreach{Limit: entity.RiskLimitAssetExposure, Asset: asset, Profile: profile, Value: cost, Threshold: limits.MaxExposurePerAsset})
+		}
+	}
+	return status, nil
+}
+
+// dailyLossBreach returns the breach of the daily loss limit by the bots of profile, nil when it is not reached
+func (m *RiskManager) dailyLossBreach(limits entity.RiskLimits, profile string) (*RiskBreach, error) {
+	if limits.MaxDailyLoss <= 0 {
+		return nil, nil
+	}
+	dailyProfitLoss, err := m.dailyProfitLoss(profile)
+	if err != nil {
+		return nil, err
+	}
+	if loss := -dailyProfitLoss; loss >= limits.MaxDailyLoss {
+		return &RiskBreach{Limit: entity.RiskLimitDailyLoss, Profile: profile, Value: loss, Threshold: limits.MaxDailyLoss, Flatten: limits.FlattenOnBreach}, nil
+	}
+	return nil, nil
+}
+
+// dailyProfitLoss sums the realized P&L of the trades of the bots of profile closed since midnight UTC, only live
+// trades being in the ledger. The trades of deleted bots count on the default profile.
+func (m *RiskManager) dailyProfitLoss(profile string) (float64, error) {
+	now := m.now().UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	trades, _, err := m.tradeRepository.GetTradesWithFilters(repository.TradeFilter{From: midnight})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load the trades of the day: %v", err)
+	}
+	bots, err := m.tradingBotRepository.GetAllTradingBots()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load the trading bots: %v", err)
+	}
+	profileOfBot := make(map[string]string, len(bots))
+	for _, bot := range bots {
+		profileOfBot[bot.Id.GetValue()] = bot.GetExchangeProfile()
+	}
+
+	total := 0.0
+	for _, trade := range trades {
+		tradeProfile, known := profileOfBot[trade.GetTradingBotId().GetValue()]
+		if !known {
+			tradeProfile = entity.DefaultExchangeProfile
+		}
+		if tradeProfile == profile {
+			total += trade.GetProfitLoss()
+		}
+	}
+	return total, nil
+}
+
+// openPositions counts the open positions of the live bots of profile and their cost per base asset
+func (m *RiskManager) openPositions(profile string) (int, map[string]float64, error) {
+	bots, err := m.tradingBotRepository.GetAllTradingBots()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load the trading bots: %v", err)
+	}
+	count := 0
+	exposure := make(map[string]float64)
+	for _, bot := range bots {
+		if bot.IsPaper() || !bot.GetIsPositioned() || bot.GetExchangeProfile() != profile {
+			continue
+		}
+		count++
+		asset := baseAssetOf(bot.GetSymbol().GetValue(), bot.GetCurrency())
+		exposure[asset] += bot.GetEntryPrice() * bot.GetActualQuantityHeld()
+	}
+	return count, exposure, nil
+}
+
+func (m *RiskManager) loadLimits() (entity.RiskLimits, error) {
+	if m.limits == nil {
+		limits, err := m.limitsRepository.Get()
+		if err != nil {
+			return entity.RiskLimits{}, fmt.Errorf("failed to load risk limits: %v", err)
+		}
+		m.limits = &limits
+	}
+	return *m.limits, nil
+}
+
+func (m *RiskManager) saveLimits(limits entity.RiskLimits) error {
+	limits.UpdatedAt = m.now()
+	if err := m.limitsRepository.Save(limits); err != nil {
+		return fmt.Errorf("failed to save risk limits: %v", err)
+	}
+	m.limits = &limits
+	return nil
+}
+
+func (m *RiskManager) clearBreach(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.breached, key)
+}
+
+// breachKey identifies the breach of a limit by the bots of a profile, on an asset for the exposure limit
+func breachKey(limit, profile, asset string) string {
+	key := limit
+	if profile != "" {
+		key += "@" + profile
+	}
+	if asset != "" {
+		key += ":" + asset
+	}
+	return key
+}
+
+// notifyBreach logs the breach and publishes it as a risk.limit_breached event, once until the limit passes again
+func (m *RiskManager) notifyBreach(breach *RiskBreach, bot *entity.TradingBot) {
+	key := breachKey(breach.Limit, breach.Profile, breach.Asset)
+	m.mu.Lock()
+	alreadyNotified := m.breached[key]
+	m.breached[key] = true
+	m.mu.Unlock()
+	if alreadyNotified {
+		return
+	}
+
+	fmt.Printf("🛑 Risk limit breached: %v (flatten: %v)\n", breach, breach.Flatten)
+	if breach.Flatten && m.flattener != nil {
+		// Breaches are found during the ticks of the bots, whose loops the liquidations stop and restart
+		go m.flattener.FlattenPositions(breach)
+	}
+	if m.messageBroker == nil {
+		return
+	}
+
+	timestamp := m.now()
+	payload := map[string]interface{}{
+		"limit":     breach.Limit,
+		"asset":     breach.Asset,
+		"profile":   breach.Profile,
+		"reason":    breach.Error(),
+		"value":     breach.Value,
+		"threshold": breach.Threshold,
+		"flatten":   breach.Flatten,
+		"timestamp": timestamp,
+	}
+	if bot != nil {
+		payload["bot_id"] = bot.Id.GetValue()
+		payload["symbol"] = bot.GetSymbol().GetValue()
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Printf("⚠️ Failed to marshal risk event payload: %v\n", err)
+		return
+	}
+
+	message := queue.Message{
+		RoutingKey: "risk.limit_breached",
+		Payload:    payloadBytes,
+		Headers: map[string]string{
+			"timestamp": timestamp.Format(time.RFC3339),
+			"limit":     breach.Limit,
+		},
+	}
+	if err := m.messageBroker.Publish(m.exchangeName, message); err != nil {
+		fmt.Printf("⚠️ Failed to emit risk event: %v\n", err)
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/repository"
+	"testing"
+	"time"
+)
+
+type riskFixture struct {
+	manager    *RiskManager
+	limitsRepo *repository.RiskLimitsRepositoryInMemory
+	botRepo    *repository.TradeBotRepositoryInMemory
+	tradeRepo  *repository.TradeRepositoryInMemory
+	broker     *recordingMessageBroker
+}
+
+func setupRiskManager(t *testing.T, limits entity.RiskLimits) *riskFixture {
+	f := &riskFixture{
+		limitsRepo: repository.NewRiskLimitsRepositoryInMemory(),
+		botRepo:    repository.NewTradeBotRepositoryInMemory(),
+		tradeRepo:  repository.NewTradeRepositoryInMemory(),
+		broker:     &recordingMessageBroker{},
+	}
+	if err := f.limitsRepo.Save(limits); err != nil {
+		t.Fatalf("failed to save limits: %v", err)
+	}
+	f.manager = NewRiskManager(f.limitsRepo, f.botRepo, f.tradeRepo, f.broker, "test_exchange")
+	f.manager.now = func() time.Time { return time.Date(2025, 7, 10, 15, 0, 0, 0, time.UTC) }
+	return f
+}
+
+// newBot saves a live bot of symbol, holding 2 coins entered at entryPrice when it is above zero
+func (f *riskFixture) newBot(t *testing.T, symbol string, entryPrice float64) *entity.TradingBot {
+	sym, _ := vo.NewSymbol(symbol)
+	bot := entity.NewTradingBot(sym, 2.0, entity.NewMovingAverageStrategy(5, 20), 60, 1000, 300, "BRL", 0.1, 2.0, true)
+	if entryPrice > 0 {
+		if err := bot.OpenPosition(entity.OrderFill{AveragePrice: entryPrice, ExecutedQuantity: 2.0, NetQuantity: 2.0}, time.Now()); err != nil {
+			t.Fatalf("failed to open position: %v", err)
+		}
+	}
+	if err := f.botRepo.Save(bot); err != nil {
+		t.Fatalf("failed to save bot: %v", err)
+	}
+	return bot
+}
+
+// closeTrade records a trade of bot closed at closedAt with profitLoss
+func (f *riskFixture) closeTrade(bot *entity.TradingBot, profitLoss float64, closedAt time.Time) {
+	trade := entity.RestoreTrade(vo.NewEntityId(), bot.Id, bot.GetSymbol().GetValue(), "", "exit", 2.0, 100, 100, 0, 0, profitLoss, 0, "BRL", closedAt.Add(-time.Hour), closedAt)
+	_ = f.tradeRepo.Save(trade)
+}
+
+func TestRiskManager_BlocksEntriesOverPositionAndExposureLimits(t *testing.T) {
+	f := setupRiskManager(t, entity.RiskLimits{MaxOpenPositions: 2, MaxExposurePerAsset: 500})
+	f.newBot(t, "SOLBRL", 100)
+	candidate := f.newBot(t, "SOLBRL", 0)
+
+	// 200 BRL of SOL held, 300 more reach the exposure limit without exceeding it
+	if breach, err := f.manager.CheckEntry(candidate, 300); err != nil || breach != nil {
+		t.Fatalf("expected the entry to be allowed, got %v (%v)", breach, err)
+	}
+	breach, _ := f.manager.CheckEntry(candidate, 301)
+	if breach == nil || breach.Limit != entity.RiskLimitAssetExposure || breach.Asset != "SOL" || breach.Value != 501 {
+		t.Fatalf("expected the SOL exposure to block the entry, got %v", breach)
+	}
+
+	// Another position reaches the limit of open positions, whatever the asset
+	f.newBot(t, "BTCBRL", 1000)
+	other := f.newBot(t, "ETHBRL", 0)
+	for i := 0; i < 2; i++ {
+		breach, _ = f.manager.CheckEntry(other, 100)
+		if breach == nil || breach.Limit != entity.RiskLimitOpenPositions {
+			t.Fatalf("expected the open positions to block the entry, got %v", breach)
+		}
+	}
+
+	// Each breach is published once
+	if len(f.broker.messages) != 2 || f.broker.messages[0].RoutingKey != "risk.limit_breached" || f.broker.messages[1].Headers["limit"] != entity.RiskLimitOpenPositions {
+		t.Errorf("expected one event per breached limit, got %v", f.broker.messages)
+	}
+
+	// Paper bots neither count nor are blocked
+	paper := f.newBot(t, "ETHBRL", 0)
+	paper.SetMode(entity.TradingModePaper)
+	if breach, _ := f.manager.CheckEntry(paper, 100); breach != nil {
+		t.Errorf("expected paper bots to be ignored, got %v", breach)
+	}
+}
+
+func TestRiskManager_DailyLossFlattensPositions(t *testing.T) {
+	f := setupRiskManager(t, entity.RiskLimits{MaxDailyLoss: 100, FlattenOnBreach: true})
+	bot := f.newBot(t, "SOLBRL", 0)
+	today := f.manager.now()
+
+	// Yesterday's loss does not count, today's profits offset the losses
+	f.closeTrade(bot, -500, today.Add(-24*time.Hour))
+	f.closeTrade(bot, -120, today.Add(-2*time.Hour))
+	f.closeTrade(bot, 30, today.Add(-time.Hour))
+	if breach, _ := f.manager.CheckEntry(bot, 100); breach != nil {
+		t.Fatalf("expected a daily loss of 90 to allow entries, got %v", breach)
+	}
+	if breach, _ := f.manager.FlattenBreach(entity.DefaultExchangeProfile); breach != nil {
+		t.Fatalf("expected the positions to be kept, got %v", breach)
+	}
+
+	f.closeTrade(bot, -10, today.Add(-time.Minute))
+	breach, _ := f.manager.CheckEntry(bot, 100)
+	if breach == nil || breach.Limit != entity.RiskLimitDailyLoss || breach.Value != 100 || !breach.Flatten {
+		t.Fatalf("expected the daily loss to block the entry, got %v", breach)
+	}
+	if breach, _ := f.manager.FlattenBreach(entity.DefaultExchangeProfile); breach == nil || breach.Limit != entity.RiskLimitDailyLoss {
+		t.Errorf("expected the daily loss to flatten the positions, got %v", breach)
+	}
+
+	status, err := f.manager.Status(entity.DefaultExchangeProfile)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.DailyProfitLoss != -100 || len(status.Breaches) != 1 || status.Breaches[0].Limit != entity.RiskLimitDailyLoss {
+		t.Errorf("expected the daily loss breach in the status, got %+v", status)
+	}
+}
+
+func TestRiskManager_CountsTheLimitsPerExchangeProfile(t *testing.T) {
+	f := setupRiskManager(t, entity.RiskLimits{MaxDailyLoss: 100, MaxOpenPositions: 1, FlattenOnBreach: true})
+	testnetBot := f.newBot(t, "SOLBRL", 100)
+	testnetBot.SetExchangeProfile("testnet")
+	_ = f.botRepo.Update(testnetBot)
+	productionBot := f.newBot(t, "SOLBRL", 0)
+
+	// The testnet account loses and holds a position, the production bots may still enter
+	f.closeTrade(testnetBot, -150, f.manager.now().Add(-time.Hour))
+	if breach, _ := f.manager.CheckEntry(productionBot, 100); breach != nil {
+		t.Fatalf("expected the testnet losses to leave the production bots trading, got %v", breach)
+	}
+	if breach, _ := f.manager.FlattenBreach(entity.DefaultExchangeProfile); breach != nil {
+		t.Errorf("expected the production positions to be kept, got %v", breach)
+	}
+
+	breach, _ := f.manager.FlattenBreach("testnet")
+	if breach == nil || breach.Limit != entity.RiskLimitDailyLoss || breach.Profile != "testnet" {
+		t.Fatalf("expected the testnet daily loss to flatten the testnet positions, got %v", breach)
+	}
+	status, err := f.manager.Status("testnet")
+	if err != nil || status.DailyProfitLoss != -150 || status.OpenPositions != 1 || len(status.Breaches) != 2 {
+		t.Errorf("expected the testnet status to hold its loss and position, got %+v (%v)", status, err)
+	}
+	status, err = f.manager.Status(entity.DefaultExchangeProfile)
+	if err != nil || status.DailyProfitLoss != 0 || status.OpenPositions != 0 || len(status.Breaches) != 0 {
+		t.Errorf("expected a clean production status, got %+v (%v)", status, err)
+	}
+}
+
+// channelFlattener receives the breaches whose positions must be flattened
+type channelFlattener chan *RiskBreach
+
+func (f channelFlattener) FlattenPositions(breach *RiskBreach) {
+	f <- breach
+}
+
+func TestRiskManager_FlattensPositionsOnceWhenTheLimitTrips(t *testing.T) {
+	f := setupRiskManager(t, entity.RiskLimits{MaxDailyLoss: 100, FlattenOnBreach: true})
+	flattener := make(channelFlattener, 2)
+	f.manager.WithPositionFlattener(flattener)
+	bot := f.newBot(t, "SOLBRL", 0)
+	f.closeTrade(bot, -100, f.manager.now().Add(-time.Hour))
+
+	for i := 0; i < 2; i++ {
+		if breach, _ := f.manager.CheckEntry(bot, 100); breach == nil {
+			t.Fatal("expected the daily loss to block the entry")
+		}
+	}
+	select {
+	case breach := <-flattener:
+		if breach.Limit != entity.RiskLimitDailyLoss || breach.Profile != entity.DefaultExchangeProfile {
+			t.Errorf("expected the daily loss of the default profile to be flattened, got %v", breach)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the positions to be flattened")
+	}
+	select {
+	case breach := <-flattener:
+		t.Errorf("expected the positions to be flattened once, got %v again", breach)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRiskManager_KillSwitch(t *testing.T) {
+	f := setupRiskManager(t, entity.RiskLimits{})
+	bot := f.newBot(t, "SOLBRL", 0)
+
+	limits, err := f.manager.UpdateLimits(entity.RiskLimits{MaxOpenPositions: 5})
+	if err != nil || limits.MaxOpenPositions != 5 {
+		t.Fatalf("expected the limits to be updated, got %+v (%v)", limits, err)
+	}
+	if _, err := f.manager.SetKillSwitch(true); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(f.broker.messages) != 1 || f.broker.messages[0].Headers["limit"] != entity.RiskLimitKillSwitch {
+		t.Fatalf("expected the kill switch to be published, got %v", f.broker.messages)
+	}
+
+	if breach, _ := f.manager.CheckEntry(bot, 10); breach == nil || breach.Limit != entity.RiskLimitKillSwitch {
+		t.Errorf("expected the kill switch to block entries, got %v", breach)
+	}
+	if breach, _ := f.manager.FlattenBreach(entity.DefaultExchangeProfile); breach != nil {
+		t.Errorf("expected positions to be kept without flatten on breach, got %v", breach)
+	}
+
+	// Updating the limits keeps the kill switch, which survives a restart
+	if _, err := f.manager.UpdateLimits(entity.RiskLimits{MaxOpenPositions: 3}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	stored, _ := f.limitsRepo.Get()
+	if !stored.KillSwitch || stored.MaxOpenPositions != 3 || stored.UpdatedAt.IsZero() {
+		t.Errorf("expected the kill switch and limits to be persisted, got %+v", stored)
+	}
+
+	if _, err := f.manager.SetKillSwitch(false); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if breach, _ := f.manager.CheckEntry(bot, 10); breach != nil {
+		t.Errorf("expected entries to be allowed again, got %v", breach)
+	}
+}
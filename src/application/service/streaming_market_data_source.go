@@ -0,0 +1,182 @@
+package service
+
+import (
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// KlineStream streams kline updates of a symbol and interval, implemented by the adapter of each venue
+type KlineStream interface {
+	Subscribe(symbol, interval string, onKline func(kline vo.Kline, isFinal bool), onConnect func()) (stop func())
+}
+
+// StreamingMarketDataSource implements MarketDataSource on kline WebSocket streams.
+// Klines are seeded from the kline cache and then kept up to date by the stream, and subscribers
+// are notified on every candle close so bots decide right when a candle closes instead of polling.
+type StreamingMarketDataSource struct {
+	cache   *KlineCache
+	stream  KlineStream
+	mu      sync.Mutex
+	streams map[string]*klineStreamState
+}
+
+type klineStreamState struct {
+	symbol          string
+	intervalSeconds int
+	klines          []vo.Kline // Latest window: closed candles plus the forming one
+	needsResync     bool       // Set on (re)connection, candles may have been missed
+	subscribers     map[int]chan vo.Kline
+	nextSubscriber  int
+	stop            func()
+}
+
+// NewStreamingMarketDataSource creates a streaming data source that seeds its klines from cache
+func NewStreamingMarketDataSource(cache *KlineCache, stream KlineStream) *StreamingMarketDataSource {
+	return &StreamingMarketDataSource{
+		cache:   cache,
+		stream:  stream,
+		streams: make(map[string]*klineStreamState),
+	}
+}
+
+// SubscribeCandleCloses starts streaming the pair if needed and returns a channel receiving every closed candle.
+// Bots on the same pair share one stream, which is closed when the last subscriber unsubscribes.
+func (s *StreamingMarketDataSource) SubscribeCandleCloses(symbol string, intervalSeconds int) (<-chan vo.Kline, func(), error) {
+	interval, err := exchange.SecondsToInterval(intervalSeconds)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid interval %d seconds: %v", intervalSeconds, err)
+	}
+
+	key := klineKey(symbol, intervalSeconds)
+
+	s.mu.Lock()
+	state, exists := s.streams[key]
+	if !exists {
+		state = &klineStreamState{
+			symbol:          symbol,
+			intervalSeconds: intervalSeconds,
+			needsResync:     true,
+			subscribers:     make(map[int]chan vo.Kline),
+		}
+		s.streams[key] = state
+	}
+
+	// Buffer one close: if the bot is still busy with the previous candle, it catches up on the latest one
+	closes := make(chan vo.Kline, 1)
+	id := state.nextSubscriber
+	state.nextSubscriber++
+	state.subscribers[id] = closes
+	s.mu.Unlock()
+
+	if !exists {
+		fmt.Printf("📡 [%s] Streaming %s klines\n", symbol, interval)
+		stop := s.stream.Subscribe(symbol, interval,
+			func(kline vo.Kline, isFinal bool) { s.onKline(state, kline, isFinal) },
+			func() { s.markForResync(state) })
+		s.mu.Lock()
+		state.stop = stop
+		unsubscribed := s.streams[key] != state
+		s.mu.Unlock()
+		if unsubscribed {
+			stop()
+		}
+	}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() { s.unsubscribe(key, state, id) })
+	}
+	return closes, unsubscribe, nil
+}
+
+// GetMarketData returns the streamed klines, resyncing from the kline cache after a (re)connection.
+// Pairs that are not being streamed are served by the kline cache.
+func (s *StreamingMarketDataSource) GetMarketData(symbol string, intervalSeconds int) ([]vo.Kline, error) {
+	s.mu.Lock()
+	state, exists := s.streams[klineKey(symbol, intervalSeconds)]
+	if !exists {
+		s.mu.Unlock()
+		return s.cache.GetKlines(symbol, intervalSeconds)
+	}
+	needsResync := state.needsResync || len(state.klines) == 0
+	s.mu.Unlock()
+
+	if needsResync {
+		fetched, err := s.cache.GetKlines(symbol, intervalSeconds)
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		// Candles streamed meanwhile are fresher than the fetched ones
+		var streamed []vo.Kline
+		if len(fetched) > 0 {
+			for _, kline := range state.klines {
+				if kline.CloseTime() >= fetched[len(fetched)-1].CloseTime() {
+					streamed = append(streamed, kline)
+				}
+			}
+		}
+		state.klines = mergeKlines(fetched, streamed, s.cache.limit)
+		state.needsResync = false
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyKlines(state.klines), nil
+}
+
+// GetCurrentTime returns the current system time for live trading
+func (s *StreamingMarketDataSource) GetCurrentTime() time.Time {
+	return time.Now()
+}
+
+func (s *StreamingMarketDataSource) onKline(state *klineStreamState, kline vo.Kline, isFinal bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state.klines = mergeKlines(state.klines, []vo.Kline{kline}, s.cache.limit)
+	if !isFinal {
+		return
+	}
+
+	for _, closes := range state.subscribers {
+		select {
+		case closes <- kline:
+		default:
+			// Drop the stale close still waiting and deliver the latest one
+			select {
+			case <-closes:
+			default:
+			}
+			closes <- kline
+		}
+	}
+}
+
+func (s *StreamingMarketDataSource) markForResync(state *klineStreamState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state.needsResync = true
+}
+
+func (s *StreamingMarketDataSource) unsubscribe(key string, state *klineStreamState, id int) {
+	s.mu.Lock()
+	delete(state.subscribers, id)
+	if len(state.subscribers) > 0 {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.streams, key)
+	stop := state.stop
+	s.mu.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+	fmt.Printf("📡 [%s] Stopped streaming klines\n", state.symbol)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/external"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// newStreamingTestSource streams from a local fake WebSocket server and seeds from a fake exchange at now
+func newStreamingTestSource(t *testing.T, now time.Time) (*StreamingMarketDataSource, *external.BinanceWsServerFake, *exchangeKlinesClient) {
+	server := external.NewBinanceWsServerFake()
+	originalURL := binance.BaseWsMainURL
+	binance.BaseWsMainURL = server.URL()
+	t.Cleanup(func() {
+		binance.BaseWsMainURL = originalURL
+		server.Close()
+	})
+
+	client := &exchangeKlinesClient{FakeExchange: external.NewFakeExchange(), now: now}
+	cache := NewKlineCacheWithOptions(client, 100, 5*time.Second, func() time.Time { return now })
+	stream := external.NewBinanceKlineStreamWithOptions(binance.WsKlineServe, 10*time.Millisecond, 50*time.Millisecond)
+	return NewStreamingMarketDataSource(cache, stream), server, client
+}
+
+func receiveClose(t *testing.T, closes <-chan vo.Kline) vo.Kline {
+	select {
+	case kline := <-closes:
+		return kline
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a candle close notification")
+		return vo.Kline{}
+	}
+}
+
+func TestStreamingMarketDataSource_NotifiesOnCandleClose(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	source, server, client := newStreamingTestSource(t, now)
+
+	closes, unsubscribe, err := source.SubscribeCandleCloses("SOLBRL", 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer unsubscribe()
+	if err := server.WaitForConnections(1, 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if streams := server.GetStreams(); streams[0] != "solbrl@kline_1m" {
+		t.Errorf("expected the solbrl@kline_1m stream, got %v", streams)
+	}
+
+	seeded, err := source.GetMarketData("SOLBRL", 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	forming := seeded[len(seeded)-1]
+
+	// A forming candle update refreshes the window but does not notify
+	update, _ := vo.NewKline(100, 150, 160, 90, 5, forming.CloseTime())
+	if err := server.SendKline("SOLBRL", "1m", update, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	closed, _ := vo.NewKline(100, 155, 160, 90, 6, forming.CloseTime())
+	if err := server.SendKline("SOLBRL", "1m", closed, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	received := receiveClose(t, closes)
+	if received.CloseTime() != forming.CloseTime() || received.Close() != 155 {
+		t.Errorf("expected the closed candle at %d with close 155, got %d with close %f", forming.CloseTime(), received.CloseTime(), received.Close())
+	}
+
+	klines, err := source.GetMarketData("SOLBRL", 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(klines) != 100 {
+		t.Fatalf("expected the window to stay at 100 klines, got %d", len(klines))
+	}
+	if last := klines[len(klines)-1]; last.CloseTime() != forming.CloseTime() || last.Close() != 155 {
+		t.Errorf("expected the streamed close to replace the forming candle, got close %f", last.Close())
+	}
+	if client.calls != 1 {
+		t.Errorf("expected klines to be served from the stream after seeding, got %d REST calls", client.calls)
+	}
+}
+
+func TestStreamingMarketDataSource_ResyncsAfterReconnect(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	source, server, client := newStreamingTestSource(t, now)
+
+	closes, unsubscribe, err := source.SubscribeCandleCloses("SOLBRL", 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer unsubscribe()
+	if err := server.WaitForConnections(1, 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.GetMarketData("SOLBRL", 60); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Three candles close while the connection is down
+	server.DropConnections()
+	client.mu.Lock()
+	client.now = now.Add(3 * time.Minute)
+	client.mu.Unlock()
+	source.cache.now = func() time.Time { return now.Add(3 * time.Minute) }
+
+	if err := server.WaitForConnections(2, 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next close arrives on the new connection, the missed candles come from the resync
+	nowMs := now.Add(3 * time.Minute).UnixMilli()
+	formingCloseTime := nowMs - nowMs%60000 + 59999
+	closed, _ := vo.NewKline(100, 200, 210, 90, 6, formingCloseTime)
+	if err := server.SendKline("SOLBRL", "1m", closed, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	receiveClose(t, closes)
+
+	klines, err := source.GetMarketData("SOLBRL", 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.calls != 2 {
+		t.Errorf("expected a resync from the exchange after reconnecting, got %d REST calls", client.calls)
+	}
+	for i := 1; i < len(klines); i++ {
+		if klines[i].CloseTime()-klines[i-1].CloseTime() != 60000 {
+			t.Fatalf("expected contiguous candles after the resync, gap at %d", i)
+		}
+	}
+	if last := klines[len(klines)-1]; last.CloseTime() != formingCloseTime || last.Close() != 200 {
+		t.Errorf("expected the streamed close to be kept over the fetched candle, got close %f", last.Close())
+	}
+}
+
+func TestStreamingMarketDataSource_SharesOneStreamPerPair(t *testing.T) {
+	source, server, _ := newStreamingTestSource(t, time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC))
+
+	first, unsubscribeFirst, _ := source.SubscribeCandleCloses("SOLBRL", 60)
+	second, unsubscribeSecond, _ := source.SubscribeCandleCloses("SOLBRL", 60)
+	if err := server.WaitForConnections(1, 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	closed, _ := vo.NewKline(100, 155, 160, 90, 6, 1704110459999)
+	if err := server.SendKline("SOLBRL", "1m", closed, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	receiveClose(t, first)
+	receiveClose(t, second)
+	if count := server.GetConnectionCount(); count != 1 {
+		t.Errorf("expected bots on the same pair to share one connection, got %d", count)
+	}
+
+	unsubscribeFirst()
+	unsubscribeSecond()
+
+	// Not streamed anymore, served by the cache
+	source.mu.Lock()
+	streams := len(source.streams)
+	source.mu.Unlock()
+	if streams != 0 {
+		t.Errorf("expected the stream to stop after the last unsubscribe, got %d streams", streams)
+	}
+
+	if _, _, err := source.SubscribeCandleCloses("SOLBRL", 120); err == nil {
+		t.Error("expected error for an unsupported interval")
+	}
+}
@@ -12,7 +12,18 @@ type TradingExecutionContext interface {
 	
 	// OnDecisionMade is called when a trading decision is made (for logging/tracking)
 	OnDecisionMade(decisionLog *entity.TradingDecisionLog) error
-	
-	// ShouldContinue returns whether the trading loop should continue
-	ShouldContinue() bool
-}
\ No newline at end of file
+}
+
+// PositionReconciler is implemented by execution contexts whose positions can be closed on the exchange while the
+// bot is not looking, e.g. by an OCO order
+type PositionReconciler interface {
+	// ReconcilePosition syncs the bot's position with the exchange before it makes a decision
+	ReconcilePosition(bot *entity.TradingBot, timestamp time.Time) error
+}
+
+// PositionLiquidator is implemented by execution contexts that can sell a position right away at market, whatever
+// the bot's order execution mode
+type PositionLiquidator interface {
+	// LiquidatePosition sells the bot's whole position, failing when it could not be sold
+	LiquidatePosition(bot *entity.TradingBot, currentPrice float64, timestamp time.Time) error
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"crypgo-machine/src/application/service"
 	"crypgo-machine/src/domain/entity"
 	"crypgo-machine/src/domain/vo"
 	"fmt"
@@ -27,6 +28,17 @@ type InputBacktestStrategy struct {
 	EndDate                time.Time
 	TradingFees            float64 // Percentage fee per trade (e.g., 0.1 for 0.1%)
 	MinimumProfitThreshold float64 // Minimum profit % required to sell (0 = sell at any profit)
+	TrailingStopPercent    float64 // Bot-level trailing stop % from the highest price since entry (0 = disabled)
+	TakeProfitPercent      float64 // Bot-level take profit % (0 = disabled)
+	MaxHoldingSeconds      int     // Bot-level max holding time in seconds (0 = disabled)
+	RiskPerTradePercent    float64 // ATR sizing: % of initial capital risked per trade (0 = size by TradeAmount)
+	ATRPeriod              int     // ATR sizing: klines in the ATR (0 = default)
+	ATRMultiplier          float64 // ATR sizing: stop distance in ATRs (0 = default)
+	CooldownCandles         int // Candles to wait after any exit (0 = disabled)
+	CooldownMinutes         int // Minutes to wait after any exit (0 = disabled)
+	StopLossCooldownCandles int // Candles to wait after a stop-loss exit (0 = disabled)
+	StopLossCooldownMinutes int // Minutes to wait after a stop-loss exit (0 = disabled)
+	MaxTradesPerDay         int // Positions opened per UTC day (0 = unlimited)
 }
 
 type BacktestSimulator struct {
@@ -37,6 +49,10 @@ type BacktestSimulator struct {
 	tradingFees            float64
 	tradeAmount            float64 // Fixed amount per trade, 0 means use all available capital
 	minimumProfitThreshold float64 // Minimum profit % required to sell
+	exitRules              entity.ExitRules
+	positionSizing         entity.PositionSizing
+	cooldownRules          entity.CooldownRules
+	currentATR             float64 // ATR at the current kline, used by ATR risk sizing
 	isPositioned           bool
 }
 
@@ -70,6 +86,21 @@ func (uc *BacktestStrategyUseCase) Execute(input InputBacktestStrategy) (*entity
 		return nil, fmt.Errorf("failed to create backtest result: %w", err)
 	}
 
+	exitRules, err := entity.NewExitRules(input.TrailingStopPercent, input.TakeProfitPercent, input.MaxHoldingSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	positionSizing, err := entity.NewPositionSizing(input.RiskPerTradePercent, input.ATRPeriod, input.ATRMultiplier)
+	if err != nil {
+		return nil, err
+	}
+
+	cooldownRules, err := entity.NewCooldownRules(input.CooldownCandles, input.CooldownMinutes, input.StopLossCooldownCandles, input.StopLossCooldownMinutes, input.MaxTradesPerDay)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create simulator
 	simulator := &BacktestSimulator{
 		result:                 result,
@@ -77,6 +108,9 @@ func (uc *BacktestStrategyUseCase) Execute(input InputBacktestStrategy) (*entity
 		tradingFees:            input.TradingFees,
 		tradeAmount:            input.TradeAmount,
 		minimumProfitThreshold: input.MinimumProfitThreshold,
+		exitRules:              exitRules,
+		positionSizing:         positionSizing,
+		cooldownRules:          cooldownRules,
 		isPositioned:           false,
 	}
 
@@ -129,133 +163,24 @@ func (uc *BacktestStrategyUseCase) validateInput(input InputBacktestStrategy) er
 }
 
 func (uc *BacktestStrategyUseCase) createStrategy(strategyName string, params map[string]interface{}) (entity.TradingStrategy, error) {
-	switch strategyName {
-	case "MovingAverage":
-		// Default parameters - conservative for reliable signals
-		fastWindow := 7
-		slowWindow := 40
-		minimumSpreadValue := 0.1 // 0.1% spread requirement
-		stoplossThreshold := 0.0
-		
-		// Override with provided parameters if available
-		if params != nil {
-			if fw, ok := params["FastWindow"]; ok {
-				if fwFloat, ok := fw.(float64); ok {
-					fastWindow = int(fwFloat)
-				}
-			}
-			if sw, ok := params["SlowWindow"]; ok {
-				if swFloat, ok := sw.(float64); ok {
-					slowWindow = int(swFloat)
-				}
-			}
-			if ms, ok := params["MinimumSpread"]; ok {
-				if msFloat, ok := ms.(float64); ok {
-					minimumSpreadValue = msFloat
-				}
-			}
-			if st, ok := params["StoplossThreshold"]; ok {
-				if stFloat, ok := st.(float64); ok {
-					stoplossThreshold = stFloat
-				}
-			}
-		}
-		
-		// Validate parameters
-		if fastWindow <= 0 || slowWindow <= 0 || fastWindow >= slowWindow {
-			return nil, fmt.Errorf("invalid MovingAverage parameters: FastWindow (%d) must be positive and less than SlowWindow (%d)", fastWindow, slowWindow)
-		}
-		
-		if minimumSpreadValue < 0 {
-			return nil, fmt.Errorf("invalid MinimumSpread parameter: %f (must be non-negative)", minimumSpreadValue)
-		}
-		
-		minimumSpread, err := vo.NewMinimumSpread(minimumSpreadValue)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create MinimumSpread: %w", err)
-		}
-		
-		// Create with stoploss if provided, otherwise use spread-based constructor
-		if stoplossThreshold > 0 {
-			return entity.NewMovingAverageStrategyWithStoploss(fastWindow, slowWindow, minimumSpread, stoplossThreshold), nil
-		} else {
-			return entity.NewMovingAverageStrategyWithSpread(fastWindow, slowWindow, minimumSpread), nil
-		}
-		
-	case "RSI":
-		// Default RSI parameters
-		period := 14
-		oversoldThreshold := 30.0
-		overboughtThreshold := 70.0
-		minimumSpreadValue := 0.1
-		stoplossThreshold := 0.0
-		
-		// Override with provided parameters if available
-		if params != nil {
-			if p, ok := params["Period"]; ok {
-				if pFloat, ok := p.(float64); ok {
-					period = int(pFloat)
-				}
-			}
-			if ot, ok := params["OversoldThreshold"]; ok {
-				if otFloat, ok := ot.(float64); ok {
-					oversoldThreshold = otFloat
-				}
-			}
-			if obt, ok := params["OverboughtThreshold"]; ok {
-				if obtFloat, ok := obt.(float64); ok {
-					overboughtThreshold = obtFloat
-				}
-			}
-			if ms, ok := params["MinimumSpread"]; ok {
-				if msFloat, ok := ms.(float64); ok {
-					minimumSpreadValue = msFloat
-				}
-			}
-			if st, ok := params["StoplossThreshold"]; ok {
-				if stFloat, ok := st.(float64); ok {
-					stoplossThreshold = stFloat
-				}
-			}
-		}
-		
-		// Validate RSI parameters
-		if period <= 0 {
-			return nil, fmt.Errorf("invalid RSI period: %d (must be positive)", period)
-		}
-		if oversoldThreshold <= 0 || oversoldThreshold >= 100 {
-			return nil, fmt.Errorf("invalid OversoldThreshold: %f (must be between 0 and 100)", oversoldThreshold)
-		}
-		if overboughtThreshold <= 0 || overboughtThreshold >= 100 {
-			return nil, fmt.Errorf("invalid OverboughtThreshold: %f (must be between 0 and 100)", overboughtThreshold)
-		}
-		if oversoldThreshold >= overboughtThreshold {
-			return nil, fmt.Errorf("OversoldThreshold (%f) must be less than OverboughtThreshold (%f)", oversoldThreshold, overboughtThreshold)
-		}
-		
-		// Create RSI strategy
-		minimumSpread, err := vo.NewMinimumSpread(minimumSpreadValue)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create MinimumSpread: %w", err)
-		}
-		
-		if stoplossThreshold > 0 {
-			return entity.NewRSIStrategyWithStoploss(period, oversoldThreshold, overboughtThreshold, minimumSpread, stoplossThreshold), nil
-		} else if oversoldThreshold != 30.0 || overboughtThreshold != 70.0 || minimumSpreadValue != 0.1 {
-			return entity.NewRSIStrategyWithCustomThresholds(period, oversoldThreshold, overboughtThreshold, minimumSpread), nil
-		} else {
-			return entity.NewRSIStrategy(period), nil
-		}
-		
-	default:
+	if !entity.IsStrategyRegistered(strategyName) {
 		return nil, fmt.Errorf("unsupported strategy: %s", strategyName)
 	}
+	return entity.NewStrategyFromParams(strategyName, params)
 }
 
 func (uc *BacktestStrategyUseCase) runSimulation(simulator *BacktestSimulator, historicalData []vo.Kline) error {
 	// Create a dummy trading bot for strategy decisions - SHARED across all iterations
+	// Its interval is the klines' so cooldowns in candles last as long as in live trading
+	baseIntervalSeconds := service.InferIntervalSeconds(historicalData)
+	botIntervalSeconds := baseIntervalSeconds
+	if botIntervalSeconds <= 0 {
+		botIntervalSeconds = 60
+	}
 	symbol, _ := vo.NewSymbol("SOLBRL") // This will be overridden by the actual symbol
-	dummyBot := entity.NewTradingBot(symbol, 1.0, simulator.strategy, 60, 10000.0, 1000.0, "BRL", 0.001, simulator.minimumProfitThreshold, false)
+	dummyBot := entity.NewTradingBot(symbol, 1.0, simulator.strategy, botIntervalSeconds, 10000.0, 1000.0, "BRL", 0.001, simulator.minimumProfitThreshold, false)
+	dummyBot.SetExitRules(simulator.exitRules)
+	dummyBot.SetCooldownRules(simulator.cooldownRules)
 
 	// CRITICAL FIX: Start the bot so it can properly track position state
 	err := dummyBot.Start()
@@ -263,6 +188,9 @@ func (uc *BacktestStrategyUseCase) runSimulation(simulator *BacktestSimulator, h
 		return fmt.Errorf("failed to start dummy bot: %w", err)
 	}
 
+	// Higher timeframes are resampled from the closed klines seen so far, so strategies never look ahead
+	requiredTimeframes := entity.RequiredTimeframes(simulator.strategy)
+
 	// Process each data point
 	for i, kline := range historicalData {
 		// Ensure we have enough data for the strategy
@@ -279,9 +207,25 @@ func (uc *BacktestStrategyUseCase) runSimulation(simulator *BacktestSimulator, h
 
 		// CRITICAL FIX: Ensure both states are synchronized BEFORE strategy decision
 		uc.syncPositionStates(simulator, dummyBot)
+		simulator.currentATR = simulator.positionSizing.ATR(windowData)
 
-		// Get strategy decision - CRITICAL FIX: Pass reference, not copy
-		analysisResult := simulator.strategy.Decide(windowData, dummyBot)
+		timeframes := make(entity.Timeframes, len(requiredTimeframes))
+		for _, intervalSeconds := range requiredTimeframes {
+			resampled, err := service.ResampleClosedKlines(windowData, baseIntervalSeconds, intervalSeconds, 100)
+			if err != nil {
+				return fmt.Errorf("failed to build %ds timeframe: %w", intervalSeconds, err)
+			}
+			timeframes[intervalSeconds] = resampled
+		}
+
+		// Bot-level exit rules run before the strategy, same as live trading
+		dummyBot.TrackPosition(currentPrice, currentTime)
+		analysisResult := dummyBot.EvaluateExitRules(currentPrice, currentTime)
+		if analysisResult == nil {
+			// Get strategy decision - CRITICAL FIX: Pass reference, not copy
+			analysisResult = entity.DecideStrategy(simulator.strategy, windowData, timeframes, dummyBot)
+		}
+		analysisResult = dummyBot.ApplyCooldown(analysisResult, currentTime)
 		
 		// DEBUG: Log critical state for sell decisions
 		if analysisResult.Decision == entity.Sell && simulator.isPositioned {
@@ -320,10 +264,19 @@ func (uc *BacktestStrategyUseCase) runSimulation(simulator *BacktestSimulator, h
 		}
 
 		// Execute trading decision
+		wasPositioned := simulator.isPositioned
 		err := uc.executeDecision(simulator, analysisResult.Decision, currentPrice, currentTime, analysisResult.AnalysisData)
 		if err != nil {
 			return fmt.Errorf("failed to execute decision at %s: %w", currentTime, err)
 		}
+
+		// Entries and exits feed the cooldown rules, same as live trading
+		if !wasPositioned && simulator.isPositioned {
+			dummyBot.RecordEntry(currentTime)
+		} else if wasPositioned && !simulator.isPositioned {
+			reason, _ := analysisResult.AnalysisData["reason"].(string)
+			dummyBot.RecordExit(reason, currentTime)
+		}
 	}
 
 	// Check final position - apply profit protection (never close at loss)
@@ -424,6 +377,12 @@ func (uc *BacktestStrategyUseCase) executeDecision(
 			feeAdjustedPrice := price * (1 + simulator.tradingFees/100)
 			quantity := amountToUse / feeAdjustedPrice
 
+			// ATR risk sizing uses the amount above as its cap
+			if atrQuantity := simulator.positionSizing.Quantity(simulator.result.GetInitialCapital(), feeAdjustedPrice, simulator.currentATR, amountToUse); atrQuantity > 0 {
+				quantity = atrQuantity
+				logMessage += fmt.Sprintf(" | ATR Risk: %.2f%% (ATR %.2f)", simulator.positionSizing.RiskPercent, simulator.currentATR)
+			}
+
 			// Enhanced log with trading info
 			fmt.Printf("%s | Qty: %.6f | %s at %s\n",
 				logMessage, quantity, reason, timestamp.Format("2006-01-02 15:04"))
@@ -440,20 +399,21 @@ func (uc *BacktestStrategyUseCase) executeDecision(
 			rawProfit := ((price - entryPrice) / entryPrice) * 100
 
 			// CRITICAL PROTECTION: Double-check profit before allowing sale
-			// Allow sale at loss only if it's a stoploss trigger
+			// Allow sale at loss only if it's a stoploss or bot exit rule trigger
 			reason := ""
 			if r, ok := analysisData["reason"].(string); ok {
 				reason = r
 			}
+			forcedExit := entity.IsForcedExitReason(reason)
 			
-			if rawProfit < 0 && reason != "stoploss_triggered" {
+			if rawProfit < 0 && !forcedExit {
 				fmt.Printf("🚨 BLOCKED SALE AT LOSS | Price: R$%.2f | Entry: R$%.2f | Loss: %.2f%% | REASON: %s | at %s\n",
 					price, entryPrice, rawProfit, reason, timestamp.Format("2006-01-02 15:04"))
 				return nil // Block the sale
 			}
 			
-			// NEW PROTECTION: Check minimum profit threshold (but allow stoploss to override)
-			if rawProfit < simulator.minimumProfitThreshold && reason != "stoploss_triggered" {
+			// NEW PROTECTION: Check minimum profit threshold (but allow stoploss and exit rules to override)
+			if rawProfit < simulator.minimumProfitThreshold && !forcedExit {
 				fmt.Printf("🎯 WAITING FOR TARGET | Price: R$%.2f | Entry: R$%.2f | Profit: %.2f%% | Target: %.2f%% | at %s\n",
 					price, entryPrice, rawProfit, simulator.minimumProfitThreshold, timestamp.Format("2006-01-02 15:04"))
 				return nil // Block the sale until target is reached
@@ -471,8 +431,15 @@ func (uc *BacktestStrategyUseCase) executeDecision(
 
 			// Enhanced log with profit info
 			sellType := "SELL"
-			if reason == "stoploss_triggered" {
+			switch reason {
+			case entity.ExitReasonStopLoss:
 				sellType = "STOPLOSS"
+			case entity.ExitReasonTrailingStop:
+				sellType = "TRAILING STOP"
+			case entity.ExitReasonTakeProfit:
+				sellType = "TAKE PROFIT"
+			case entity.ExitReasonMaxHoldingTime:
+				sellType = "MAX HOLDING TIME"
 			}
 			fmt.Printf("🔴 %s %s | Price: R$%.2f | Entry: R$%.2f | Raw Profit: %.2f%% | Final P&L: %s | at %s\n",
 				sellType, symbol.GetValue(), price, entryPrice, rawProfit, finalProfit.String(), timestamp.Format("2006-01-02 15:04"))
@@ -507,6 +474,7 @@ func (uc *BacktestStrategyUseCase) syncPositionStates(simulator *BacktestSimulat
 		// Sync entry price from current trade
 		if simulator.currentTrade != nil {
 			bot.SetEntryPrice(simulator.currentTrade.GetEntryPrice())
+			bot.StartPositionTracking(simulator.currentTrade.GetEntryPrice(), simulator.currentTrade.GetEntryTime())
 		}
 	} else if !simPos && botPos {
 		err := bot.GetOutOfPosition() // Bot positioned, simulator not - take bot out of position
@@ -186,6 +186,130 @@ func TestBacktestStrategyUseCase_UnsupportedStrategy(t *testing.T) {
 	}
 }
 
+func TestBacktestStrategyUseCase_ExitRulesOverrideLossProtection(t *testing.T) {
+	useCase := NewBacktestStrategyUseCase()
+
+	input := InputBacktestStrategy{
+		StrategyName:   "RSI",
+		Symbol:         "BTCBRL",
+		Params:         map[string]interface{}{"Period": 14.0},
+		HistoricalData: createFallingTestData(30, 130.0),
+		InitialCapital: 10000.0,
+		TradeAmount:    1000.0,
+		Currency:       "BRL",
+		StartDate:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+		TradingFees:    0.1,
+	}
+
+	// Without exit rules the oversold entry is never sold at a loss
+	result, err := useCase.Execute(input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.GetTotalTrades() != 0 {
+		t.Fatalf("Expected no closed trades without exit rules, got %d", result.GetTotalTrades())
+	}
+
+	// Max holding time closes the position even though it is losing
+	input.MaxHoldingSeconds = 7200
+	result, err = useCase.Execute(input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.GetTotalTrades() == 0 || result.GetLosingTrades() != result.GetTotalTrades() {
+		t.Errorf("Expected only losing trades closed by max holding time, got %d trades (%d losing)", result.GetTotalTrades(), result.GetLosingTrades())
+	}
+}
+
+func TestBacktestStrategyUseCase_ATRPositionSizing(t *testing.T) {
+	useCase := NewBacktestStrategyUseCase()
+
+	input := InputBacktestStrategy{
+		StrategyName:        "RSI",
+		Symbol:              "BTCBRL",
+		Params:              map[string]interface{}{"Period": 14.0},
+		HistoricalData:      createFallingTestData(30, 130.0),
+		InitialCapital:      10000.0,
+		TradeAmount:         1000.0,
+		Currency:            "BRL",
+		StartDate:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:             time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+		MaxHoldingSeconds:   7200,
+		RiskPerTradePercent: 0.1,
+		ATRPeriod:           14,
+		ATRMultiplier:       2.0,
+	}
+
+	result, err := useCase.Execute(input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.GetTrades()) == 0 {
+		t.Fatal("Expected trades to be opened")
+	}
+
+	// Risking 10 BRL with a stop 2 ATRs (2 x 1.5) away buys 3.33 units instead of the 1000 BRL trade amount
+	quantity := result.GetTrades()[0].GetQuantity()
+	if quantity < 3.33 || quantity > 3.34 {
+		t.Errorf("Expected ATR sized quantity of 3.33, got %.6f", quantity)
+	}
+}
+
+func TestBacktestStrategyUseCase_HigherTimeframeTrendFilter(t *testing.T) {
+	useCase := NewBacktestStrategyUseCase()
+
+	input := InputBacktestStrategy{
+		StrategyName:      "MovingAverage",
+		Symbol:            "BTCBRL",
+		Params:            map[string]interface{}{"FastWindow": 3.0, "SlowWindow": 10.0},
+		HistoricalData:    createFallingTestData(40, 130.0),
+		InitialCapital:    10000.0,
+		TradeAmount:       1000.0,
+		Currency:          "BRL",
+		StartDate:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:           time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+		MaxHoldingSeconds: 7200, // Closes the dip entries so they show up as trades
+	}
+
+	result, err := useCase.Execute(input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.GetTrades()) == 0 {
+		t.Fatal("Expected the falling prices to trigger a dip entry without the trend filter")
+	}
+
+	// The 2h klines resampled from the hourly data are falling too, so the filter blocks every entry
+	input.Params = map[string]interface{}{"FastWindow": 3.0, "SlowWindow": 10.0, "TrendIntervalSeconds": 7200.0, "TrendWindow": 3.0}
+	result, err = useCase.Execute(input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.GetTrades()) != 0 {
+		t.Errorf("Expected no trades against the higher timeframe downtrend, got %d", len(result.GetTrades()))
+	}
+}
+
+func TestBacktestStrategyUseCase_InvalidExitRules(t *testing.T) {
+	useCase := NewBacktestStrategyUseCase()
+
+	input := InputBacktestStrategy{
+		StrategyName:        "RSI",
+		Symbol:              "BTCBRL",
+		HistoricalData:      createTestHistoricalData(),
+		InitialCapital:      10000.0,
+		Currency:            "BRL",
+		StartDate:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:             time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+		TrailingStopPercent: 150.0,
+	}
+
+	if _, err := useCase.Execute(input); err == nil {
+		t.Fatal("Expected error for invalid trailing stop, got nil")
+	}
+}
+
 // Helper functions for test data
 func createTestHistoricalData() []vo.Kline {
 	var klines []vo.Kline
@@ -243,4 +367,69 @@ func createBreakoutTestData() []vo.Kline {
 	}
 
 	return klines
-}
\ No newline at end of file
+}
+// createFallingTestData creates hourly klines falling 1.0 per kline, keeping RSI oversold
+func createFallingTestData(count int, startPrice float64) []vo.Kline {
+	var klines []vo.Kline
+	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < count; i++ {
+		price := startPrice - float64(i)
+		kline, _ := vo.NewKline(price+0.5, price, price+1.0, price-0.5, 1000.0, baseTime.Add(time.Hour*time.Duration(i)).UnixMilli())
+		klines = append(klines, kline)
+	}
+
+	return klines
+}
+
+func TestBacktestStrategyUseCase_CooldownRulesLimitReentries(t *testing.T) {
+	useCase := NewBacktestStrategyUseCase()
+
+	input := InputBacktestStrategy{
+		StrategyName:      "RSI",
+		Symbol:            "BTCBRL",
+		Params:            map[string]interface{}{"Period": 14.0},
+		HistoricalData:    createFallingTestData(60, 130.0),
+		InitialCapital:    10000.0,
+		TradeAmount:       1000.0,
+		Currency:          "BRL",
+		StartDate:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:           time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+		TradingFees:       0.1,
+		MaxHoldingSeconds: 7200,
+	}
+
+	// The oversold fall re-enters right after each exit
+	result, err := useCase.Execute(input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	withoutCooldown := result.GetTotalTrades()
+
+	// Waiting 6 hourly candles after each exit leaves fewer trades
+	input.CooldownCandles = 6
+	result, err = useCase.Execute(input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.GetTotalTrades() == 0 || result.GetTotalTrades() >= withoutCooldown {
+		t.Errorf("Expected the cooldown to cut the %d trades, got %d", withoutCooldown, result.GetTotalTrades())
+	}
+
+	// A single trade per day
+	input.CooldownCandles = 0
+	input.MaxTradesPerDay = 1
+	result, err = useCase.Execute(input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Trading starts on the 21st candle, at 21:00 of the first of 3 days
+	if result.GetTotalTrades() != 3 {
+		t.Errorf("Expected one trade on each of the 3 days, got %d (%d without the limit)", result.GetTotalTrades(), withoutCooldown)
+	}
+
+	input.MaxTradesPerDay = -1
+	if _, err := useCase.Execute(input); err == nil {
+		t.Error("Expected error for negative max trades per day")
+	}
+}
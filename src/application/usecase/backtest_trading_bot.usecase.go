@@ -4,10 +4,9 @@ import (
 	"context"
 	"crypgo-machine/src/application/service"
 	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
 	"crypgo-machine/src/domain/vo"
-	"crypgo-machine/src/infra/external"
 	"fmt"
-	"strconv"
 	"time"
 )
 
@@ -26,17 +25,28 @@ type BacktestTradingBotInput struct {
 	Currency               string                 `json:"currency"`
 	Quantity               float64                `json:"quantity"`
 	IntervalSeconds        int                    `json:"interval_seconds"`
+	TrailingStopPercent    float64                `json:"trailing_stop_percent"`
+	TakeProfitPercent      float64                `json:"take_profit_percent"`
+	MaxHoldingSeconds      int                    `json:"max_holding_seconds"`
+	RiskPerTradePercent    float64                `json:"risk_per_trade_percent"`
+	ATRPeriod              int                    `json:"atr_period"`
+	ATRMultiplier          float64                `json:"atr_multiplier"`
+	CooldownCandles         int                   `json:"cooldown_candles"`
+	CooldownMinutes         int                   `json:"cooldown_minutes"`
+	StopLossCooldownCandles int                   `json:"stop_loss_cooldown_candles"`
+	StopLossCooldownMinutes int                   `json:"stop_loss_cooldown_minutes"`
+	MaxTradesPerDay         int                   `json:"max_trades_per_day"`
 }
 
 // BacktestTradingBotUseCase performs backtesting using the same logic as live trading
 type BacktestTradingBotUseCase struct {
-	client external.BinanceClientInterface
+	exchange exchange.Exchange
 }
 
 // NewBacktestTradingBotUseCase creates a new BacktestTradingBotUseCase
-func NewBacktestTradingBotUseCase(client external.BinanceClientInterface) *BacktestTradingBotUseCase {
+func NewBacktestTradingBotUseCase(exchangeClient exchange.Exchange) *BacktestTradingBotUseCase {
 	return &BacktestTradingBotUseCase{
-		client: client,
+		exchange: exchangeClient,
 	}
 }
 
@@ -70,7 +80,7 @@ func (uc *BacktestTradingBotUseCase) Execute(input BacktestTradingBotInput) (*se
 	tradingUseCase := NewStartTradingBotUseCaseWithServices(
 		nil, // No repository needed for backtest
 		nil, // No decision log repository needed for backtest
-		uc.client,
+		uc.exchange,
 		dataSource,
 		executionContext,
 	)
@@ -147,32 +157,18 @@ func (uc *BacktestTradingBotUseCase) fetchHistoricalData(symbol string, startDat
 			currentStart.Format("2006-01-02 15:04"), currentEnd.Format("2006-01-02 15:04"))
 
 		// Fetch klines for this period
-		binanceKlines, err := uc.client.NewKlinesService().
-			Symbol(symbol).
-			Interval(interval).
-			StartTime(currentStart.UnixMilli()).
-			EndTime(currentEnd.UnixMilli()).
-			Limit(1000).
-			Do(context.Background())
+		klines, err := uc.exchange.GetKlines(context.Background(), exchange.KlineQuery{
+			Symbol:    symbol,
+			Interval:  interval,
+			StartTime: currentStart.UnixMilli(),
+			EndTime:   currentEnd.UnixMilli(),
+			Limit:     1000,
+		})
 
 		if err != nil {
 			return nil, fmt.Errorf("error fetching klines: %v", err)
 		}
-
-		// Convert to domain klines
-		for _, bkline := range binanceKlines {
-			openPrice, _ := strconv.ParseFloat(bkline.Open, 64)
-			closePrice, _ := strconv.ParseFloat(bkline.Close, 64)
-			highPrice, _ := strconv.ParseFloat(bkline.High, 64)
-			lowPrice, _ := strconv.ParseFloat(bkline.Low, 64)
-			volumePrice, _ := strconv.ParseFloat(bkline.Volume, 64)
-
-			kline, err := vo.NewKline(openPrice, closePrice, highPrice, lowPrice, volumePrice, bkline.CloseTime)
-			if err != nil {
-				return nil, fmt.Errorf("error creating kline: %v", err)
-			}
-			allKlines = append(allKlines, kline)
-		}
+		allKlines = append(allKlines, klines...)
 
 		// Move to next batch
 		currentStart = currentEnd.Add(time.Millisecond)
@@ -195,29 +191,29 @@ func (uc *BacktestTradingBotUseCase) createBotForBacktest(input BacktestTradingB
 		quantity = 0.001 // Default quantity
 	}
 
-	// Create strategy with all parameters
-	var strategy entity.TradingStrategy
-	switch input.Strategy {
-	case "MovingAverage":
-		fast, ok1 := input.StrategyParams["FastWindow"].(float64)
-		slow, ok2 := input.StrategyParams["SlowWindow"].(float64)
-		if !ok1 || !ok2 {
-			return nil, fmt.Errorf("invalid MovingAverage parameters")
-		}
-
-		// Check for minimum spread parameter
-		if minimumSpread, ok := input.StrategyParams["MinimumSpread"].(float64); ok {
-			spread, err := vo.NewMinimumSpread(minimumSpread)
-			if err != nil {
-				return nil, fmt.Errorf("invalid minimum spread: %v", err)
-			}
-			strategy = entity.NewMovingAverageStrategyWithSpread(int(fast), int(slow), spread)
-		} else {
-			strategy = entity.NewMovingAverageStrategy(int(fast), int(slow))
-		}
-	default:
+	// Create strategy with all parameters through the strategy registry
+	if !entity.IsStrategyRegistered(input.Strategy) {
 		return nil, fmt.Errorf("unsupported strategy: %s", input.Strategy)
 	}
+	strategy, err := entity.NewStrategyFromParams(input.Strategy, input.StrategyParams)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s parameters: %v", input.Strategy, err)
+	}
+
+	exitRules, err := entity.NewExitRules(input.TrailingStopPercent, input.TakeProfitPercent, input.MaxHoldingSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	positionSizing, err := entity.NewPositionSizing(input.RiskPerTradePercent, input.ATRPeriod, input.ATRMultiplier)
+	if err != nil {
+		return nil, err
+	}
+
+	cooldownRules, err := entity.NewCooldownRules(input.CooldownCandles, input.CooldownMinutes, input.StopLossCooldownCandles, input.StopLossCooldownMinutes, input.MaxTradesPerDay)
+	if err != nil {
+		return nil, err
+	}
 
 	// Use provided currency or default
 	currency := input.Currency
@@ -244,6 +240,9 @@ func (uc *BacktestTradingBotUseCase) createBotForBacktest(input BacktestTradingB
 		input.MinimumProfitThreshold,
 		false,
 	)
+	bot.SetExitRules(exitRules)
+	bot.SetPositionSizing(positionSizing)
+	bot.SetCooldownRules(cooldownRules)
 
 	return bot, nil
 }
@@ -8,7 +8,7 @@ import (
 
 func TestBacktestTradingBotUseCase_MovingAverage(t *testing.T) {
 	// Create a fake client with predefined market data
-	client := external.NewBinanceClientFake()
+	client := external.NewFakeExchange()
 	
 	// Configure the fake client with a strong trend scenario
 	client.SetPredefinedKlines(external.CreateStrongTrendKlines())
@@ -63,7 +63,7 @@ func TestBacktestTradingBotUseCase_MovingAverage(t *testing.T) {
 }
 
 func TestBacktestTradingBotUseCase_InvalidStrategy(t *testing.T) {
-	client := external.NewBinanceClientFake()
+	client := external.NewFakeExchange()
 	useCase := NewBacktestTradingBotUseCase(client)
 	
 	input := BacktestTradingBotInput{
@@ -90,7 +90,7 @@ func TestBacktestTradingBotUseCase_InvalidStrategy(t *testing.T) {
 }
 
 func TestBacktestTradingBotUseCase_MinimumProfitThreshold(t *testing.T) {
-	client := external.NewBinanceClientFake()
+	client := external.NewFakeExchange()
 	
 	// Set up a scenario with small price movements
 	client.SetPredefinedKlines(external.CreateWhipsawKlines())
@@ -0,0 +1,24 @@
+package usecase
+
+import "crypgo-machine/src/domain/entity"
+
+// CloseTradingBotPositionUseCase makes a positioned bot sell at its next tick, with its order execution mode, and
+// stop once the position is sold. A bot stopped with a position has its loop run again to sell it.
+type CloseTradingBotPositionUseCase struct {
+	startTradingBotUseCase *StartTradingBotUseCase // Runs the loops of the bots
+}
+
+func NewCloseTradingBotPositionUseCase(startTradingBotUseCase *StartTradingBotUseCase) *CloseTradingBotPositionUseCase {
+	return &CloseTradingBotPositionUseCase{
+		startTradingBotUseCase: startTradingBotUseCase,
+	}
+}
+
+type InputCloseTradingBotPosition struct {
+	BotId string `json:"bot_id"`
+}
+
+func (uc *CloseTradingBotPositionUseCase) Execute(input InputCloseTradingBotPosition) error {
+	_, err := uc.startTradingBotUseCase.changeBot(input.BotId, (*entity.TradingBot).BeginClosing)
+	return err
+}
@@ -3,35 +3,42 @@ package usecase
 import (
 	"crypgo-machine/src/application/repository"
 	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
 	"crypgo-machine/src/domain/service"
 	"crypgo-machine/src/domain/vo"
 	"crypgo-machine/src/infra/queue"
 	"encoding/json"
 	"fmt"
-	"github.com/adshao/go-binance/v2"
 )
 
 type CreateTradingBotUseCase struct {
 	tradingBotRepository repository.TradingBotRepository
-	client               binance.Client
+	exchange             exchange.Exchange
 	messageBroker        queue.MessageBroker
 	exchangeName         string
+	exchangeProfiles     []string // Configured exchange profiles besides the default one
 }
 
 func NewCreateTradingBotUseCase(
 	tradingBotRepository repository.TradingBotRepository,
-	client binance.Client,
+	exchangeClient exchange.Exchange,
 	messageBroker queue.MessageBroker,
 	exchangeName string,
 ) *CreateTradingBotUseCase {
 	return &CreateTradingBotUseCase{
 		tradingBotRepository: tradingBotRepository,
-		client:               client,
+		exchange:             exchangeClient,
 		messageBroker:        messageBroker,
 		exchangeName:         exchangeName,
 	}
 }
 
+// WithExchangeProfiles sets the exchange profiles bots may be created on besides the default one
+func (uc *CreateTradingBotUseCase) WithExchangeProfiles(exchangeProfiles ...string) *CreateTradingBotUseCase {
+	uc.exchangeProfiles = exchangeProfiles
+	return uc
+}
+
 type InputCreateTradingBot struct {
 	Symbol                   string      `json:"symbol"`
 	Quantity                 float64     `json:"quantity"`
@@ -44,6 +51,29 @@ type InputCreateTradingBot struct {
 	TradingFees              float64     `json:"trading_fees"`
 	MinimumProfitThreshold   float64     `json:"minimum_profit_threshold"`
 	UseFixedQuantity         bool        `json:"use_fixed_quantity"`
+	PositionSizingMode       string      `json:"position_sizing_mode"` // fixed_quantity, trade_amount or atr_risk (empty = use_fixed_quantity decides)
+	RiskPerTradePercent      float64     `json:"risk_per_trade_percent"`
+	ATRPeriod                int         `json:"atr_period"`
+	ATRMultiplier            float64     `json:"atr_multiplier"`
+	TrailingStopPercent      float64     `json:"trailing_stop_percent"`
+	TakeProfitPercent        float64     `json:"take_profit_percent"`
+	MaxHoldingSeconds        int         `json:"max_holding_seconds"`
+	UseStreaming             bool        `json:"use_streaming"` // Decide on each candle close from the kline WebSocket stream
+	OrderExecutionMode       string      `json:"order_execution_mode"`  // market, limit or post_only (empty = market)
+	TimeInForce              string      `json:"time_in_force"`         // GTC, IOC or FOK for limit orders (empty = GTC)
+	RepriceAfterSeconds      int         `json:"reprice_after_seconds"` // Cancel and reprice resting limit orders after this long
+	MaxOrderAttempts         int         `json:"max_order_attempts"`    // Limit orders placed before falling back to market
+	UseExchangeOCO           bool        `json:"use_exchange_oco"`           // Protect each entry with an OCO sell on the exchange
+	OCOStopLossPercent       float64     `json:"oco_stop_loss_percent"`      // 0 = the strategy's StoplossThreshold
+	OCOTakeProfitPercent     float64     `json:"oco_take_profit_percent"`    // 0 = take_profit_percent
+	OCOStopLimitGapPercent   float64     `json:"oco_stop_limit_gap_percent"` // 0 = 0.5%
+	Mode                     string      `json:"mode"`                       // live or paper (empty = live)
+	ExchangeProfile          string      `json:"exchange_profile"`           // Exchange account to trade on (empty = production)
+	CooldownCandles          int         `json:"cooldown_candles"`           // Candles to wait after any exit
+	CooldownMinutes          int         `json:"cooldown_minutes"`           // Minutes to wait after any exit
+	StopLossCooldownCandles  int         `json:"stop_loss_cooldown_candles"` // Candles to wait after a stop-loss exit
+	StopLossCooldownMinutes  int         `json:"stop_loss_cooldown_minutes"` // Minutes to wait after a stop-loss exit
+	MaxTradesPerDay          int         `json:"max_trades_per_day"`         // 0 = unlimited
 }
 
 func (uc *CreateTradingBotUseCase) Execute(input InputCreateTradingBot) error {
@@ -51,39 +81,45 @@ func (uc *CreateTradingBotUseCase) Execute(input InputCreateTradingBot) error {
 	if err != nil {
 		return fmt.Errorf("invalid symbol: %s", err)
 	}
-	if input.Quantity <= 0 {
-		return fmt.Errorf("invalid quantity: must be greater than zero")
-	}
 	if input.InitialCapital <= 0 {
 		return fmt.Errorf("invalid initial capital: must be greater than zero")
 	}
-	if input.TradeAmount <= 0 {
-		return fmt.Errorf("invalid trade amount: must be greater than zero")
-	}
-	if input.TradingFees < 0 {
-		return fmt.Errorf("invalid trading fees: must be greater than or equal to zero")
+
+	mode, errMode := entity.NewTradingMode(input.Mode)
+	if errMode != nil {
+		return errMode
 	}
-	if input.MinimumProfitThreshold < 0 {
-		return fmt.Errorf("invalid minimum profit threshold: must be greater than or equal to zero")
+
+	exchangeProfile, errProfile := uc.resolveExchangeProfile(input.ExchangeProfile)
+	if errProfile != nil {
+		return errProfile
 	}
 
-	strategy, errStrategy := service.NewTradeStrategyFactory(input.Strategy, input.Params)
-	if errStrategy != nil {
-		return fmt.Errorf("invalid strategy: %s", err)
+	config, errConfig := resolveTradingBotConfig(input, mode)
+	if errConfig != nil {
+		return errConfig
 	}
 
 	bot := entity.NewTradingBot(
 		symbol,
-		input.Quantity,
-		strategy,
-		input.IntervalSeconds,
+		config.Quantity,
+		config.Strategy,
+		config.IntervalSeconds,
 		input.InitialCapital,
-		input.TradeAmount,
+		config.TradeAmount,
 		input.Currency,
-		input.TradingFees,
-		input.MinimumProfitThreshold,
-		input.UseFixedQuantity,
+		config.TradingFees,
+		config.MinimumProfitThreshold,
+		config.UseFixedQuantity,
 	)
+	bot.SetExitRules(config.ExitRules)
+	bot.SetCooldownRules(config.CooldownRules)
+	bot.SetPositionSizing(config.PositionSizing)
+	bot.SetOrderExecution(config.OrderExecution)
+	bot.SetExchangeOCO(config.ExchangeOCO)
+	bot.SetUseStreaming(config.UseStreaming)
+	bot.SetMode(mode)
+	bot.SetExchangeProfile(exchangeProfile)
 
 	errSave := uc.tradingBotRepository.Save(bot)
 	if errSave != nil {
@@ -91,10 +127,12 @@ func (uc *CreateTradingBotUseCase) Execute(input InputCreateTradingBot) error {
 	}
 
 	payload, errMarshal := json.Marshal(map[string]interface{}{
-		"id":       bot.Id,
-		"symbol":   bot.GetSymbol(),
-		"quantity": bot.GetQuantity(),
-		"strategy": bot.GetStrategy().GetName(),
+		"id":               bot.Id,
+		"symbol":           bot.GetSymbol(),
+		"quantity":         bot.GetQuantity(),
+		"strategy":         bot.GetStrategy().GetName(),
+		"mode":             bot.GetMode(),
+		"exchange_profile": bot.GetExchangeProfile(),
 	})
 	if errMarshal != nil {
 		return errMarshal
@@ -107,6 +145,115 @@ func (uc *CreateTradingBotUseCase) Execute(input InputCreateTradingBot) error {
 	return nil
 }
 
+// resolveExchangeProfile validates the exchange profile of a new bot, which must be configured
+func (uc *CreateTradingBotUseCase) resolveExchangeProfile(name string) (string, error) {
+	exchangeProfile, err := entity.NewExchangeProfileName(name)
+	if err != nil {
+		return "", err
+	}
+	if exchangeProfile == entity.DefaultExchangeProfile {
+		return exchangeProfile, nil
+	}
+	for _, configured := range uc.exchangeProfiles {
+		if configured == exchangeProfile {
+			return exchangeProfile, nil
+		}
+	}
+	return "", fmt.Errorf("invalid exchange profile: %s is not configured", exchangeProfile)
+}
+
+// resolveTradingBotConfig validates the editable config of a bot of the given mode, shared by its creation and
+// its updates
+func resolveTradingBotConfig(input InputCreateTradingBot, mode string) (entity.BotConfig, error) {
+	if input.Quantity <= 0 {
+		return entity.BotConfig{}, fmt.Errorf("invalid quantity: must be greater than zero")
+	}
+	if input.TradeAmount <= 0 {
+		return entity.BotConfig{}, fmt.Errorf("invalid trade amount: must be greater than zero")
+	}
+	if input.TradingFees < 0 {
+		return entity.BotConfig{}, fmt.Errorf("invalid trading fees: must be greater than or equal to zero")
+	}
+	if input.MinimumProfitThreshold < 0 {
+		return entity.BotConfig{}, fmt.Errorf("invalid minimum profit threshold: must be greater than or equal to zero")
+	}
+
+	exitRules, errExitRules := entity.NewExitRules(input.TrailingStopPercent, input.TakeProfitPercent, input.MaxHoldingSeconds)
+	if errExitRules != nil {
+		return entity.BotConfig{}, errExitRules
+	}
+
+	cooldownRules, errCooldown := entity.NewCooldownRules(input.CooldownCandles, input.CooldownMinutes, input.StopLossCooldownCandles, input.StopLossCooldownMinutes, input.MaxTradesPerDay)
+	if errCooldown != nil {
+		return entity.BotConfig{}, errCooldown
+	}
+
+	useFixedQuantity, positionSizing, errSizing := resolvePositionSizing(input)
+	if errSizing != nil {
+		return entity.BotConfig{}, errSizing
+	}
+
+	orderExecution, errExecution := entity.NewOrderExecution(input.OrderExecutionMode, input.TimeInForce, input.RepriceAfterSeconds, input.MaxOrderAttempts)
+	if errExecution != nil {
+		return entity.BotConfig{}, errExecution
+	}
+
+	if mode == entity.TradingModePaper && input.UseExchangeOCO {
+		return entity.BotConfig{}, fmt.Errorf("invalid exchange OCO: paper bots place no orders on the exchange")
+	}
+
+	strategy, errStrategy := service.NewTradeStrategyFactory(input.Strategy, input.Params)
+	if errStrategy != nil {
+		return entity.BotConfig{}, fmt.Errorf("invalid strategy: %s", errStrategy)
+	}
+
+	exchangeOCO, errOCO := resolveExchangeOCO(input, strategy, exitRules)
+	if errOCO != nil {
+		return entity.BotConfig{}, errOCO
+	}
+
+	return entity.BotConfig{
+		Strategy:               strategy,
+		Quantity:               input.Quantity,
+		IntervalSeconds:        input.IntervalSeconds,
+		TradeAmount:            input.TradeAmount,
+		TradingFees:            input.TradingFees,
+		MinimumProfitThreshold: input.MinimumProfitThreshold,
+		UseFixedQuantity:       useFixedQuantity,
+		PositionSizing:         positionSizing,
+		OrderExecution:         orderExecution,
+		ExchangeOCO:            exchangeOCO,
+		ExitRules:              exitRules,
+		CooldownRules:          cooldownRules,
+		UseStreaming:           input.UseStreaming,
+	}, nil
+}
+
+// resolvePositionSizing maps the requested sizing mode to the bot's useFixedQuantity flag and ATR sizing
+func resolvePositionSizing(input InputCreateTradingBot) (bool, entity.PositionSizing, error) {
+	mode := input.PositionSizingMode
+	if mode == "" && input.RiskPerTradePercent > 0 {
+		mode = entity.SizingModeATRRisk
+	}
+
+	switch mode {
+	case "":
+		return input.UseFixedQuantity, entity.PositionSizing{}, nil
+	case entity.SizingModeFixedQuantity:
+		return true, entity.PositionSizing{}, nil
+	case entity.SizingModeTradeAmount:
+		return false, entity.PositionSizing{}, nil
+	case entity.SizingModeATRRisk:
+		if input.RiskPerTradePercent <= 0 {
+			return false, entity.PositionSizing{}, fmt.Errorf("invalid risk per trade: must be greater than zero for %s sizing", entity.SizingModeATRRisk)
+		}
+		sizing, err := entity.NewPositionSizing(input.RiskPerTradePercent, input.ATRPeriod, input.ATRMultiplier)
+		return false, sizing, err
+	default:
+		return false, entity.PositionSizing{}, fmt.Errorf("invalid position sizing mode: %s", mode)
+	}
+}
+
 func (uc *CreateTradingBotUseCase) emitEvent(topic string, payload []byte) error {
 	fmt.Println("emitEvent ---- topic:", topic, "payload:", string(payload))
 	event := queue.Message{
@@ -121,3 +268,24 @@ func (uc *CreateTradingBotUseCase) emitEvent(topic string, payload []byte) error
 	}
 	return nil
 }
+
+// resolveExchangeOCO builds the OCO protection of a bot, defaulting its legs to the strategy's stoploss and the
+// bot's take profit exit rule
+func resolveExchangeOCO(input InputCreateTradingBot, strategy entity.TradingStrategy, exitRules entity.ExitRules) (entity.ExchangeOCO, error) {
+	if !input.UseExchangeOCO {
+		return entity.ExchangeOCO{}, nil
+	}
+
+	stopLossPercent := input.OCOStopLossPercent
+	if stopLossPercent == 0 {
+		stopLossPercent = entity.StoplossThresholdOf(strategy)
+	}
+	takeProfitPercent := input.OCOTakeProfitPercent
+	if takeProfitPercent == 0 {
+		takeProfitPercent = exitRules.TakeProfitPercent
+	}
+	if stopLossPercent == 0 || takeProfitPercent == 0 {
+		return entity.ExchangeOCO{}, fmt.Errorf("invalid exchange OCO: needs a stop loss (oco_stop_loss_percent or the strategy StoplossThreshold) and a take profit (oco_take_profit_percent or take_profit_percent)")
+	}
+	return entity.NewExchangeOCO(stopLossPercent, takeProfitPercent, input.OCOStopLimitGapPercent)
+}
@@ -2,9 +2,9 @@ package usecase
 
 import (
 	"crypgo-machine/src/domain/service"
+	"crypgo-machine/src/infra/external"
 	"crypgo-machine/src/infra/queue"
 	"errors"
-	"github.com/adshao/go-binance/v2"
 	"testing"
 
 	"crypgo-machine/src/domain/entity"
@@ -80,7 +80,7 @@ func TestCreateTradingBotUseCase_Success(t *testing.T) {
 	}
 
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -104,7 +104,7 @@ func TestCreateTradingBotUseCase_Success(t *testing.T) {
 func TestCreateTradingBotUseCase_InvalidSymbol(t *testing.T) {
 	mockRepo := &MockTradeBotRepository{}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	// Testando símbolos inválidos segundo a nova validação
 	invalidSymbols := []string{
@@ -145,7 +145,7 @@ func TestCreateTradingBotUseCase_RepoError(t *testing.T) {
 		},
 	}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -169,7 +169,7 @@ func TestCreateTradingBotUseCase_RepoError(t *testing.T) {
 func TestCreateTradingBotUseCase_InvalidQuantity(t *testing.T) {
 	mockRepo := &MockTradeBotRepository{}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -193,7 +193,7 @@ func TestCreateTradingBotUseCase_InvalidQuantity(t *testing.T) {
 func TestCreateTradingBotUseCase_UnknownStrategy(t *testing.T) {
 	mockRepo := &MockTradeBotRepository{}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -221,7 +221,7 @@ func TestCreateTradingBotUseCase_DuplicateBot(t *testing.T) {
 		},
 	}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -251,7 +251,7 @@ func TestCreateTradingBotUseCase_MultipleBotsDifferentParams(t *testing.T) {
 		},
 	}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input1 := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -291,7 +291,7 @@ func TestCreateTradingBotUseCase_MultipleBotsDifferentParams(t *testing.T) {
 func TestCreateTradingBotUseCase_InvalidInitialCapital(t *testing.T) {
 	mockRepo := &MockTradeBotRepository{}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -315,7 +315,7 @@ func TestCreateTradingBotUseCase_InvalidInitialCapital(t *testing.T) {
 func TestCreateTradingBotUseCase_InvalidTradeAmount(t *testing.T) {
 	mockRepo := &MockTradeBotRepository{}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -339,7 +339,7 @@ func TestCreateTradingBotUseCase_InvalidTradeAmount(t *testing.T) {
 func TestCreateTradingBotUseCase_InvalidTradingFees(t *testing.T) {
 	mockRepo := &MockTradeBotRepository{}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -363,7 +363,7 @@ func TestCreateTradingBotUseCase_InvalidTradingFees(t *testing.T) {
 func TestCreateTradingBotUseCase_InvalidMinimumProfitThreshold(t *testing.T) {
 	mockRepo := &MockTradeBotRepository{}
 	mockMessageBroker := &MockMessageBroker{}
-	uc := NewCreateTradingBotUseCase(mockRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 
 	input := InputCreateTradingBot{
 		Symbol:                   "SOLBRL",
@@ -383,3 +383,252 @@ func TestCreateTradingBotUseCase_InvalidMinimumProfitThreshold(t *testing.T) {
 		t.Errorf("expected minimum profit threshold error, got %v", err)
 	}
 }
+
+func TestCreateTradingBotUseCase_ExitRules(t *testing.T) {
+	var savedBot *entity.TradingBot
+	mockRepo := &MockTradeBotRepository{
+		SaveFunc: func(bot *entity.TradingBot) error {
+			savedBot = bot
+			return nil
+		},
+	}
+	mockMessageBroker := &MockMessageBroker{}
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
+
+	input := InputCreateTradingBot{
+		Symbol:                   "SOLBRL",
+		Quantity:                 1.0,
+		Strategy:                 "MovingAverage",
+		Params:                   service.MovingAverageParams{FastWindow: 7, SlowWindow: 21},
+		IntervalSeconds:          3600,
+		InitialCapital:           10000.0,
+		TradeAmount:              4000.0,
+		Currency:                 "BRL",
+		TradingFees:              0.001,
+		MinimumProfitThreshold:   5.0,
+		TrailingStopPercent:      3.0,
+		TakeProfitPercent:        8.0,
+		MaxHoldingSeconds:        86400,
+	}
+
+	if err := uc.Execute(input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := entity.ExitRules{TrailingStopPercent: 3.0, TakeProfitPercent: 8.0, MaxHoldingSeconds: 86400}
+	if savedBot == nil || savedBot.GetExitRules() != expected {
+		t.Fatalf("expected saved bot with exit rules %+v", expected)
+	}
+
+	input.TrailingStopPercent = 100.0 // Invalid
+	err := uc.Execute(input)
+	if err == nil || err.Error() != "invalid trailing stop: must be between 0 and 100" {
+		t.Errorf("expected trailing stop error, got %v", err)
+	}
+}
+
+func TestCreateTradingBotUseCase_PositionSizingMode(t *testing.T) {
+	var savedBot *entity.TradingBot
+	mockRepo := &MockTradeBotRepository{
+		SaveFunc: func(bot *entity.TradingBot) error {
+			savedBot = bot
+			return nil
+		},
+	}
+	mockMessageBroker := &MockMessageBroker{}
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
+
+	input := InputCreateTradingBot{
+		Symbol:                   "SOLBRL",
+		Quantity:                 1.0,
+		Strategy:                 "MovingAverage",
+		Params:                   service.MovingAverageParams{FastWindow: 7, SlowWindow: 21},
+		IntervalSeconds:          3600,
+		InitialCapital:           10000.0,
+		TradeAmount:              4000.0,
+		Currency:                 "BRL",
+		TradingFees:              0.001,
+		MinimumProfitThreshold:   5.0,
+		UseFixedQuantity:         true,
+		PositionSizingMode:       entity.SizingModeATRRisk,
+		RiskPerTradePercent:      1.0,
+	}
+
+	if err := uc.Execute(input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := entity.PositionSizing{RiskPercent: 1.0, ATRPeriod: entity.DefaultSizingATRPeriod, ATRMultiplier: entity.DefaultSizingATRMultiplier}
+	if savedBot.GetPositionSizing() != expected || savedBot.GetPositionSizingMode() != entity.SizingModeATRRisk {
+		t.Errorf("expected ATR risk sizing %+v, got %+v (%s)", expected, savedBot.GetPositionSizing(), savedBot.GetPositionSizingMode())
+	}
+
+	input.PositionSizingMode = entity.SizingModeTradeAmount
+	if err := uc.Execute(input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if savedBot.GetPositionSizingMode() != entity.SizingModeTradeAmount {
+		t.Errorf("expected trade amount sizing, got %s", savedBot.GetPositionSizingMode())
+	}
+
+	input.PositionSizingMode = entity.SizingModeATRRisk
+	input.RiskPerTradePercent = 0
+	err := uc.Execute(input)
+	if err == nil || err.Error() != "invalid risk per trade: must be greater than zero for atr_risk sizing" {
+		t.Errorf("expected risk per trade error, got %v", err)
+	}
+
+	input.PositionSizingMode = "kelly"
+	err = uc.Execute(input)
+	if err == nil || err.Error() != "invalid position sizing mode: kelly" {
+		t.Errorf("expected sizing mode error, got %v", err)
+	}
+}
+
+func TestCreateTradingBotUseCase_ExchangeOCO(t *testing.T) {
+	var savedBot *entity.TradingBot
+	mockRepo := &MockTradeBotRepository{
+		SaveFunc: func(bot *entity.TradingBot) error {
+			savedBot = bot
+			return nil
+		},
+	}
+	mockMessageBroker := &MockMessageBroker{}
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
+
+	input := InputCreateTradingBot{
+		Symbol:                   "SOLBRL",
+		Quantity:                 1.0,
+		Strategy:                 "MovingAverage",
+		Params:                   service.MovingAverageParams{FastWindow: 7, SlowWindow: 21, StoplossThreshold: 4.0},
+		IntervalSeconds:          1800,
+		InitialCapital:           10000.0,
+		TradeAmount:              4000.0,
+		Currency:                 "BRL",
+		TradingFees:              0.001,
+		MinimumProfitThreshold:   5.0,
+		TakeProfitPercent:        8.0,
+		UseExchangeOCO:           true,
+	}
+
+	// The legs default to the strategy stoploss and the take profit exit rule
+	if err := uc.Execute(input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := entity.ExchangeOCO{StopLossPercent: 4.0, TakeProfitPercent: 8.0, StopLimitGapPercent: entity.DefaultOCOStopLimitGapPercent}
+	if savedBot == nil || savedBot.GetExchangeOCO() != expected {
+		t.Fatalf("expected saved bot with OCO %+v", expected)
+	}
+
+	input.OCOStopLossPercent = 2.0
+	input.OCOTakeProfitPercent = 6.0
+	input.OCOStopLimitGapPercent = 1.0
+	if err := uc.Execute(input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected = entity.ExchangeOCO{StopLossPercent: 2.0, TakeProfitPercent: 6.0, StopLimitGapPercent: 1.0}
+	if savedBot.GetExchangeOCO() != expected {
+		t.Errorf("expected OCO %+v, got %+v", expected, savedBot.GetExchangeOCO())
+	}
+
+	input.OCOTakeProfitPercent = 0
+	input.TakeProfitPercent = 0
+	if err := uc.Execute(input); err == nil {
+		t.Error("expected error for an OCO without take profit")
+	}
+}
+
+func TestCreateTradingBotUseCase_TradingMode(t *testing.T) {
+	var savedBot *entity.TradingBot
+	mockRepo := &MockTradeBotRepository{
+		SaveFunc: func(bot *entity.TradingBot) error {
+			savedBot = bot
+			return nil
+		},
+	}
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), &MockMessageBroker{}, "test-exchange")
+
+	input := InputCreateTradingBot{
+		Symbol:                 "SOLBRL",
+		Quantity:               1.0,
+		Strategy:               "MovingAverage",
+		Params:                 service.MovingAverageParams{FastWindow: 7, SlowWindow: 21, StoplossThreshold: 4.0},
+		IntervalSeconds:        1800,
+		InitialCapital:         10000.0,
+		TradeAmount:            4000.0,
+		Currency:               "BRL",
+		TradingFees:            0.001,
+		MinimumProfitThreshold: 5.0,
+	}
+
+	// Bots trade live unless created in paper mode
+	if err := uc.Execute(input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if savedBot.GetMode() != entity.TradingModeLive || savedBot.IsPaper() {
+		t.Errorf("expected a live bot, got %s", savedBot.GetMode())
+	}
+
+	input.Mode = "PAPER"
+	if err := uc.Execute(input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !savedBot.IsPaper() || savedBot.GetPaperBalance() != 10000.0 {
+		t.Errorf("expected a paper bot with the initial capital as balance, got %s with %.2f", savedBot.GetMode(), savedBot.GetPaperBalance())
+	}
+
+	input.UseExchangeOCO = true
+	if err := uc.Execute(input); err == nil {
+		t.Error("expected error for a paper bot with an exchange OCO")
+	}
+
+	input.UseExchangeOCO = false
+	input.Mode = "demo"
+	if err := uc.Execute(input); err == nil || err.Error() != "invalid trading mode: demo (use live or paper)" {
+		t.Errorf("expected trading mode error, got %v", err)
+	}
+}
+
+func TestCreateTradingBotUseCase_ExchangeProfile(t *testing.T) {
+	var savedBot *entity.TradingBot
+	mockRepo := &MockTradeBotRepository{
+		SaveFunc: func(bot *entity.TradingBot) error {
+			savedBot = bot
+			return nil
+		},
+	}
+	uc := NewCreateTradingBotUseCase(mockRepo, external.NewFakeExchange(), &MockMessageBroker{}, "test-exchange").
+		WithExchangeProfiles("testnet")
+
+	input := InputCreateTradingBot{
+		Symbol:                 "SOLBRL",
+		Quantity:               1.0,
+		Strategy:               "MovingAverage",
+		Params:                 service.MovingAverageParams{FastWindow: 7, SlowWindow: 21, StoplossThreshold: 4.0},
+		IntervalSeconds:        1800,
+		InitialCapital:         10000.0,
+		TradeAmount:            4000.0,
+		Currency:               "BRL",
+		TradingFees:            0.001,
+		MinimumProfitThreshold: 5.0,
+	}
+
+	// Bots trade on the default profile unless created on another one
+	if err := uc.Execute(input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if savedBot.GetExchangeProfile() != entity.DefaultExchangeProfile || savedBot.ToDTO().ExchangeProfile != "production" {
+		t.Errorf("expected a bot on production, got %s", savedBot.GetExchangeProfile())
+	}
+
+	input.ExchangeProfile = "Testnet"
+	if err := uc.Execute(input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if savedBot.GetExchangeProfile() != "testnet" {
+		t.Errorf("expected a bot on testnet, got %s", savedBot.GetExchangeProfile())
+	}
+
+	input.ExchangeProfile = "staging"
+	if err := uc.Execute(input); err == nil || err.Error() != "invalid exchange profile: staging is not configured" {
+		t.Errorf("expected exchange profile error, got %v", err)
+	}
+}
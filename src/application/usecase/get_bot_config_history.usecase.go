@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+)
+
+// GetBotConfigHistoryUseCase lists the config versions of a bot, to attribute its decision logs to the parameters
+// in force when they were made
+type GetBotConfigHistoryUseCase struct {
+	tradingBotRepository    repository.TradingBotRepository
+	configHistoryRepository repository.BotConfigHistoryRepository
+}
+
+func NewGetBotConfigHistoryUseCase(
+	tradingBotRepository repository.TradingBotRepository,
+	configHistoryRepository repository.BotConfigHistoryRepository,
+) *GetBotConfigHistoryUseCase {
+	return &GetBotConfigHistoryUseCase{
+		tradingBotRepository:    tradingBotRepository,
+		configHistoryRepository: configHistoryRepository,
+	}
+}
+
+type InputGetBotConfigHistory struct {
+	BotId string `json:"bot_id"`
+}
+
+type OutputGetBotConfigHistory struct {
+	BotId         string                       `json:"bot_id"`
+	ConfigVersion int                          `json:"config_version"` // Version in force
+	Versions      []entity.BotConfigVersionDTO `json:"versions"`       // Oldest first
+}
+
+func (uc *GetBotConfigHistoryUseCase) Execute(input InputGetBotConfigHistory) (*OutputGetBotConfigHistory, error) {
+	if input.BotId == "" {
+		return nil, fmt.Errorf("bot_id is required")
+	}
+	if _, err := vo.RestoreEntityId(input.BotId); err != nil {
+		return nil, fmt.Errorf("invalid bot_id format: %v", err)
+	}
+
+	tradingBot, err := uc.tradingBotRepository.GetTradeByID(input.BotId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trading bot: %v", err)
+	}
+	if tradingBot == nil {
+		return nil, fmt.Errorf("trading bot not found with id: %s", input.BotId)
+	}
+
+	history, err := uc.configHistoryRepository.GetByTradingBotId(input.BotId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config history: %v", err)
+	}
+
+	versions := make([]entity.BotConfigVersionDTO, 0, len(history)+1)
+	for _, version := range history {
+		versions = append(versions, version.ToDTO())
+	}
+	// A bot never edited has its creation config in force, which is only recorded by the first edit
+	if len(history) == 0 {
+		initial := entity.RestoreBotConfigVersion(vo.NewEntityId(), tradingBot.Id, tradingBot.GetConfigVersion(), tradingBot.GetConfig().ToDTO(), nil, tradingBot.GetCreatedAt())
+		versions = append(versions, initial.ToDTO())
+	}
+
+	return &OutputGetBotConfigHistory{
+		BotId:         input.BotId,
+		ConfigVersion: tradingBot.GetConfigVersion(),
+		Versions:      versions,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"crypgo-machine/src/infra/repository"
+	"testing"
+)
+
+func TestGetBotConfigHistoryUseCase_ListsTheVersionsOfABot(t *testing.T) {
+	startUseCase, tradingBotRepo, _, _ := setupStartTradingBotUseCase()
+	historyRepo := repository.NewBotConfigHistoryRepositoryInMemory()
+	updateUseCase := NewUpdateTradingBotUseCase(startUseCase, historyRepo)
+	historyUseCase := NewGetBotConfigHistoryUseCase(tradingBotRepo, historyRepo)
+
+	bot := createConfigurableTradingBot(t)
+	if err := tradingBotRepo.Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+	botId := bot.Id.GetValue()
+
+	// A bot never edited has its creation config in force
+	output, err := historyUseCase.Execute(InputGetBotConfigHistory{BotId: botId})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if output.ConfigVersion != 1 || len(output.Versions) != 1 || output.Versions[0].Version != 1 || output.Versions[0].Config.Params["FastWindow"] != 7 {
+		t.Errorf("Expected the creation config as version 1, got %+v", output)
+	}
+
+	for _, fastWindow := range []float64{9, 12} {
+		changes := map[string]interface{}{"params": map[string]interface{}{"FastWindow": fastWindow}}
+		if _, err := updateUseCase.Execute(InputUpdateTradingBot{BotId: botId, Changes: changes}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	output, err = historyUseCase.Execute(InputGetBotConfigHistory{BotId: botId})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if output.ConfigVersion != 3 || len(output.Versions) != 3 {
+		t.Fatalf("Expected versions 1 to 3, got %+v", output)
+	}
+	for i, version := range output.Versions {
+		if version.Version != i+1 {
+			t.Errorf("Expected version %d at position %d, got %d", i+1, i, version.Version)
+		}
+	}
+	if output.Versions[2].Config.Params["FastWindow"] != 12 || len(output.Versions[0].ChangedFields) != 0 {
+		t.Errorf("Expected the last version on FastWindow 12, got %+v", output.Versions)
+	}
+
+	if _, err := historyUseCase.Execute(InputGetBotConfigHistory{BotId: "invalid"}); err == nil {
+		t.Error("Expected an invalid bot id to be refused")
+	}
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import "crypgo-machine/src/application/service"
+
+// GetBotRuntimeUseCase reports the live state of the bot loops run by the supervisor
+type GetBotRuntimeUseCase struct {
+	supervisor *service.BotSupervisor
+}
+
+func NewGetBotRuntimeUseCase(supervisor *service.BotSupervisor) *GetBotRuntimeUseCase {
+	return &GetBotRuntimeUseCase{
+		supervisor: supervisor,
+	}
+}
+
+func (uc *GetBotRuntimeUseCase) Execute() []service.BotRuntimeState {
+	return uc.supervisor.States()
+}
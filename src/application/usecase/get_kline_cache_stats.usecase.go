@@ -0,0 +1,18 @@
+package usecase
+
+import "crypgo-machine/src/application/service"
+
+// GetKlineCacheStatsUseCase reports the hit/miss metrics of the shared kline cache
+type GetKlineCacheStatsUseCase struct {
+	klineCache *service.KlineCache
+}
+
+func NewGetKlineCacheStatsUseCase(klineCache *service.KlineCache) *GetKlineCacheStatsUseCase {
+	return &GetKlineCacheStatsUseCase{
+		klineCache: klineCache,
+	}
+}
+
+func (uc *GetKlineCacheStatsUseCase) Execute() service.KlineCacheStats {
+	return uc.klineCache.Stats()
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/service"
+	"crypgo-machine/src/domain/entity"
+	"time"
+)
+
+// GetRiskStatusUseCase reports the risk limits shared by the live bots and how far the bots of an exchange profile are
+// from them
+type GetRiskStatusUseCase struct {
+	riskManager *service.RiskManager
+}
+
+func NewGetRiskStatusUseCase(riskManager *service.RiskManager) *GetRiskStatusUseCase {
+	return &GetRiskStatusUseCase{
+		riskManager: riskManager,
+	}
+}
+
+// RiskLimitsOutput represents the risk limits in API responses, a zero limit being not enforced
+type RiskLimitsOutput struct {
+	MaxDailyLoss        float64    `json:"max_daily_loss"`
+	MaxOpenPositions    int        `json:"max_open_positions"`
+	MaxExposurePerAsset float64    `json:"max_exposure_per_asset"`
+	FlattenOnBreach     bool       `json:"flatten_on_breach"`
+	KillSwitch          bool       `json:"kill_switch"`
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+}
+
+// RiskBreachOutput represents a breached risk limit
+type RiskBreachOutput struct {
+	Limit     string  `json:"limit"`
+	Asset     string  `json:"asset,omitempty"`
+	Reason    string  `json:"reason"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Flatten   bool    `json:"flatten"`
+}
+
+// InputGetRiskStatus selects the exchange profile whose bots are reported, empty for the default profile
+type InputGetRiskStatus struct {
+	ExchangeProfile string
+}
+
+type GetRiskStatusOutput struct {
+	ExchangeProfile  string             `json:"exchange_profile"`
+	Limits           RiskLimitsOutput   `json:"limits"`
+	DailyProfitLoss  float64            `json:"daily_profit_loss"`
+	OpenPositions    int                `json:"open_positions"`
+	ExposurePerAsset map[string]float64 `json:"exposure_per_asset"`
+	Breaches         []RiskBreachOutput `json:"breaches"`
+}
+
+func (uc *GetRiskStatusUseCase) Execute(input InputGetRiskStatus) (*GetRiskStatusOutput, error) {
+	exchangeProfile, err := entity.NewExchangeProfileName(input.ExchangeProfile)
+	if err != nil {
+		return nil, err
+	}
+	status, err := uc.riskManager.Status(exchangeProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	breaches := make([]RiskBreachOutput, 0, len(status.Breaches))
+	for _, breach := range status.Breaches {
+		breaches = append(breaches, RiskBreachOutput{
+			Limit:     breach.Limit,
+			Asset:     breach.Asset,
+			Reason:    breach.Error(),
+			Value:     breach.Value,
+			Threshold: breach.Threshold,
+			Flatten:   breach.Flatten,
+		})
+	}
+
+	return &GetRiskStatusOutput{
+		ExchangeProfile:  status.Profile,
+		Limits:           riskLimitsOutputOf(status.Limits),
+		DailyProfitLoss:  status.DailyProfitLoss,
+		OpenPositions:    status.OpenPositions,
+		ExposurePerAsset: status.ExposurePerAsset,
+		Breaches:         breaches,
+	}, nil
+}
+
+func riskLimitsOutputOf(limits entity.RiskLimits) RiskLimitsOutput {
+	output := RiskLimitsOutput{
+		MaxDailyLoss:        limits.MaxDailyLoss,
+		MaxOpenPositions:    limits.MaxOpenPositions,
+		MaxExposurePerAsset: limits.MaxExposurePerAsset,
+		FlattenOnBreach:     limits.FlattenOnBreach,
+		KillSwitch:          limits.KillSwitch,
+	}
+	if !limits.UpdatedAt.IsZero() {
+		updatedAt := limits.UpdatedAt
+		output.UpdatedAt = &updatedAt
+	}
+	return output
+}
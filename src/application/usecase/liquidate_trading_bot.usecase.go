@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"fmt"
+)
+
+// LiquidateTradingBotUseCase sells the position of a bot right away at market, whatever its order execution mode,
+// and stops it. It is the way out when waiting for the next tick, as closing does, is too slow.
+type LiquidateTradingBotUseCase struct {
+	startTradingBotUseCase *StartTradingBotUseCase // Runs the loops of the bots and knows their execution contexts
+}
+
+func NewLiquidateTradingBotUseCase(startTradingBotUseCase *StartTradingBotUseCase) *LiquidateTradingBotUseCase {
+	return &LiquidateTradingBotUseCase{
+		startTradingBotUseCase: startTradingBotUseCase,
+	}
+}
+
+type InputLiquidateTradingBot struct {
+	BotId string `json:"bot_id"`
+}
+
+func (uc *LiquidateTradingBotUseCase) Execute(input InputLiquidateTradingBot) error {
+	_, err := uc.startTradingBotUseCase.changeBot(input.BotId, uc.liquidate)
+	return err
+}
+
+func (uc *LiquidateTradingBotUseCase) liquidate(tradingBot *entity.TradingBot) error {
+	if !tradingBot.GetIsPositioned() {
+		return fmt.Errorf("trading bot has no open position to liquidate")
+	}
+	if err := uc.startTradingBotUseCase.liquidatePosition(tradingBot); err != nil {
+		return err
+	}
+	fmt.Printf("🚨 [%s] Position liquidated, bot stopped\n", tradingBot.GetSymbol().GetValue())
+
+	if tradingBot.GetStatus() == entity.StatusStopped {
+		return nil
+	}
+	return tradingBot.Stop()
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/service"
+	"crypgo-machine/src/domain/entity"
+	"testing"
+	"time"
+)
+
+func TestLiquidateTradingBotUseCase_SellsAndStopsThePaperBot(t *testing.T) {
+	startUseCase, tradingBotRepo, _, _ := setupStartTradingBotUseCase()
+	supervisor := service.NewBotSupervisor(nil, "")
+	startUseCase.WithSupervisor(supervisor)
+	liquidateUseCase := NewLiquidateTradingBotUseCase(startUseCase)
+
+	bot := createTestTradingBot()
+	bot.SetMode(entity.TradingModePaper)
+	if err := bot.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := bot.OpenPosition(entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 1.0, NetQuantity: 1.0, QuoteQuantity: 100.0}, time.Now()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	bot.AdjustPaperBalance(-100.0)
+	if err := tradingBotRepo.Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+	botId := bot.Id.GetValue()
+	defer supervisor.Stop(botId)
+
+	if err := liquidateUseCase.Execute(InputLiquidateTradingBot{BotId: botId}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	liquidated, _ := tradingBotRepo.GetTradeByID(botId)
+	if liquidated.GetIsPositioned() || liquidated.GetStatus() != entity.StatusStopped {
+		t.Fatalf("Expected a stopped bot out of position, got %v (positioned: %v)", liquidated.GetStatus(), liquidated.GetIsPositioned())
+	}
+	if liquidated.GetCooldownState().LastExitReason != entity.ExitReasonLiquidated {
+		t.Errorf("Expected the exit to be recorded as a liquidation, got %q", liquidated.GetCooldownState().LastExitReason)
+	}
+	if liquidated.GetPaperBalance() <= 9900.0 {
+		t.Errorf("Expected the sale to be credited to the paper balance, got %.2f", liquidated.GetPaperBalance())
+	}
+	if state, ok := supervisor.State(botId); ok && state.Running {
+		t.Errorf("Expected no loop for the liquidated bot, got %+v", state)
+	}
+
+	// Nothing is left to liquidate
+	if err := liquidateUseCase.Execute(InputLiquidateTradingBot{BotId: botId}); err == nil {
+		t.Error("Expected a bot without position not to be liquidated")
+	}
+}
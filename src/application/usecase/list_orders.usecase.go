@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"time"
+)
+
+// ListOrdersUseCase handles listing of the order ledger
+type ListOrdersUseCase struct {
+	orderRepository repository.OrderRepository
+}
+
+// ListOrdersInput represents the input for listing orders
+type ListOrdersInput struct {
+	BotID  string    `json:"bot_id"` // Optional filter by trading bot
+	Symbol string    `json:"symbol"` // Optional filter by symbol (BTCBRL, SOLBRL, ...)
+	Side   string    `json:"side"`   // Optional filter by side (BUY, SELL)
+	Status string    `json:"status"` // Optional filter by status (FILLED, REJECTED, FAILED, ...)
+	From   time.Time `json:"from"`   // Optional, orders created at or after
+	To     time.Time `json:"to"`     // Optional, orders created before
+	Limit  int       `json:"limit"`  // Number of orders to return (default 20)
+	Offset int       `json:"offset"` // Number of orders to skip for pagination (default 0)
+}
+
+// OrderOutput represents a single order placement attempt
+type OrderOutput struct {
+	ID                string        `json:"id"`
+	BotID             string        `json:"bot_id"`
+	DecisionLogID     string        `json:"decision_log_id,omitempty"`
+	ClientOrderID     string        `json:"client_order_id,omitempty"`
+	Symbol            string        `json:"symbol"`
+	Side              string        `json:"side"`
+	Type              string        `json:"type"`
+	RequestedQuantity float64       `json:"requested_quantity"`
+	RequestedPrice    float64       `json:"requested_price"`
+	Status            string        `json:"status"`
+	ExchangeOrderID   int64         `json:"exchange_order_id,omitempty"`
+	ExecutedQuantity  float64       `json:"executed_quantity"`
+	AveragePrice      float64       `json:"average_price"`
+	QuoteQuantity     float64       `json:"quote_quantity"`
+	Commission        float64       `json:"commission"`
+	CommissionAsset   string        `json:"commission_asset,omitempty"`
+	FeesInQuote       float64       `json:"fees_in_quote"`
+	Estimated         bool          `json:"estimated"`
+	Fills             []entity.Fill `json:"fills"`
+	Error             string        `json:"error,omitempty"`
+	CreatedAt         string        `json:"created_at"`
+}
+
+// ListOrdersOutput represents the output of listing orders
+type ListOrdersOutput struct {
+	Orders []OrderOutput `json:"orders"`
+	Total  int           `json:"total"`
+}
+
+// NewListOrdersUseCase creates a new use case for listing orders
+func NewListOrdersUseCase(orderRepository repository.OrderRepository) *ListOrdersUseCase {
+	return &ListOrdersUseCase{
+		orderRepository: orderRepository,
+	}
+}
+
+// Execute lists orders with optional filters, most recent first
+func (uc *ListOrdersUseCase) Execute(input ListOrdersInput) (*ListOrdersOutput, error) {
+	if input.Limit <= 0 {
+		input.Limit = 20
+	}
+
+	orders, total, err := uc.orderRepository.GetOrdersWithFilters(repository.OrderFilter{
+		TradingBotId: input.BotID,
+		Symbol:       input.Symbol,
+		Side:         input.Side,
+		Status:       input.Status,
+		From:         input.From,
+		To:           input.To,
+		Limit:        input.Limit,
+		Offset:       input.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outputOrders := make([]OrderOutput, 0, len(orders))
+	for _, order := range orders {
+		fill := order.GetFill()
+		fills := fill.Fills
+		if fills == nil {
+			fills = []entity.Fill{}
+		}
+
+		outputOrders = append(outputOrders, OrderOutput{
+			ID:                order.GetId().GetValue(),
+			BotID:             order.GetTradingBotId().GetValue(),
+			DecisionLogID:     order.GetDecisionLogId(),
+			ClientOrderID:     order.GetClientOrderId(),
+			Symbol:            order.GetSymbol(),
+			Side:              string(order.GetSide()),
+			Type:              order.GetOrderType(),
+			RequestedQuantity: order.GetRequestedQuantity(),
+			RequestedPrice:    order.GetRequestedPrice(),
+			Status:            string(order.GetStatus()),
+			ExchangeOrderID:   fill.ExchangeOrderID,
+			ExecutedQuantity:  fill.ExecutedQuantity,
+			AveragePrice:      fill.AveragePrice,
+			QuoteQuantity:     fill.QuoteQuantity,
+			Commission:        fill.Commission,
+			CommissionAsset:   fill.CommissionAsset,
+			FeesInQuote:       fill.FeesInQuote,
+			Estimated:         fill.Estimated,
+			Fills:             fills,
+			Error:             order.GetErrorMessage(),
+			CreatedAt:         order.GetCreatedAt().Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+
+	return &ListOrdersOutput{
+		Orders: outputOrders,
+		Total:  total,
+	}, nil
+}
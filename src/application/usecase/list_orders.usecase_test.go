@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/repository"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestListOrdersUseCase_AppliesTheFilters(t *testing.T) {
+	orderRepo := repository.NewOrderRepositoryInMemory()
+	botId := vo.NewEntityId()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	orders := []*entity.Order{
+		entity.RestoreOrder(vo.NewEntityId(), botId, "", "", 0, "BTCUSDT", entity.OrderSideBuy, "MARKET", 1.0, 100.0, entity.OrderStatusFilled, entity.OrderFill{}, "", base),
+		entity.RestoreOrder(vo.NewEntityId(), botId, "", "", 0, "BTCUSDT", entity.OrderSideSell, "MARKET", 1.0, 110.0, entity.OrderStatusFailed, entity.OrderFill{}, "insufficient balance", base.Add(time.Hour)),
+		entity.RestoreOrder(vo.NewEntityId(), vo.NewEntityId(), "", "", 0, "SOLBRL", entity.OrderSideBuy, "MARKET", 2.0, 50.0, entity.OrderStatusFilled, entity.OrderFill{}, "", base.Add(2*time.Hour)),
+	}
+	for _, order := range orders {
+		_ = orderRepo.Save(order)
+	}
+
+	uc := NewListOrdersUseCase(orderRepo)
+
+	tests := []struct {
+		name     string
+		input    ListOrdersInput
+		expected []*entity.Order
+	}{
+		{"no filters, most recent first", ListOrdersInput{}, []*entity.Order{orders[2], orders[1], orders[0]}},
+		{"by bot", ListOrdersInput{BotID: botId.GetValue()}, []*entity.Order{orders[1], orders[0]}},
+		{"by symbol", ListOrdersInput{Symbol: "SOLBRL"}, []*entity.Order{orders[2]}},
+		{"by side", ListOrdersInput{Side: "SELL"}, []*entity.Order{orders[1]}},
+		{"by status", ListOrdersInput{Status: "FILLED"}, []*entity.Order{orders[2], orders[0]}},
+		{"by time range", ListOrdersInput{From: base.Add(30 * time.Minute), To: base.Add(2 * time.Hour)}, []*entity.Order{orders[1]}},
+		{"with limit", ListOrdersInput{Limit: 1}, []*entity.Order{orders[2]}},
+		{"with offset", ListOrdersInput{Limit: 1, Offset: 2}, []*entity.Order{orders[0]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := uc.Execute(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(output.Orders) != len(tt.expected) {
+				t.Fatalf("expected %d orders, got %d", len(tt.expected), len(output.Orders))
+			}
+			for i, order := range tt.expected {
+				if output.Orders[i].ID != order.GetId().GetValue() {
+					t.Errorf("expected order %d to be %s, got %s", i, order.GetId().GetValue(), output.Orders[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestListOrdersUseCase_MapsTheOrder(t *testing.T) {
+	orderRepo := repository.NewOrderRepositoryInMemory()
+	uc := NewListOrdersUseCase(orderRepo)
+
+	order := entity.NewOrder(vo.NewEntityId(), "decision-1", "BTCUSDT", entity.OrderSideBuy, "MARKET", 1.0, 100.0)
+	order.MarkFailed(errors.New("insufficient balance"))
+	_ = orderRepo.Save(order)
+
+	output, err := uc.Execute(ListOrdersInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Total != 1 {
+		t.Fatalf("expected 1 order, got %d", output.Total)
+	}
+
+	listed := output.Orders[0]
+	if listed.Status != "FAILED" || listed.Error != "insufficient balance" || listed.DecisionLogID != "decision-1" {
+		t.Errorf("expected the failed order with its decision and error, got %+v", listed)
+	}
+	if listed.Fills == nil {
+		t.Error("expected an empty fills list rather than null")
+	}
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"crypgo-machine/src/domain/entity"
+)
+
+// ListStrategiesUseCase exposes the parameter schemas of every registered strategy
+type ListStrategiesUseCase struct{}
+
+func NewListStrategiesUseCase() *ListStrategiesUseCase {
+	return &ListStrategiesUseCase{}
+}
+
+func (u *ListStrategiesUseCase) Execute() []entity.StrategyDefinitionDTO {
+	definitions := entity.ListStrategyDefinitions()
+	strategies := make([]entity.StrategyDefinitionDTO, 0, len(definitions))
+	for _, definition := range definitions {
+		strategies = append(strategies, definition.ToDTO())
+	}
+	return strategies
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/repository"
+	"time"
+)
+
+// ListTradesUseCase handles listing of the closed trades ledger
+type ListTradesUseCase struct {
+	tradeRepository repository.TradeRepository
+}
+
+// ListTradesInput represents the input for listing closed trades
+type ListTradesInput struct {
+	BotID  string    `json:"bot_id"` // Optional filter by trading bot
+	Symbol string    `json:"symbol"` // Optional filter by symbol (BTCBRL, SOLBRL, ...)
+	From   time.Time `json:"from"`   // Optional, trades closed at or after
+	To     time.Time `json:"to"`     // Optional, trades closed before
+	Limit  int       `json:"limit"`  // Number of trades to return (default 20)
+	Offset int       `json:"offset"` // Number of trades to skip for pagination (default 0)
+}
+
+// TradeOutput represents a single closed trade
+type TradeOutput struct {
+	ID                string  `json:"id"`
+	BotID             string  `json:"bot_id"`
+	Symbol            string  `json:"symbol"`
+	EntryOrderID      string  `json:"entry_order_id,omitempty"`
+	ExitOrderID       string  `json:"exit_order_id"`
+	Quantity          float64 `json:"quantity"`
+	EntryPrice        float64 `json:"entry_price"`
+	ExitPrice         float64 `json:"exit_price"`
+	EntryFees         float64 `json:"entry_fees"`
+	ExitFees          float64 `json:"exit_fees"`
+	ProfitLoss        float64 `json:"profit_loss"`
+	ProfitLossPercent float64 `json:"profit_loss_percent"`
+	Currency          string  `json:"currency"`
+	OpenedAt          string  `json:"opened_at,omitempty"`
+	ClosedAt          string  `json:"closed_at"`
+}
+
+// ListTradesOutput represents the output of listing closed trades
+type ListTradesOutput struct {
+	Trades          []TradeOutput `json:"trades"`
+	Total           int           `json:"total"`
+	TotalProfitLoss float64       `json:"total_profit_loss"` // Sum of the realized P&L of the listed trades
+}
+
+// NewListTradesUseCase creates a new use case for listing closed trades
+func NewListTradesUseCase(tradeRepository repository.TradeRepository) *ListTradesUseCase {
+	return &ListTradesUseCase{
+		tradeRepository: tradeRepository,
+	}
+}
+
+// Execute lists closed trades with optional filters, most recent first
+func (uc *ListTradesUseCase) Execute(input ListTradesInput) (*ListTradesOutput, error) {
+	if input.Limit <= 0 {
+		input.Limit = 20
+	}
+
+	trades, total, err := uc.tradeRepository.GetTradesWithFilters(repository.TradeFilter{
+		TradingBotId: input.BotID,
+		Symbol:       input.Symbol,
+		From:         input.From,
+		To:           input.To,
+		Limit:        input.Limit,
+		Offset:       input.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ListTradesOutput{
+		Trades: make([]TradeOutput, 0, len(trades)),
+		Total:  total,
+	}
+	for _, trade := range trades {
+		openedAt := ""
+		if !trade.GetOpenedAt().IsZero() {
+			openedAt = trade.GetOpenedAt().Format("2006-01-02T15:04:05Z07:00")
+		}
+
+		output.Trades = append(output.Trades, TradeOutput{
+			ID:                trade.GetId().GetValue(),
+			BotID:             trade.GetTradingBotId().GetValue(),
+			Symbol:            trade.GetSymbol(),
+			EntryOrderID:      trade.GetEntryOrderId(),
+			ExitOrderID:       trade.GetExitOrderId(),
+			Quantity:          trade.GetQuantity(),
+			EntryPrice:        trade.GetEntryPrice(),
+			ExitPrice:         trade.GetExitPrice(),
+			EntryFees:         trade.GetEntryFees(),
+			ExitFees:          trade.GetExitFees(),
+			ProfitLoss:        trade.GetProfitLoss(),
+			ProfitLossPercent: trade.GetProfitLossPercent(),
+			Currency:          trade.GetCurrency(),
+			OpenedAt:          openedAt,
+			ClosedAt:          trade.GetClosedAt().Format("2006-01-02T15:04:05Z07:00"),
+		})
+		output.TotalProfitLoss += trade.GetProfitLoss()
+	}
+
+	return output, nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/repository"
+	"testing"
+	"time"
+)
+
+func TestListTradesUseCase_AppliesTheFiltersAndSumsTheProfit(t *testing.T) {
+	tradeRepo := repository.NewTradeRepositoryInMemory()
+	botId := vo.NewEntityId()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	trades := []*entity.Trade{
+		entity.RestoreTrade(vo.NewEntityId(), botId, "BTCUSDT", "", "exit-1", 1.0, 100.0, 110.0, 0.1, 0.1, 9.8, 9.8, "USDT", base.Add(-time.Hour), base),
+		entity.RestoreTrade(vo.NewEntityId(), botId, "BTCUSDT", "", "exit-2", 1.0, 110.0, 105.0, 0.1, 0.1, -5.2, -4.7, "USDT", time.Time{}, base.Add(time.Hour)),
+		entity.RestoreTrade(vo.NewEntityId(), vo.NewEntityId(), "SOLBRL", "", "exit-3", 2.0, 50.0, 51.0, 0, 0, 2.0, 2.0, "BRL", base, base.Add(2*time.Hour)),
+	}
+	for _, trade := range trades {
+		_ = tradeRepo.Save(trade)
+	}
+
+	uc := NewListTradesUseCase(tradeRepo)
+
+	tests := []struct {
+		name            string
+		input           ListTradesInput
+		expected        []*entity.Trade
+		totalProfitLoss float64
+	}{
+		{"no filters, most recent first", ListTradesInput{}, []*entity.Trade{trades[2], trades[1], trades[0]}, 6.6},
+		{"by bot", ListTradesInput{BotID: botId.GetValue()}, []*entity.Trade{trades[1], trades[0]}, 4.6},
+		{"by symbol", ListTradesInput{Symbol: "SOLBRL"}, []*entity.Trade{trades[2]}, 2.0},
+		{"by time range", ListTradesInput{From: base, To: base.Add(time.Hour)}, []*entity.Trade{trades[0]}, 9.8},
+		{"with limit", ListTradesInput{Limit: 2}, []*entity.Trade{trades[2], trades[1]}, -3.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := uc.Execute(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(output.Trades) != len(tt.expected) {
+				t.Fatalf("expected %d trades, got %d", len(tt.expected), len(output.Trades))
+			}
+			for i, trade := range tt.expected {
+				if output.Trades[i].ID != trade.GetId().GetValue() {
+					t.Errorf("expected trade %d to be %s, got %s", i, trade.GetId().GetValue(), output.Trades[i].ID)
+				}
+			}
+			if diff := output.TotalProfitLoss - tt.totalProfitLoss; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("expected total profit %.2f, got %.2f", tt.totalProfitLoss, output.TotalProfitLoss)
+			}
+		})
+	}
+}
+
+func TestListTradesUseCase_LeavesOutUnknownOpenTimes(t *testing.T) {
+	tradeRepo := repository.NewTradeRepositoryInMemory()
+	closedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	_ = tradeRepo.Save(entity.RestoreTrade(vo.NewEntityId(), vo.NewEntityId(), "BTCUSDT", "", "exit-1", 1.0, 100.0, 110.0, 0, 0, 10.0, 10.0, "USDT", time.Time{}, closedAt))
+
+	output, err := NewListTradesUseCase(tradeRepo).Execute(ListTradesInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Trades[0].OpenedAt != "" {
+		t.Errorf("expected no open time, got %q", output.Trades[0].OpenedAt)
+	}
+	if output.Trades[0].ClosedAt != "2025-01-01T12:00:00Z" {
+		t.Errorf("expected the close time in RFC3339, got %q", output.Trades[0].ClosedAt)
+	}
+}
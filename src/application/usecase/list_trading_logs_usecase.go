@@ -29,6 +29,7 @@ type TradingLogOutput struct {
 	EntryPrice          *float64               `json:"entry_price,omitempty"`
 	ProfitPercentage    *float64               `json:"profit_percentage,omitempty"`
 	StrategyName        string                 `json:"strategy_name"`
+	ConfigVersion       int                    `json:"config_version"` // Bot config version in force, 0 for logs older than the config history
 	AnalysisData        map[string]interface{} `json:"analysis_data"`
 	Timestamp           string                 `json:"timestamp"`
 	IsPositioned        bool                   `json:"is_positioned"`
@@ -93,6 +94,7 @@ func (uc *ListTradingLogsUseCase) Execute(input ListTradingLogsInput) (*ListTrad
 			Decision:     string(log.GetDecision()),
 			CurrentPrice: log.GetCurrentPrice(),
 			StrategyName: log.GetStrategyName(),
+			ConfigVersion: log.GetConfigVersion(),
 			AnalysisData: log.GetAnalysisData(),
 			Timestamp:    log.GetTimestamp().Format("2006-01-02T15:04:05Z07:00"),
 		}
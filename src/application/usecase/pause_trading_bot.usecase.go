@@ -0,0 +1,24 @@
+package usecase
+
+import "crypgo-machine/src/domain/entity"
+
+// PauseTradingBotUseCase keeps a running bot managing its open position, i.e. evaluating its exits, without letting
+// it open new positions
+type PauseTradingBotUseCase struct {
+	startTradingBotUseCase *StartTradingBotUseCase // Runs the loops of the bots
+}
+
+func NewPauseTradingBotUseCase(startTradingBotUseCase *StartTradingBotUseCase) *PauseTradingBotUseCase {
+	return &PauseTradingBotUseCase{
+		startTradingBotUseCase: startTradingBotUseCase,
+	}
+}
+
+type InputPauseTradingBot struct {
+	BotId string `json:"bot_id"`
+}
+
+func (uc *PauseTradingBotUseCase) Execute(input InputPauseTradingBot) error {
+	_, err := uc.startTradingBotUseCase.changeBot(input.BotId, (*entity.TradingBot).Pause)
+	return err
+}
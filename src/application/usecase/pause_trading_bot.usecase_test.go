@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/service"
+	"crypgo-machine/src/domain/entity"
+	"testing"
+)
+
+func TestPauseTradingBotUseCase_PausesAndResumesTheLoop(t *testing.T) {
+	startUseCase, tradingBotRepo, _, _ := setupStartTradingBotUseCase()
+	supervisor := service.NewBotSupervisor(nil, "")
+	startUseCase.WithSupervisor(supervisor)
+	pauseUseCase := NewPauseTradingBotUseCase(startUseCase)
+	resumeUseCase := NewResumeTradingBotUseCase(startUseCase)
+
+	bot := createTestTradingBot()
+	if err := tradingBotRepo.Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+	botId := bot.Id.GetValue()
+	defer supervisor.Stop(botId)
+
+	// Only a running bot is paused
+	if err := pauseUseCase.Execute(InputPauseTradingBot{BotId: botId}); err == nil {
+		t.Fatal("Expected a stopped bot not to be paused")
+	}
+	if state, _ := supervisor.State(botId); state.Running {
+		t.Errorf("Expected no loop for a stopped bot, got %+v", state)
+	}
+
+	if err := startUseCase.Execute(InputStartTradingBot{TradingBotId: botId}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := pauseUseCase.Execute(InputPauseTradingBot{BotId: botId}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// A paused bot keeps its loop to manage its exits
+	paused, _ := tradingBotRepo.GetTradeByID(botId)
+	if paused.GetStatus() != entity.StatusPaused {
+		t.Errorf("Expected bot status %v, got %v", entity.StatusPaused, paused.GetStatus())
+	}
+	if state, _ := supervisor.State(botId); !state.Running {
+		t.Errorf("Expected the loop of the paused bot to run, got %+v", state)
+	}
+	if err := startUseCase.Execute(InputStartTradingBot{TradingBotId: botId}); err == nil {
+		t.Error("Expected a paused bot to be resumed, not started")
+	}
+
+	if err := resumeUseCase.Execute(InputResumeTradingBot{BotId: botId}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	resumed, _ := tradingBotRepo.GetTradeByID(botId)
+	if resumed.GetStatus() != entity.StatusRunning {
+		t.Errorf("Expected bot status %v, got %v", entity.StatusRunning, resumed.GetStatus())
+	}
+	if state, _ := supervisor.State(botId); !state.Running {
+		t.Errorf("Expected the loop of the resumed bot to run, got %+v", state)
+	}
+
+	// A refused transition leaves the loop running
+	if err := resumeUseCase.Execute(InputResumeTradingBot{BotId: botId}); err == nil {
+		t.Error("Expected a running bot not to be resumed")
+	}
+	if state, _ := supervisor.State(botId); !state.Running {
+		t.Errorf("Expected the loop to keep running, got %+v", state)
+	}
+}
+
+func TestPauseTradingBotUseCase_InvalidBotId(t *testing.T) {
+	startUseCase, _, _, _ := setupStartTradingBotUseCase()
+	pauseUseCase := NewPauseTradingBotUseCase(startUseCase)
+
+	if err := pauseUseCase.Execute(InputPauseTradingBot{}); err == nil {
+		t.Error("Expected an error without bot_id")
+	}
+	if err := pauseUseCase.Execute(InputPauseTradingBot{BotId: "not-a-uuid"}); err == nil {
+		t.Error("Expected an error for an invalid bot_id")
+	}
+}
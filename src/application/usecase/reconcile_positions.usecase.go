@@ -0,0 +1,375 @@
+package usecase
+
+import (
+	"context"
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/infra/queue"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ReconciliationTolerancePercent is how much less than the positioned bots expect the account may hold, covering
+// step size rounding and commissions paid in the base asset
+const ReconciliationTolerancePercent = 1.0
+
+// reconciliationOrderHistoryLimit is how many of the latest exchange orders of a symbol are checked
+const reconciliationOrderHistoryLimit = 50
+
+// Outcomes of the reconciliation of a bot
+const (
+	ReconciliationConsistent     = "consistent"
+	ReconciliationCorrected      = "corrected"
+	ReconciliationNeedsAttention = "needs_attention"
+)
+
+// BotReconciliation is the outcome of comparing a bot's position with the account holdings
+type BotReconciliation struct {
+	BotId            string  `json:"bot_id"`
+	Symbol           string  `json:"symbol"`
+	Outcome          string  `json:"outcome"`
+	Reason           string  `json:"reason,omitempty"`
+	ExpectedQuantity float64 `json:"expected_quantity"`
+	HeldQuantity     float64 `json:"held_quantity"`
+}
+
+// ReconcilePositionsUseCase compares the positions the bots believe they hold with the account balances and
+// the recent order history of the exchange. A mismatch explained by an order of the bot itself, e.g. a sell that
+// filled before the process died, is corrected; anything else, e.g. a manual trade, parks the bot in
+// NEEDS_ATTENTION and sends a notification.
+type ReconcilePositionsUseCase struct {
+	tradingBotRepository repository.TradingBotRepository
+	orderRepository      repository.OrderRepository
+	tradeRepository      repository.TradeRepository
+	exchange             exchange.Exchange
+	exchangeProfiles     map[string]exchange.Exchange // Accounts of the other profiles than the default one
+	messageBroker        queue.MessageBroker
+	exchangeName         string
+}
+
+func NewReconcilePositionsUseCase(
+	tradingBotRepo repository.TradingBotRepository,
+	orderRepo repository.OrderRepository,
+	tradeRepo repository.TradeRepository,
+	exchangeClient exchange.Exchange,
+	messageBroker queue.MessageBroker,
+	exchangeName string,
+) *ReconcilePositionsUseCase {
+	return &ReconcilePositionsUseCase{
+		tradingBotRepository: tradingBotRepo,
+		orderRepository:      orderRepo,
+		tradeRepository:      tradeRepo,
+		exchange:             exchangeClient,
+		messageBroker:        messageBroker,
+		exchangeName:         exchangeName,
+	}
+}
+
+// WithExchangeProfile sets the account of an exchange profile other than the default one, whose bots are
+// reconciled against its own balances and orders
+func (uc *ReconcilePositionsUseCase) WithExchangeProfile(name string, exchangeClient exchange.Exchange) *ReconcilePositionsUseCase {
+	if uc.exchangeProfiles == nil {
+		uc.exchangeProfiles = make(map[string]exchange.Exchange)
+	}
+	uc.exchangeProfiles[name] = exchangeClient
+	return uc
+}
+
+// Execute reconciles bots, e.g. the ones left RUNNING before a restart, updating those it corrects or parks. Each
+// bot is compared with the account of its exchange profile.
+func (uc *ReconcilePositionsUseCase) Execute(bots []*entity.TradingBot) ([]BotReconciliation, error) {
+	// Bots sharing a base asset on an account share its balance, whether or not they are being reconciled
+	allBots, err := uc.tradingBotRepository.GetAllTradingBots()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trading bots: %v", err)
+	}
+	accounts := make(map[string]*reconciledAccount)
+	for _, bot := range bots {
+		profile := bot.GetExchangeProfile()
+		if _, ok := accounts[profile]; ok || bot.IsPaper() {
+			continue
+		}
+		exchangeClient, ok := uc.exchangeFor(profile)
+		if !ok {
+			continue
+		}
+		holdings, err := accountHoldings(exchangeClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account balances of exchange profile %s: %v", profile, err)
+		}
+		accounts[profile] = &reconciledAccount{
+			holdings: holdings,
+			expected: make(map[string]float64),
+			history:  newExchangeOrderHistory(exchangeClient),
+		}
+	}
+	for _, bot := range allBots {
+		if account, ok := accounts[bot.GetExchangeProfile()]; ok && bot.GetIsPositioned() && !bot.IsPaper() {
+			account.expected[baseAssetOfBot(bot)] += bot.CalculateQuantityForSell()
+		}
+	}
+
+	results := make([]BotReconciliation, 0, len(bots))
+	for _, bot := range bots {
+		result := uc.reconcileBotOnAccount(bot, accounts[bot.GetExchangeProfile()])
+		switch result.Outcome {
+		case ReconciliationCorrected:
+			fmt.Printf("🔧 [%s] Position of bot %s corrected: %s\n", result.Symbol, result.BotId, result.Reason)
+		case ReconciliationNeedsAttention:
+			fmt.Printf("🚨 [%s] Bot %s needs attention: %s\n", result.Symbol, result.BotId, result.Reason)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// reconciledAccount is the exchange account of a profile as seen by a reconciliation
+type reconciledAccount struct {
+	holdings map[string]float64
+	expected map[string]float64 // Quantity of each base asset the positioned bots on the account expect
+	history  *exchangeOrderHistory
+}
+
+// exchangeFor returns the account of an exchange profile, false when the profile is not configured
+func (uc *ReconcilePositionsUseCase) exchangeFor(profile string) (exchange.Exchange, bool) {
+	if profile == entity.DefaultExchangeProfile {
+		return uc.exchange, true
+	}
+	exchangeClient, ok := uc.exchangeProfiles[profile]
+	return exchangeClient, ok
+}
+
+// reconcileBotOnAccount reconciles a bot with the account of its exchange profile, parking live bots whose
+// profile is not configured
+func (uc *ReconcilePositionsUseCase) reconcileBotOnAccount(bot *entity.TradingBot, account *reconciledAccount) BotReconciliation {
+	if account == nil && !bot.IsPaper() {
+		result := BotReconciliation{
+			BotId:   bot.Id.GetValue(),
+			Symbol:  bot.GetSymbol().GetValue(),
+			Outcome: ReconciliationConsistent,
+		}
+		return uc.park(bot, result, fmt.Sprintf("exchange profile %s is not configured", bot.GetExchangeProfile()))
+	}
+	if account == nil {
+		account = &reconciledAccount{}
+	}
+	return uc.reconcileBot(bot, account.holdings, account.expected, account.history)
+}
+
+func (uc *ReconcilePositionsUseCase) reconcileBot(bot *entity.TradingBot, holdings, expected map[string]float64, history *exchangeOrderHistory) BotReconciliation {
+	symbol := bot.GetSymbol().GetValue()
+	asset := baseAssetOfBot(bot)
+	held := holdings[asset]
+	result := BotReconciliation{
+		BotId:        bot.Id.GetValue(),
+		Symbol:       symbol,
+		Outcome:      ReconciliationConsistent,
+		HeldQuantity: held,
+	}
+	// Paper positions are virtual, there is nothing on the exchange to reconcile them against
+	if bot.IsPaper() {
+		result.Reason = "paper trading"
+		return result
+	}
+	lastOrder := uc.latestFilledOrder(bot, "")
+
+	if bot.GetIsPositioned() {
+		result.ExpectedQuantity = bot.CalculateQuantityForSell()
+		if covers(held, expected[asset]) {
+			return result
+		}
+
+		active := bot.GetActiveOCO()
+		if active.IsActive() && (history.isFilled(symbol, active.TakeProfitOrderID) || history.isFilled(symbol, active.StopLossOrderID)) {
+			result.Reason = fmt.Sprintf("closed by OCO %d, recorded when the bot starts", active.OrderListID)
+			return result
+		}
+
+		// A sell of the bot filled but the process died before recording it
+		if lastOrder != nil && lastOrder.GetSide() == entity.OrderSideSell && !lastOrder.GetCreatedAt().Before(bot.GetPositionOpenedAt()) &&
+			history.isFilled(symbol, lastOrder.GetFill().ExchangeOrderID) {
+			quantity := bot.CalculateQuantityForSell()
+			if err := uc.closeFromSell(bot, lastOrder); err != nil {
+				return uc.park(bot, result, fmt.Sprintf("failed to record sell %d: %v", lastOrder.GetFill().ExchangeOrderID, err))
+			}
+			expected[asset] -= quantity
+			result.Outcome = ReconciliationCorrected
+			result.Reason = fmt.Sprintf("sell %d filled but was not recorded, position closed", lastOrder.GetFill().ExchangeOrderID)
+			return result
+		}
+
+		return uc.park(bot, result, fmt.Sprintf("account holds %.8f %s but positioned bots expect %.8f%s",
+			held, asset, expected[asset], history.foreignOrderNote(symbol, uc.orderRepository)))
+	}
+
+	// Holdings of a bot without a position belong to someone else, unless the bot's last fill was a buy
+	if lastOrder == nil || lastOrder.GetSide() != entity.OrderSideBuy {
+		return result
+	}
+	fill := lastOrder.GetFill()
+	result.ExpectedQuantity = fill.NetQuantity
+	if !history.isFilled(symbol, fill.ExchangeOrderID) || !covers(held-expected[asset], fill.NetQuantity) {
+		return uc.park(bot, result, fmt.Sprintf("buy %d filled but the account holds %.8f %s for %.8f expected by positioned bots",
+			fill.ExchangeOrderID, held, asset, expected[asset]))
+	}
+
+	openedAt := fill.ExecutedAt
+	if openedAt.IsZero() {
+		openedAt = lastOrder.GetCreatedAt()
+	}
+	if err := bot.OpenPosition(fill, openedAt); err != nil {
+		return uc.park(bot, result, err.Error())
+	}
+	if err := uc.tradingBotRepository.Update(bot); err != nil {
+		return uc.park(bot, result, fmt.Sprintf("failed to record buy %d: %v", fill.ExchangeOrderID, err))
+	}
+	expected[asset] += fill.NetQuantity
+	result.Outcome = ReconciliationCorrected
+	result.Reason = fmt.Sprintf("buy %d filled but was not recorded, position opened at %.8f", fill.ExchangeOrderID, fill.AveragePrice)
+	return result
+}
+
+// closeFromSell closes the position of bot with its sell order, recording the trade unless it already was
+func (uc *ReconcilePositionsUseCase) closeFromSell(bot *entity.TradingBot, sell *entity.Order) error {
+	trades, _, err := uc.tradeRepository.GetTradesWithFilters(repository.TradeFilter{TradingBotId: bot.Id.GetValue(), Limit: 1})
+	if err != nil {
+		return err
+	}
+	if len(trades) == 0 || trades[0].GetExitOrderId() != sell.GetId().GetValue() {
+		entryOrderId := ""
+		if entry := uc.latestFilledOrder(bot, entity.OrderSideBuy); entry != nil {
+			entryOrderId = entry.GetId().GetValue()
+		}
+		if err := uc.tradeRepository.Save(entity.NewClosedTrade(bot, entryOrderId, sell)); err != nil {
+			return err
+		}
+	}
+
+	if err := bot.ClosePosition(); err != nil {
+		return err
+	}
+	return uc.tradingBotRepository.Update(bot)
+}
+
+// park sets the bot aside in NEEDS_ATTENTION and notifies about it
+func (uc *ReconcilePositionsUseCase) park(bot *entity.TradingBot, result BotReconciliation, reason string) BotReconciliation {
+	result.Outcome = ReconciliationNeedsAttention
+	result.Reason = reason
+
+	bot.MarkNeedsAttention()
+	if err := uc.tradingBotRepository.Update(bot); err != nil {
+		fmt.Printf("⚠️ Failed to park bot %s: %v\n", result.BotId, err)
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"id":                result.BotId,
+		"symbol":            result.Symbol,
+		"status":            entity.StatusNeedsAttention,
+		"reason":            reason,
+		"expected_quantity": result.ExpectedQuantity,
+		"held_quantity":     result.HeldQuantity,
+	})
+	message := queue.Message{
+		RoutingKey: "trading_bot.needs_attention",
+		Payload:    payload,
+		Headers: map[string]string{
+			"event_type": "trading_bot.needs_attention",
+		},
+	}
+	if uc.messageBroker != nil {
+		if err := uc.messageBroker.Publish(uc.exchangeName, message); err != nil {
+			fmt.Printf("⚠️ Failed to publish needs attention event: %v\n", err)
+		}
+	}
+	return result
+}
+
+// latestFilledOrder returns the bot's most recent filled order of the ledger on side, any side when empty
+func (uc *ReconcilePositionsUseCase) latestFilledOrder(bot *entity.TradingBot, side entity.OrderSide) *entity.Order {
+	orders, _, err := uc.orderRepository.GetOrdersWithFilters(repository.OrderFilter{
+		TradingBotId: bot.Id.GetValue(),
+		Side:         string(side),
+		Status:       string(entity.OrderStatusFilled),
+		Limit:        1,
+	})
+	if err != nil || len(orders) == 0 {
+		return nil
+	}
+	return orders[0]
+}
+
+// accountHoldings returns the free and locked balance of every asset, locked covering open orders such as OCOs
+func accountHoldings(exchangeClient exchange.Exchange) (map[string]float64, error) {
+	balances, err := exchangeClient.GetBalances(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	holdings := make(map[string]float64)
+	for _, balance := range balances {
+		holdings[balance.Asset] = balance.Free + balance.Locked
+	}
+	return holdings, nil
+}
+
+// covers reports whether held is enough for quantity, within ReconciliationTolerancePercent
+func covers(held, quantity float64) bool {
+	return held >= quantity*(1-ReconciliationTolerancePercent/100)
+}
+
+func baseAssetOfBot(bot *entity.TradingBot) string {
+	return strings.TrimSuffix(bot.GetSymbol().GetValue(), bot.GetCurrency())
+}
+
+// exchangeOrderHistory fetches the recent orders of each symbol once per reconciliation
+type exchangeOrderHistory struct {
+	exchange exchange.Exchange
+	orders   map[string][]*exchange.Order
+}
+
+func newExchangeOrderHistory(exchangeClient exchange.Exchange) *exchangeOrderHistory {
+	return &exchangeOrderHistory{exchange: exchangeClient, orders: make(map[string][]*exchange.Order)}
+}
+
+func (h *exchangeOrderHistory) of(symbol string) []*exchange.Order {
+	if orders, ok := h.orders[symbol]; ok {
+		return orders
+	}
+	orders, err := h.exchange.ListOrders(context.Background(), symbol, reconciliationOrderHistoryLimit)
+	if err != nil {
+		fmt.Printf("⚠️ [%s] Failed to get the order history: %v\n", symbol, err)
+	}
+	h.orders[symbol] = orders
+	return orders
+}
+
+// isFilled reports whether the exchange order is among the recent orders of symbol and filled
+func (h *exchangeOrderHistory) isFilled(symbol string, orderID int64) bool {
+	if orderID == 0 {
+		return false
+	}
+	for _, order := range h.of(symbol) {
+		if order.OrderID == orderID {
+			return order.Status == exchange.OrderStatusFilled
+		}
+	}
+	return false
+}
+
+// foreignOrderNote describes the latest filled order of symbol when no bot placed it, e.g. a manual trade
+func (h *exchangeOrderHistory) foreignOrderNote(symbol string, orderRepo repository.OrderRepository) string {
+	orders := h.of(symbol)
+	for i := len(orders) - 1; i >= 0; i-- {
+		order := orders[i]
+		if order.Status != exchange.OrderStatusFilled {
+			continue
+		}
+		ledger, _, err := orderRepo.GetOrdersWithFilters(repository.OrderFilter{Symbol: symbol, ExchangeOrderId: order.OrderID, Limit: 1})
+		if err != nil || len(ledger) > 0 {
+			return ""
+		}
+		return fmt.Sprintf(", last %s order %d (%.8f) was not placed by a bot", order.Side, order.OrderID, order.ExecutedQuantity)
+	}
+	return ""
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	appRepository "crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/external"
+	"crypgo-machine/src/infra/queue"
+	"crypgo-machine/src/infra/repository"
+	"strings"
+	"testing"
+	"time"
+
+)
+
+// recordingMessageBroker keeps the published messages
+type recordingMessageBroker struct {
+	MockMessageBroker
+	messages []queue.Message
+}
+
+func (m *recordingMessageBroker) Publish(exchangeName string, message queue.Message) error {
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+type reconcileFixture struct {
+	uc        *ReconcilePositionsUseCase
+	client    *external.FakeExchange
+	botRepo   *repository.TradeBotRepositoryInMemory
+	orderRepo *repository.OrderRepositoryInMemory
+	tradeRepo *repository.TradeRepositoryInMemory
+	broker    *recordingMessageBroker
+}
+
+func setupReconcilePositionsUseCase() *reconcileFixture {
+	f := &reconcileFixture{
+		client:    external.NewFakeExchange(),
+		botRepo:   repository.NewTradeBotRepositoryInMemory(),
+		orderRepo: repository.NewOrderRepositoryInMemory(),
+		tradeRepo: repository.NewTradeRepositoryInMemory(),
+		broker:    &recordingMessageBroker{},
+	}
+	f.uc = NewReconcilePositionsUseCase(f.botRepo, f.orderRepo, f.tradeRepo, f.client, f.broker, "test-exchange")
+	return f
+}
+
+// newPositionedBot saves a SOLBRL bot holding 1.998 entered at 100 an hour ago
+func (f *reconcileFixture) newPositionedBot(t *testing.T) *entity.TradingBot {
+	bot := f.newBot(t)
+	if err := bot.OpenPosition(entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 2.0, NetQuantity: 1.998, FeesInQuote: 0.2}, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to open position: %v", err)
+	}
+	_ = f.botRepo.Update(bot)
+	return bot
+}
+
+func (f *reconcileFixture) newBot(t *testing.T) *entity.TradingBot {
+	symbol, _ := vo.NewSymbol("SOLBRL")
+	bot := entity.NewTradingBot(symbol, 2.0, entity.NewMovingAverageStrategy(5, 20), 60, 1000, 300, "BRL", 0.1, 2.0, true)
+	_ = bot.Start()
+	if err := f.botRepo.Save(bot); err != nil {
+		t.Fatalf("failed to save bot: %v", err)
+	}
+	return bot
+}
+
+// recordFilledOrder adds a filled order of the bot to the ledger and the exchange order history
+func (f *reconcileFixture) recordFilledOrder(bot *entity.TradingBot, side entity.OrderSide, exchangeOrderID int64, fill entity.OrderFill) *entity.Order {
+	fill.ExchangeOrderID = exchangeOrderID
+	fill.ExecutedAt = time.Now()
+	order := entity.NewOrder(bot.Id, "", "SOLBRL", side, "MARKET", fill.ExecutedQuantity, fill.AveragePrice)
+	order.MarkFilled(fill)
+	_ = f.orderRepo.Save(order)
+	f.client.AddOrderHistory(filledExchangeOrder(exchangeOrderID, side))
+	return order
+}
+
+func filledExchangeOrder(orderID int64, side entity.OrderSide) *exchange.Order {
+	return &exchange.Order{Symbol: "SOLBRL", OrderID: orderID, Side: side, Status: exchange.OrderStatusFilled, ExecutedQuantity: 1.99}
+}
+
+func TestReconcilePositionsUseCase_ConsistentPositions(t *testing.T) {
+	f := setupReconcilePositionsUseCase()
+	positioned := f.newPositionedBot(t)
+	idle := f.newBot(t)
+	// Step size rounding leaves a little less than the bot expects
+	f.client.SetBalance("SOL", 1.99)
+
+	results, err := f.uc.Execute([]*entity.TradingBot{positioned, idle})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, result := range results {
+		if result.Outcome != ReconciliationConsistent {
+			t.Errorf("expected bot %s to be consistent, got %+v", result.BotId, result)
+		}
+	}
+	if len(f.broker.messages) != 0 || positioned.GetStatus() != entity.StatusRunning {
+		t.Error("expected consistent bots to keep running without notification")
+	}
+}
+
+func TestReconcilePositionsUseCase_CorrectsUnrecordedSell(t *testing.T) {
+	f := setupReconcilePositionsUseCase()
+	bot := f.newPositionedBot(t)
+	sell := f.recordFilledOrder(bot, entity.OrderSideSell, 77, entity.OrderFill{AveragePrice: 110.0, ExecutedQuantity: 1.99, FeesInQuote: 0.2189})
+	f.client.SetBalance("SOL", 0.008)
+
+	results, err := f.uc.Execute([]*entity.TradingBot{bot})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if results[0].Outcome != ReconciliationCorrected {
+		t.Fatalf("expected the position to be corrected, got %+v", results[0])
+	}
+
+	stored, _ := f.botRepo.GetTradeByID(bot.Id.GetValue())
+	if stored.GetIsPositioned() || stored.GetActualQuantityHeld() != 0 || stored.GetStatus() != entity.StatusRunning {
+		t.Errorf("expected the bot to be out of position and still running, got positioned=%v status=%s", stored.GetIsPositioned(), stored.GetStatus())
+	}
+	trades, total, _ := f.tradeRepo.GetTradesWithFilters(appRepository.TradeFilter{TradingBotId: bot.Id.GetValue()})
+	if total != 1 || trades[0].GetExitOrderId() != sell.GetId().GetValue() {
+		t.Fatalf("expected the sell to be recorded as a trade, got %d trades", total)
+	}
+}
+
+func TestReconcilePositionsUseCase_ParksBotAfterManualSell(t *testing.T) {
+	f := setupReconcilePositionsUseCase()
+	bot := f.newPositionedBot(t)
+	f.client.AddOrderHistory(filledExchangeOrder(88, entity.OrderSideSell))
+	f.client.SetBalance("SOL", 0)
+
+	results, err := f.uc.Execute([]*entity.TradingBot{bot})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	result := results[0]
+	if result.Outcome != ReconciliationNeedsAttention || !strings.Contains(result.Reason, "order 88") {
+		t.Fatalf("expected the manual sell to park the bot, got %+v", result)
+	}
+
+	stored, _ := f.botRepo.GetTradeByID(bot.Id.GetValue())
+	if stored.GetStatus() != entity.StatusNeedsAttention || !stored.GetIsPositioned() {
+		t.Errorf("expected the bot parked with its position untouched, got status %s", stored.GetStatus())
+	}
+	if len(f.broker.messages) != 1 || f.broker.messages[0].RoutingKey != "trading_bot.needs_attention" {
+		t.Errorf("expected a needs attention notification, got %v", f.broker.messages)
+	}
+	if err := stored.Start(); err == nil {
+		t.Error("expected a parked bot not to start before being stopped")
+	}
+}
+
+func TestReconcilePositionsUseCase_AdoptsUnrecordedBuy(t *testing.T) {
+	f := setupReconcilePositionsUseCase()
+	bot := f.newBot(t)
+	f.recordFilledOrder(bot, entity.OrderSideBuy, 99, entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 2.0, NetQuantity: 1.998, FeesInQuote: 0.2})
+	f.client.SetBalance("SOL", 1.998)
+
+	results, err := f.uc.Execute([]*entity.TradingBot{bot})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if results[0].Outcome != ReconciliationCorrected {
+		t.Fatalf("expected the buy to be adopted, got %+v", results[0])
+	}
+	stored, _ := f.botRepo.GetTradeByID(bot.Id.GetValue())
+	if !stored.GetIsPositioned() || stored.GetEntryPrice() != 100.0 || stored.GetActualQuantityHeld() != 1.998 {
+		t.Errorf("expected the position of the buy, got positioned=%v entry=%.2f qty=%.4f",
+			stored.GetIsPositioned(), stored.GetEntryPrice(), stored.GetActualQuantityHeld())
+	}
+
+	// Without the coins on the account the buy cannot be adopted
+	other := setupReconcilePositionsUseCase()
+	lost := other.newBot(t)
+	other.recordFilledOrder(lost, entity.OrderSideBuy, 99, entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 2.0, NetQuantity: 1.998})
+	other.client.SetBalance("SOL", 0.5)
+	results, _ = other.uc.Execute([]*entity.TradingBot{lost})
+	if results[0].Outcome != ReconciliationNeedsAttention {
+		t.Errorf("expected a missing buy to park the bot, got %+v", results[0])
+	}
+}
+
+func TestReconcilePositionsUseCase_SkipsPaperBots(t *testing.T) {
+	f := setupReconcilePositionsUseCase()
+	live := f.newPositionedBot(t)
+	paper := f.newPositionedBot(t)
+	paper.SetMode(entity.TradingModePaper)
+	_ = f.botRepo.Update(paper)
+	// Only the live position is on the account
+	f.client.SetBalance("SOL", 1.998)
+
+	results, err := f.uc.Execute([]*entity.TradingBot{live, paper})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, result := range results {
+		if result.Outcome != ReconciliationConsistent {
+			t.Errorf("expected bot %s to be consistent, got %+v", result.BotId, result)
+		}
+	}
+	if results[1].Reason != "paper trading" {
+		t.Errorf("expected the paper bot to be skipped, got %+v", results[1])
+	}
+}
+
+func TestReconcilePositionsUseCase_ReconcilesEachExchangeProfile(t *testing.T) {
+	f := setupReconcilePositionsUseCase()
+	testnet := external.NewFakeExchange()
+	f.uc.WithExchangeProfile("testnet", testnet)
+
+	production := f.newPositionedBot(t)
+	onTestnet := f.newPositionedBot(t)
+	onTestnet.SetExchangeProfile("testnet")
+	_ = f.botRepo.Update(onTestnet)
+	unconfigured := f.newPositionedBot(t)
+	unconfigured.SetExchangeProfile("mock")
+	_ = f.botRepo.Update(unconfigured)
+	// Each account only holds the position of its own bot
+	f.client.SetBalance("SOL", 1.998)
+	testnet.SetBalance("SOL", 1.998)
+
+	results, err := f.uc.Execute([]*entity.TradingBot{production, onTestnet, unconfigured})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if results[0].Outcome != ReconciliationConsistent || results[1].Outcome != ReconciliationConsistent {
+		t.Errorf("expected the bots to match their own accounts, got %+v and %+v", results[0], results[1])
+	}
+	if results[2].Outcome != ReconciliationNeedsAttention || !strings.Contains(results[2].Reason, "exchange profile mock") {
+		t.Errorf("expected the bot on an unconfigured profile to be parked, got %+v", results[2])
+	}
+	stored, _ := f.botRepo.GetTradeByID(unconfigured.Id.GetValue())
+	if stored.GetStatus() != entity.StatusNeedsAttention || !stored.GetIsPositioned() {
+		t.Errorf("expected the bot parked with its position untouched, got status %s", stored.GetStatus())
+	}
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import "crypgo-machine/src/domain/entity"
+
+// ResumeTradingBotUseCase lets a paused bot open positions again
+type ResumeTradingBotUseCase struct {
+	startTradingBotUseCase *StartTradingBotUseCase // Runs the loops of the bots
+}
+
+func NewResumeTradingBotUseCase(startTradingBotUseCase *StartTradingBotUseCase) *ResumeTradingBotUseCase {
+	return &ResumeTradingBotUseCase{
+		startTradingBotUseCase: startTradingBotUseCase,
+	}
+}
+
+type InputResumeTradingBot struct {
+	BotId string `json:"bot_id"`
+}
+
+func (uc *ResumeTradingBotUseCase) Execute(input InputResumeTradingBot) error {
+	_, err := uc.startTradingBotUseCase.changeBot(input.BotId, (*entity.TradingBot).Resume)
+	return err
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import "crypgo-machine/src/application/service"
+
+// SetKillSwitchUseCase turns the risk kill switch on, blocking every entry of the live bots, or back off
+type SetKillSwitchUseCase struct {
+	riskManager *service.RiskManager
+}
+
+func NewSetKillSwitchUseCase(riskManager *service.RiskManager) *SetKillSwitchUseCase {
+	return &SetKillSwitchUseCase{
+		riskManager: riskManager,
+	}
+}
+
+type InputSetKillSwitch struct {
+	Active bool `json:"active"`
+}
+
+func (uc *SetKillSwitchUseCase) Execute(input InputSetKillSwitch) (*RiskLimitsOutput, error) {
+	limits, err := uc.riskManager.SetKillSwitch(input.Active)
+	if err != nil {
+		return nil, err
+	}
+	output := riskLimitsOutputOf(limits)
+	return &output, nil
+}
@@ -5,58 +5,85 @@ import (
 	"crypgo-machine/src/application/repository"
 	"crypgo-machine/src/application/service"
 	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
 	"crypgo-machine/src/domain/vo"
-	"crypgo-machine/src/infra/external"
 	"crypgo-machine/src/infra/queue"
 	"fmt"
-	"strconv"
 	"time"
 )
 
 type StartTradingBotUseCase struct {
 	tradingBotRepository         repository.TradingBotRepository
 	tradingDecisionLogRepository repository.TradingDecisionLogRepository
-	client                       external.BinanceClientInterface
+	exchange                     exchange.Exchange
 	dataSource                   service.MarketDataSource
+	streamingDataSource          *service.StreamingMarketDataSource // Used by bots that opted in to kline streaming (nil = polling only)
 	executionContext             service.TradingExecutionContext
+	paperExecutionContext        service.TradingExecutionContext // Used by paper bots (nil = executionContext runs all bots)
+	riskManager                  *service.RiskManager            // Enforces the risk limits across live bots (nil = no limits)
+	supervisor                   *service.BotSupervisor          // Runs the loop of every started bot
+	exchangeProfiles             map[string]exchangeProfileServices // Services of the bots on other profiles than the default one
+}
+
+// exchangeProfileServices reads the market data of and trades for the bots on an exchange profile
+type exchangeProfileServices struct {
+	dataSource       service.MarketDataSource
+	executionContext service.TradingExecutionContext
 }
 
 func NewStartTradingBotUseCase(
 	tradingBotRepo repository.TradingBotRepository,
 	decisionLogRepo repository.TradingDecisionLogRepository,
-	client external.BinanceClientInterface,
+	exchangeClient exchange.Exchange,
 ) *StartTradingBotUseCase {
 	// Create default live implementations for backward compatibility
-	dataSource := service.NewLiveMarketDataSource(client)
-	executionContext := service.NewLiveTradingExecutionContext(client, tradingBotRepo, decisionLogRepo, nil, "")
+	dataSource := service.NewLiveMarketDataSource(exchangeClient)
+	executionContext := service.NewLiveTradingExecutionContext(exchangeClient, tradingBotRepo, decisionLogRepo, nil, "")
+	paperExecutionContext := service.NewPaperTradingExecutionContext(tradingBotRepo, decisionLogRepo, nil, "", service.DefaultPaperSlippagePercent)
 	
 	return &StartTradingBotUseCase{
 		tradingBotRepository:         tradingBotRepo,
 		tradingDecisionLogRepository: decisionLogRepo,
-		client:                       client,
+		exchange:                     exchangeClient,
 		dataSource:                   dataSource,
 		executionContext:             executionContext,
+		paperExecutionContext:        paperExecutionContext,
+		supervisor:                   service.NewBotSupervisor(nil, ""),
 	}
 }
 
 // NewStartTradingBotUseCaseWithMessaging creates a new StartTradingBotUseCase with message broker for notifications
+// All bots read their klines from klineCache, so bots on the same symbol and interval share one fetch,
+// bots that opted in to streaming receive their klines from klineStream,
+// and every order and closed trade is recorded in the order and trade ledger
 func NewStartTradingBotUseCaseWithMessaging(
 	tradingBotRepo repository.TradingBotRepository,
 	decisionLogRepo repository.TradingDecisionLogRepository,
-	client external.BinanceClientInterface,
+	orderRepo repository.OrderRepository,
+	tradeRepo repository.TradeRepository,
+	exchangeClient exchange.Exchange,
+	klineCache *service.KlineCache,
+	klineStream service.KlineStream,
 	messageBroker queue.MessageBroker,
 	exchangeName string,
 ) *StartTradingBotUseCase {
 	// Create live implementations with messaging support
-	dataSource := service.NewLiveMarketDataSource(client)
-	executionContext := service.NewLiveTradingExecutionContext(client, tradingBotRepo, decisionLogRepo, messageBroker, exchangeName)
+	dataSource := service.NewLiveMarketDataSourceWithCache(klineCache)
+	streamingDataSource := service.NewStreamingMarketDataSource(klineCache, klineStream)
+	executionContext := service.NewLiveTradingExecutionContext(exchangeClient, tradingBotRepo, decisionLogRepo, messageBroker, exchangeName).
+		WithLedger(orderRepo, tradeRepo).
+		WithCapitalAllocator(service.NewCapitalAllocator(exchangeClient))
+	paperExecutionContext := service.NewPaperTradingExecutionContext(tradingBotRepo, decisionLogRepo, messageBroker, exchangeName, service.DefaultPaperSlippagePercent)
 	
 	return &StartTradingBotUseCase{
 		tradingBotRepository:         tradingBotRepo,
 		tradingDecisionLogRepository: decisionLogRepo,
-		client:                       client,
+		exchange:                     exchangeClient,
 		dataSource:                   dataSource,
+		streamingDataSource:          streamingDataSource,
 		executionContext:             executionContext,
+		paperExecutionContext:        paperExecutionContext,
+		supervisor:                   service.NewBotSupervisor(messageBroker, exchangeName),
 	}
 }
 
@@ -64,17 +91,80 @@ func NewStartTradingBotUseCaseWithMessaging(
 func NewStartTradingBotUseCaseWithServices(
 	tradingBotRepo repository.TradingBotRepository,
 	decisionLogRepo repository.TradingDecisionLogRepository,
-	client external.BinanceClientInterface,
+	exchangeClient exchange.Exchange,
 	dataSource service.MarketDataSource,
 	executionContext service.TradingExecutionContext,
 ) *StartTradingBotUseCase {
 	return &StartTradingBotUseCase{
 		tradingBotRepository:         tradingBotRepo,
 		tradingDecisionLogRepository: decisionLogRepo,
-		client:                       client,
+		exchange:                     exchangeClient,
 		dataSource:                   dataSource,
 		executionContext:             executionContext,
+		supervisor:                   service.NewBotSupervisor(nil, ""),
+	}
+}
+
+// WithStreamingDataSource sets the data source used by bots that opted in to kline streaming
+func (uc *StartTradingBotUseCase) WithStreamingDataSource(streamingDataSource *service.StreamingMarketDataSource) *StartTradingBotUseCase {
+	uc.streamingDataSource = streamingDataSource
+	return uc
+}
+
+// WithPaperExecutionContext sets the execution context of paper bots, e.g. one with a custom slippage
+func (uc *StartTradingBotUseCase) WithPaperExecutionContext(paperExecutionContext service.TradingExecutionContext) *StartTradingBotUseCase {
+	uc.paperExecutionContext = paperExecutionContext
+	return uc
+}
+
+// WithRiskManager checks the decisions of live bots against the risk limits before they are executed, and liquidates
+// the positions of every live bot as soon as a limit flattening them is breached
+func (uc *StartTradingBotUseCase) WithRiskManager(riskManager *service.RiskManager) *StartTradingBotUseCase {
+	uc.riskManager = riskManager.WithPositionFlattener(uc)
+	return uc
+}
+
+// WithSupervisor runs the bot loops in supervisor, shared with the use cases stopping them
+func (uc *StartTradingBotUseCase) WithSupervisor(supervisor *service.BotSupervisor) *StartTradingBotUseCase {
+	uc.supervisor = supervisor
+	return uc
+}
+
+// WithExchangeProfile sets the data source and execution context of the bots trading on an exchange profile other
+// than the default one, e.g. on testnet
+func (uc *StartTradingBotUseCase) WithExchangeProfile(name string, dataSource service.MarketDataSource, executionContext service.TradingExecutionContext) *StartTradingBotUseCase {
+	if uc.exchangeProfiles == nil {
+		uc.exchangeProfiles = make(map[string]exchangeProfileServices)
+	}
+	uc.exchangeProfiles[name] = exchangeProfileServices{dataSource: dataSource, executionContext: executionContext}
+	return uc
+}
+
+// profileServices returns the services of the bot's exchange profile, false when the profile is not configured
+func (uc *StartTradingBotUseCase) profileServices(tradingBot *entity.TradingBot) (exchangeProfileServices, bool) {
+	if tradingBot.GetExchangeProfile() == entity.DefaultExchangeProfile {
+		return exchangeProfileServices{dataSource: uc.dataSource, executionContext: uc.executionContext}, true
 	}
+	services, ok := uc.exchangeProfiles[tradingBot.GetExchangeProfile()]
+	return services, ok
+}
+
+// checkExchangeProfile refuses to run a bot whose exchange profile is not configured, rather than trade on another account
+func (uc *StartTradingBotUseCase) checkExchangeProfile(tradingBot *entity.TradingBot) error {
+	if _, ok := uc.profileServices(tradingBot); !ok {
+		return fmt.Errorf("exchange profile %s of the bot is not configured", tradingBot.GetExchangeProfile())
+	}
+	return nil
+}
+
+// executionContextFor returns the paper execution context for paper bots, the one of the bot's exchange profile
+// otherwise (nil when the profile is not configured)
+func (uc *StartTradingBotUseCase) executionContextFor(tradingBot *entity.TradingBot) service.TradingExecutionContext {
+	if tradingBot.IsPaper() && uc.paperExecutionContext != nil {
+		return uc.paperExecutionContext
+	}
+	services, _ := uc.profileServices(tradingBot)
+	return services.executionContext
 }
 
 type InputStartTradingBot struct {
@@ -89,6 +179,16 @@ func (uc *StartTradingBotUseCase) Execute(input InputStartTradingBot) error {
 	if tradingBot == nil {
 		return fmt.Errorf("trading bot not found")
 	}
+	if err := uc.checkExchangeProfile(tradingBot); err != nil {
+		return err
+	}
+
+	// The OCO protecting the position may have filled while the bot was stopped
+	if reconciler, ok := uc.executionContextFor(tradingBot).(service.PositionReconciler); ok {
+		if err := reconciler.ReconcilePosition(tradingBot, time.Now()); err != nil {
+			fmt.Printf("⚠️ [%s] Failed to reconcile position: %v\n", tradingBot.GetSymbol().GetValue(), err)
+		}
+	}
 
 	errStart := tradingBot.Start()
 	if errStart != nil {
@@ -100,7 +200,9 @@ func (uc *StartTradingBotUseCase) Execute(input InputStartTradingBot) error {
 		return errSave
 	}
 
-	go uc.runStrategyLoop(tradingBot)
+	if err := uc.RunLoop(tradingBot); err != nil {
+		return err
+	}
 	
 	fmt.Printf("✅ [%s] Bot started - %s strategy (%.6f qty, %ds intervals)\n", 
 		tradingBot.GetSymbol().GetValue(), 
@@ -111,11 +213,119 @@ func (uc *StartTradingBotUseCase) Execute(input InputStartTradingBot) error {
 	return nil
 }
 
-func (uc *StartTradingBotUseCase) runStrategyLoop(tradingBot *entity.TradingBot) {
+// RunLoop runs the trading loop of an active bot as it is, e.g. of a paused or closing bot after a server restart
+func (uc *StartTradingBotUseCase) RunLoop(tradingBot *entity.TradingBot) error {
+	if !tradingBot.IsActive() {
+		return fmt.Errorf("trading bot is not active, current status: %s", tradingBot.GetStatus())
+	}
+	if err := uc.checkExchangeProfile(tradingBot); err != nil {
+		return err
+	}
+	return uc.supervisor.Start(tradingBot.Id.GetValue(), tradingBot.GetSymbol().GetValue(), func(ctx context.Context, tick func(func() error)) {
+		uc.runStrategyLoop(ctx, tradingBot, tick)
+	})
+}
+
+// changeBot applies change to a bot with its loop stopped, so the loop never trades on a stale status or config,
+// and runs the loop again when the bot is still active afterwards, even if the change failed
+func (uc *StartTradingBotUseCase) changeBot(botId string, change func(*entity.TradingBot) error) (*entity.TradingBot, error) {
+	return uc.changeAndSaveBot(botId, change, uc.tradingBotRepository.Update)
+}
+
+// changeAndSaveBot is changeBot saving the changed bot with save. When the change or its save fails the bot is read
+// again, so the loop runs on the bot as saved rather than on a change applied in memory only.
+func (uc *StartTradingBotUseCase) changeAndSaveBot(botId string, change, save func(*entity.TradingBot) error) (*entity.TradingBot, error) {
+	if botId == "" {
+		return nil, fmt.Errorf("bot_id is required")
+	}
+	if _, err := vo.RestoreEntityId(botId); err != nil {
+		return nil, fmt.Errorf("invalid bot_id format: %v", err)
+	}
+
+	// Wait for the tick in flight, so the bot is read after its last trade
+	uc.supervisor.Stop(botId)
+
+	tradingBot, err := uc.tradingBotRepository.GetTradeByID(botId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trading bot: %v", err)
+	}
+	if tradingBot == nil {
+		return nil, fmt.Errorf("trading bot not found with id: %s", botId)
+	}
+
+	errChange := change(tradingBot)
+	if errChange == nil {
+		if err := save(tradingBot); err != nil {
+			errChange = fmt.Errorf("failed to update trading bot: %v", err)
+		}
+	}
+	if errChange != nil {
+		saved, err := uc.tradingBotRepository.GetTradeByID(botId)
+		if err != nil || saved == nil {
+			fmt.Printf("❌ [%s] Failed to read bot %s again after a failed change, not running its loop: %v\n", tradingBot.GetSymbol().GetValue(), botId, err)
+			return tradingBot, errChange
+		}
+		tradingBot = saved
+	}
+	if tradingBot.IsActive() {
+		if err := uc.RunLoop(tradingBot); err != nil {
+			fmt.Printf("❌ [%s] Failed to run the loop of bot %s again: %v\n", tradingBot.GetSymbol().GetValue(), botId, err)
+		}
+	}
+	return tradingBot, errChange
+}
+
+// runStrategyLoop runs the bot until the supervisor cancels ctx, every analysis going through tick
+func (uc *StartTradingBotUseCase) runStrategyLoop(ctx context.Context, tradingBot *entity.TradingBot, tick func(func() error)) {
+	if uc.streams(tradingBot) {
+		uc.runStreamingLoop(ctx, tradingBot, tick)
+		return
+	}
+	if tradingBot.GetUseStreaming() && uc.streamingDataSource != nil {
+		fmt.Printf("⚠️ [%s] Kline stream only follows the %s exchange profile, bot %s on profile %s polls instead\n",
+			tradingBot.GetSymbol().GetValue(), entity.DefaultExchangeProfile, tradingBot.Id.GetValue(), tradingBot.GetExchangeProfile())
+	}
+	uc.runPollingLoop(ctx, tradingBot, tick)
+}
+
+// runStreamingLoop decides on every candle close received from the kline stream
+func (uc *StartTradingBotUseCase) runStreamingLoop(ctx context.Context, tradingBot *entity.TradingBot, tick func(func() error)) {
+	symbol := tradingBot.GetSymbol().GetValue()
+	candleCloses, unsubscribe, err := uc.streamingDataSource.SubscribeCandleCloses(symbol, tradingBot.GetIntervalSeconds())
+	if err != nil {
+		fmt.Printf("⚠️ [%s] Kline stream unavailable, falling back to polling: %v\n", symbol, err)
+		uc.runPollingLoop(ctx, tradingBot, tick)
+		return
+	}
+	defer unsubscribe()
+
+	// Execute first analysis immediately
+	if !uc.analyzeWhileActive(tradingBot, tick) {
+		return
+	}
+
+	// Status ticker - show summary every 10 minutes
+	statusTicker := time.NewTicker(10 * time.Minute)
+	defer statusTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Printf("🛑 Trading bot %s stopped, exiting loop\n", tradingBot.Id.GetValue())
+			return
+		case <-candleCloses:
+			if !uc.analyzeWhileActive(tradingBot, tick) {
+				return
+			}
+		case <-statusTicker.C:
+			uc.printStatusSummary(tradingBot)
+		}
+	}
+}
+
+func (uc *StartTradingBotUseCase) runPollingLoop(ctx context.Context, tradingBot *entity.TradingBot, tick func(func() error)) {
 	// Execute first analysis immediately
-	shouldContinue := uc.executeAnalysisAndTrade(tradingBot)
-	if !shouldContinue {
-		fmt.Printf("🛑 Trading bot %s stopped before starting loop\n", tradingBot.Id.GetValue())
+	if !uc.analyzeWhileActive(tradingBot, tick) {
 		return
 	}
 
@@ -129,50 +339,119 @@ func (uc *StartTradingBotUseCase) runStrategyLoop(tradingBot *entity.TradingBot)
 
 	for {
 		select {
+		case <-ctx.Done():
+			fmt.Printf("🛑 Trading bot %s stopped, exiting loop\n", tradingBot.Id.GetValue())
+			return // Exit the loop completely
 		case <-ticker.C:
-			shouldContinue := uc.executeAnalysisAndTrade(tradingBot)
-			if !shouldContinue {
-				fmt.Printf("🛑 Trading bot %s stopped, exiting loop\n", tradingBot.Id.GetValue())
-				return // Exit the loop completely
+			if !uc.analyzeWhileActive(tradingBot, tick) {
+				return
 			}
 		case <-statusTicker.C:
-			// Show periodic status summary
-			symbol := tradingBot.GetSymbol().GetValue()
-			if tradingBot.GetIsPositioned() {
-				entryPrice := tradingBot.GetEntryPrice()
-				if entryPrice > 0 {
-					// Get current price for profit calculation
-					klines, err := uc.dataSource.GetMarketData(symbol, tradingBot.GetIntervalSeconds())
-					if err == nil && len(klines) > 0 {
-						currentPrice := klines[len(klines)-1].Close()
-						profit := ((currentPrice - entryPrice) / entryPrice) * 100
-						fmt.Printf("📊 [%s] Status: POSITIONED (entry: %.2f, current: %.2f, profit: %.2f%%)\n", 
-							symbol, entryPrice, currentPrice, profit)
-					}
-				}
-			} else {
-				fmt.Printf("📊 [%s] Status: MONITORING (no position)\n", symbol)
+			uc.printStatusSummary(tradingBot)
+		}
+	}
+}
+
+// analyzeWhileActive runs one analysis of the bot through tick and reports whether the bot is still active, a
+// closing bot stopping once its position is sold
+func (uc *StartTradingBotUseCase) analyzeWhileActive(tradingBot *entity.TradingBot, tick func(func() error)) bool {
+	tick(func() error { return uc.ExecuteAnalysisAndTrade(tradingBot) })
+	if tradingBot.IsActive() {
+		return true
+	}
+	fmt.Printf("🏁 Trading bot %s is %s, exiting loop\n", tradingBot.Id.GetValue(), tradingBot.GetStatus())
+	return false
+}
+
+// printStatusSummary shows the periodic status summary of a running bot
+func (uc *StartTradingBotUseCase) printStatusSummary(tradingBot *entity.TradingBot) {
+	symbol := tradingBot.GetSymbol().GetValue()
+	if tradingBot.GetIsPositioned() {
+		entryPrice := tradingBot.GetEntryPrice()
+		if entryPrice > 0 {
+			// Get current price for profit calculation
+			klines, err := uc.marketDataSource(tradingBot).GetMarketData(symbol, tradingBot.GetIntervalSeconds())
+			if err == nil && len(klines) > 0 {
+				currentPrice := klines[len(klines)-1].Close()
+				profit := ((currentPrice - entryPrice) / entryPrice) * 100
+				fmt.Printf("📊 [%s] Status: POSITIONED (entry: %.2f, current: %.2f, profit: %.2f%%)\n", 
+					symbol, entryPrice, currentPrice, profit)
 			}
 		}
+	} else {
+		fmt.Printf("📊 [%s] Status: MONITORING (no position)\n", symbol)
 	}
 }
 
+// marketDataSource returns the streaming data source for bots that opted in to it, the one of the bot's exchange
+// profile otherwise
+func (uc *StartTradingBotUseCase) marketDataSource(tradingBot *entity.TradingBot) service.MarketDataSource {
+	if uc.streams(tradingBot) {
+		return uc.streamingDataSource
+	}
+	services, _ := uc.profileServices(tradingBot)
+	return services.dataSource
+}
+
+// streams reports whether the bot decides on the candle closes of the kline stream. The stream follows the default
+// exchange profile, bots on other profiles poll theirs.
+func (uc *StartTradingBotUseCase) streams(tradingBot *entity.TradingBot) bool {
+	return tradingBot.GetUseStreaming() && uc.streamingDataSource != nil && tradingBot.GetExchangeProfile() == entity.DefaultExchangeProfile
+}
+
 // ExecuteAnalysisAndTrade performs a single analysis and trading decision
 // This method is public so it can be reused by other use cases like backtest
 func (uc *StartTradingBotUseCase) ExecuteAnalysisAndTrade(tradingBot *entity.TradingBot) error {
+	if err := uc.checkExchangeProfile(tradingBot); err != nil {
+		return err
+	}
+
 	// Fetch market data using abstraction with bot's configured interval
-	klines, err := uc.dataSource.GetMarketData(tradingBot.GetSymbol().GetValue(), tradingBot.GetIntervalSeconds())
+	dataSource := uc.marketDataSource(tradingBot)
+	klines, err := dataSource.GetMarketData(tradingBot.GetSymbol().GetValue(), tradingBot.GetIntervalSeconds())
 	if err != nil {
 		return fmt.Errorf("error fetching market data for %s with interval %ds: %v", tradingBot.GetSymbol().GetValue(), tradingBot.GetIntervalSeconds(), err)
 	}
 
+	// Multi-timeframe strategies also get the klines of their additional intervals
 	strategy := tradingBot.GetStrategy()
-	analysisResult := strategy.Decide(klines, tradingBot)
+	timeframes, err := service.FetchTimeframes(dataSource, tradingBot.GetSymbol().GetValue(), entity.RequiredTimeframes(strategy))
+	if err != nil {
+		return fmt.Errorf("error fetching market data for %s: %v", tradingBot.GetSymbol().GetValue(), err)
+	}
 
-	// Create and save decision log
 	currentPrice := klines[len(klines)-1].Close()
-	currentTime := uc.dataSource.GetCurrentTime()
+	currentTime := dataSource.GetCurrentTime()
+	tradingBot.UpdateATR(klines)
+
+	executionContext := uc.executionContextFor(tradingBot)
+
+	// Pick up a position closed on the exchange by the bot's OCO since the last tick
+	if reconciler, ok := executionContext.(service.PositionReconciler); ok {
+		if err := reconciler.ReconcilePosition(tradingBot, currentTime); err != nil {
+			fmt.Printf("⚠️ [%s] Failed to reconcile position: %v\n", tradingBot.GetSymbol().GetValue(), err)
+		}
+	}
 
+	// Keep the high-water mark persisted so the trailing stop survives restarts (no repository in backtests)
+	if tradingBot.TrackPosition(currentPrice, currentTime) && uc.tradingBotRepository != nil {
+		if err := uc.tradingBotRepository.Update(tradingBot); err != nil {
+			fmt.Printf("⚠️ Failed to persist position tracking: %v\n", err)
+		}
+	}
+
+	// Bot-level exit rules take precedence over the strategy
+	analysisResult := tradingBot.EvaluateExitRules(currentPrice, currentTime)
+	if analysisResult == nil {
+		analysisResult = entity.DecideStrategy(strategy, klines, timeframes, tradingBot)
+	}
+	analysisResult = tradingBot.ApplyCooldown(analysisResult, currentTime)
+	analysisResult = tradingBot.ApplyLifecycle(analysisResult)
+	if uc.riskManager != nil && !tradingBot.IsPaper() {
+		analysisResult = uc.applyRiskLimits(tradingBot, analysisResult, currentPrice)
+	}
+
+	// Create and save decision log
 	// Extract possible profit from analysis data, defaulting to 0.0 if not found
 	possibleProfit := 0.0
 	if profit, exists := analysisResult.AnalysisData["possibleProfit"]; exists {
@@ -190,9 +469,10 @@ func (uc *StartTradingBotUseCase) ExecuteAnalysisAndTrade(tradingBot *entity.Tra
 		currentPrice,
 		possibleProfit,
 	)
+	decisionLog.SetConfigVersion(tradingBot.GetConfigVersion())
 
 	// Save decision log using execution context
-	if err := uc.executionContext.OnDecisionMade(decisionLog); err != nil {
+	if err := executionContext.OnDecisionMade(decisionLog); err != nil {
 		fmt.Printf("⚠️ Failed to save decision log: %v\n", err)
 	}
 
@@ -229,111 +509,158 @@ func (uc *StartTradingBotUseCase) ExecuteAnalysisAndTrade(tradingBot *entity.Tra
 	}
 
 	// Execute trading decision using abstraction
-	if err := uc.executionContext.ExecuteTrade(analysisResult.Decision, tradingBot, currentPrice, currentTime); err != nil {
+	wasPositioned := tradingBot.GetIsPositioned()
+	if err := executionContext.ExecuteTrade(analysisResult.Decision, tradingBot, currentPrice, currentTime); err != nil {
 		return fmt.Errorf("error executing trade: %v", err)
 	}
+	uc.recordCooldown(tradingBot, wasPositioned, analysisResult, currentTime)
+
+	// A closing bot stops once it is out of position, whether this tick's sell or its OCO closed it
+	if tradingBot.GetStatus() == entity.StatusClosing && !tradingBot.GetIsPositioned() {
+		uc.finishClosing(tradingBot)
+	}
 
 	return nil
 }
 
-func (uc *StartTradingBotUseCase) executeAnalysisAndTrade(tradingBot *entity.TradingBot) bool {
-	currentBot, err := uc.tradingBotRepository.GetTradeByID(tradingBot.Id.GetValue())
-	if err != nil || currentBot == nil || currentBot.GetStatus() != entity.StatusRunning {
-		fmt.Printf("🔍 Trading bot %s status check: stopped or error (%v)\n", tradingBot.Id.GetValue(), err)
-		return false // Stop the loop
+// finishClosing stops a closing bot whose position was sold
+func (uc *StartTradingBotUseCase) finishClosing(tradingBot *entity.TradingBot) {
+	if err := tradingBot.FinishClosing(); err != nil {
+		fmt.Printf("⚠️ [%s] Failed to finish closing: %v\n", tradingBot.GetSymbol().GetValue(), err)
+		return
+	}
+	fmt.Printf("🏁 [%s] Position closed, bot stopped\n", tradingBot.GetSymbol().GetValue())
+	if uc.tradingBotRepository != nil {
+		if err := uc.tradingBotRepository.Update(tradingBot); err != nil {
+			fmt.Printf("⚠️ Failed to persist stopped bot: %v\n", err)
+		}
 	}
+}
 
-	// Check if execution context wants to continue
-	if !uc.executionContext.ShouldContinue() {
-		fmt.Printf("🔍 Trading bot %s execution context requested stop\n", tradingBot.Id.GetValue())
-		return false // Stop the loop
+// recordCooldown counts the entry or remembers the exit the trade made, for the cooldown rules to hold later buys.
+// An exit the execution context recorded itself during the trade is kept: when the OCO closed the position before the
+// strategy's sell reached the exchange, the reason is the OCO leg that filled, not the strategy's.
+func (uc *StartTradingBotUseCase) recordCooldown(tradingBot *entity.TradingBot, wasPositioned bool, result *entity.StrategyAnalysisResult, currentTime time.Time) {
+	switch {
+	case !wasPositioned && tradingBot.GetIsPositioned():
+		tradingBot.RecordEntry(currentTime)
+	case wasPositioned && !tradingBot.GetIsPositioned():
+		if tradingBot.GetCooldownState().LastExitAt.Equal(currentTime) {
+			break
+		}
+		reason, _ := result.AnalysisData["reason"].(string)
+		tradingBot.RecordExit(reason, currentTime)
+	default:
+		return
 	}
 
-	// Use the public method to perform the analysis and trade
-	if err := uc.ExecuteAnalysisAndTrade(tradingBot); err != nil {
-		fmt.Printf("❌ Error in analysis and trade for bot %s: %v\n", tradingBot.Id.GetValue(), err)
-		// Continue despite errors - don't stop the bot for temporary issues
+	// Only bots with cooldown rules need their state to survive a restart
+	if uc.tradingBotRepository != nil && tradingBot.GetCooldownRules().IsEnabled() {
+		if err := uc.tradingBotRepository.Update(tradingBot); err != nil {
+			fmt.Printf("⚠️ Failed to persist cooldown state: %v\n", err)
+		}
 	}
-	
-	return true // Continue the loop
-}
-
-// convertIntervalSecondsToBinanceInterval converts interval_seconds to Binance API interval format
-func convertIntervalSecondsToBinanceInterval(intervalSeconds int) string {
-	switch intervalSeconds {
-	case 60:
-		return "1m"
-	case 180:
-		return "3m"
-	case 300:
-		return "5m"
-	case 900:
-		return "15m"
-	case 1800:
-		return "30m"
-	case 3600:
-		return "1h"
-	case 7200:
-		return "2h"
-	case 14400:
-		return "4h"
-	case 21600:
-		return "6h"
-	case 28800:
-		return "8h"
-	case 43200:
-		return "12h"
-	case 86400:
-		return "1d"
-	case 259200:
-		return "3d"
-	case 604800:
-		return "1w"
-	default:
-		// Fallback: try to calculate the best match
-		if intervalSeconds < 3600 {
-			// Less than 1 hour, use minutes
-			minutes := intervalSeconds / 60
-			return fmt.Sprintf("%dm", minutes)
-		} else if intervalSeconds < 86400 {
-			// Less than 1 day, use hours
-			hours := intervalSeconds / 3600
-			return fmt.Sprintf("%dh", hours)
-		} else {
-			// Default to 1 hour for safety
-			return "1h"
+}
+
+// applyRiskLimits turns a buy into a hold while a risk limit blocks new entries, and the decision of a positioned bot
+// into a sell while the risk limits flatten the positions. A buy is held when the limits cannot be checked.
+func (uc *StartTradingBotUseCase) applyRiskLimits(tradingBot *entity.TradingBot, result *entity.StrategyAnalysisResult, currentPrice float64) *entity.StrategyAnalysisResult {
+	symbol := tradingBot.GetSymbol().GetValue()
+
+	switch {
+	case result.Decision == entity.Buy:
+		breach, err := uc.riskManager.CheckEntry(tradingBot, tradingBot.CalculateBuyQuantity(currentPrice)*currentPrice)
+		if err != nil {
+			fmt.Printf("⚠️ [%s] BUY held, risk limits could not be checked: %v\n", symbol, err)
+			return result.Override(entity.Hold, entity.HoldReasonRiskCheckFailed, map[string]interface{}{"error": err.Error()})
+		}
+		if breach != nil {
+			fmt.Printf("🛑 [%s] BUY blocked by risk limits: %v\n", symbol, breach)
+			return result.Override(entity.Hold, entity.HoldReasonRiskLimit, riskBreachDetails(breach))
+		}
+
+	case tradingBot.GetIsPositioned() && result.Decision != entity.Sell:
+		breach, err := uc.riskManager.FlattenBreach(tradingBot.GetExchangeProfile())
+		if err != nil {
+			fmt.Printf("⚠️ [%s] Risk limits could not be checked: %v\n", symbol, err)
+		}
+		if breach != nil {
+			fmt.Printf("🛑 [%s] Flattening position: %v\n", symbol, breach)
+			return result.Override(entity.Sell, entity.ExitReasonRiskFlattened, riskBreachDetails(breach))
 		}
 	}
+	return result
 }
 
-func (uc *StartTradingBotUseCase) getMarketData(symbol string, intervalSeconds int) ([]vo.Kline, error) {
-	binanceInterval := convertIntervalSecondsToBinanceInterval(intervalSeconds)
-	binanceKlines, err := uc.client.NewKlinesService().
-		Symbol(symbol).
-		Interval(binanceInterval).
-		Limit(100).
-		Do(context.Background())
+func riskBreachDetails(breach *service.RiskBreach) map[string]interface{} {
+	return map[string]interface{}{
+		"riskLimit": breach.Limit,
+		"breach":    breach.Error(),
+	}
+}
 
+// FlattenPositions liquidates at market the position of every live bot the breach flattens, right away rather than on
+// the next tick of each bot. The bots keep running, their entries blocked while the limit is breached.
+func (uc *StartTradingBotUseCase) FlattenPositions(breach *service.RiskBreach) {
+	if uc.tradingBotRepository == nil {
+		return
+	}
+	tradingBots, err := uc.tradingBotRepository.GetAllTradingBots()
 	if err != nil {
-		return nil, err
+		fmt.Printf("❌ Failed to load the trading bots to flatten their positions: %v\n", err)
+		return
 	}
 
-	klines := make([]vo.Kline, len(binanceKlines))
-	for i, bkline := range binanceKlines {
-		openPrice, _ := strconv.ParseFloat(bkline.Open, 64)
-		closePrice, _ := strconv.ParseFloat(bkline.Close, 64)
-		highPrice, _ := strconv.ParseFloat(bkline.High, 64)
-		lowPrice, _ := strconv.ParseFloat(bkline.Low, 64)
-		volumePrice, _ := strconv.ParseFloat(bkline.Volume, 64)
+	for _, tradingBot := range tradingBots {
+		if tradingBot.IsPaper() || !tradingBot.GetIsPositioned() {
+			continue
+		}
+		if breach.Profile != "" && tradingBot.GetExchangeProfile() != breach.Profile {
+			continue
+		}
 
-		kline, err := vo.NewKline(openPrice, closePrice, highPrice, lowPrice, volumePrice, bkline.CloseTime)
+		symbol := tradingBot.GetSymbol().GetValue()
+		_, err := uc.changeBot(tradingBot.Id.GetValue(), func(tradingBot *entity.TradingBot) error {
+			// The bot may have sold on its own tick meanwhile
+			if !tradingBot.GetIsPositioned() {
+				return nil
+			}
+			return uc.liquidatePosition(tradingBot)
+		})
 		if err != nil {
-			return nil, err
+			fmt.Printf("❌ [%s] Failed to flatten the position of bot %s: %v\n", symbol, tradingBot.Id.GetValue(), err)
+			continue
 		}
-		klines[i] = kline
+		fmt.Printf("🚨 [%s] Position flattened by risk limits: %v\n", symbol, breach)
 	}
-
-	return klines, nil
 }
 
+// liquidatePosition sells the position of the bot right away at market, at the latest price of its market data
+func (uc *StartTradingBotUseCase) liquidatePosition(tradingBot *entity.TradingBot) error {
+	if tradingBot.GetStatus() == entity.StatusNeedsAttention {
+		return fmt.Errorf("trading bot position needs attention, check it on the exchange first")
+	}
+	if err := uc.checkExchangeProfile(tradingBot); err != nil {
+		return err
+	}
 
+	liquidator, ok := uc.executionContextFor(tradingBot).(service.PositionLiquidator)
+	if !ok {
+		return fmt.Errorf("trading bot execution context cannot liquidate positions")
+	}
+
+	symbol := tradingBot.GetSymbol().GetValue()
+	dataSource := uc.marketDataSource(tradingBot)
+	klines, err := dataSource.GetMarketData(symbol, tradingBot.GetIntervalSeconds())
+	if err != nil {
+		return fmt.Errorf("error fetching market data for %s: %v", symbol, err)
+	}
+	if len(klines) == 0 {
+		return fmt.Errorf("no market data for %s", symbol)
+	}
+
+	if err := liquidator.LiquidatePosition(tradingBot, klines[len(klines)-1].Close(), dataSource.GetCurrentTime()); err != nil {
+		return fmt.Errorf("failed to liquidate position: %v", err)
+	}
+	return nil
+}
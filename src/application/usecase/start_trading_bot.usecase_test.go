@@ -1,17 +1,20 @@
 package usecase
 
 import (
+	"crypgo-machine/src/application/service"
 	"crypgo-machine/src/domain/entity"
 	"crypgo-machine/src/domain/vo"
 	"crypgo-machine/src/infra/external"
 	"crypgo-machine/src/infra/repository"
+	"math"
 	"testing"
+	"time"
 )
 
-func setupStartTradingBotUseCase() (*StartTradingBotUseCase, *repository.TradeBotRepositoryInMemory, *repository.TradingDecisionLogRepositoryInMemory, *external.BinanceClientFake) {
+func setupStartTradingBotUseCase() (*StartTradingBotUseCase, *repository.TradeBotRepositoryInMemory, *repository.TradingDecisionLogRepositoryInMemory, *external.FakeExchange) {
 	tradingBotRepo := repository.NewTradeBotRepositoryInMemory()
 	decisionLogRepo := repository.NewTradingDecisionLogRepositoryInMemory()
-	binanceClient := external.NewBinanceClientFake()
+	binanceClient := external.NewFakeExchange()
 
 	useCase := NewStartTradingBotUseCase(tradingBotRepo, decisionLogRepo, binanceClient)
 
@@ -120,7 +123,7 @@ func TestStartTradingBotUseCase_WhipsawScenario(t *testing.T) {
 	}
 
 	// Simulate the strategy loop execution manually (since we can't wait for goroutine)
-	klines, err := useCase.getMarketData(bot.GetSymbol().GetValue(), 300)
+	klines, err := useCase.dataSource.GetMarketData(bot.GetSymbol().GetValue(), 300)
 	if err != nil {
 		t.Fatalf("Failed to get market data: %v", err)
 	}
@@ -170,7 +173,7 @@ func TestStartTradingBotUseCase_StrongTrendScenario(t *testing.T) {
 	}
 
 	// Simulate the strategy loop execution manually
-	klines, err := useCase.getMarketData(bot.GetSymbol().GetValue(), 300)
+	klines, err := useCase.dataSource.GetMarketData(bot.GetSymbol().GetValue(), 300)
 	if err != nil {
 		t.Fatalf("Failed to get market data: %v", err)
 	}
@@ -205,7 +208,7 @@ func TestStartTradingBotUseCase_DecisionLogCreation(t *testing.T) {
 	}
 
 	// Simulate strategy execution (what happens in the goroutine)
-	klines, err := useCase.getMarketData(bot.GetSymbol().GetValue(), 300)
+	klines, err := useCase.dataSource.GetMarketData(bot.GetSymbol().GetValue(), 300)
 	if err != nil {
 		t.Fatalf("Failed to get market data: %v", err)
 	}
@@ -268,7 +271,7 @@ func TestStartTradingBotUseCase_BinanceErrorHandling(t *testing.T) {
 	}
 
 	// Try to get market data (simulating what happens in the goroutine)
-	_, err = useCase.getMarketData(bot.GetSymbol().GetValue(), 300)
+	_, err = useCase.dataSource.GetMarketData(bot.GetSymbol().GetValue(), 300)
 	if err == nil {
 		t.Fatal("Expected error from Binance client, got none")
 	}
@@ -279,6 +282,400 @@ func TestStartTradingBotUseCase_BinanceErrorHandling(t *testing.T) {
 	}
 }
 
+func TestStartTradingBotUseCase_ExitRulesRunBeforeStrategy(t *testing.T) {
+	// Oversold fall triggers an RSI buy at 106, a new high at 107, then the fall resumes
+	prices := []float64{}
+	for price := 120.0; price >= 106.0; price-- {
+		prices = append(prices, price)
+	}
+	prices = append(prices, 107.0, 105.0, 104.0)
+
+	klines := createHourlyTestKlines(prices)
+
+	updates := 0
+	tradingBotRepo := &MockTradeBotRepository{
+		UpdateFunc: func(bot *entity.TradingBot) error {
+			updates++
+			return nil
+		},
+	}
+	dataSource := service.NewHistoricalMarketDataSource(klines, 100)
+	executionContext := service.NewBacktestTradingExecutionContext("BTCBRL", 1000.0)
+	useCase := NewStartTradingBotUseCaseWithServices(tradingBotRepo, nil, external.NewFakeExchange(), dataSource, executionContext)
+
+	symbol, _ := vo.NewSymbol("BTCBRL")
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 3600, 1000.0, 100.0, "BRL", 0.1, 0.0, true)
+	bot.SetExitRules(entity.ExitRules{MaxHoldingSeconds: 7200})
+
+	for {
+		if err := useCase.ExecuteAnalysisAndTrade(bot); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !dataSource.AdvanceToNext() {
+			break
+		}
+	}
+
+	if updates != 1 {
+		t.Errorf("Expected the new high-water mark to be persisted once, got %d updates", updates)
+	}
+
+	result := executionContext.GetResult()
+	if result.TotalTrades != 1 || result.LosingTrades != 1 {
+		t.Fatalf("Expected one losing trade closed by the exit rule, got %d trades (%d losing)", result.TotalTrades, result.LosingTrades)
+	}
+	if result.Trades[0].ExitPrice != 105.0 {
+		t.Errorf("Expected exit at 105 after 2 hours, got %.2f", result.Trades[0].ExitPrice)
+	}
+
+	exitDecision := result.Decisions[16]
+	if exitDecision.GetDecision() != entity.Sell || exitDecision.GetAnalysisData()["reason"] != entity.ExitReasonMaxHoldingTime {
+		t.Errorf("Expected max holding time sell in the decision log, got %s (%v)", exitDecision.GetDecision(), exitDecision.GetAnalysisData()["reason"])
+	}
+}
+
+func TestStartTradingBotUseCase_CooldownHoldsReentries(t *testing.T) {
+	// Oversold fall: an RSI buy at 106, closed 2 hours later by the max holding time, then the fall goes on
+	prices := []float64{}
+	for price := 120.0; price >= 96.0; price-- {
+		prices = append(prices, price)
+	}
+	klines := createHourlyTestKlines(prices)
+
+	dataSource := service.NewHistoricalMarketDataSource(klines, 100)
+	executionContext := service.NewBacktestTradingExecutionContext("BTCBRL", 1000.0)
+	useCase := NewStartTradingBotUseCaseWithServices(&MockTradeBotRepository{}, nil, external.NewFakeExchange(), dataSource, executionContext)
+
+	symbol, _ := vo.NewSymbol("BTCBRL")
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 3600, 1000.0, 100.0, "BRL", 0.1, 0.0, true)
+	bot.SetExitRules(entity.ExitRules{MaxHoldingSeconds: 7200})
+	bot.SetCooldownRules(entity.CooldownRules{CooldownCandles: 24})
+
+	for {
+		if err := useCase.ExecuteAnalysisAndTrade(bot); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !dataSource.AdvanceToNext() {
+			break
+		}
+	}
+
+	result := executionContext.GetResult()
+	if result.TotalTrades != 1 || bot.GetIsPositioned() {
+		t.Fatalf("Expected a single trade within the cooldown, got %d trades", result.TotalTrades)
+	}
+	state := bot.GetCooldownState()
+	if state.LastExitReason != entity.ExitReasonMaxHoldingTime || state.TradesToday != 1 {
+		t.Errorf("Expected the entry and the max holding time exit to be recorded, got %+v", state)
+	}
+
+	held := 0
+	for _, decision := range result.Decisions {
+		data := decision.GetAnalysisData()
+		if data["reason"] == entity.HoldReasonCooldown {
+			held++
+			if decision.GetDecision() != entity.Hold || data["strategyDecision"] != string(entity.Buy) {
+				t.Errorf("Expected a held buy, got %s (%v)", decision.GetDecision(), data)
+			}
+		}
+	}
+	if held == 0 {
+		t.Error("Expected the cooldown reason in the decision log")
+	}
+}
+
+func TestStartTradingBotUseCase_CooldownKeepsTheExitOfTheOCO(t *testing.T) {
+	useCase := NewStartTradingBotUseCaseWithServices(&MockTradeBotRepository{}, nil, external.NewFakeExchange(), nil, nil)
+	symbol, _ := vo.NewSymbol("BTCBRL")
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 3600, 1000.0, 100.0, "BRL", 0.1, 0.0, true)
+	bot.SetCooldownRules(entity.CooldownRules{CooldownCandles: 1, StopLossCooldownCandles: 24})
+	if err := bot.OpenPosition(entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 0.001, NetQuantity: 0.001}, time.Now()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The OCO stop loss closes the position while the strategy's sell cancels it
+	now := time.Now()
+	bot.RecordExit(entity.ExitReasonOCOStopLoss, now)
+	if err := bot.ClosePosition(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	strategySell := entity.NewStrategyAnalysisResult(entity.Sell, map[string]interface{}{"reason": "rsi_overbought_sell_with_profit"})
+	useCase.recordCooldown(bot, true, strategySell, now)
+
+	if reason := bot.GetCooldownState().LastExitReason; reason != entity.ExitReasonOCOStopLoss {
+		t.Fatalf("Expected the OCO stop loss to start the cooldown, got %q", reason)
+	}
+	held := bot.ApplyCooldown(entity.NewStrategyAnalysisResult(entity.Buy, map[string]interface{}{}), now.Add(2*time.Hour))
+	if held.Decision != entity.Hold || held.AnalysisData["reason"] != entity.HoldReasonStopLossCooldown {
+		t.Errorf("Expected the stop loss cooldown to hold the buy, got %s (%v)", held.Decision, held.AnalysisData["reason"])
+	}
+}
+
+func TestStartTradingBotUseCase_LifecycleOverridesTheStrategy(t *testing.T) {
+	// Oversold fall that triggers an RSI buy at 106
+	prices := []float64{}
+	for price := 120.0; price >= 96.0; price-- {
+		prices = append(prices, price)
+	}
+	symbol, _ := vo.NewSymbol("BTCBRL")
+
+	// A paused bot takes no entries
+	dataSource := service.NewHistoricalMarketDataSource(createHourlyTestKlines(prices), 100)
+	executionContext := service.NewBacktestTradingExecutionContext("BTCBRL", 1000.0)
+	useCase := NewStartTradingBotUseCaseWithServices(&MockTradeBotRepository{}, nil, external.NewFakeExchange(), dataSource, executionContext)
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 3600, 1000.0, 100.0, "BRL", 0.1, 0.0, true)
+	bot.Start()
+	bot.Pause()
+	for {
+		if err := useCase.ExecuteAnalysisAndTrade(bot); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !dataSource.AdvanceToNext() {
+			break
+		}
+	}
+	result := executionContext.GetResult()
+	held := 0
+	for _, decision := range result.Decisions {
+		if decision.GetAnalysisData()["reason"] == entity.HoldReasonPaused {
+			held++
+		}
+	}
+	if result.TotalTrades != 0 || held == 0 || bot.GetStatus() != entity.StatusPaused {
+		t.Fatalf("Expected the paused bot to hold its buys, got %d trades and %d held buys", result.TotalTrades, held)
+	}
+
+	// A closing bot sells on its next tick, then stops
+	dataSource = service.NewHistoricalMarketDataSource(createHourlyTestKlines(prices), 100)
+	executionContext = service.NewBacktestTradingExecutionContext("BTCBRL", 1000.0)
+	useCase = NewStartTradingBotUseCaseWithServices(&MockTradeBotRepository{}, nil, external.NewFakeExchange(), dataSource, executionContext)
+	bot = entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 3600, 1000.0, 100.0, "BRL", 0.1, 0.0, true)
+	bot.Start()
+	for !bot.GetIsPositioned() {
+		if err := useCase.ExecuteAnalysisAndTrade(bot); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !dataSource.AdvanceToNext() {
+			t.Fatal("Expected the RSI to buy during the fall")
+		}
+	}
+	if err := bot.BeginClosing(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := useCase.ExecuteAnalysisAndTrade(bot); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result = executionContext.GetResult()
+	if bot.GetIsPositioned() || bot.GetStatus() != entity.StatusStopped || result.TotalTrades != 1 {
+		t.Fatalf("Expected the closing bot to sell and stop, got %v (positioned: %v, %d trades)", bot.GetStatus(), bot.GetIsPositioned(), result.TotalTrades)
+	}
+	last := result.Decisions[len(result.Decisions)-1]
+	if last.GetDecision() != entity.Sell || last.GetAnalysisData()["reason"] != entity.ExitReasonClosing {
+		t.Errorf("Expected the closing sell in the decision log, got %s (%v)", last.GetDecision(), last.GetAnalysisData()["reason"])
+	}
+}
+
+func TestStartTradingBotUseCase_RiskLimitsBlockEntries(t *testing.T) {
+	// Oversold fall that triggers an RSI buy at 106
+	prices := []float64{}
+	for price := 120.0; price >= 105.0; price-- {
+		prices = append(prices, price)
+	}
+
+	dataSource := service.NewHistoricalMarketDataSource(createHourlyTestKlines(prices), 100)
+	executionContext := service.NewBacktestTradingExecutionContext("BTCBRL", 1000.0)
+	riskManager := service.NewRiskManager(repository.NewRiskLimitsRepositoryInMemory(), repository.NewTradeBotRepositoryInMemory(), repository.NewTradeRepositoryInMemory(), nil, "")
+	if _, err := riskManager.SetKillSwitch(true); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	useCase := NewStartTradingBotUseCaseWithServices(nil, nil, external.NewFakeExchange(), dataSource, executionContext).
+		WithRiskManager(riskManager)
+
+	symbol, _ := vo.NewSymbol("BTCBRL")
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 3600, 1000.0, 100.0, "BRL", 0.1, 0.0, true)
+
+	for {
+		if err := useCase.ExecuteAnalysisAndTrade(bot); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !dataSource.AdvanceToNext() {
+			break
+		}
+	}
+
+	result := executionContext.GetResult()
+	if bot.GetIsPositioned() || result.TotalTrades != 0 {
+		t.Fatalf("Expected the kill switch to block the buy, got %d trades", result.TotalTrades)
+	}
+	blocked := 0
+	for _, decision := range result.Decisions {
+		if decision.GetAnalysisData()["strategyDecision"] == string(entity.Buy) {
+			blocked++
+			if decision.GetDecision() != entity.Hold {
+				t.Errorf("Expected the blocked buy to be logged as a hold, got %s", decision.GetDecision())
+			}
+			if data := decision.GetAnalysisData(); data["reason"] != entity.HoldReasonRiskLimit || data["riskLimit"] != entity.RiskLimitKillSwitch {
+				t.Errorf("Expected the kill switch as the hold reason, got %v (%v)", data["reason"], data["riskLimit"])
+			}
+		}
+	}
+	if blocked == 0 {
+		t.Error("Expected the blocked buy in the decision log")
+	}
+}
+
+func TestStartTradingBotUseCase_RiskLimitsFlattenWithANamedExitReason(t *testing.T) {
+	riskManager := service.NewRiskManager(repository.NewRiskLimitsRepositoryInMemory(), repository.NewTradeBotRepositoryInMemory(), repository.NewTradeRepositoryInMemory(), nil, "")
+	if _, err := riskManager.UpdateLimits(entity.RiskLimits{FlattenOnBreach: true}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := riskManager.SetKillSwitch(true); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	useCase := NewStartTradingBotUseCaseWithServices(nil, nil, external.NewFakeExchange(), nil, nil).WithRiskManager(riskManager)
+
+	bot := createTestTradingBot()
+	_ = bot.GetIntoPosition()
+	held := entity.NewStrategyAnalysisResult(entity.Hold, map[string]interface{}{"reason": "rsi_neutral"})
+
+	result := useCase.applyRiskLimits(bot, held, 100.0)
+	if result.Decision != entity.Sell || result.AnalysisData["reason"] != entity.ExitReasonRiskFlattened {
+		t.Fatalf("Expected the position to be flattened, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+	if result.AnalysisData["riskLimit"] != entity.RiskLimitKillSwitch || result.AnalysisData["breach"] != "kill switch is on" {
+		t.Errorf("Expected the breach in the decision details, got %v", result.AnalysisData)
+	}
+	if !entity.IsForcedExitReason(result.AnalysisData["reason"].(string)) {
+		t.Error("Expected the flattening to be a forced exit")
+	}
+}
+
+func TestStartTradingBotUseCase_FlattenPositionsLiquidatesTheBotsOfTheBreachedProfile(t *testing.T) {
+	startUseCase, tradingBotRepo, _, _ := setupStartTradingBotUseCase()
+
+	openBot := func(profile, mode string) *entity.TradingBot {
+		bot := createTestTradingBot()
+		bot.SetExchangeProfile(profile)
+		bot.SetMode(mode)
+		if err := bot.OpenPosition(entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 1.0, NetQuantity: 1.0, QuoteQuantity: 100.0}, time.Now()); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if err := tradingBotRepo.Save(bot); err != nil {
+			t.Fatalf("Failed to save bot: %v", err)
+		}
+		return bot
+	}
+	liveBot := openBot(entity.DefaultExchangeProfile, entity.TradingModeLive)
+	paperBot := openBot(entity.DefaultExchangeProfile, entity.TradingModePaper)
+	testnetBot := openBot("testnet", entity.TradingModeLive)
+
+	startUseCase.FlattenPositions(&service.RiskBreach{Limit: entity.RiskLimitDailyLoss, Profile: entity.DefaultExchangeProfile, Flatten: true})
+
+	liquidated, _ := tradingBotRepo.GetTradeByID(liveBot.Id.GetValue())
+	if liquidated.GetIsPositioned() || liquidated.GetCooldownState().LastExitReason != entity.ExitReasonLiquidated {
+		t.Errorf("Expected the live bot to be liquidated, got positioned: %v (%q)", liquidated.GetIsPositioned(), liquidated.GetCooldownState().LastExitReason)
+	}
+	for _, kept := range []*entity.TradingBot{paperBot, testnetBot} {
+		if saved, _ := tradingBotRepo.GetTradeByID(kept.Id.GetValue()); !saved.GetIsPositioned() {
+			t.Errorf("Expected the position of bot %s to be kept", kept.Id.GetValue())
+		}
+	}
+}
+
+func TestStartTradingBotUseCase_ATRPositionSizing(t *testing.T) {
+	// Oversold fall triggers an RSI buy at 106, max holding time sells it an hour later
+	prices := []float64{}
+	for price := 120.0; price >= 105.0; price-- {
+		prices = append(prices, price)
+	}
+	klines := createHourlyTestKlines(prices)
+
+	dataSource := service.NewHistoricalMarketDataSource(klines, 100)
+	executionContext := service.NewBacktestTradingExecutionContext("BTCBRL", 10000.0)
+	useCase := NewStartTradingBotUseCaseWithServices(nil, nil, external.NewFakeExchange(), dataSource, executionContext)
+
+	symbol, _ := vo.NewSymbol("BTCBRL")
+	sizing := entity.PositionSizing{RiskPercent: 0.1, ATRPeriod: 14, ATRMultiplier: 2.0}
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 3600, 10000.0, 1000.0, "BRL", 0.1, 0.0, false)
+	bot.SetPositionSizing(sizing)
+	bot.SetExitRules(entity.ExitRules{MaxHoldingSeconds: 3600})
+
+	for {
+		if err := useCase.ExecuteAnalysisAndTrade(bot); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !dataSource.AdvanceToNext() {
+			break
+		}
+	}
+
+	result := executionContext.GetResult()
+	if len(result.Trades) != 1 {
+		t.Fatalf("Expected one closed trade, got %d", len(result.Trades))
+	}
+
+	// Risking 10 BRL with a stop 2 ATRs away, well below the 1000 BRL trade amount
+	expectedQuantity := 10.0 / (2.0 * sizing.ATR(klines[:15]))
+	trade := result.Trades[0]
+	if trade.EntryPrice != 106.0 || math.Abs(trade.Quantity-expectedQuantity) > 1e-9 {
+		t.Errorf("Expected %.6f units bought at 106, got %.6f at %.2f", expectedQuantity, trade.Quantity, trade.EntryPrice)
+	}
+	if expectedFees := trade.Quantity * 106.0 * 0.001; math.Abs(trade.Fees-2*expectedFees) > 1e-9 {
+		t.Errorf("Expected fees on the ATR sized order value, got %.6f", trade.Fees)
+	}
+}
+
+func TestStartTradingBotUseCase_MultiTimeframeStrategy(t *testing.T) {
+	// Hourly dip below the slow average while the 4h trend keeps falling
+	prices := []float64{}
+	for price := 140.0; price >= 100.0; price-- {
+		prices = append(prices, price)
+	}
+	klines := createHourlyTestKlines(prices)
+
+	dataSource := service.NewHistoricalMarketDataSource(klines, 100)
+	executionContext := service.NewBacktestTradingExecutionContext("BTCBRL", 1000.0)
+	useCase := NewStartTradingBotUseCaseWithServices(nil, nil, external.NewFakeExchange(), dataSource, executionContext)
+
+	symbol, _ := vo.NewSymbol("BTCBRL")
+	strategy := entity.NewMovingAverageStrategy(3, 10).WithTrendFilter(14400, 3)
+	bot := entity.NewTradingBot(symbol, 0.001, strategy, 3600, 1000.0, 100.0, "BRL", 0.1, 0.0, true)
+
+	for {
+		if err := useCase.ExecuteAnalysisAndTrade(bot); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !dataSource.AdvanceToNext() {
+			break
+		}
+	}
+
+	result := executionContext.GetResult()
+	if bot.GetIsPositioned() || len(result.Trades) != 0 {
+		t.Fatalf("Expected the 4h downtrend to block every entry, got %d trades", len(result.Trades))
+	}
+
+	lastDecision := result.Decisions[len(result.Decisions)-1]
+	analysisData := lastDecision.GetAnalysisData()
+	if analysisData["reason"] != "fast_below_slow_higher_timeframe_downtrend_wait" {
+		t.Errorf("Expected the higher timeframe downtrend to hold, got %v", analysisData["reason"])
+	}
+	if analysisData["trendClose"] != 100.0 {
+		t.Errorf("Expected the forming 4h kline to close at the current price 100, got %v", analysisData["trendClose"])
+	}
+}
+
+// createHourlyTestKlines creates hourly klines closing at each price with a 1.0 high-low range
+func createHourlyTestKlines(prices []float64) []vo.Kline {
+	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := make([]vo.Kline, len(prices))
+	for i, price := range prices {
+		klines[i], _ = vo.NewKline(price, price, price+0.5, price-0.5, 1000.0, baseTime.Add(time.Hour*time.Duration(i)).UnixMilli())
+	}
+	return klines
+}
+
 // Note: Order placement tests moved to LiveTradingExecutionContext tests
 // since order logic is now centralized there with LOT_SIZE validation
 
@@ -296,8 +693,45 @@ func BenchmarkStartTradingBotUseCase_StrategyExecution(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		klines, _ := useCase.getMarketData(bot.GetSymbol().GetValue(), 300)
+		klines, _ := useCase.dataSource.GetMarketData(bot.GetSymbol().GetValue(), 300)
 		strategy := bot.GetStrategy()
 		_ = strategy.Decide(klines, bot)
 	}
 }
+
+func TestStartTradingBotUseCase_RoutesBotsToTheirExchangeProfile(t *testing.T) {
+	prices := []float64{}
+	for price := 120.0; price >= 105.0; price-- {
+		prices = append(prices, price)
+	}
+
+	productionContext := service.NewBacktestTradingExecutionContext("BTCBRL", 1000.0)
+	testnetDataSource := service.NewHistoricalMarketDataSource(createHourlyTestKlines(prices), 100)
+	testnetContext := service.NewBacktestTradingExecutionContext("BTCBRL", 1000.0)
+	useCase := NewStartTradingBotUseCaseWithServices(nil, nil, external.NewFakeExchange(), service.NewHistoricalMarketDataSource(nil, 100), productionContext).
+		WithExchangeProfile("testnet", testnetDataSource, testnetContext)
+
+	symbol, _ := vo.NewSymbol("BTCBRL")
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 3600, 1000.0, 100.0, "BRL", 0.1, 0.0, true)
+	bot.SetExchangeProfile("testnet")
+
+	if err := useCase.ExecuteAnalysisAndTrade(bot); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(testnetContext.GetResult().Decisions) != 1 || len(productionContext.GetResult().Decisions) != 0 {
+		t.Errorf("Expected the decision on the testnet context only, got %d on testnet and %d on production",
+			len(testnetContext.GetResult().Decisions), len(productionContext.GetResult().Decisions))
+	}
+
+	// A bot on a profile that is not configured never falls back to another account
+	bot.SetExchangeProfile("staging")
+	if err := useCase.ExecuteAnalysisAndTrade(bot); err == nil || err.Error() != "exchange profile staging of the bot is not configured" {
+		t.Errorf("Expected exchange profile error, got %v", err)
+	}
+	if err := bot.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := useCase.RunLoop(bot); err == nil {
+		t.Error("Expected RunLoop to refuse a bot on a profile that is not configured")
+	}
+}
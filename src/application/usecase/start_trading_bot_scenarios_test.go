@@ -6,13 +6,12 @@ import (
 	"crypgo-machine/src/infra/external"
 	"testing"
 
-	"github.com/adshao/go-binance/v2"
 )
 
 func TestMovingAverageStrategy_AntiWhipsawScenarios(t *testing.T) {
 	tests := []struct {
 		name           string
-		klines         []*binance.Kline
+		klines         []vo.Kline
 		minimumSpread  float64
 		expectedDecision entity.TradingDecision
 		expectedReason   string
@@ -59,7 +58,7 @@ func TestMovingAverageStrategy_AntiWhipsawScenarios(t *testing.T) {
 			}
 			
 			// Execute strategy
-			klines, err := useCase.getMarketData(bot.GetSymbol().GetValue(), 300)
+			klines, err := useCase.dataSource.GetMarketData(bot.GetSymbol().GetValue(), 300)
 			if err != nil {
 				t.Fatalf("Failed to get market data: %v", err)
 			}
@@ -112,7 +111,7 @@ func TestTradingDecisionLog_FullWorkflow(t *testing.T) {
 	
 	// Simulate multiple strategy executions
 	for i := 0; i < 3; i++ {
-		klines, err := useCase.getMarketData(bot.GetSymbol().GetValue(), 300)
+		klines, err := useCase.dataSource.GetMarketData(bot.GetSymbol().GetValue(), 300)
 		if err != nil {
 			t.Fatalf("Failed to get market data: %v", err)
 		}
@@ -177,20 +176,14 @@ func TestMarketDataConversion(t *testing.T) {
 	useCase, _, _, binanceClient := setupStartTradingBotUseCase()
 	
 	// Setup test klines
-	testKlines := []*binance.Kline{
-		{
-			Open:      "100.50",
-			Close:     "101.75",
-			High:      "102.00",
-			Low:       "100.25",
-			Volume:    "1000.0",
-			CloseTime: 1640995200000,
-		},
+	testKline, err := vo.NewKline(100.50, 101.75, 102.00, 100.25, 1000.0, 1640995200000)
+	if err != nil {
+		t.Fatalf("Failed to create kline: %v", err)
 	}
-	binanceClient.SetPredefinedKlines(testKlines)
+	binanceClient.SetPredefinedKlines([]vo.Kline{testKline})
 	
 	// Convert to domain klines
-	domainKlines, err := useCase.getMarketData("BTCUSDT", 300)
+	domainKlines, err := useCase.dataSource.GetMarketData("BTCUSDT", 300)
 	if err != nil {
 		t.Fatalf("Failed to convert market data: %v", err)
 	}
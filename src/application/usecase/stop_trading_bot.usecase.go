@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/application/service"
 	"crypgo-machine/src/domain/entity"
 	"crypgo-machine/src/domain/vo"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 
 type StopTradingBotUseCase struct {
 	tradingBotRepository repository.TradingBotRepository
+	supervisor           *service.BotSupervisor // Cancels the loop of the bot (nil = the bot has no loop in this process)
 }
 
 func NewStopTradingBotUseCase(tradingBotRepository repository.TradingBotRepository) *StopTradingBotUseCase {
@@ -17,6 +19,12 @@ func NewStopTradingBotUseCase(tradingBotRepository repository.TradingBotReposito
 	}
 }
 
+// WithSupervisor stops the loops the supervisor runs, shared with the start use case
+func (uc *StopTradingBotUseCase) WithSupervisor(supervisor *service.BotSupervisor) *StopTradingBotUseCase {
+	uc.supervisor = supervisor
+	return uc
+}
+
 type InputStopTradingBot struct {
 	BotId string `json:"bot_id"`
 }
@@ -31,6 +39,11 @@ func (uc *StopTradingBotUseCase) Execute(input InputStopTradingBot) error {
 		return fmt.Errorf("invalid bot_id format: %v", err)
 	}
 
+	// Stop the loop first and wait for its tick in flight, so the bot is read after its last trade
+	if uc.supervisor != nil && uc.supervisor.Stop(input.BotId) {
+		fmt.Printf("🛑 Trading bot %s loop stopped\n", input.BotId)
+	}
+
 	bot, err := uc.tradingBotRepository.GetTradeByID(input.BotId)
 	if err != nil {
 		return fmt.Errorf("failed to find trading bot: %v", err)
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/service"
+	"crypgo-machine/src/domain/entity"
+	"testing"
+)
+
+func TestStopTradingBotUseCase_CancelsTheBotLoop(t *testing.T) {
+	startUseCase, tradingBotRepo, _, _ := setupStartTradingBotUseCase()
+	supervisor := service.NewBotSupervisor(nil, "")
+	startUseCase.WithSupervisor(supervisor)
+	stopUseCase := NewStopTradingBotUseCase(tradingBotRepo).WithSupervisor(supervisor)
+
+	bot := createTestTradingBot()
+	if err := tradingBotRepo.Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+	botId := bot.Id.GetValue()
+	if err := startUseCase.Execute(InputStartTradingBot{TradingBotId: botId}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if state, ok := supervisor.State(botId); !ok || !state.Running {
+		t.Fatalf("Expected the bot loop to be running, got %+v", state)
+	}
+
+	if err := stopUseCase.Execute(InputStopTradingBot{BotId: botId}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The loop is gone without waiting for the next tick of the 60s interval
+	if state, _ := supervisor.State(botId); state.Running {
+		t.Errorf("Expected the bot loop to be stopped, got %+v", state)
+	}
+	stopped, _ := tradingBotRepo.GetTradeByID(botId)
+	if stopped.GetStatus() != entity.StatusStopped {
+		t.Errorf("Expected bot status %v, got %v", entity.StatusStopped, stopped.GetStatus())
+	}
+
+	// The bot can be started again
+	if err := startUseCase.Execute(InputStartTradingBot{TradingBotId: botId}); err != nil {
+		t.Fatalf("Expected the bot to restart, got: %v", err)
+	}
+	supervisor.Stop(botId)
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/service"
+	"crypgo-machine/src/domain/entity"
+)
+
+// UpdateRiskLimitsUseCase replaces the risk limits shared by the live bots, the kill switch being set on its own
+type UpdateRiskLimitsUseCase struct {
+	riskManager *service.RiskManager
+}
+
+func NewUpdateRiskLimitsUseCase(riskManager *service.RiskManager) *UpdateRiskLimitsUseCase {
+	return &UpdateRiskLimitsUseCase{
+		riskManager: riskManager,
+	}
+}
+
+type InputUpdateRiskLimits struct {
+	MaxDailyLoss        float64 `json:"max_daily_loss"`
+	MaxOpenPositions    int     `json:"max_open_positions"`
+	MaxExposurePerAsset float64 `json:"max_exposure_per_asset"`
+	FlattenOnBreach     bool    `json:"flatten_on_breach"`
+}
+
+func (uc *UpdateRiskLimitsUseCase) Execute(input InputUpdateRiskLimits) (*RiskLimitsOutput, error) {
+	limits, err := entity.NewRiskLimits(input.MaxDailyLoss, input.MaxOpenPositions, input.MaxExposurePerAsset, input.FlattenOnBreach)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := uc.riskManager.UpdateLimits(limits)
+	if err != nil {
+		return nil, err
+	}
+	output := riskLimitsOutputOf(saved)
+	return &output, nil
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// UpdateTradingBotUseCase edits the strategy and trading parameters of a bot in place, keeping its open position
+// and its history. A running bot has its loop restarted with the new config, so the next tick already uses it. Each
+// change is recorded as a new config version, the version the decision logs are attributed to.
+type UpdateTradingBotUseCase struct {
+	startTradingBotUseCase  *StartTradingBotUseCase // Runs the loops of the bots
+	configHistoryRepository repository.BotConfigHistoryRepository
+}
+
+func NewUpdateTradingBotUseCase(
+	startTradingBotUseCase *StartTradingBotUseCase,
+	configHistoryRepository repository.BotConfigHistoryRepository,
+) *UpdateTradingBotUseCase {
+	return &UpdateTradingBotUseCase{
+		startTradingBotUseCase:  startTradingBotUseCase,
+		configHistoryRepository: configHistoryRepository,
+	}
+}
+
+type InputUpdateTradingBot struct {
+	BotId string `json:"bot_id"`
+	// Config fields to change, named as in the create request. The params are merged with the current ones unless
+	// the strategy changes too, a new strategy without params getting its defaults, as null params do.
+	Changes map[string]interface{} `json:"changes"`
+}
+
+type OutputUpdateTradingBot struct {
+	ConfigVersion int                  `json:"config_version"`
+	ChangedFields []string             `json:"changed_fields"` // Empty when the changes left the config as it was
+	Bot           entity.TradingBotDTO `json:"bot"`
+}
+
+func (uc *UpdateTradingBotUseCase) Execute(input InputUpdateTradingBot) (*OutputUpdateTradingBot, error) {
+	if len(input.Changes) == 0 {
+		return nil, fmt.Errorf("no changes to apply")
+	}
+
+	var changedFields []string
+	var versions []*entity.BotConfigVersion
+	reconfigure := func(tradingBot *entity.TradingBot) error {
+		var errReconfigure error
+		changedFields, versions, errReconfigure = uc.reconfigure(tradingBot, input.Changes)
+		return errReconfigure
+	}
+	// The new version is recorded with the bot, a failure leaving both as they were
+	save := func(tradingBot *entity.TradingBot) error {
+		return uc.configHistoryRepository.SaveWithBot(tradingBot, versions...)
+	}
+	tradingBot, err := uc.startTradingBotUseCase.changeAndSaveBot(input.BotId, reconfigure, save)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changedFields) > 0 {
+		fmt.Printf("🔧 [%s] Bot config updated to version %d (%v)\n", tradingBot.GetSymbol().GetValue(), tradingBot.GetConfigVersion(), changedFields)
+	}
+	return &OutputUpdateTradingBot{
+		ConfigVersion: tradingBot.GetConfigVersion(),
+		ChangedFields: changedFields,
+		Bot:           tradingBot.ToDTO(),
+	}, nil
+}
+
+// reconfigure validates the changed config as the create use case does and applies it to the bot. It returns the
+// fields that changed and the config versions to record with the bot.
+func (uc *UpdateTradingBotUseCase) reconfigure(tradingBot *entity.TradingBot, changes map[string]interface{}) ([]string, []*entity.BotConfigVersion, error) {
+	current := tradingBot.GetConfig().ToDTO()
+	input, err := mergeConfigChanges(current, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config, err := resolveTradingBotConfig(input, tradingBot.GetMode())
+	if err != nil {
+		return nil, nil, err
+	}
+	if config.IntervalSeconds <= 0 {
+		return nil, nil, fmt.Errorf("invalid interval: must be greater than zero")
+	}
+
+	updated := config.ToDTO()
+	changedFields, err := changedConfigFields(current, updated)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(changedFields) == 0 {
+		return []string{}, nil, nil
+	}
+
+	previousVersion := tradingBot.GetConfigVersion()
+	if err := tradingBot.Reconfigure(config); err != nil {
+		return nil, nil, err
+	}
+	versions, err := uc.versionsToRecord(tradingBot, previousVersion, current, updated, changedFields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config history: %v", err)
+	}
+	return changedFields, versions, nil
+}
+
+// versionsToRecord returns the new config version of the bot, preceded by the previous one when it was never
+// recorded, as the version a bot is created with
+func (uc *UpdateTradingBotUseCase) versionsToRecord(tradingBot *entity.TradingBot, previousVersion int, previous, updated entity.BotConfigDTO, changedFields []string) ([]*entity.BotConfigVersion, error) {
+	history, err := uc.configHistoryRepository.GetByTradingBotId(tradingBot.Id.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := false
+	for _, version := range history {
+		if version.GetVersion() == previousVersion {
+			recorded = true
+			break
+		}
+	}
+	var versions []*entity.BotConfigVersion
+	if !recorded {
+		since := time.Now()
+		if previousVersion == 1 {
+			since = tradingBot.GetCreatedAt()
+		}
+		versions = append(versions, entity.RestoreBotConfigVersion(vo.NewEntityId(), tradingBot.Id, previousVersion, previous, nil, since))
+	}
+	return append(versions, entity.NewBotConfigVersion(tradingBot.Id, tradingBot.GetConfigVersion(), updated, changedFields)), nil
+}
+
+// mergeConfigChanges applies the changes to the current config, returning it as a create request to be validated
+func mergeConfigChanges(current entity.BotConfigDTO, changes map[string]interface{}) (InputCreateTradingBot, error) {
+	fields, err := configFields(current)
+	if err != nil {
+		return InputCreateTradingBot{}, err
+	}
+
+	for field := range changes {
+		if _, editable := fields[field]; !editable {
+			return InputCreateTradingBot{}, fmt.Errorf("field %s cannot be edited", field)
+		}
+	}
+
+	_, changesStrategy := changes["strategy"]
+	strategyChanged := changesStrategy && changes["strategy"] != current.Strategy
+	params, changesParams := changes["params"]
+	switch {
+	case changesParams && params != nil && !strategyChanged:
+		changedParams, ok := params.(map[string]interface{})
+		if !ok {
+			return InputCreateTradingBot{}, fmt.Errorf("invalid params: must be an object")
+		}
+		merged := make(map[string]interface{}, len(current.Params)+len(changedParams))
+		for name, value := range current.Params {
+			merged[name] = value
+		}
+		for name, value := range changedParams {
+			merged[name] = value
+		}
+		params = merged
+	case !changesParams && strategyChanged:
+		params = nil
+	case !changesParams:
+		params = current.Params
+	}
+
+	for field, value := range changes {
+		fields[field] = value
+	}
+	fields["params"] = params
+
+	// The current sizing mode would override the fields a sizing change is made of
+	_, changesSizingMode := changes["position_sizing_mode"]
+	_, changesFixedQuantity := changes["use_fixed_quantity"]
+	_, changesRisk := changes["risk_per_trade_percent"]
+	if !changesSizingMode && (changesFixedQuantity || changesRisk) {
+		fields["position_sizing_mode"] = ""
+	}
+
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return InputCreateTradingBot{}, err
+	}
+	var input InputCreateTradingBot
+	if err := json.Unmarshal(encoded, &input); err != nil {
+		return InputCreateTradingBot{}, fmt.Errorf("invalid changes: %v", err)
+	}
+	return input, nil
+}
+
+// changedConfigFields returns the fields of the config that differ, sorted by name
+func changedConfigFields(before, after entity.BotConfigDTO) ([]string, error) {
+	beforeFields, err := configFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := configFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for field, value := range afterFields {
+		if !reflect.DeepEqual(beforeFields[field], value) {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// configFields returns the config as its JSON fields, the names the changes are made of
+func configFields(config entity.BotConfigDTO) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"crypgo-machine/src/application/service"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/external"
+	"crypgo-machine/src/infra/repository"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// createConfigurableTradingBot creates a bot whose strategy is built from the registry, as created bots are
+func createConfigurableTradingBot(t *testing.T) *entity.TradingBot {
+	strategy, err := entity.NewStrategyFromParams("MovingAverage", map[string]interface{}{"FastWindow": 7, "SlowWindow": 40})
+	if err != nil {
+		t.Fatalf("Failed to build strategy: %v", err)
+	}
+	symbol, _ := vo.NewSymbol("BTCUSDT")
+	return entity.NewTradingBot(symbol, 0.001, strategy, 60, 10000.0, 1000.0, "USDT", 0.001, 0.0, true)
+}
+
+func TestUpdateTradingBotUseCase_ReconfiguresARunningBotKeepingItsPosition(t *testing.T) {
+	startUseCase, tradingBotRepo, decisionLogRepo, _ := setupStartTradingBotUseCase()
+	supervisor := service.NewBotSupervisor(nil, "")
+	startUseCase.WithSupervisor(supervisor)
+	historyRepo := repository.NewBotConfigHistoryRepositoryInMemory().WithTradingBotRepository(tradingBotRepo)
+	updateUseCase := NewUpdateTradingBotUseCase(startUseCase, historyRepo)
+
+	bot := createConfigurableTradingBot(t)
+	bot.GetIntoPosition()
+	bot.SetEntryPrice(50000.0)
+	if err := tradingBotRepo.Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+	botId := bot.Id.GetValue()
+	defer supervisor.Stop(botId)
+	if err := startUseCase.Execute(InputStartTradingBot{TradingBotId: botId}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output, err := updateUseCase.Execute(InputUpdateTradingBot{
+		BotId: botId,
+		Changes: map[string]interface{}{
+			"params":                   map[string]interface{}{"FastWindow": 9.0},
+			"minimum_profit_threshold": 1.5,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if output.ConfigVersion != 2 || !reflect.DeepEqual(output.ChangedFields, []string{"minimum_profit_threshold", "params"}) {
+		t.Errorf("Expected version 2 changing the threshold and the params, got %+v", output)
+	}
+
+	updated, _ := tradingBotRepo.GetTradeByID(botId)
+	params := updated.GetStrategy().GetParams()
+	if params["FastWindow"] != 9 || params["SlowWindow"] != 40 {
+		t.Errorf("Expected the changed param merged with the current ones, got %v", params)
+	}
+	if updated.GetMinimumProfitThreshold() != 1.5 || updated.GetConfigVersion() != 2 {
+		t.Errorf("Expected the new threshold on version 2, got %.2f on version %d", updated.GetMinimumProfitThreshold(), updated.GetConfigVersion())
+	}
+	if !updated.GetIsPositioned() || updated.GetEntryPrice() != 50000.0 || updated.GetStatus() != entity.StatusRunning {
+		t.Errorf("Expected the running bot to keep its position, got %+v", updated.ToDTO())
+	}
+	if state, _ := supervisor.State(botId); !state.Running {
+		t.Errorf("Expected the loop to run again on the new config, got %+v", state)
+	}
+
+	// The creation config is recorded with the first edit
+	history, _ := historyRepo.GetByTradingBotId(botId)
+	if len(history) != 2 || history[0].GetVersion() != 1 || history[1].GetVersion() != 2 {
+		t.Fatalf("Expected versions 1 and 2 in the history, got %d versions", len(history))
+	}
+	if history[0].GetConfig().Params["FastWindow"] != 7 || history[0].GetConfig().MinimumProfitThreshold != 0.0 {
+		t.Errorf("Expected version 1 to hold the creation config, got %+v", history[0].GetConfig())
+	}
+	if !reflect.DeepEqual(history[1].GetChangedFields(), output.ChangedFields) {
+		t.Errorf("Expected version 2 to record its changed fields, got %v", history[1].GetChangedFields())
+	}
+
+	// Decisions are attributed to the version in force, the loop deciding once on each version as it starts
+	supervisor.Stop(botId)
+	if err := startUseCase.ExecuteAnalysisAndTrade(updated); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	logs, _ := decisionLogRepo.GetByTradingBotId(botId)
+	if len(logs) < 2 || logs[0].GetConfigVersion() != 2 || logs[len(logs)-1].GetConfigVersion() != 1 {
+		t.Errorf("Expected the first decision on config version 1 and the last one on version 2, got %d logs", len(logs))
+	}
+
+	// Changes leaving the config as it is make no version
+	output, err = updateUseCase.Execute(InputUpdateTradingBot{
+		BotId:   botId,
+		Changes: map[string]interface{}{"minimum_profit_threshold": 1.5},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if output.ConfigVersion != 2 || len(output.ChangedFields) != 0 {
+		t.Errorf("Expected no new version, got %+v", output)
+	}
+}
+
+func TestUpdateTradingBotUseCase_NewStrategyGetsItsDefaults(t *testing.T) {
+	startUseCase, tradingBotRepo, _, _ := setupStartTradingBotUseCase()
+	updateUseCase := NewUpdateTradingBotUseCase(startUseCase, repository.NewBotConfigHistoryRepositoryInMemory().WithTradingBotRepository(tradingBotRepo))
+
+	bot := createConfigurableTradingBot(t)
+	if err := tradingBotRepo.Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+
+	output, err := updateUseCase.Execute(InputUpdateTradingBot{
+		BotId:   bot.Id.GetValue(),
+		Changes: map[string]interface{}{"strategy": "RSI"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if output.Bot.Strategy != "RSI" || output.Bot.StrategyParams.(map[string]interface{})["Period"] != 14 {
+		t.Errorf("Expected the RSI strategy with its default params, got %v %v", output.Bot.Strategy, output.Bot.StrategyParams)
+	}
+	if !reflect.DeepEqual(output.ChangedFields, []string{"params", "strategy"}) {
+		t.Errorf("Expected the strategy and its params to change, got %v", output.ChangedFields)
+	}
+}
+
+func TestUpdateTradingBotUseCase_RejectsInvalidChanges(t *testing.T) {
+	startUseCase, tradingBotRepo, _, _ := setupStartTradingBotUseCase()
+	historyRepo := repository.NewBotConfigHistoryRepositoryInMemory().WithTradingBotRepository(tradingBotRepo)
+	updateUseCase := NewUpdateTradingBotUseCase(startUseCase, historyRepo)
+
+	bot := createConfigurableTradingBot(t)
+	if err := tradingBotRepo.Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+	botId := bot.Id.GetValue()
+
+	tests := []struct {
+		name    string
+		botId   string
+		changes map[string]interface{}
+	}{
+		{"no changes", botId, map[string]interface{}{}},
+		{"invalid bot id", "invalid", map[string]interface{}{"quantity": 0.002}},
+		{"fixed field", botId, map[string]interface{}{"symbol": "ETHUSDT"}},
+		{"unknown field", botId, map[string]interface{}{"leverage": 10}},
+		{"invalid params", botId, map[string]interface{}{"params": map[string]interface{}{"FastWindow": 50}}},
+		{"unknown strategy", botId, map[string]interface{}{"strategy": "Unknown"}},
+		{"invalid quantity", botId, map[string]interface{}{"quantity": 0}},
+		{"invalid interval", botId, map[string]interface{}{"interval_seconds": 0}},
+		{"wrong type", botId, map[string]interface{}{"interval_seconds": "60"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := updateUseCase.Execute(InputUpdateTradingBot{BotId: tt.botId, Changes: tt.changes}); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	unchanged, _ := tradingBotRepo.GetTradeByID(botId)
+	if unchanged.GetConfigVersion() != 1 || unchanged.GetStrategy().GetParams()["FastWindow"] != 7 {
+		t.Errorf("Expected the bot config untouched, got version %d", unchanged.GetConfigVersion())
+	}
+	if history, _ := historyRepo.GetByTradingBotId(botId); len(history) != 0 {
+		t.Errorf("Expected no config history, got %d versions", len(history))
+	}
+}
+
+// failingUpdateBotRepository fails every bot update and reads back the bots it is given, oldest first
+type failingUpdateBotRepository struct {
+	*repository.TradeBotRepositoryInMemory
+	reads []*entity.TradingBot
+}
+
+func (r *failingUpdateBotRepository) Update(bot *entity.TradingBot) error {
+	return errors.New("connection lost")
+}
+
+func (r *failingUpdateBotRepository) GetTradeByID(id string) (*entity.TradingBot, error) {
+	bot := r.reads[0]
+	if len(r.reads) > 1 {
+		r.reads = r.reads[1:]
+	}
+	return bot, nil
+}
+
+func TestUpdateTradingBotUseCase_FailedSaveLeavesBotAndHistoryAsSaved(t *testing.T) {
+	changed := createConfigurableTradingBot(t)
+	saved := createConfigurableTradingBot(t)
+	tradingBotRepo := &failingUpdateBotRepository{TradeBotRepositoryInMemory: repository.NewTradeBotRepositoryInMemory(), reads: []*entity.TradingBot{changed, saved}}
+	startUseCase := NewStartTradingBotUseCase(tradingBotRepo, repository.NewTradingDecisionLogRepositoryInMemory(), external.NewFakeExchange())
+	historyRepo := repository.NewBotConfigHistoryRepositoryInMemory().WithTradingBotRepository(tradingBotRepo)
+	updateUseCase := NewUpdateTradingBotUseCase(startUseCase, historyRepo)
+
+	_, err := updateUseCase.Execute(InputUpdateTradingBot{
+		BotId:   changed.Id.GetValue(),
+		Changes: map[string]interface{}{"minimum_profit_threshold": 1.5},
+	})
+	if err == nil {
+		t.Fatal("Expected the failed save to be reported")
+	}
+	if history, _ := historyRepo.GetByTradingBotId(changed.Id.GetValue()); len(history) != 0 {
+		t.Errorf("Expected no version recorded for a bot that was not saved, got %d versions", len(history))
+	}
+
+	// The loop runs again on the bot as saved, not on the change applied in memory
+	tradingBotRepo.reads = []*entity.TradingBot{changed, saved}
+	reloaded, err := startUseCase.changeBot(changed.Id.GetValue(), func(tradingBot *entity.TradingBot) error {
+		tradingBot.SetUseStreaming(true)
+		return nil
+	})
+	if err == nil || reloaded != saved {
+		t.Errorf("Expected the bot to be read again after the failed save, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/indicator"
+	"crypgo-machine/src/domain/vo"
+)
+
+const (
+	BollingerEntryModeTouch = "touch" // Enter/exit when the kline wick touches the band
+	BollingerEntryModeClose = "close" // Enter/exit only when the kline closes beyond the band
+)
+
+type BollingerBandsStrategy struct {
+	Period            int
+	StdDevMultiplier  float64
+	EntryMode         string
+	StoplossThreshold float64
+}
+
+func init() {
+	RegisterStrategy(StrategyDefinition{
+		Name:        "BollingerBands",
+		Description: "Mean reversion: buys at the lower band and sells at the upper band",
+		Params: []StrategyParamSpec{
+			{Name: "Period", Type: ParamTypeInt, Default: 20, Min: paramBound(2), Description: "Number of klines in the middle band (SMA) and standard deviation"},
+			{Name: "StdDevMultiplier", Type: ParamTypeFloat, Default: 2.0, Min: paramBound(0.1), Max: paramBound(10), Description: "Number of standard deviations between the middle and outer bands"},
+			{Name: "EntryMode", Type: ParamTypeString, Default: BollingerEntryModeClose, Options: []string{BollingerEntryModeTouch, BollingerEntryModeClose}, Description: "touch = wick reaching the band triggers, close = kline must close beyond the band"},
+			{Name: "StoplossThreshold", Type: ParamTypeFloat, Default: 0.0, Min: paramBound(0), Max: paramBound(100), Description: "Loss % that forces a sell (0 disables stoploss)"},
+		},
+		Build: func(params StrategyParams) (TradingStrategy, error) {
+			return NewBollingerBandsStrategyWithStoploss(
+				params.Int("Period"),
+				params.Float("StdDevMultiplier"),
+				params.String("EntryMode"),
+				params.Float("StoplossThreshold"),
+			), nil
+		},
+	})
+}
+
+func NewBollingerBandsStrategy(period int, stdDevMultiplier float64, entryMode string) *BollingerBandsStrategy {
+	return &BollingerBandsStrategy{
+		Period:            period,
+		StdDevMultiplier:  stdDevMultiplier,
+		EntryMode:         entryMode,
+		StoplossThreshold: 0.0,
+	}
+}
+
+func NewBollingerBandsStrategyWithStoploss(period int, stdDevMultiplier float64, entryMode string, stoplossThreshold float64) *BollingerBandsStrategy {
+	return &BollingerBandsStrategy{
+		Period:            period,
+		StdDevMultiplier:  stdDevMultiplier,
+		EntryMode:         entryMode,
+		StoplossThreshold: stoplossThreshold,
+	}
+}
+
+func (s *BollingerBandsStrategy) GetName() string {
+	return "BollingerBands"
+}
+
+func (s *BollingerBandsStrategy) GetParams() map[string]interface{} {
+	return map[string]interface{}{
+		"Period":            s.Period,
+		"StdDevMultiplier":  s.StdDevMultiplier,
+		"EntryMode":         s.EntryMode,
+		"StoplossThreshold": s.StoplossThreshold,
+	}
+}
+
+func (s *BollingerBandsStrategy) Decide(klines []vo.Kline, tradingBot *TradingBot) *StrategyAnalysisResult {
+	if len(klines) < s.Period {
+		return NewStrategyAnalysisResult(Hold, map[string]interface{}{
+			"upperBand":  0.0,
+			"middleBand": 0.0,
+			"lowerBand":  0.0,
+			"reason":     "insufficient_data",
+		})
+	}
+
+	bands := indicator.Bollinger(indicator.Closes(klines), s.Period, s.StdDevMultiplier)
+	upper, lower := indicator.Last(bands.Upper), indicator.Last(bands.Lower)
+	lastKline := klines[len(klines)-1]
+	currentPrice := lastKline.Close()
+
+	exit := NewPositionExit(tradingBot, currentPrice, s.StoplossThreshold)
+
+	analysisData := exit.AnalysisData(map[string]interface{}{
+		"upperBand":  upper,
+		"middleBand": indicator.Last(bands.Middle),
+		"lowerBand":  lower,
+		"percentB":   indicator.Last(bands.PercentB),
+		"bandwidth":  indicator.Last(bands.Bandwidth),
+		"entryMode":  s.EntryMode,
+	})
+
+	// Check for stoploss first if positioned and stoploss is enabled
+	if result := exit.CheckStoploss("BOLLINGER STOPLOSS", analysisData); result != nil {
+		return result
+	}
+
+	var decision TradingDecision
+
+	belowLower, aboveUpper := s.evaluateBands(lastKline, upper, lower)
+
+	if belowLower && !tradingBot.GetIsPositioned() {
+		decision = Buy
+		analysisData["reason"] = "price_below_lower_band_buy"
+	} else if aboveUpper && tradingBot.GetIsPositioned() {
+		decision = exit.SellIfProfitable(analysisData, "price_above_upper_band_sell_with_profit", "price_above_upper_band_hold_insufficient_profit")
+	} else {
+		decision = Hold
+		if belowLower && tradingBot.GetIsPositioned() {
+			analysisData["reason"] = "price_below_lower_band_positioned_holding"
+		} else if aboveUpper && !tradingBot.GetIsPositioned() {
+			analysisData["reason"] = "price_above_upper_band_wait_for_dip"
+		} else if tradingBot.GetIsPositioned() {
+			analysisData["reason"] = "price_inside_bands_positioned_holding"
+		} else {
+			analysisData["reason"] = "price_inside_bands_wait_for_signal"
+		}
+	}
+
+	return NewStrategyAnalysisResult(decision, analysisData)
+}
+
+// evaluateBands reports whether the kline crossed below the lower band or above the upper band
+// according to the configured entry mode
+func (s *BollingerBandsStrategy) evaluateBands(kline vo.Kline, upper, lower float64) (bool, bool) {
+	if s.EntryMode == BollingerEntryModeTouch {
+		return kline.Low() <= lower, kline.High() >= upper
+	}
+	return kline.Close() < lower, kline.Close() > upper
+}
@@ -0,0 +1,132 @@
+package entity
+
+import (
+	"math"
+	"testing"
+)
+
+// With a flat series and one outlier the bands are easy to compute by hand:
+// prices 100,100,100,100,90 -> middle 98, stddev 4
+var bollingerDipPrices = []float64{100, 100, 100, 100, 90}
+
+func TestBollingerBandsStrategy_AnalysisData(t *testing.T) {
+	strategy := NewBollingerBandsStrategy(5, 2.0, BollingerEntryModeClose)
+	bot := createTestTradingBot(false, 0.0, 1.0)
+
+	result := strategy.Decide(createTestKlinesWithPrices(bollingerDipPrices), bot)
+
+	expected := map[string]float64{
+		"middleBand": 98.0,
+		"upperBand":  106.0,
+		"lowerBand":  90.0,
+		"percentB":   0.0,
+		"bandwidth":  16.0 / 98.0 * 100,
+	}
+	for key, value := range expected {
+		actual, ok := result.AnalysisData[key].(float64)
+		if !ok {
+			t.Fatalf("expected %s in analysis data, got %v", key, result.AnalysisData[key])
+		}
+		if math.Abs(actual-value) > 1e-9 {
+			t.Errorf("expected %s=%.6f, got %.6f", key, value, actual)
+		}
+	}
+}
+
+func TestBollingerBandsStrategy_InsufficientData(t *testing.T) {
+	strategy := NewBollingerBandsStrategy(20, 2.0, BollingerEntryModeClose)
+	bot := createTestTradingBot(false, 0.0, 1.0)
+
+	result := strategy.Decide(createTestKlinesWithPrices(bollingerDipPrices), bot)
+
+	if result.Decision != Hold {
+		t.Errorf("expected Hold for insufficient data, got %s", result.Decision)
+	}
+	if result.AnalysisData["reason"] != "insufficient_data" {
+		t.Errorf("expected insufficient_data reason, got %v", result.AnalysisData["reason"])
+	}
+}
+
+func TestBollingerBandsStrategy_EntryModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		multiplier float64
+		entryMode  string
+		expected   TradingDecision
+	}{
+		// Close of 90 sits exactly on the lower band: only the wick (89.9) crosses it
+		{"touch buys when wick reaches lower band", 2.0, BollingerEntryModeTouch, Buy},
+		{"close holds when close only reaches lower band", 2.0, BollingerEntryModeClose, Hold},
+		// Narrower bands put the lower band at 92, below the close
+		{"close buys when close is below lower band", 1.5, BollingerEntryModeClose, Buy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := NewBollingerBandsStrategy(5, tt.multiplier, tt.entryMode)
+			bot := createTestTradingBot(false, 0.0, 1.0)
+
+			result := strategy.Decide(createTestKlinesWithPrices(bollingerDipPrices), bot)
+
+			if result.Decision != tt.expected {
+				t.Errorf("expected %s, got %s (reason: %v)", tt.expected, result.Decision, result.AnalysisData["reason"])
+			}
+		})
+	}
+}
+
+func TestBollingerBandsStrategy_SellAtUpperBand(t *testing.T) {
+	// prices 100,100,100,100,115 -> middle 103, stddev 6, upper band 115
+	prices := []float64{100, 100, 100, 100, 115}
+	strategy := NewBollingerBandsStrategy(5, 2.0, BollingerEntryModeTouch)
+
+	bot := createTestTradingBot(true, 100.0, 1.0)
+	result := strategy.Decide(createTestKlinesWithPrices(prices), bot)
+	if result.Decision != Sell {
+		t.Fatalf("expected Sell at upper band with profit, got %s", result.Decision)
+	}
+	if result.AnalysisData["reason"] != "price_above_upper_band_sell_with_profit" {
+		t.Errorf("expected sell with profit reason, got %v", result.AnalysisData["reason"])
+	}
+
+	// Entry at 114 gives only ~0.88% profit, below the 1% minimum
+	bot = createTestTradingBot(true, 114.0, 1.0)
+	result = strategy.Decide(createTestKlinesWithPrices(prices), bot)
+	if result.Decision != Hold {
+		t.Fatalf("expected Hold with insufficient profit, got %s", result.Decision)
+	}
+	if result.AnalysisData["reason"] != "price_above_upper_band_hold_insufficient_profit" {
+		t.Errorf("expected insufficient profit reason, got %v", result.AnalysisData["reason"])
+	}
+}
+
+func TestBollingerBandsStrategy_Stoploss(t *testing.T) {
+	strategy := NewBollingerBandsStrategyWithStoploss(5, 2.0, BollingerEntryModeClose, 5.0)
+	bot := createTestTradingBot(true, 100.0, 1.0)
+
+	result := strategy.Decide(createTestKlinesWithPrices(bollingerDipPrices), bot)
+
+	if result.Decision != Sell {
+		t.Fatalf("expected Sell on stoploss, got %s", result.Decision)
+	}
+	if result.AnalysisData["reason"] != "stoploss_triggered" {
+		t.Errorf("expected stoploss_triggered reason, got %v", result.AnalysisData["reason"])
+	}
+}
+
+func TestBollingerBandsStrategy_Registry(t *testing.T) {
+	strategy, err := NewStrategyFromParams("BollingerBands", map[string]interface{}{"Period": 10.0})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	bollinger := strategy.(*BollingerBandsStrategy)
+	if bollinger.Period != 10 || bollinger.StdDevMultiplier != 2.0 || bollinger.EntryMode != BollingerEntryModeClose {
+		t.Errorf("expected 10/2.0/close, got %d/%.1f/%s", bollinger.Period, bollinger.StdDevMultiplier, bollinger.EntryMode)
+	}
+
+	_, err = NewStrategyFromParams("BollingerBands", map[string]interface{}{"EntryMode": "cross"})
+	if err == nil || err.Error() != "EntryMode must be one of [touch close]" {
+		t.Errorf("expected EntryMode options error, got %v", err)
+	}
+}
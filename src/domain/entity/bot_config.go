@@ -0,0 +1,215 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+	"time"
+)
+
+// BotConfig is the part of a bot that can be edited after its creation: the strategy and the trading parameters
+// read on every tick. The symbol, currency, initial capital and mode are fixed for the life of the bot.
+type BotConfig struct {
+	Strategy               TradingStrategy
+	Quantity               float64
+	IntervalSeconds        int
+	TradeAmount            float64
+	TradingFees            float64
+	MinimumProfitThreshold float64
+	UseFixedQuantity       bool
+	PositionSizing         PositionSizing
+	OrderExecution         OrderExecution
+	ExchangeOCO            ExchangeOCO
+	ExitRules              ExitRules
+	CooldownRules          CooldownRules
+	UseStreaming           bool
+}
+
+// BotConfigDTO is a snapshot of a bot config, with the field names of the create and update requests
+type BotConfigDTO struct {
+	Strategy                string                 `json:"strategy"`
+	Params                  map[string]interface{} `json:"params"`
+	Quantity                float64                `json:"quantity"`
+	IntervalSeconds         int                    `json:"interval_seconds"`
+	TradeAmount             float64                `json:"trade_amount"`
+	TradingFees             float64                `json:"trading_fees"`
+	MinimumProfitThreshold  float64                `json:"minimum_profit_threshold"`
+	UseFixedQuantity        bool                   `json:"use_fixed_quantity"`
+	PositionSizingMode      string                 `json:"position_sizing_mode"`
+	RiskPerTradePercent     float64                `json:"risk_per_trade_percent"`
+	ATRPeriod               int                    `json:"atr_period"`
+	ATRMultiplier           float64                `json:"atr_multiplier"`
+	OrderExecutionMode      string                 `json:"order_execution_mode"`
+	TimeInForce             string                 `json:"time_in_force"`
+	RepriceAfterSeconds     int                    `json:"reprice_after_seconds"`
+	MaxOrderAttempts        int                    `json:"max_order_attempts"`
+	UseExchangeOCO          bool                   `json:"use_exchange_oco"`
+	OCOStopLossPercent      float64                `json:"oco_stop_loss_percent"`
+	OCOTakeProfitPercent    float64                `json:"oco_take_profit_percent"`
+	OCOStopLimitGapPercent  float64                `json:"oco_stop_limit_gap_percent"`
+	TrailingStopPercent     float64                `json:"trailing_stop_percent"`
+	TakeProfitPercent       float64                `json:"take_profit_percent"`
+	MaxHoldingSeconds       int                    `json:"max_holding_seconds"`
+	CooldownCandles         int                    `json:"cooldown_candles"`
+	CooldownMinutes         int                    `json:"cooldown_minutes"`
+	StopLossCooldownCandles int                    `json:"stop_loss_cooldown_candles"`
+	StopLossCooldownMinutes int                    `json:"stop_loss_cooldown_minutes"`
+	MaxTradesPerDay         int                    `json:"max_trades_per_day"`
+	UseStreaming            bool                   `json:"use_streaming"`
+}
+
+func (c BotConfig) ToDTO() BotConfigDTO {
+	return BotConfigDTO{
+		Strategy:                c.Strategy.GetName(),
+		Params:                  c.Strategy.GetParams(),
+		Quantity:                c.Quantity,
+		IntervalSeconds:         c.IntervalSeconds,
+		TradeAmount:             c.TradeAmount,
+		TradingFees:             c.TradingFees,
+		MinimumProfitThreshold:  c.MinimumProfitThreshold,
+		UseFixedQuantity:        c.UseFixedQuantity,
+		PositionSizingMode:      positionSizingModeOf(c.PositionSizing, c.UseFixedQuantity),
+		RiskPerTradePercent:     c.PositionSizing.RiskPercent,
+		ATRPeriod:               c.PositionSizing.ATRPeriod,
+		ATRMultiplier:           c.PositionSizing.ATRMultiplier,
+		OrderExecutionMode:      c.OrderExecution.GetMode(),
+		TimeInForce:             c.OrderExecution.TimeInForce,
+		RepriceAfterSeconds:     c.OrderExecution.RepriceAfterSeconds,
+		MaxOrderAttempts:        c.OrderExecution.MaxAttempts,
+		UseExchangeOCO:          c.ExchangeOCO.IsEnabled(),
+		OCOStopLossPercent:      c.ExchangeOCO.StopLossPercent,
+		OCOTakeProfitPercent:    c.ExchangeOCO.TakeProfitPercent,
+		OCOStopLimitGapPercent:  c.ExchangeOCO.StopLimitGapPercent,
+		TrailingStopPercent:     c.ExitRules.TrailingStopPercent,
+		TakeProfitPercent:       c.ExitRules.TakeProfitPercent,
+		MaxHoldingSeconds:       c.ExitRules.MaxHoldingSeconds,
+		CooldownCandles:         c.CooldownRules.CooldownCandles,
+		CooldownMinutes:         c.CooldownRules.CooldownMinutes,
+		StopLossCooldownCandles: c.CooldownRules.StopLossCooldownCandles,
+		StopLossCooldownMinutes: c.CooldownRules.StopLossCooldownMinutes,
+		MaxTradesPerDay:         c.CooldownRules.MaxTradesPerDay,
+		UseStreaming:            c.UseStreaming,
+	}
+}
+
+// GetConfig returns the editable config the bot trades with
+func (b *TradingBot) GetConfig() BotConfig {
+	return BotConfig{
+		Strategy:               b.strategy,
+		Quantity:               b.quantity,
+		IntervalSeconds:        b.intervalSeconds,
+		TradeAmount:            b.tradeAmount,
+		TradingFees:            b.tradingFees,
+		MinimumProfitThreshold: b.minimumProfitThreshold,
+		UseFixedQuantity:       b.useFixedQuantity,
+		PositionSizing:         b.positionSizing,
+		OrderExecution:         b.orderExecution,
+		ExchangeOCO:            b.exchangeOCO,
+		ExitRules:              b.exitRules,
+		CooldownRules:          b.cooldownRules,
+		UseStreaming:           b.useStreaming,
+	}
+}
+
+// GetConfigVersion returns the version of the config in force, 1 for a bot never reconfigured
+func (b *TradingBot) GetConfigVersion() int {
+	return b.configVersion
+}
+
+// Reconfigure replaces the editable config of the bot and bumps its version. The open position, its tracking and
+// the OCO already protecting it are kept, the new exchange OCO legs apply from the next entry.
+func (b *TradingBot) Reconfigure(config BotConfig) error {
+	if config.Strategy == nil {
+		return fmt.Errorf("bot config needs a strategy")
+	}
+	b.strategy = config.Strategy
+	b.quantity = config.Quantity
+	b.intervalSeconds = config.IntervalSeconds
+	b.tradeAmount = config.TradeAmount
+	b.tradingFees = config.TradingFees
+	b.minimumProfitThreshold = config.MinimumProfitThreshold
+	b.useFixedQuantity = config.UseFixedQuantity
+	b.positionSizing = config.PositionSizing
+	b.orderExecution = config.OrderExecution
+	b.exchangeOCO = config.ExchangeOCO
+	b.exitRules = config.ExitRules
+	b.cooldownRules = config.CooldownRules
+	b.useStreaming = config.UseStreaming
+	b.configVersion++
+	return nil
+}
+
+// BotConfigVersion is the config a bot traded with from createdAt on, until the next version
+type BotConfigVersion struct {
+	Id            *vo.EntityId
+	tradingBotId  *vo.EntityId
+	version       int
+	config        BotConfigDTO
+	changedFields []string // Fields changed from the previous version, empty for the first one
+	createdAt     time.Time
+}
+
+func NewBotConfigVersion(tradingBotId *vo.EntityId, version int, config BotConfigDTO, changedFields []string) *BotConfigVersion {
+	return &BotConfigVersion{
+		Id:            vo.NewEntityId(),
+		tradingBotId:  tradingBotId,
+		version:       version,
+		config:        config,
+		changedFields: changedFields,
+		createdAt:     time.Now(),
+	}
+}
+
+func RestoreBotConfigVersion(id *vo.EntityId, tradingBotId *vo.EntityId, version int, config BotConfigDTO, changedFields []string, createdAt time.Time) *BotConfigVersion {
+	return &BotConfigVersion{
+		Id:            id,
+		tradingBotId:  tradingBotId,
+		version:       version,
+		config:        config,
+		changedFields: changedFields,
+		createdAt:     createdAt,
+	}
+}
+
+func (v *BotConfigVersion) GetTradingBotId() *vo.EntityId {
+	return v.tradingBotId
+}
+
+func (v *BotConfigVersion) GetVersion() int {
+	return v.version
+}
+
+func (v *BotConfigVersion) GetConfig() BotConfigDTO {
+	return v.config
+}
+
+func (v *BotConfigVersion) GetChangedFields() []string {
+	return v.changedFields
+}
+
+func (v *BotConfigVersion) GetCreatedAt() time.Time {
+	return v.createdAt
+}
+
+type BotConfigVersionDTO struct {
+	Id            string       `json:"id"`
+	TradingBotId  string       `json:"trading_bot_id"`
+	Version       int          `json:"version"`
+	Config        BotConfigDTO `json:"config"`
+	ChangedFields []string     `json:"changed_fields"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+func (v *BotConfigVersion) ToDTO() BotConfigVersionDTO {
+	changedFields := v.changedFields
+	if changedFields == nil {
+		changedFields = []string{}
+	}
+	return BotConfigVersionDTO{
+		Id:            v.Id.GetValue(),
+		TradingBotId:  v.tradingBotId.GetValue(),
+		Version:       v.version,
+		Config:        v.config,
+		ChangedFields: changedFields,
+		CreatedAt:     v.createdAt,
+	}
+}
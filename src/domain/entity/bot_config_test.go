@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTradingBot_ReconfigureKeepsThePosition(t *testing.T) {
+	bot := createTestTradingBot(true, 100.0, 1.0)
+	bot.actualQuantityHeld = 0.5
+	bot.StartPositionTracking(100.0, time.Now())
+	bot.SetActiveOCO(ActiveOCO{OrderListID: 42})
+	if bot.GetConfigVersion() != 1 {
+		t.Fatalf("expected a new bot on config version 1, got %d", bot.GetConfigVersion())
+	}
+
+	config := bot.GetConfig()
+	config.Strategy = NewMovingAverageStrategy(9, 21)
+	config.IntervalSeconds = 900
+	config.MinimumProfitThreshold = 2.0
+	config.ExitRules = ExitRules{TrailingStopPercent: 3.0}
+	if err := bot.Reconfigure(config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if bot.GetConfigVersion() != 2 || bot.GetStrategy().GetName() != "MovingAverage" || bot.GetIntervalSeconds() != 900 {
+		t.Errorf("expected the new config on version 2, got %+v", bot.ToDTO())
+	}
+	if bot.GetMinimumProfitThreshold() != 2.0 || bot.GetExitRules().TrailingStopPercent != 3.0 {
+		t.Errorf("expected the new trading parameters, got %+v", bot.ToDTO())
+	}
+	if !bot.GetIsPositioned() || bot.GetEntryPrice() != 100.0 || bot.GetActualQuantityHeld() != 0.5 {
+		t.Errorf("expected the open position to be kept, got %+v", bot.ToDTO())
+	}
+	if bot.GetHighestPriceSinceEntry() != 100.0 || !bot.GetActiveOCO().IsActive() {
+		t.Errorf("expected the position tracking and its OCO to be kept, got %+v", bot.ToDTO())
+	}
+
+	config.Strategy = nil
+	if err := bot.Reconfigure(config); err == nil || bot.GetConfigVersion() != 2 {
+		t.Errorf("expected a config without strategy to be refused, got version %d (%v)", bot.GetConfigVersion(), err)
+	}
+}
+
+func TestBotConfig_ToDTO(t *testing.T) {
+	bot := createTestTradingBot(false, 0, 1.0)
+	bot.SetPositionSizing(PositionSizing{RiskPercent: 1.0, ATRPeriod: 14, ATRMultiplier: 2.0})
+	bot.SetExchangeOCO(ExchangeOCO{StopLossPercent: 3.0, TakeProfitPercent: 6.0, StopLimitGapPercent: 0.5})
+
+	dto := bot.GetConfig().ToDTO()
+	if dto.Strategy != "RSI" || dto.Params["Period"] != 14 {
+		t.Errorf("expected the strategy and its params, got %s %v", dto.Strategy, dto.Params)
+	}
+	if dto.PositionSizingMode != SizingModeATRRisk || dto.RiskPerTradePercent != 1.0 {
+		t.Errorf("expected ATR risk sizing, got %s (%.2f%%)", dto.PositionSizingMode, dto.RiskPerTradePercent)
+	}
+	if !dto.UseExchangeOCO || dto.OCOStopLossPercent != 3.0 || dto.OCOTakeProfitPercent != 6.0 {
+		t.Errorf("expected the exchange OCO, got %+v", dto)
+	}
+}
@@ -0,0 +1,80 @@
+package entity
+
+import "fmt"
+
+// Reasons of the decisions the lifecycle of a bot overrides
+const (
+	HoldReasonPaused     = "bot_paused"
+	ExitReasonClosing    = "closing_position"
+	ExitReasonLiquidated = "force_liquidated"
+)
+
+// Pause stops a running bot from opening positions, its open position is still managed by its exit logic
+func (b *TradingBot) Pause() error {
+	if b.status != StatusRunning {
+		return fmt.Errorf("bot is not in running status, current status: %s", b.status)
+	}
+	b.status = StatusPaused
+	return nil
+}
+
+// Resume lets a paused bot open positions again
+func (b *TradingBot) Resume() error {
+	if b.status != StatusPaused {
+		return fmt.Errorf("bot is not in paused status, current status: %s", b.status)
+	}
+	b.status = StatusRunning
+	return nil
+}
+
+// BeginClosing makes the bot sell its open position at the next opportunity and stop afterwards. A stopped bot
+// can be closed too, so the position it was stopped with is not left without an exit.
+func (b *TradingBot) BeginClosing() error {
+	switch b.status {
+	case StatusRunning, StatusPaused, StatusStopped:
+	default:
+		return fmt.Errorf("bot cannot close its position in %s status", b.status)
+	}
+	if !b.isPositioned {
+		return fmt.Errorf("bot has no open position to close")
+	}
+	b.status = StatusClosing
+	return nil
+}
+
+// FinishClosing stops a closing bot once its position is sold
+func (b *TradingBot) FinishClosing() error {
+	if b.status != StatusClosing {
+		return fmt.Errorf("bot is not in closing status, current status: %s", b.status)
+	}
+	if b.isPositioned {
+		return fmt.Errorf("bot still has an open position")
+	}
+	b.status = StatusStopped
+	return nil
+}
+
+// IsActive reports whether the bot's trading loop has to run, i.e. it is running, paused or closing
+func (b *TradingBot) IsActive() bool {
+	switch b.status {
+	case StatusRunning, StatusPaused, StatusClosing:
+		return true
+	}
+	return false
+}
+
+// ApplyLifecycle turns a buy into a hold while the bot is paused or closing, and the decision of a closing bot
+// into a sell while it is positioned, keeping the strategy's analysis data. Other decisions are returned unchanged.
+func (b *TradingBot) ApplyLifecycle(result *StrategyAnalysisResult) *StrategyAnalysisResult {
+	switch {
+	case b.status == StatusClosing && b.isPositioned && result.Decision != Sell:
+		fmt.Printf("🏁 [%s] Closing position before stopping\n", b.symbol.GetValue())
+		return result.Override(Sell, ExitReasonClosing, nil)
+	case b.status == StatusClosing && result.Decision == Buy:
+		return result.Override(Hold, ExitReasonClosing, nil)
+	case b.status == StatusPaused && result.Decision == Buy:
+		fmt.Printf("⏸️ [%s] BUY suppressed: %s\n", b.symbol.GetValue(), HoldReasonPaused)
+		return result.Override(Hold, HoldReasonPaused, nil)
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package entity
+
+import "testing"
+
+func TestTradingBot_LifecycleTransitions(t *testing.T) {
+	bot := createTestTradingBot(false, 0, 1.0)
+
+	// A stopped bot can be neither paused nor resumed
+	if err := bot.Pause(); err == nil {
+		t.Error("expected a stopped bot not to be paused")
+	}
+	if err := bot.Resume(); err == nil {
+		t.Error("expected a stopped bot not to be resumed")
+	}
+
+	if err := bot.Start(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := bot.Pause(); err != nil || bot.GetStatus() != StatusPaused {
+		t.Fatalf("expected the bot to be paused, got %s (%v)", bot.GetStatus(), err)
+	}
+	if err := bot.Start(); err == nil {
+		t.Error("expected a paused bot to be resumed, not started")
+	}
+	if err := bot.Pause(); err == nil {
+		t.Error("expected a paused bot not to be paused again")
+	}
+	if err := bot.Resume(); err != nil || bot.GetStatus() != StatusRunning {
+		t.Fatalf("expected the bot to be running again, got %s (%v)", bot.GetStatus(), err)
+	}
+
+	// Without a position there is nothing to close
+	if err := bot.BeginClosing(); err == nil {
+		t.Error("expected a bot without position not to be closed")
+	}
+
+	bot.isPositioned = true
+	if err := bot.Pause(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := bot.BeginClosing(); err != nil || bot.GetStatus() != StatusClosing {
+		t.Fatalf("expected the paused bot to be closing, got %s (%v)", bot.GetStatus(), err)
+	}
+	if err := bot.Pause(); err == nil {
+		t.Error("expected a closing bot not to be paused")
+	}
+	if err := bot.Resume(); err == nil {
+		t.Error("expected a closing bot not to be resumed")
+	}
+	if err := bot.FinishClosing(); err == nil {
+		t.Error("expected a closing bot to keep closing while positioned")
+	}
+	if !bot.IsActive() {
+		t.Error("expected a closing bot to be active")
+	}
+
+	bot.isPositioned = false
+	if err := bot.FinishClosing(); err != nil || bot.GetStatus() != StatusStopped {
+		t.Fatalf("expected the bot to stop once out of position, got %s (%v)", bot.GetStatus(), err)
+	}
+	if bot.IsActive() {
+		t.Error("expected a stopped bot not to be active")
+	}
+}
+
+func TestTradingBot_BeginClosing_FromStoppedOrNeedsAttention(t *testing.T) {
+	// A bot stopped with a position can still get out of it
+	bot := createTestTradingBot(true, 100.0, 1.0)
+	if err := bot.BeginClosing(); err != nil || bot.GetStatus() != StatusClosing {
+		t.Fatalf("expected the stopped bot to be closing, got %s (%v)", bot.GetStatus(), err)
+	}
+
+	// A position that does not match the exchange is checked by hand first
+	bot = createTestTradingBot(true, 100.0, 1.0)
+	bot.MarkNeedsAttention()
+	if err := bot.BeginClosing(); err == nil {
+		t.Error("expected a bot needing attention not to be closed")
+	}
+}
+
+func TestTradingBot_ApplyLifecycle(t *testing.T) {
+	sellSignal := NewStrategyAnalysisResult(Sell, map[string]interface{}{"reason": "rsi_overbought_sell"})
+	holdSignal := NewStrategyAnalysisResult(Hold, map[string]interface{}{"reason": "no_signal", "rsi": 50.0})
+
+	// Running bots trade as their strategy decides
+	bot := createTestTradingBot(false, 0, 1.0)
+	bot.Start()
+	if result := bot.ApplyLifecycle(buySignal()); result.Decision != Buy {
+		t.Errorf("expected a running bot to buy, got %s", result.Decision)
+	}
+
+	// Paused bots take no entries but still exit
+	bot.Pause()
+	result := bot.ApplyLifecycle(buySignal())
+	if result.Decision != Hold || result.AnalysisData["reason"] != HoldReasonPaused {
+		t.Fatalf("expected the buy to be held while paused, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+	if result.AnalysisData["strategyDecision"] != string(Buy) || result.AnalysisData["rsi"] != 25.0 {
+		t.Errorf("expected the strategy's analysis to be kept, got %v", result.AnalysisData)
+	}
+	bot.isPositioned = true
+	if result := bot.ApplyLifecycle(sellSignal); result != sellSignal {
+		t.Errorf("expected a paused bot to exit as the strategy decides, got %s", result.Decision)
+	}
+
+	// Closing bots sell whatever the strategy decides
+	bot.BeginClosing()
+	result = bot.ApplyLifecycle(holdSignal)
+	if result.Decision != Sell || result.AnalysisData["reason"] != ExitReasonClosing || result.AnalysisData["rsi"] != 50.0 {
+		t.Fatalf("expected the closing bot to sell, got %s (%v)", result.Decision, result.AnalysisData)
+	}
+	if result := bot.ApplyLifecycle(sellSignal); result != sellSignal {
+		t.Errorf("expected the strategy's sell to be kept, got %v", result.AnalysisData["reason"])
+	}
+
+	// Until it stops, a closing bot out of position opens nothing
+	bot.isPositioned = false
+	if result := bot.ApplyLifecycle(buySignal()); result.Decision != Hold {
+		t.Errorf("expected a closing bot not to buy, got %s", result.Decision)
+	}
+}
@@ -0,0 +1,284 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+	"sort"
+)
+
+const (
+	CompositeRuleAll       = "all"        // Every child must agree
+	CompositeRuleAny       = "any"        // A single child is enough, as long as no child votes the opposite way
+	CompositeRuleWeighted  = "weighted"   // Weighted votes must exceed MajorityThreshold of the total weight
+	CompositeRuleEntryExit = "entry_exit" // First child decides entries, second child decides exits
+)
+
+// CompositeChild is one child strategy of a CompositeStrategy with its voting weight
+type CompositeChild struct {
+	Strategy TradingStrategy
+	Weight   float64
+}
+
+type CompositeStrategy struct {
+	Children          []CompositeChild
+	Rule              string
+	MajorityThreshold float64
+	StoplossThreshold float64
+}
+
+func init() {
+	RegisterStrategy(StrategyDefinition{
+		Name:        "Composite",
+		Description: "Combines child strategies with a voting rule (all, any, weighted or entry/exit)",
+		Params: []StrategyParamSpec{
+			{Name: "Strategies", Type: ParamTypeList, Default: nil, Description: "Child strategies as objects with Name, Params and optional Weight (default 1)"},
+			{Name: "Rule", Type: ParamTypeString, Default: CompositeRuleAll, Options: []string{CompositeRuleAll, CompositeRuleAny, CompositeRuleWeighted, CompositeRuleEntryExit}, Description: "How child decisions are combined; entry_exit takes entries from the first child and exits from the second"},
+			{Name: "MajorityThreshold", Type: ParamTypeFloat, Default: 0.5, Min: paramBound(0), Max: paramBound(1), Description: "Share of the total weight a decision must exceed under the weighted rule"},
+			{Name: "StoplossThreshold", Type: ParamTypeFloat, Default: 0.0, Min: paramBound(0), Max: paramBound(100), Description: "Loss % that forces a sell regardless of the children (0 disables stoploss)"},
+		},
+		Validate: func(params StrategyParams) error {
+			children := params.List("Strategies")
+			if len(children) == 0 {
+				return fmt.Errorf("missing or invalid fields for Composite: Strategies must have at least one child strategy")
+			}
+			if params.String("Rule") == CompositeRuleEntryExit && len(children) != 2 {
+				return fmt.Errorf("invalid Composite parameters: rule %s requires exactly 2 child strategies (entry, exit), got %d", CompositeRuleEntryExit, len(children))
+			}
+			return nil
+		},
+		Build: func(params StrategyParams) (TradingStrategy, error) {
+			children, err := buildCompositeChildren(params.List("Strategies"))
+			if err != nil {
+				return nil, err
+			}
+			return NewCompositeStrategyWithStoploss(children, params.String("Rule"), params.Float("MajorityThreshold"), params.Float("StoplossThreshold")), nil
+		},
+	})
+}
+
+// buildCompositeChildren resolves each child config through the strategy registry
+func buildCompositeChildren(configs []map[string]interface{}) ([]CompositeChild, error) {
+	children := make([]CompositeChild, 0, len(configs))
+	for i, config := range configs {
+		name, _ := config["Name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("invalid Composite child %d: Name is required", i)
+		}
+
+		var params map[string]interface{}
+		switch raw := config["Params"].(type) {
+		case nil:
+			params = map[string]interface{}{}
+		case map[string]interface{}:
+			params = raw
+		default:
+			return nil, fmt.Errorf("invalid Composite child %d (%s): Params must be an object", i, name)
+		}
+
+		weight := 1.0
+		if raw, exists := config["Weight"]; exists && raw != nil {
+			value, ok := toFloat64(raw)
+			if !ok || value <= 0 {
+				return nil, fmt.Errorf("invalid Composite child %d (%s): Weight must be a number > 0", i, name)
+			}
+			weight = value
+		}
+
+		strategy, err := NewStrategyFromParams(name, params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Composite child %d (%s): %w", i, name, err)
+		}
+		children = append(children, CompositeChild{Strategy: strategy, Weight: weight})
+	}
+	return children, nil
+}
+
+func NewCompositeStrategy(children []CompositeChild, rule string) *CompositeStrategy {
+	return &CompositeStrategy{
+		Children:          children,
+		Rule:              rule,
+		MajorityThreshold: 0.5,
+		StoplossThreshold: 0.0,
+	}
+}
+
+func NewCompositeStrategyWithStoploss(children []CompositeChild, rule string, majorityThreshold float64, stoplossThreshold float64) *CompositeStrategy {
+	return &CompositeStrategy{
+		Children:          children,
+		Rule:              rule,
+		MajorityThreshold: majorityThreshold,
+		StoplossThreshold: stoplossThreshold,
+	}
+}
+
+func (s *CompositeStrategy) GetName() string {
+	return "Composite"
+}
+
+// GetParams returns the nested child configs in the same shape Build accepts, so
+// strategy_params JSON round-trips through the registry
+func (s *CompositeStrategy) GetParams() map[string]interface{} {
+	children := make([]map[string]interface{}, 0, len(s.Children))
+	for _, child := range s.Children {
+		children = append(children, map[string]interface{}{
+			"Name":   child.Strategy.GetName(),
+			"Params": child.Strategy.GetParams(),
+			"Weight": child.Weight,
+		})
+	}
+
+	return map[string]interface{}{
+		"Strategies":        children,
+		"Rule":              s.Rule,
+		"MajorityThreshold": s.MajorityThreshold,
+		"StoplossThreshold": s.StoplossThreshold,
+	}
+}
+
+// GetTimeframes returns the additional intervals needed by any of the children
+func (s *CompositeStrategy) GetTimeframes() []int {
+	seen := map[int]bool{}
+	var timeframes []int
+	for _, child := range s.Children {
+		for _, interval := range RequiredTimeframes(child.Strategy) {
+			if !seen[interval] {
+				seen[interval] = true
+				timeframes = append(timeframes, interval)
+			}
+		}
+	}
+	sort.Ints(timeframes)
+	return timeframes
+}
+
+func (s *CompositeStrategy) Decide(klines []vo.Kline, tradingBot *TradingBot) *StrategyAnalysisResult {
+	return s.DecideWithTimeframes(klines, nil, tradingBot)
+}
+
+func (s *CompositeStrategy) DecideWithTimeframes(klines []vo.Kline, timeframes Timeframes, tradingBot *TradingBot) *StrategyAnalysisResult {
+	if len(klines) == 0 {
+		return NewStrategyAnalysisResult(Hold, map[string]interface{}{
+			"rule":   s.Rule,
+			"reason": "insufficient_data",
+		})
+	}
+
+	votes := make([]map[string]interface{}, 0, len(s.Children))
+	decisions := make([]TradingDecision, 0, len(s.Children))
+	weights := map[TradingDecision]float64{Buy: 0, Sell: 0, Hold: 0}
+	totalWeight := 0.0
+
+	for _, child := range s.Children {
+		result := DecideStrategy(child.Strategy, klines, timeframes, tradingBot)
+		decisions = append(decisions, result.Decision)
+		weights[result.Decision] += child.Weight
+		totalWeight += child.Weight
+		votes = append(votes, map[string]interface{}{
+			"strategy":     child.Strategy.GetName(),
+			"weight":       child.Weight,
+			"decision":     string(result.Decision),
+			"reason":       result.AnalysisData["reason"],
+			"analysisData": result.AnalysisData,
+		})
+	}
+
+	currentPrice := klines[len(klines)-1].Close()
+	exit := NewPositionExit(tradingBot, currentPrice, s.StoplossThreshold)
+
+	analysisData := exit.AnalysisData(map[string]interface{}{
+		"rule":        s.Rule,
+		"children":    votes,
+		"buyWeight":   weights[Buy],
+		"sellWeight":  weights[Sell],
+		"holdWeight":  weights[Hold],
+		"totalWeight": totalWeight,
+	})
+
+	// Check for stoploss first if positioned and stoploss is enabled
+	if result := exit.CheckStoploss("COMPOSITE STOPLOSS", analysisData); result != nil {
+		return result
+	}
+
+	var decision TradingDecision
+
+	switch s.Rule {
+	case CompositeRuleAny:
+		decision = s.decideAny(weights, analysisData)
+	case CompositeRuleWeighted:
+		decision = s.decideWeighted(weights, totalWeight, analysisData)
+	case CompositeRuleEntryExit:
+		decision = s.decideEntryExit(decisions, tradingBot, analysisData)
+	default:
+		decision = s.decideAll(weights, totalWeight, analysisData)
+	}
+
+	return NewStrategyAnalysisResult(decision, analysisData)
+}
+
+func (s *CompositeStrategy) decideAll(weights map[TradingDecision]float64, totalWeight float64, analysisData map[string]interface{}) TradingDecision {
+	if weights[Buy] == totalWeight {
+		analysisData["reason"] = "composite_all_agree_buy"
+		return Buy
+	}
+	if weights[Sell] == totalWeight {
+		analysisData["reason"] = "composite_all_agree_sell"
+		return Sell
+	}
+	analysisData["reason"] = "composite_no_consensus_hold"
+	return Hold
+}
+
+func (s *CompositeStrategy) decideAny(weights map[TradingDecision]float64, analysisData map[string]interface{}) TradingDecision {
+	if weights[Buy] > 0 && weights[Sell] > 0 {
+		analysisData["reason"] = "composite_conflicting_votes_hold"
+		return Hold
+	}
+	if weights[Buy] > 0 {
+		analysisData["reason"] = "composite_any_buy"
+		return Buy
+	}
+	if weights[Sell] > 0 {
+		analysisData["reason"] = "composite_any_sell"
+		return Sell
+	}
+	analysisData["reason"] = "composite_no_signal_hold"
+	return Hold
+}
+
+func (s *CompositeStrategy) decideWeighted(weights map[TradingDecision]float64, totalWeight float64, analysisData map[string]interface{}) TradingDecision {
+	required := totalWeight * s.MajorityThreshold
+	analysisData["requiredWeight"] = required
+
+	if weights[Buy] > required && weights[Buy] > weights[Sell] {
+		analysisData["reason"] = "composite_weighted_majority_buy"
+		return Buy
+	}
+	if weights[Sell] > required && weights[Sell] > weights[Buy] {
+		analysisData["reason"] = "composite_weighted_majority_sell"
+		return Sell
+	}
+	analysisData["reason"] = "composite_weighted_no_majority_hold"
+	return Hold
+}
+
+func (s *CompositeStrategy) decideEntryExit(decisions []TradingDecision, tradingBot *TradingBot, analysisData map[string]interface{}) TradingDecision {
+	if len(decisions) != 2 {
+		analysisData["reason"] = "composite_entry_exit_invalid_children"
+		return Hold
+	}
+
+	if !tradingBot.GetIsPositioned() && decisions[0] == Buy {
+		analysisData["reason"] = "composite_entry_buy"
+		return Buy
+	}
+	if tradingBot.GetIsPositioned() && decisions[1] == Sell {
+		analysisData["reason"] = "composite_exit_sell"
+		return Sell
+	}
+	if tradingBot.GetIsPositioned() {
+		analysisData["reason"] = "composite_positioned_waiting_for_exit"
+	} else {
+		analysisData["reason"] = "composite_waiting_for_entry"
+	}
+	return Hold
+}
@@ -0,0 +1,206 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// fixedStrategy always returns the same decision, to test the combination rules in isolation
+type fixedStrategy struct {
+	decision TradingDecision
+}
+
+func (s *fixedStrategy) GetName() string                   { return "Fixed" }
+func (s *fixedStrategy) GetParams() map[string]interface{} { return map[string]interface{}{} }
+func (s *fixedStrategy) Decide(klines []vo.Kline, tradingBot *TradingBot) *StrategyAnalysisResult {
+	return NewStrategyAnalysisResult(s.decision, map[string]interface{}{"reason": "fixed_" + string(s.decision)})
+}
+
+func fixedChildren(weighted map[TradingDecision]float64, order ...TradingDecision) []CompositeChild {
+	children := make([]CompositeChild, 0, len(order))
+	for _, decision := range order {
+		weight := 1.0
+		if w, exists := weighted[decision]; exists {
+			weight = w
+		}
+		children = append(children, CompositeChild{Strategy: &fixedStrategy{decision: decision}, Weight: weight})
+	}
+	return children
+}
+
+func TestCompositeStrategy_Rules(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		children   []CompositeChild
+		positioned bool
+		expected   TradingDecision
+	}{
+		{"all agree buy", CompositeRuleAll, fixedChildren(nil, Buy, Buy), false, Buy},
+		{"all without consensus holds", CompositeRuleAll, fixedChildren(nil, Buy, Hold), false, Hold},
+		{"any buys on one vote", CompositeRuleAny, fixedChildren(nil, Hold, Buy, Hold), false, Buy},
+		{"any holds on conflicting votes", CompositeRuleAny, fixedChildren(nil, Buy, Sell), false, Hold},
+		{"weighted majority buys", CompositeRuleWeighted, fixedChildren(nil, Buy, Buy, Hold), false, Buy},
+		{"weighted heavy hold wins", CompositeRuleWeighted, fixedChildren(map[TradingDecision]float64{Hold: 3}, Buy, Buy, Hold), false, Hold},
+		{"weighted tie holds", CompositeRuleWeighted, fixedChildren(nil, Sell, Hold), true, Hold},
+		{"entry from first child", CompositeRuleEntryExit, fixedChildren(nil, Buy, Hold), false, Buy},
+		{"entry ignores second child", CompositeRuleEntryExit, fixedChildren(nil, Hold, Buy), false, Hold},
+		{"exit from second child", CompositeRuleEntryExit, fixedChildren(nil, Hold, Sell), true, Sell},
+		{"exit ignores first child", CompositeRuleEntryExit, fixedChildren(nil, Sell, Hold), true, Hold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := NewCompositeStrategy(tt.children, tt.rule)
+			bot := createTestTradingBot(tt.positioned, 100.0, 0.0)
+
+			result := strategy.Decide(createTestKlinesWithPrices([]float64{100, 101}), bot)
+
+			if result.Decision != tt.expected {
+				t.Errorf("expected %s, got %s (reason: %v)", tt.expected, result.Decision, result.AnalysisData["reason"])
+			}
+		})
+	}
+}
+
+func TestCompositeStrategy_NestsChildAnalysis(t *testing.T) {
+	strategy := NewCompositeStrategy(fixedChildren(nil, Buy, Hold), CompositeRuleAll)
+	bot := createTestTradingBot(false, 0.0, 1.0)
+
+	result := strategy.Decide(createTestKlinesWithPrices([]float64{100, 101}), bot)
+
+	votes, ok := result.AnalysisData["children"].([]map[string]interface{})
+	if !ok || len(votes) != 2 {
+		t.Fatalf("expected 2 child votes, got %v", result.AnalysisData["children"])
+	}
+	if votes[0]["decision"] != "BUY" || votes[1]["decision"] != "HOLD" {
+		t.Errorf("expected BUY/HOLD votes, got %v/%v", votes[0]["decision"], votes[1]["decision"])
+	}
+	childData := votes[0]["analysisData"].(map[string]interface{})
+	if childData["reason"] != "fixed_BUY" {
+		t.Errorf("expected child analysis data to be nested, got %v", childData)
+	}
+	if result.AnalysisData["currentPrice"] != 101.0 {
+		t.Errorf("expected composite position keys, got %v", result.AnalysisData)
+	}
+}
+
+func TestCompositeStrategy_Stoploss(t *testing.T) {
+	strategy := NewCompositeStrategyWithStoploss(fixedChildren(nil, Hold), CompositeRuleAll, 0.5, 5.0)
+	bot := createTestTradingBot(true, 110.0, 1.0)
+
+	result := strategy.Decide(createTestKlinesWithPrices([]float64{100, 100}), bot)
+
+	if result.Decision != Sell || result.AnalysisData["reason"] != "stoploss_triggered" {
+		t.Errorf("expected stoploss Sell, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+}
+
+func TestCompositeStrategy_RegistryRoundTripsJSON(t *testing.T) {
+	raw := map[string]interface{}{
+		"Rule": CompositeRuleEntryExit,
+		"Strategies": []interface{}{
+			map[string]interface{}{"Name": "MovingAverage", "Params": map[string]interface{}{"FastWindow": 5.0, "SlowWindow": 20.0}},
+			map[string]interface{}{"Name": "RSI", "Params": map[string]interface{}{"Period": 10.0}, "Weight": 2.0},
+		},
+	}
+
+	strategy, err := NewStrategyFromParams("Composite", raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Simulate the strategy_params column: marshal GetParams and restore from the decoded JSON
+	encoded, err := json.Marshal(strategy.GetParams())
+	if err != nil {
+		t.Fatalf("failed to marshal params: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal params: %v", err)
+	}
+
+	restored, err := NewStrategyFromParams("Composite", decoded)
+	if err != nil {
+		t.Fatalf("expected restored composite, got %v", err)
+	}
+
+	composite := restored.(*CompositeStrategy)
+	if composite.Rule != CompositeRuleEntryExit || len(composite.Children) != 2 {
+		t.Fatalf("expected entry_exit with 2 children, got %s with %d", composite.Rule, len(composite.Children))
+	}
+	ma := composite.Children[0].Strategy.(*MovingAverageStrategy)
+	if ma.FastWindow != 5 || ma.SlowWindow != 20 {
+		t.Errorf("expected MA 5/20, got %d/%d", ma.FastWindow, ma.SlowWindow)
+	}
+	if composite.Children[1].Strategy.GetName() != "RSI" || composite.Children[1].Weight != 2.0 {
+		t.Errorf("expected RSI child with weight 2, got %s/%.1f", composite.Children[1].Strategy.GetName(), composite.Children[1].Weight)
+	}
+
+	reencoded, _ := json.Marshal(restored.GetParams())
+	var redecoded map[string]interface{}
+	json.Unmarshal(reencoded, &redecoded)
+	if !reflect.DeepEqual(decoded, redecoded) {
+		t.Errorf("expected params to round trip unchanged\nfirst:  %s\nsecond: %s", encoded, reencoded)
+	}
+}
+
+func TestCompositeStrategy_RejectsInvalidParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		expected string
+	}{
+		{"no children", map[string]interface{}{}, "missing or invalid fields for Composite: Strategies must have at least one child strategy"},
+		{"not a list", map[string]interface{}{"Strategies": "RSI"}, "Strategies must be a list of objects"},
+		{"entry_exit needs two children", map[string]interface{}{
+			"Rule":       CompositeRuleEntryExit,
+			"Strategies": []interface{}{map[string]interface{}{"Name": "RSI"}},
+		}, "invalid Composite parameters: rule entry_exit requires exactly 2 child strategies (entry, exit), got 1"},
+		{"unknown child", map[string]interface{}{
+			"Strategies": []interface{}{map[string]interface{}{"Name": "Nope"}},
+		}, "invalid Composite child 0 (Nope): unknown strategy: Nope"},
+		{"invalid child params", map[string]interface{}{
+			"Strategies": []interface{}{map[string]interface{}{"Name": "RSI", "Params": map[string]interface{}{"Period": "x"}}},
+		}, "invalid Composite child 0 (RSI): Period must be a number"},
+		{"invalid weight", map[string]interface{}{
+			"Strategies": []interface{}{map[string]interface{}{"Name": "RSI", "Weight": 0.0}},
+		}, "invalid Composite child 0 (RSI): Weight must be a number > 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStrategyFromParams("Composite", tt.params)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestCompositeStrategy_PassesTimeframesToChildren(t *testing.T) {
+	klines := []vo.Kline{
+		mustKline(10), mustKline(9), mustKline(8), mustKline(8), mustKline(8),
+	}
+	children := []CompositeChild{
+		{Strategy: NewMovingAverageStrategy(3, 5).WithTrendFilter(14400, 3), Weight: 1},
+		{Strategy: NewMovingAverageStrategy(3, 5).WithTrendFilter(3600, 3), Weight: 1},
+		{Strategy: &fixedStrategy{decision: Buy}, Weight: 1},
+	}
+	strategy := NewCompositeStrategy(children, CompositeRuleAll)
+
+	if timeframes := strategy.GetTimeframes(); !reflect.DeepEqual(timeframes, []int{3600, 14400}) {
+		t.Fatalf("expected the children timeframes [3600 14400], got %v", timeframes)
+	}
+
+	uptrend := []vo.Kline{mustKline(20), mustKline(21), mustKline(25)}
+	result := DecideStrategy(strategy, klines, Timeframes{3600: uptrend, 14400: uptrend}, createTestBot())
+	if result.Decision != Buy {
+		t.Errorf("expected Buy when every child sees an uptrend, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+}
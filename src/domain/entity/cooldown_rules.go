@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Hold reasons of the buys suppressed by the cooldown rules
+const (
+	HoldReasonCooldown         = "cooldown_after_exit"
+	HoldReasonStopLossCooldown = "cooldown_after_stop_loss"
+	HoldReasonMaxTradesPerDay  = "max_trades_per_day_reached"
+)
+
+// CooldownRules are bot-level re-entry conditions checked on every buy decision. After any exit the bot waits for
+// the longer of CooldownCandles candles and CooldownMinutes, after a stop-loss exit for the longer of those and the
+// stop-loss cooldown. MaxTradesPerDay caps the positions opened per UTC day. A zero value disables the rule.
+type CooldownRules struct {
+	CooldownCandles         int
+	CooldownMinutes         int
+	StopLossCooldownCandles int
+	StopLossCooldownMinutes int
+	MaxTradesPerDay         int
+}
+
+func NewCooldownRules(cooldownCandles, cooldownMinutes, stopLossCooldownCandles, stopLossCooldownMinutes, maxTradesPerDay int) (CooldownRules, error) {
+	if cooldownCandles < 0 || cooldownMinutes < 0 {
+		return CooldownRules{}, fmt.Errorf("invalid cooldown: must be greater than or equal to zero")
+	}
+	if stopLossCooldownCandles < 0 || stopLossCooldownMinutes < 0 {
+		return CooldownRules{}, fmt.Errorf("invalid stop loss cooldown: must be greater than or equal to zero")
+	}
+	if maxTradesPerDay < 0 {
+		return CooldownRules{}, fmt.Errorf("invalid max trades per day: must be greater than or equal to zero")
+	}
+	return CooldownRules{
+		CooldownCandles:         cooldownCandles,
+		CooldownMinutes:         cooldownMinutes,
+		StopLossCooldownCandles: stopLossCooldownCandles,
+		StopLossCooldownMinutes: stopLossCooldownMinutes,
+		MaxTradesPerDay:         maxTradesPerDay,
+	}, nil
+}
+
+// IsEnabled reports whether at least one rule is active
+func (r CooldownRules) IsEnabled() bool {
+	return r.CooldownCandles > 0 || r.CooldownMinutes > 0 || r.StopLossCooldownCandles > 0 || r.StopLossCooldownMinutes > 0 || r.MaxTradesPerDay > 0
+}
+
+// Duration returns how long the bot waits after an exit with the given reason, candles lasting intervalSeconds
+func (r CooldownRules) Duration(exitReason string, intervalSeconds int) time.Duration {
+	candle := time.Duration(intervalSeconds) * time.Second
+	cooldown := maxDuration(time.Duration(r.CooldownCandles)*candle, time.Duration(r.CooldownMinutes)*time.Minute)
+	if IsStopLossExitReason(exitReason) {
+		cooldown = maxDuration(cooldown, time.Duration(r.StopLossCooldownCandles)*candle)
+		cooldown = maxDuration(cooldown, time.Duration(r.StopLossCooldownMinutes)*time.Minute)
+	}
+	return cooldown
+}
+
+// IsStopLossExitReason reports whether a sell reason means the position was stopped out at a loss
+func IsStopLossExitReason(reason string) bool {
+	switch reason {
+	case ExitReasonStopLoss, ExitReasonTrailingStop, ExitReasonOCOStopLoss:
+		return true
+	}
+	return false
+}
+
+// CooldownState is what the cooldown rules remember of the bot's trades
+type CooldownState struct {
+	LastExitAt     time.Time
+	LastExitReason string
+	TradesDay      time.Time // UTC day TradesToday counts the entries of
+	TradesToday    int
+}
+
+// RecordEntry counts a position opened at the given time in the trades of its UTC day
+func (b *TradingBot) RecordEntry(at time.Time) {
+	day := utcDay(at)
+	if !b.cooldownState.TradesDay.Equal(day) {
+		b.cooldownState.TradesDay = day
+		b.cooldownState.TradesToday = 0
+	}
+	b.cooldownState.TradesToday++
+}
+
+// RecordExit remembers when and why the last position was closed, starting the cooldown
+func (b *TradingBot) RecordExit(reason string, at time.Time) {
+	b.cooldownState.LastExitAt = at
+	b.cooldownState.LastExitReason = reason
+}
+
+// ApplyCooldown turns a buy into a hold while the bot cools down from its last exit or has reached its trades of
+// the day, keeping the strategy's analysis data. Other decisions are returned unchanged.
+func (b *TradingBot) ApplyCooldown(result *StrategyAnalysisResult, now time.Time) *StrategyAnalysisResult {
+	if result.Decision != Buy || b.isPositioned || !b.cooldownRules.IsEnabled() {
+		return result
+	}
+
+	state := b.cooldownState
+	reason := ""
+	details := map[string]interface{}{}
+	if !state.LastExitAt.IsZero() {
+		cooldownUntil := state.LastExitAt.Add(b.cooldownRules.Duration(state.LastExitReason, b.intervalSeconds))
+		if now.Before(cooldownUntil) {
+			reason = HoldReasonCooldown
+			if IsStopLossExitReason(state.LastExitReason) {
+				reason = HoldReasonStopLossCooldown
+			}
+			details["lastExitReason"] = state.LastExitReason
+			details["cooldownUntil"] = cooldownUntil
+		}
+	}
+	if reason == "" && b.cooldownRules.MaxTradesPerDay > 0 && state.TradesDay.Equal(utcDay(now)) && state.TradesToday >= b.cooldownRules.MaxTradesPerDay {
+		reason = HoldReasonMaxTradesPerDay
+		details["tradesToday"] = state.TradesToday
+		details["maxTradesPerDay"] = b.cooldownRules.MaxTradesPerDay
+	}
+	if reason == "" {
+		return result
+	}
+
+	fmt.Printf("⏳ [%s] BUY suppressed: %s\n", b.symbol.GetValue(), reason)
+	return result.Override(Hold, reason, details)
+}
+
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
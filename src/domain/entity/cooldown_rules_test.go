@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func createTestBotWithCooldownRules(rules CooldownRules) *TradingBot {
+	bot := createTestTradingBot(false, 0, 1.0) // 5 minute candles
+	bot.SetCooldownRules(rules)
+	return bot
+}
+
+func buySignal() *StrategyAnalysisResult {
+	return NewStrategyAnalysisResult(Buy, map[string]interface{}{"reason": "rsi_oversold_buy", "rsi": 25.0})
+}
+
+func TestNewCooldownRules_Validation(t *testing.T) {
+	if _, err := NewCooldownRules(3, 15, 12, 60, 4); err != nil {
+		t.Fatalf("expected valid rules, got %v", err)
+	}
+	if _, err := NewCooldownRules(-1, 0, 0, 0, 0); err == nil {
+		t.Error("expected error for negative cooldown")
+	}
+	if _, err := NewCooldownRules(0, 0, 0, -5, 0); err == nil {
+		t.Error("expected error for negative stop loss cooldown")
+	}
+	if _, err := NewCooldownRules(0, 0, 0, 0, -1); err == nil {
+		t.Error("expected error for negative max trades per day")
+	}
+}
+
+func TestTradingBot_ApplyCooldown_AfterExit(t *testing.T) {
+	exitAt := time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC)
+	bot := createTestBotWithCooldownRules(CooldownRules{CooldownCandles: 3, CooldownMinutes: 10, StopLossCooldownMinutes: 60})
+
+	// Without an exit there is nothing to cool down from
+	if result := bot.ApplyCooldown(buySignal(), exitAt); result.Decision != Buy {
+		t.Fatalf("expected the buy to pass before any exit, got %s", result.Decision)
+	}
+
+	// 3 candles of 5 minutes outlast the 10 minutes
+	bot.RecordExit(ExitReasonTakeProfit, exitAt)
+	result := bot.ApplyCooldown(buySignal(), exitAt.Add(14*time.Minute))
+	if result.Decision != Hold || result.AnalysisData["reason"] != HoldReasonCooldown {
+		t.Fatalf("expected the buy to be held by the cooldown, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+	if result.AnalysisData["strategyDecision"] != string(Buy) || result.AnalysisData["rsi"] != 25.0 {
+		t.Errorf("expected the strategy's analysis to be kept, got %v", result.AnalysisData)
+	}
+	if result := bot.ApplyCooldown(buySignal(), exitAt.Add(15*time.Minute)); result.Decision != Buy {
+		t.Errorf("expected the buy to pass once the cooldown is over, got %s", result.Decision)
+	}
+
+	// A stop-loss exit waits for the longer stop-loss cooldown
+	bot.RecordExit(ExitReasonTrailingStop, exitAt)
+	result = bot.ApplyCooldown(buySignal(), exitAt.Add(59*time.Minute))
+	if result.Decision != Hold || result.AnalysisData["reason"] != HoldReasonStopLossCooldown {
+		t.Fatalf("expected the buy to be held by the stop loss cooldown, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+	if result := bot.ApplyCooldown(buySignal(), exitAt.Add(time.Hour)); result.Decision != Buy {
+		t.Errorf("expected the buy to pass after the stop loss cooldown, got %s", result.Decision)
+	}
+
+	// Only buys are held
+	sell := NewStrategyAnalysisResult(Sell, map[string]interface{}{})
+	if result := bot.ApplyCooldown(sell, exitAt); result != sell {
+		t.Errorf("expected other decisions to be unchanged, got %s", result.Decision)
+	}
+}
+
+func TestTradingBot_ApplyCooldown_MaxTradesPerDay(t *testing.T) {
+	day := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	bot := createTestBotWithCooldownRules(CooldownRules{MaxTradesPerDay: 2})
+
+	bot.RecordEntry(day.Add(-time.Hour)) // Yesterday's entry does not count
+	bot.RecordEntry(day.Add(9 * time.Hour))
+	if result := bot.ApplyCooldown(buySignal(), day.Add(10*time.Hour)); result.Decision != Buy {
+		t.Fatalf("expected a second trade to be allowed, got %s", result.Decision)
+	}
+
+	bot.RecordEntry(day.Add(11 * time.Hour))
+	result := bot.ApplyCooldown(buySignal(), day.Add(12*time.Hour))
+	if result.Decision != Hold || result.AnalysisData["reason"] != HoldReasonMaxTradesPerDay || result.AnalysisData["tradesToday"] != 2 {
+		t.Fatalf("expected the buy to be held by the daily limit, got %s (%v)", result.Decision, result.AnalysisData)
+	}
+
+	if result := bot.ApplyCooldown(buySignal(), day.Add(24*time.Hour)); result.Decision != Buy {
+		t.Errorf("expected the limit to reset the next day, got %s", result.Decision)
+	}
+}
@@ -0,0 +1,72 @@
+package entity
+
+import "fmt"
+
+const DefaultOCOStopLimitGapPercent = 0.5
+
+// Sell reasons of positions stopped out by the strategy's stoploss, and of positions closed by an exchange-side OCO
+const (
+	ExitReasonStopLoss      = "stoploss_triggered"
+	ExitReasonOCOTakeProfit = "oco_take_profit_filled"
+	ExitReasonOCOStopLoss   = "oco_stop_loss_filled"
+)
+
+// ExchangeOCO configures the exchange-native OCO sell placed after each entry, so the position stays protected
+// between ticks and while the bot is down. One leg is a take-profit limit order TakeProfitPercent above the entry
+// price, the other a stop-loss limit order triggered StopLossPercent below it, with its limit price
+// StopLimitGapPercent under the trigger so it still fills in a fast drop. The zero value places no OCO.
+type ExchangeOCO struct {
+	StopLossPercent     float64
+	TakeProfitPercent   float64
+	StopLimitGapPercent float64
+}
+
+// NewExchangeOCO validates the OCO parameters, using the default gap for a zero StopLimitGapPercent
+func NewExchangeOCO(stopLossPercent, takeProfitPercent, stopLimitGapPercent float64) (ExchangeOCO, error) {
+	if stopLossPercent <= 0 || stopLossPercent >= 100 {
+		return ExchangeOCO{}, fmt.Errorf("invalid OCO stop loss: must be between 0 and 100")
+	}
+	if takeProfitPercent <= 0 {
+		return ExchangeOCO{}, fmt.Errorf("invalid OCO take profit: must be greater than zero")
+	}
+	if stopLimitGapPercent < 0 || stopLossPercent+stopLimitGapPercent >= 100 {
+		return ExchangeOCO{}, fmt.Errorf("invalid OCO stop limit gap: must be greater than or equal to zero and keep the stop limit price above zero")
+	}
+	if stopLimitGapPercent == 0 {
+		stopLimitGapPercent = DefaultOCOStopLimitGapPercent
+	}
+	return ExchangeOCO{
+		StopLossPercent:     stopLossPercent,
+		TakeProfitPercent:   takeProfitPercent,
+		StopLimitGapPercent: stopLimitGapPercent,
+	}, nil
+}
+
+func (o ExchangeOCO) IsEnabled() bool {
+	return o.StopLossPercent > 0 && o.TakeProfitPercent > 0
+}
+
+// Prices returns the take-profit limit price, the stop-loss trigger and the stop-loss limit price for an entry
+func (o ExchangeOCO) Prices(entryPrice float64) (takeProfitPrice, stopPrice, stopLimitPrice float64) {
+	takeProfitPrice = entryPrice * (1 + o.TakeProfitPercent/100)
+	stopPrice = entryPrice * (1 - o.StopLossPercent/100)
+	stopLimitPrice = stopPrice * (1 - o.StopLimitGapPercent/100)
+	return takeProfitPrice, stopPrice, stopLimitPrice
+}
+
+// ActiveOCO identifies the OCO protecting the bot's open position on the exchange
+type ActiveOCO struct {
+	OrderListID       int64
+	TakeProfitOrderID int64
+	StopLossOrderID   int64
+}
+
+func (a ActiveOCO) IsActive() bool {
+	return a.OrderListID != 0
+}
+
+// StoplossThresholdOf returns the StoplossThreshold param of a strategy, 0 if it has none
+func StoplossThresholdOf(strategy TradingStrategy) float64 {
+	threshold, _ := strategy.GetParams()["StoplossThreshold"].(float64)
+	return threshold
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewExchangeOCO_Validation(t *testing.T) {
+	oco, err := NewExchangeOCO(2.0, 5.0, 0)
+	if err != nil || !oco.IsEnabled() || oco.StopLimitGapPercent != DefaultOCOStopLimitGapPercent {
+		t.Fatalf("expected a valid OCO with the default gap, got %+v (err: %v)", oco, err)
+	}
+
+	if (ExchangeOCO{}).IsEnabled() {
+		t.Error("expected the zero value not to place an OCO")
+	}
+	if _, err := NewExchangeOCO(0, 5.0, 0); err == nil {
+		t.Error("expected error for a missing stop loss")
+	}
+	if _, err := NewExchangeOCO(2.0, 0, 0); err == nil {
+		t.Error("expected error for a missing take profit")
+	}
+	if _, err := NewExchangeOCO(2.0, 5.0, -1); err == nil {
+		t.Error("expected error for a negative stop limit gap")
+	}
+	if _, err := NewExchangeOCO(60, 5.0, 40); err == nil {
+		t.Error("expected error for a stop limit price at or below zero")
+	}
+}
+
+func TestExchangeOCO_Prices(t *testing.T) {
+	oco, _ := NewExchangeOCO(2.0, 5.0, 1.0)
+	takeProfit, stop, stopLimit := oco.Prices(200.0)
+
+	for _, price := range []struct {
+		name             string
+		expected, actual float64
+	}{
+		{"take profit", 210.0, takeProfit},
+		{"stop", 196.0, stop},
+		{"stop limit", 194.04, stopLimit},
+	} {
+		if math.Abs(price.expected-price.actual) > 1e-9 {
+			t.Errorf("expected %s price %.4f, got %.4f", price.name, price.expected, price.actual)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultExchangeProfile is the exchange account bots trade on unless created on another profile
+const DefaultExchangeProfile = "production"
+
+var exchangeProfileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// NewExchangeProfileName validates the name of an exchange profile, an empty name is the default profile
+func NewExchangeProfileName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultExchangeProfile, nil
+	}
+	if !exchangeProfileNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid exchange profile: %s (use up to 50 letters, digits, - or _)", name)
+	}
+	return name, nil
+}
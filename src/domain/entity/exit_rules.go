@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Sell reasons produced by the bot-level exit rules
+const (
+	ExitReasonTrailingStop   = "trailing_stop_triggered"
+	ExitReasonTakeProfit     = "take_profit_triggered"
+	ExitReasonMaxHoldingTime = "max_holding_time_reached"
+)
+
+// ExitRules are bot-level exit conditions evaluated before the strategy on every tick.
+// A zero value disables the corresponding rule.
+type ExitRules struct {
+	TrailingStopPercent float64 // Sell when price drops this % below the highest price since entry
+	TakeProfitPercent   float64 // Sell when profit from the entry price reaches this %
+	MaxHoldingSeconds   int     // Sell when the position has been open for this long
+}
+
+func NewExitRules(trailingStopPercent, takeProfitPercent float64, maxHoldingSeconds int) (ExitRules, error) {
+	if trailingStopPercent < 0 || trailingStopPercent >= 100 {
+		return ExitRules{}, fmt.Errorf("invalid trailing stop: must be between 0 and 100")
+	}
+	if takeProfitPercent < 0 {
+		return ExitRules{}, fmt.Errorf("invalid take profit: must be greater than or equal to zero")
+	}
+	if maxHoldingSeconds < 0 {
+		return ExitRules{}, fmt.Errorf("invalid max holding time: must be greater than or equal to zero")
+	}
+	return ExitRules{
+		TrailingStopPercent: trailingStopPercent,
+		TakeProfitPercent:   takeProfitPercent,
+		MaxHoldingSeconds:   maxHoldingSeconds,
+	}, nil
+}
+
+// IsEnabled reports whether at least one rule is active
+func (r ExitRules) IsEnabled() bool {
+	return r.TrailingStopPercent > 0 || r.TakeProfitPercent > 0 || r.MaxHoldingSeconds > 0
+}
+
+// IsForcedExitReason reports whether a sell reason must go through even at a loss or below the minimum profit
+func IsForcedExitReason(reason string) bool {
+	switch reason {
+	case ExitReasonStopLoss, ExitReasonTrailingStop, ExitReasonTakeProfit, ExitReasonMaxHoldingTime, ExitReasonRiskFlattened:
+		return true
+	}
+	return false
+}
+
+// TrackPosition updates the high-water mark of the open position and reports whether it changed.
+// Positions restored without tracking data start tracking from the current tick.
+func (b *TradingBot) TrackPosition(currentPrice float64, now time.Time) bool {
+	if !b.isPositioned {
+		return false
+	}
+
+	changed := false
+	if b.highestPriceSinceEntry < b.entryPrice {
+		b.highestPriceSinceEntry = b.entryPrice
+		changed = true
+	}
+	if currentPrice > b.highestPriceSinceEntry {
+		b.highestPriceSinceEntry = currentPrice
+		changed = true
+	}
+	if b.positionOpenedAt.IsZero() {
+		b.positionOpenedAt = now
+		changed = true
+	}
+	return changed
+}
+
+// EvaluateExitRules returns a Sell when one of the bot's exit rules fires for the open position, nil otherwise.
+// Call TrackPosition first so the trailing stop sees the latest high-water mark.
+func (b *TradingBot) EvaluateExitRules(currentPrice float64, now time.Time) *StrategyAnalysisResult {
+	if !b.isPositioned || b.entryPrice <= 0 || !b.exitRules.IsEnabled() {
+		return nil
+	}
+
+	exit := NewPositionExit(b, currentPrice, 0)
+	possibleProfit := exit.GetPossibleProfit()
+
+	trailingStopPrice := 0.0
+	if b.exitRules.TrailingStopPercent > 0 && b.highestPriceSinceEntry > 0 {
+		trailingStopPrice = b.highestPriceSinceEntry * (1 - b.exitRules.TrailingStopPercent/100)
+	}
+	holdingSeconds := 0
+	if !b.positionOpenedAt.IsZero() {
+		holdingSeconds = int(now.Sub(b.positionOpenedAt).Seconds())
+	}
+
+	analysisData := exit.AnalysisData(map[string]interface{}{
+		"highestPriceSinceEntry": b.highestPriceSinceEntry,
+		"trailingStopPercent":    b.exitRules.TrailingStopPercent,
+		"trailingStopPrice":      trailingStopPrice,
+		"takeProfitPercent":      b.exitRules.TakeProfitPercent,
+		"maxHoldingSeconds":      b.exitRules.MaxHoldingSeconds,
+		"holdingSeconds":         holdingSeconds,
+	})
+
+	symbol := b.symbol.GetValue()
+	switch {
+	case trailingStopPrice > 0 && currentPrice <= trailingStopPrice:
+		fmt.Printf("🚨 TRAILING STOP TRIGGERED! [%s] Price: %.2f, High: %.2f, Stop: %.2f (%.2f%%)\n",
+			symbol, currentPrice, b.highestPriceSinceEntry, trailingStopPrice, b.exitRules.TrailingStopPercent)
+		analysisData["reason"] = ExitReasonTrailingStop
+	case b.exitRules.TakeProfitPercent > 0 && possibleProfit >= b.exitRules.TakeProfitPercent:
+		fmt.Printf("🎯 TAKE PROFIT TRIGGERED! [%s] Profit: %.2f%%, Target: %.2f%%\n",
+			symbol, possibleProfit, b.exitRules.TakeProfitPercent)
+		analysisData["reason"] = ExitReasonTakeProfit
+	case b.exitRules.MaxHoldingSeconds > 0 && !b.positionOpenedAt.IsZero() && holdingSeconds >= b.exitRules.MaxHoldingSeconds:
+		fmt.Printf("⏰ MAX HOLDING TIME REACHED! [%s] Held: %ds, Limit: %ds, Profit: %.2f%%\n",
+			symbol, holdingSeconds, b.exitRules.MaxHoldingSeconds, possibleProfit)
+		analysisData["reason"] = ExitReasonMaxHoldingTime
+	default:
+		return nil
+	}
+
+	return NewStrategyAnalysisResult(Sell, analysisData)
+}
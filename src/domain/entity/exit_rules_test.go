@@ -0,0 +1,139 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func createTestBotWithExitRules(entryPrice float64, rules ExitRules, openedAt time.Time) *TradingBot {
+	bot := createTestTradingBot(true, entryPrice, 1.0)
+	bot.SetExitRules(rules)
+	bot.StartPositionTracking(entryPrice, openedAt)
+	return bot
+}
+
+func TestNewExitRules_Validation(t *testing.T) {
+	if _, err := NewExitRules(3.0, 8.0, 3600); err != nil {
+		t.Fatalf("expected valid rules, got %v", err)
+	}
+	if _, err := NewExitRules(100.0, 0, 0); err == nil {
+		t.Error("expected error for trailing stop of 100%")
+	}
+	if _, err := NewExitRules(0, -1.0, 0); err == nil {
+		t.Error("expected error for negative take profit")
+	}
+	if _, err := NewExitRules(0, 0, -60); err == nil {
+		t.Error("expected error for negative max holding time")
+	}
+}
+
+func TestTradingBot_EvaluateExitRules_Disabled(t *testing.T) {
+	now := time.Now()
+	bot := createTestBotWithExitRules(100.0, ExitRules{}, now.Add(-48*time.Hour))
+
+	bot.TrackPosition(50.0, now)
+	if result := bot.EvaluateExitRules(50.0, now); result != nil {
+		t.Errorf("expected no exit without rules, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+}
+
+func TestTradingBot_EvaluateExitRules_TrailingStop(t *testing.T) {
+	now := time.Now()
+	bot := createTestBotWithExitRules(100.0, ExitRules{TrailingStopPercent: 5.0}, now)
+
+	// Rally to 120 moves the high-water mark, the stop follows at 114
+	for _, price := range []float64{105.0, 120.0, 116.0} {
+		bot.TrackPosition(price, now)
+		if result := bot.EvaluateExitRules(price, now); result != nil {
+			t.Fatalf("expected no exit at %.2f, got %v", price, result.AnalysisData["reason"])
+		}
+	}
+	if bot.GetHighestPriceSinceEntry() != 120.0 {
+		t.Fatalf("expected high-water mark 120, got %.2f", bot.GetHighestPriceSinceEntry())
+	}
+
+	bot.TrackPosition(114.0, now)
+	result := bot.EvaluateExitRules(114.0, now)
+	if result == nil || result.Decision != Sell {
+		t.Fatal("expected Sell when price falls 5% below the high-water mark")
+	}
+	if result.AnalysisData["reason"] != ExitReasonTrailingStop {
+		t.Errorf("expected %s reason, got %v", ExitReasonTrailingStop, result.AnalysisData["reason"])
+	}
+	if result.AnalysisData["trailingStopPrice"].(float64) != 114.0 {
+		t.Errorf("expected trailing stop price 114, got %v", result.AnalysisData["trailingStopPrice"])
+	}
+}
+
+func TestTradingBot_EvaluateExitRules_TakeProfit(t *testing.T) {
+	now := time.Now()
+	bot := createTestBotWithExitRules(100.0, ExitRules{TakeProfitPercent: 8.0}, now)
+
+	if result := bot.EvaluateExitRules(107.0, now); result != nil {
+		t.Fatalf("expected no exit below take profit, got %v", result.AnalysisData["reason"])
+	}
+
+	result := bot.EvaluateExitRules(108.0, now)
+	if result == nil || result.AnalysisData["reason"] != ExitReasonTakeProfit {
+		t.Fatalf("expected take profit exit at 8%%, got %+v", result)
+	}
+}
+
+func TestTradingBot_EvaluateExitRules_MaxHoldingTime(t *testing.T) {
+	openedAt := time.Now()
+	bot := createTestBotWithExitRules(100.0, ExitRules{MaxHoldingSeconds: 3600}, openedAt)
+
+	if result := bot.EvaluateExitRules(95.0, openedAt.Add(59*time.Minute)); result != nil {
+		t.Fatalf("expected no exit before max holding time, got %v", result.AnalysisData["reason"])
+	}
+
+	// Sells even at a loss once the position is held too long
+	result := bot.EvaluateExitRules(95.0, openedAt.Add(time.Hour))
+	if result == nil || result.AnalysisData["reason"] != ExitReasonMaxHoldingTime {
+		t.Fatalf("expected max holding time exit, got %+v", result)
+	}
+	if result.AnalysisData["holdingSeconds"] != 3600 {
+		t.Errorf("expected 3600 holding seconds, got %v", result.AnalysisData["holdingSeconds"])
+	}
+}
+
+func TestTradingBot_TrackPosition_RestoredWithoutTracking(t *testing.T) {
+	now := time.Now()
+	bot := createTestTradingBot(true, 100.0, 1.0)
+
+	if !bot.TrackPosition(98.0, now) {
+		t.Fatal("expected tracking to start for a position restored without it")
+	}
+	if bot.GetHighestPriceSinceEntry() != 100.0 || !bot.GetPositionOpenedAt().Equal(now) {
+		t.Errorf("expected tracking from entry price and current tick, got %.2f at %v", bot.GetHighestPriceSinceEntry(), bot.GetPositionOpenedAt())
+	}
+	if bot.TrackPosition(99.0, now) {
+		t.Error("expected no change below the high-water mark")
+	}
+}
+
+func TestTradingBot_GetOutOfPosition_ClearsTracking(t *testing.T) {
+	bot := createTestBotWithExitRules(100.0, ExitRules{TrailingStopPercent: 5.0}, time.Now())
+	bot.TrackPosition(110.0, time.Now())
+
+	if err := bot.GetOutOfPosition(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if bot.GetHighestPriceSinceEntry() != 0 || !bot.GetPositionOpenedAt().IsZero() {
+		t.Error("expected position tracking to be cleared after leaving the position")
+	}
+	if bot.TrackPosition(120.0, time.Now()) {
+		t.Error("expected no tracking without an open position")
+	}
+}
+
+func TestIsForcedExitReason(t *testing.T) {
+	for _, reason := range []string{"stoploss_triggered", ExitReasonTrailingStop, ExitReasonTakeProfit, ExitReasonMaxHoldingTime, ExitReasonRiskFlattened} {
+		if !IsForcedExitReason(reason) {
+			t.Errorf("expected %s to be a forced exit", reason)
+		}
+	}
+	if IsForcedExitReason("ma_crossover_sell_with_profit") {
+		t.Error("expected strategy sell reasons not to be forced exits")
+	}
+}
@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/indicator"
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+)
+
+type MACDStrategy struct {
+	FastPeriod            int
+	SlowPeriod            int
+	SignalPeriod          int
+	HistogramConfirmation bool
+	StoplossThreshold     float64
+}
+
+func init() {
+	RegisterStrategy(StrategyDefinition{
+		Name:        "MACD",
+		Description: "Buys when the MACD line crosses above its signal line and sells when it crosses below it",
+		Params: []StrategyParamSpec{
+			{Name: "FastPeriod", Type: ParamTypeInt, Default: 12, Min: paramBound(1), Description: "Number of klines in the fast EMA"},
+			{Name: "SlowPeriod", Type: ParamTypeInt, Default: 26, Min: paramBound(2), Description: "Number of klines in the slow EMA"},
+			{Name: "SignalPeriod", Type: ParamTypeInt, Default: 9, Min: paramBound(1), Description: "Number of MACD values in the signal line EMA"},
+			{Name: "HistogramConfirmation", Type: ParamTypeBool, Default: false, Description: "Act one kline after a crossover, once the histogram keeps moving away from zero"},
+			{Name: "StoplossThreshold", Type: ParamTypeFloat, Default: 0.0, Min: paramBound(0), Max: paramBound(100), Description: "Loss % that forces a sell (0 disables stoploss)"},
+		},
+		Validate: func(params StrategyParams) error {
+			fast, slow := params.Int("FastPeriod"), params.Int("SlowPeriod")
+			if fast <= 0 || slow <= 0 || params.Int("SignalPeriod") <= 0 {
+				return fmt.Errorf("missing or invalid fields for MACD: FastPeriod, SlowPeriod and SignalPeriod must be > 0")
+			}
+			if fast >= slow {
+				return fmt.Errorf("invalid MACD parameters: FastPeriod (%d) must be less than SlowPeriod (%d)", fast, slow)
+			}
+			return nil
+		},
+		Build: func(params StrategyParams) (TradingStrategy, error) {
+			return NewMACDStrategyWithStoploss(
+				params.Int("FastPeriod"),
+				params.Int("SlowPeriod"),
+				params.Int("SignalPeriod"),
+				params.Bool("HistogramConfirmation"),
+				params.Float("StoplossThreshold"),
+			), nil
+		},
+	})
+}
+
+func NewMACDStrategy(fastPeriod, slowPeriod, signalPeriod int) *MACDStrategy {
+	return &MACDStrategy{
+		FastPeriod:            fastPeriod,
+		SlowPeriod:            slowPeriod,
+		SignalPeriod:          signalPeriod,
+		HistogramConfirmation: false,
+		StoplossThreshold:     0.0,
+	}
+}
+
+func NewMACDStrategyWithStoploss(fastPeriod, slowPeriod, signalPeriod int, histogramConfirmation bool, stoplossThreshold float64) *MACDStrategy {
+	return &MACDStrategy{
+		FastPeriod:            fastPeriod,
+		SlowPeriod:            slowPeriod,
+		SignalPeriod:          signalPeriod,
+		HistogramConfirmation: histogramConfirmation,
+		StoplossThreshold:     stoplossThreshold,
+	}
+}
+
+func (s *MACDStrategy) GetName() string {
+	return "MACD"
+}
+
+func (s *MACDStrategy) GetParams() map[string]interface{} {
+	return map[string]interface{}{
+		"FastPeriod":            s.FastPeriod,
+		"SlowPeriod":            s.SlowPeriod,
+		"SignalPeriod":          s.SignalPeriod,
+		"HistogramConfirmation": s.HistogramConfirmation,
+		"StoplossThreshold":     s.StoplossThreshold,
+	}
+}
+
+// requiredKlines is the minimum history to get the current and previous histogram values, and the one before them to
+// confirm a crossover
+func (s *MACDStrategy) requiredKlines() int {
+	if s.HistogramConfirmation {
+		return s.SlowPeriod + s.SignalPeriod + 1
+	}
+	return s.SlowPeriod + s.SignalPeriod
+}
+
+// Decide buys when the histogram turns positive, the MACD line crossing above its signal line, and sells when it turns
+// negative. With histogram confirmation the crossover must be on the previous kline and the histogram still moving
+// away from zero on this one.
+func (s *MACDStrategy) Decide(klines []vo.Kline, tradingBot *TradingBot) *StrategyAnalysisResult {
+	if len(klines) < s.requiredKlines() {
+		return NewStrategyAnalysisResult(Hold, map[string]interface{}{
+			"macd":       0.0,
+			"macdSignal": 0.0,
+			"histogram":  0.0,
+			"reason":     "insufficient_data",
+		})
+	}
+
+	series := indicator.MACD(indicator.Closes(klines), s.FastPeriod, s.SlowPeriod, s.SignalPeriod)
+	last := len(klines) - 1
+	macd, signal, histogram := series.MACD[last], series.Signal[last], series.Histogram[last]
+	previousHistogram := series.Histogram[last-1]
+	histogramSlope := histogram - previousHistogram
+
+	currentPrice := klines[len(klines)-1].Close()
+	exit := NewPositionExit(tradingBot, currentPrice, s.StoplossThreshold)
+
+	analysisData := exit.AnalysisData(map[string]interface{}{
+		"macd":                  macd,
+		"macdSignal":            signal,
+		"histogram":             histogram,
+		"previousHistogram":     previousHistogram,
+		"histogramSlope":        histogramSlope,
+		"histogramConfirmation": s.HistogramConfirmation,
+	})
+
+	// Check for stoploss first if positioned and stoploss is enabled
+	if result := exit.CheckStoploss("MACD STOPLOSS", analysisData); result != nil {
+		return result
+	}
+
+	crossedAbove := previousHistogram <= 0 && histogram > 0
+	crossedBelow := previousHistogram >= 0 && histogram < 0
+	awaitingConfirmation := false
+	if s.HistogramConfirmation {
+		earlierHistogram := series.Histogram[last-2]
+		awaitingConfirmation = crossedAbove || crossedBelow
+		crossedAbove = earlierHistogram <= 0 && previousHistogram > 0 && histogramSlope > 0
+		crossedBelow = earlierHistogram >= 0 && previousHistogram < 0 && histogramSlope < 0
+	}
+
+	decision := Hold
+	positioned := tradingBot.GetIsPositioned()
+	switch {
+	case crossedAbove && !positioned:
+		decision = Buy
+		analysisData["reason"] = "macd_crossed_above_signal_buy"
+	case crossedBelow && positioned:
+		decision = exit.SellIfProfitable(analysisData, "macd_crossed_below_signal_sell_with_profit", "macd_crossed_below_signal_hold_insufficient_profit")
+	case awaitingConfirmation:
+		analysisData["reason"] = "macd_crossover_wait_for_confirmation"
+	case histogram > 0 && positioned:
+		analysisData["reason"] = "macd_above_signal_positioned_holding"
+	case histogram > 0:
+		analysisData["reason"] = "macd_above_signal_wait_for_crossover"
+	case histogram < 0 && positioned:
+		analysisData["reason"] = "macd_below_signal_wait_for_crossover_holding"
+	case histogram < 0:
+		analysisData["reason"] = "macd_below_signal_wait_for_crossover"
+	default:
+		analysisData["reason"] = "macd_equals_signal_neutral"
+	}
+
+	return NewStrategyAnalysisResult(decision, analysisData)
+}
@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"testing"
+)
+
+// macdTestPrices falls for 30 klines and then rallies, so the MACD line ends above its signal
+func macdTestPrices(rally []float64) []float64 {
+	prices := make([]float64, 0, 30+len(rally))
+	for i := 0; i < 30; i++ {
+		prices = append(prices, 130-float64(i))
+	}
+	return append(prices, rally...)
+}
+
+func TestMACDStrategy_InsufficientData(t *testing.T) {
+	strategy := NewMACDStrategy(12, 26, 9)
+	bot := createTestTradingBot(false, 0.0, 1.0)
+
+	result := strategy.Decide(createTestKlinesWithPrices(macdTestPrices(nil)), bot)
+
+	if result.Decision != Hold {
+		t.Errorf("expected Hold for insufficient data, got %s", result.Decision)
+	}
+	if result.AnalysisData["reason"] != "insufficient_data" {
+		t.Errorf("expected insufficient_data reason, got %v", result.AnalysisData["reason"])
+	}
+}
+
+func TestMACDStrategy_BuysOnlyOnTheCrossoverAboveSignal(t *testing.T) {
+	strategy := NewMACDStrategy(3, 6, 3)
+
+	// The first rally kline turns the histogram positive
+	result := strategy.Decide(createTestKlinesWithPrices(macdTestPrices([]float64{105})), createTestTradingBot(false, 0.0, 1.0))
+	if result.Decision != Buy {
+		t.Fatalf("expected Buy on the crossover, got %s (reason: %v)", result.Decision, result.AnalysisData["reason"])
+	}
+	if result.AnalysisData["previousHistogram"].(float64) > 0 || result.AnalysisData["histogram"].(float64) <= 0 {
+		t.Errorf("expected the histogram to turn positive, got %v -> %v", result.AnalysisData["previousHistogram"], result.AnalysisData["histogram"])
+	}
+
+	// Two klines later the MACD line is still above its signal line, but it crossed it before
+	result = strategy.Decide(createTestKlinesWithPrices(macdTestPrices([]float64{105, 110, 115})), createTestTradingBot(false, 0.0, 1.0))
+	if result.Decision != Hold {
+		t.Fatalf("expected Hold after the crossover, got %s", result.Decision)
+	}
+	if result.AnalysisData["reason"] != "macd_above_signal_wait_for_crossover" {
+		t.Errorf("expected wait for crossover reason, got %v", result.AnalysisData["reason"])
+	}
+}
+
+func TestMACDStrategy_HistogramConfirmation(t *testing.T) {
+	confirmed := NewMACDStrategyWithStoploss(3, 6, 3, true, 0.0)
+
+	// The crossover kline waits for the next one
+	result := confirmed.Decide(createTestKlinesWithPrices(macdTestPrices([]float64{105})), createTestTradingBot(false, 0.0, 1.0))
+	if result.Decision != Hold || result.AnalysisData["reason"] != "macd_crossover_wait_for_confirmation" {
+		t.Fatalf("expected Hold waiting for confirmation, got %s (reason: %v)", result.Decision, result.AnalysisData["reason"])
+	}
+
+	// The histogram keeps rising after the crossover
+	result = confirmed.Decide(createTestKlinesWithPrices(macdTestPrices([]float64{105, 110})), createTestTradingBot(false, 0.0, 1.0))
+	if result.Decision != Buy {
+		t.Fatalf("expected Buy once confirmed, got %s (reason: %v)", result.Decision, result.AnalysisData["reason"])
+	}
+
+	// The histogram shrinks after the crossover, the signal is dropped
+	result = confirmed.Decide(createTestKlinesWithPrices(macdTestPrices([]float64{105, 104})), createTestTradingBot(false, 0.0, 1.0))
+	if result.Decision != Hold {
+		t.Fatalf("expected Hold without confirmation, got %s", result.Decision)
+	}
+	if result.AnalysisData["histogramSlope"].(float64) >= 0 {
+		t.Errorf("expected falling histogram for this series, got slope %v", result.AnalysisData["histogramSlope"])
+	}
+}
+
+func TestMACDStrategy_SellsOnlyOnTheCrossoverBelowSignal(t *testing.T) {
+	// Rally then drop: MACD crosses below signal on the last kline
+	prices := macdTestPrices([]float64{110, 120, 130, 140, 125})
+	strategy := NewMACDStrategy(3, 6, 3)
+
+	result := strategy.Decide(createTestKlinesWithPrices(prices), createTestTradingBot(true, 100.0, 1.0))
+	if result.Decision != Sell {
+		t.Fatalf("expected Sell with profit, got %s (reason: %v)", result.Decision, result.AnalysisData["reason"])
+	}
+	if result.AnalysisData["reason"] != "macd_crossed_below_signal_sell_with_profit" {
+		t.Errorf("expected sell with profit reason, got %v", result.AnalysisData["reason"])
+	}
+
+	result = strategy.Decide(createTestKlinesWithPrices(prices), createTestTradingBot(true, 125.0, 1.0))
+	if result.Decision != Hold {
+		t.Fatalf("expected Hold with insufficient profit, got %s", result.Decision)
+	}
+	if result.AnalysisData["reason"] != "macd_crossed_below_signal_hold_insufficient_profit" {
+		t.Errorf("expected insufficient profit reason, got %v", result.AnalysisData["reason"])
+	}
+
+	// One kline later the MACD line is still below its signal line, but it crossed it before
+	result = strategy.Decide(createTestKlinesWithPrices(append(prices, 115)), createTestTradingBot(true, 100.0, 1.0))
+	if result.Decision != Hold {
+		t.Fatalf("expected Hold after the crossover, got %s (reason: %v)", result.Decision, result.AnalysisData["reason"])
+	}
+}
+
+func TestMACDStrategy_Stoploss(t *testing.T) {
+	strategy := NewMACDStrategyWithStoploss(3, 6, 3, false, 5.0)
+	bot := createTestTradingBot(true, 150.0, 1.0)
+
+	result := strategy.Decide(createTestKlinesWithPrices(macdTestPrices([]float64{105, 110, 115})), bot)
+
+	if result.Decision != Sell {
+		t.Fatalf("expected Sell on stoploss, got %s", result.Decision)
+	}
+	if result.AnalysisData["reason"] != "stoploss_triggered" {
+		t.Errorf("expected stoploss_triggered reason, got %v", result.AnalysisData["reason"])
+	}
+}
+
+func TestMACDStrategy_Registry(t *testing.T) {
+	strategy, err := NewStrategyFromParams("MACD", map[string]interface{}{"HistogramConfirmation": true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	macd := strategy.(*MACDStrategy)
+	if macd.FastPeriod != 12 || macd.SlowPeriod != 26 || macd.SignalPeriod != 9 || !macd.HistogramConfirmation {
+		t.Errorf("expected 12/26/9 with confirmation, got %+v", macd)
+	}
+
+	_, err = NewStrategyFromParams("MACD", map[string]interface{}{"FastPeriod": 30.0})
+	if err == nil || err.Error() != "invalid MACD parameters: FastPeriod (30) must be less than SlowPeriod (26)" {
+		t.Errorf("expected FastPeriod error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"crypgo-machine/src/domain/indicator"
 	"crypgo-machine/src/domain/vo"
 	"fmt"
 )
@@ -10,6 +11,43 @@ type MovingAverageStrategy struct {
 	SlowWindow        int
 	MinimumSpread     vo.MinimumSpread
 	StoplossThreshold float64
+	// Optional higher timeframe trend filter: buys only while the close of the TrendIntervalSeconds
+	// klines is above their TrendWindow moving average (0 disables the filter)
+	TrendIntervalSeconds int
+	TrendWindow          int
+}
+
+func init() {
+	RegisterStrategy(StrategyDefinition{
+		Name:        "MovingAverage",
+		Description: "Buys when the fast moving average is below the slow one and sells on the reverse crossover",
+		Params: []StrategyParamSpec{
+			{Name: "FastWindow", Type: ParamTypeInt, Default: 7, Min: paramBound(1), Description: "Number of klines in the fast moving average"},
+			{Name: "SlowWindow", Type: ParamTypeInt, Default: 40, Min: paramBound(2), Description: "Number of klines in the slow moving average"},
+			{Name: "MinimumSpread", Type: ParamTypeFloat, Default: 0.1, Min: paramBound(0), Max: paramBound(100), Description: "Minimum % distance between the averages to avoid whipsaw entries"},
+			{Name: "StoplossThreshold", Type: ParamTypeFloat, Default: 0.0, Min: paramBound(0), Max: paramBound(100), Description: "Loss % that forces a sell (0 disables stoploss)"},
+			{Name: "TrendIntervalSeconds", Type: ParamTypeInt, Default: 0, Min: paramBound(0), Description: "Higher timeframe in seconds whose trend must be up to buy (0 disables the trend filter)"},
+			{Name: "TrendWindow", Type: ParamTypeInt, Default: 50, Min: paramBound(1), Description: "Number of higher timeframe klines in the trend moving average"},
+		},
+		Validate: func(params StrategyParams) error {
+			fast, slow := params.Int("FastWindow"), params.Int("SlowWindow")
+			if fast <= 0 || slow <= 0 {
+				return fmt.Errorf("missing or invalid fields for MovingAverage: FastWindow and SlowWindow must be > 0")
+			}
+			if fast >= slow {
+				return fmt.Errorf("invalid MovingAverage parameters: FastWindow (%d) must be less than SlowWindow (%d)", fast, slow)
+			}
+			return nil
+		},
+		Build: func(params StrategyParams) (TradingStrategy, error) {
+			minimumSpread, err := vo.NewMinimumSpread(params.Float("MinimumSpread"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create MinimumSpread: %w", err)
+			}
+			strategy := NewMovingAverageStrategyWithStoploss(params.Int("FastWindow"), params.Int("SlowWindow"), minimumSpread, params.Float("StoplossThreshold"))
+			return strategy.WithTrendFilter(params.Int("TrendIntervalSeconds"), params.Int("TrendWindow")), nil
+		},
+	})
 }
 
 func NewMovingAverageStrategy(fast, slow int) *MovingAverageStrategy {
@@ -42,20 +80,41 @@ func NewMovingAverageStrategyWithStoploss(fast, slow int, minimumSpread vo.Minim
 	}
 }
 
+// WithTrendFilter enables the higher timeframe trend filter (trendIntervalSeconds 0 disables it)
+func (s *MovingAverageStrategy) WithTrendFilter(trendIntervalSeconds, trendWindow int) *MovingAverageStrategy {
+	s.TrendIntervalSeconds = trendIntervalSeconds
+	s.TrendWindow = trendWindow
+	return s
+}
+
 func (s *MovingAverageStrategy) GetName() string {
 	return "MovingAverage"
 }
 
 func (s *MovingAverageStrategy) GetParams() map[string]interface{} {
 	return map[string]interface{}{
-		"FastWindow":        s.FastWindow,
-		"SlowWindow":        s.SlowWindow,
-		"MinimumSpread":     s.MinimumSpread.GetValue(),
-		"StoplossThreshold": s.StoplossThreshold,
+		"FastWindow":           s.FastWindow,
+		"SlowWindow":           s.SlowWindow,
+		"MinimumSpread":        s.MinimumSpread.GetValue(),
+		"StoplossThreshold":    s.StoplossThreshold,
+		"TrendIntervalSeconds": s.TrendIntervalSeconds,
+		"TrendWindow":          s.TrendWindow,
+	}
+}
+
+// GetTimeframes returns the trend filter interval when the filter is enabled
+func (s *MovingAverageStrategy) GetTimeframes() []int {
+	if s.TrendIntervalSeconds <= 0 {
+		return nil
 	}
+	return []int{s.TrendIntervalSeconds}
 }
 
 func (s *MovingAverageStrategy) Decide(klines []vo.Kline, tradingBot *TradingBot) *StrategyAnalysisResult {
+	return s.DecideWithTimeframes(klines, nil, tradingBot)
+}
+
+func (s *MovingAverageStrategy) DecideWithTimeframes(klines []vo.Kline, timeframes Timeframes, tradingBot *TradingBot) *StrategyAnalysisResult {
 	if len(klines) < s.SlowWindow {
 		return NewStrategyAnalysisResult(Hold, map[string]interface{}{
 			"fast":   0.0,
@@ -64,52 +123,40 @@ func (s *MovingAverageStrategy) Decide(klines []vo.Kline, tradingBot *TradingBot
 		})
 	}
 
-	fast := s.movingAverage(klines, s.FastWindow)
-	slow := s.movingAverage(klines, s.SlowWindow)
+	closes := indicator.Closes(klines)
+	fast := indicator.Last(indicator.SMA(closes, s.FastWindow))
+	slow := indicator.Last(indicator.SMA(closes, s.SlowWindow))
 	currentPrice := klines[len(klines)-1].Close()
 
 	hasSufficientSpread := s.MinimumSpread.HasSufficientSpread(fast, slow)
 
-	entryPrice := tradingBot.GetEntryPrice()
-	possibleProfit := s.calculatePossibleProfit(entryPrice, currentPrice)
-
-	analysisData := map[string]interface{}{
-		"fast":                   fast,
-		"slow":                   slow,
-		"currentPrice":           currentPrice,
-		"isPositioned":           tradingBot.GetIsPositioned(),
-		"hasSufficientSpread":    hasSufficientSpread,
-		"minimumSpread":          s.MinimumSpread.GetValue(),
-		"actualSpread":           s.calculateSpreadPercentage(fast, slow),
-		"entryPrice":             entryPrice,
-		"possibleProfit":         possibleProfit,
-		"minimumProfitThreshold": tradingBot.GetMinimumProfitThreshold(),
-		"stoplossThreshold":      s.StoplossThreshold,
-	}
+	exit := NewPositionExit(tradingBot, currentPrice, s.StoplossThreshold)
 
-	var decision TradingDecision
+	analysisData := exit.AnalysisData(map[string]interface{}{
+		"fast":                fast,
+		"slow":                slow,
+		"hasSufficientSpread": hasSufficientSpread,
+		"minimumSpread":       s.MinimumSpread.GetValue(),
+		"actualSpread":        s.calculateSpreadPercentage(fast, slow),
+	})
 
 	// Check for stoploss first if positioned and stoploss is enabled
-	if tradingBot.GetIsPositioned() && s.StoplossThreshold > 0 && possibleProfit <= -s.StoplossThreshold {
-		fmt.Printf("🚨 STOPLOSS TRIGGERED! Price: %.2f | Entry: %.2f | Loss: %.2f%% | Threshold: %.2f%%\n", 
-			currentPrice, entryPrice, possibleProfit, s.StoplossThreshold)
-		decision = Sell
-		analysisData["reason"] = "stoploss_triggered"
-		return NewStrategyAnalysisResult(decision, analysisData)
+	if result := exit.CheckStoploss("STOPLOSS", analysisData); result != nil {
+		return result
 	}
 
-	if fast < slow && !tradingBot.GetIsPositioned() && hasSufficientSpread {
+	trendAllowsBuy, trendReason := s.checkTrend(timeframes, analysisData)
+
+	var decision TradingDecision
+
+	if fast < slow && !tradingBot.GetIsPositioned() && hasSufficientSpread && trendAllowsBuy {
 		decision = Buy
 		analysisData["reason"] = "fast_below_slow_buy_low"
+	} else if fast < slow && !tradingBot.GetIsPositioned() && hasSufficientSpread {
+		decision = Hold
+		analysisData["reason"] = trendReason
 	} else if fast > slow && tradingBot.GetIsPositioned() {
-
-		if possibleProfit >= tradingBot.GetMinimumProfitThreshold() {
-			decision = Sell
-			analysisData["reason"] = "fast_above_slow_sell_high_with_profit"
-		} else {
-			decision = Hold
-			analysisData["reason"] = "fast_above_slow_hold_insufficient_profit"
-		}
+		decision = exit.SellIfProfitable(analysisData, "fast_above_slow_sell_high_with_profit", "fast_above_slow_hold_insufficient_profit")
 	} else {
 		decision = Hold
 		if fast < slow && !tradingBot.GetIsPositioned() && !hasSufficientSpread {
@@ -128,12 +175,27 @@ func (s *MovingAverageStrategy) Decide(klines []vo.Kline, tradingBot *TradingBot
 	return NewStrategyAnalysisResult(decision, analysisData)
 }
 
-func (s *MovingAverageStrategy) movingAverage(klines []vo.Kline, window int) float64 {
-	sum := 0.0
-	for i := len(klines) - window; i < len(klines); i++ {
-		sum += klines[i].Close()
+// checkTrend reports whether the higher timeframe trend allows a buy, adding the trend values to analysisData
+func (s *MovingAverageStrategy) checkTrend(timeframes Timeframes, analysisData map[string]interface{}) (bool, string) {
+	if s.TrendIntervalSeconds <= 0 {
+		return true, ""
 	}
-	return sum / float64(window)
+
+	analysisData["trendIntervalSeconds"] = s.TrendIntervalSeconds
+	trendKlines := timeframes[s.TrendIntervalSeconds]
+	if len(trendKlines) < s.TrendWindow {
+		return false, "fast_below_slow_trend_unavailable_wait"
+	}
+
+	trendClose := trendKlines[len(trendKlines)-1].Close()
+	trendMA := indicator.Last(indicator.SMA(indicator.Closes(trendKlines), s.TrendWindow))
+	analysisData["trendClose"] = trendClose
+	analysisData["trendMA"] = trendMA
+
+	if trendClose <= trendMA {
+		return false, "fast_below_slow_higher_timeframe_downtrend_wait"
+	}
+	return true, ""
 }
 
 func (s *MovingAverageStrategy) calculateSpreadPercentage(fast, slow float64) float64 {
@@ -148,11 +210,3 @@ func (s *MovingAverageStrategy) calculateSpreadPercentage(fast, slow float64) fl
 
 	return percentageDiff
 }
-
-func (s *MovingAverageStrategy) calculatePossibleProfit(entryPrice, currentPrice float64) float64 {
-	if entryPrice == 0 {
-		return 0.0
-	}
-
-	return ((currentPrice - entryPrice) / entryPrice) * 100
-}
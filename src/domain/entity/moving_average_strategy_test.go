@@ -274,3 +274,31 @@ func TestMovingAverageStrategy_Stoploss_Priority(t *testing.T) {
 		t.Errorf("Expected stoploss_triggered reason (priority), got: %s", result.AnalysisData["reason"])
 	}
 }
+
+func TestMovingAverageStrategy_TrendFilter(t *testing.T) {
+	klines := []vo.Kline{
+		mustKline(10), mustKline(9), mustKline(8), mustKline(8), mustKline(8),
+	}
+	strategy := NewMovingAverageStrategy(3, 5).WithTrendFilter(14400, 3)
+
+	if timeframes := RequiredTimeframes(strategy); len(timeframes) != 1 || timeframes[0] != 14400 {
+		t.Fatalf("expected the 4h timeframe to be required, got %v", timeframes)
+	}
+
+	uptrend := Timeframes{14400: {mustKline(20), mustKline(21), mustKline(25)}}
+	if result := DecideStrategy(strategy, klines, uptrend, createTestBot()); result.Decision != Buy {
+		t.Errorf("expected Buy with the higher timeframe in an uptrend, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+
+	downtrend := Timeframes{14400: {mustKline(25), mustKline(21), mustKline(20)}}
+	result := DecideStrategy(strategy, klines, downtrend, createTestBot())
+	if result.Decision != Hold || result.AnalysisData["reason"] != "fast_below_slow_higher_timeframe_downtrend_wait" {
+		t.Errorf("expected Hold on a higher timeframe downtrend, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+
+	// Without the higher timeframe klines the filter never allows an entry
+	result = strategy.Decide(klines, createTestBot())
+	if result.Decision != Hold || result.AnalysisData["reason"] != "fast_below_slow_trend_unavailable_wait" {
+		t.Errorf("expected Hold without trend data, got %s (%v)", result.Decision, result.AnalysisData["reason"])
+	}
+}
@@ -0,0 +1,179 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"time"
+)
+
+type OrderSide string
+
+const (
+	OrderSideBuy  OrderSide = "BUY"
+	OrderSideSell OrderSide = "SELL"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPending  OrderStatus = "PENDING"  // Not sent to the exchange yet
+	OrderStatusFilled   OrderStatus = "FILLED"   // Executed by the exchange
+	OrderStatusRejected OrderStatus = "REJECTED" // Refused before reaching the exchange, e.g. by order validation
+	OrderStatusFailed   OrderStatus = "FAILED"   // The exchange returned an error
+	OrderStatusUnknown  OrderStatus = "UNKNOWN"  // Sent without knowing whether the exchange placed it, looked up before the bot's next order
+)
+
+// Order is one order placement attempt of a bot: what was requested, what the exchange filled and why it failed
+type Order struct {
+	Id                *vo.EntityId
+	tradingBotId      *vo.EntityId
+	decisionLogId     string // Decision that caused the order, empty if unknown
+	clientOrderId     string // Client order ID sent to the exchange, retries of the order reuse it
+	maxAttempts       int    // Exchange orders the order may be sent as, e.g. repriced limit orders, 0 if unknown
+	symbol            string
+	side              OrderSide
+	orderType         string
+	requestedQuantity float64
+	requestedPrice    float64 // Market price when the order was requested
+	status            OrderStatus
+	fill              OrderFill
+	errorMessage      string
+	createdAt         time.Time
+}
+
+func NewOrder(tradingBotId *vo.EntityId, decisionLogId string, symbol string, side OrderSide, orderType string, requestedQuantity float64, requestedPrice float64) *Order {
+	return &Order{
+		Id:                vo.NewEntityId(),
+		tradingBotId:      tradingBotId,
+		decisionLogId:     decisionLogId,
+		symbol:            symbol,
+		side:              side,
+		orderType:         orderType,
+		requestedQuantity: requestedQuantity,
+		requestedPrice:    requestedPrice,
+		status:            OrderStatusPending,
+		createdAt:         time.Now(),
+	}
+}
+
+func RestoreOrder(id *vo.EntityId, tradingBotId *vo.EntityId, decisionLogId string, clientOrderId string, maxAttempts int, symbol string, side OrderSide, orderType string, requestedQuantity float64, requestedPrice float64, status OrderStatus, fill OrderFill, errorMessage string, createdAt time.Time) *Order {
+	return &Order{
+		Id:                id,
+		tradingBotId:      tradingBotId,
+		decisionLogId:     decisionLogId,
+		clientOrderId:     clientOrderId,
+		maxAttempts:       maxAttempts,
+		symbol:            symbol,
+		side:              side,
+		orderType:         orderType,
+		requestedQuantity: requestedQuantity,
+		requestedPrice:    requestedPrice,
+		status:            status,
+		fill:              fill,
+		errorMessage:      errorMessage,
+		createdAt:         createdAt,
+	}
+}
+
+// SetClientOrderId records the client order ID the order is sent to the exchange with
+func (o *Order) SetClientOrderId(clientOrderId string) {
+	o.clientOrderId = clientOrderId
+}
+
+// SetMaxAttempts records how many exchange orders the order may be sent as, so it can be looked up by all their
+// client order IDs even after the bot's order execution changed
+func (o *Order) SetMaxAttempts(maxAttempts int) {
+	o.maxAttempts = maxAttempts
+}
+
+// MarkFilled records what the exchange executed, keeping the exchange status (e.g. PARTIALLY_FILLED) when it has one
+func (o *Order) MarkFilled(fill OrderFill) {
+	o.fill = fill
+	o.status = OrderStatusFilled
+	if fill.Status != "" {
+		o.status = OrderStatus(fill.Status)
+	}
+}
+
+// MarkRejected records an order refused before reaching the exchange
+func (o *Order) MarkRejected(reason string) {
+	o.status = OrderStatusRejected
+	o.errorMessage = reason
+}
+
+// MarkFailed records an order the exchange returned an error for
+func (o *Order) MarkFailed(err error) {
+	o.status = OrderStatusFailed
+	o.errorMessage = err.Error()
+}
+
+// MarkUnknown records an order the exchange could neither confirm nor deny having placed, it may still be live
+func (o *Order) MarkUnknown(err error) {
+	o.status = OrderStatusUnknown
+	o.errorMessage = err.Error()
+}
+
+// IsUnknown reports whether the order was sent without knowing whether the exchange placed it
+func (o *Order) IsUnknown() bool {
+	return o.status == OrderStatusUnknown
+}
+
+// IsExecuted reports whether the exchange executed any quantity of the order
+func (o *Order) IsExecuted() bool {
+	return o.fill.ExecutedQuantity > 0
+}
+
+func (o *Order) GetId() *vo.EntityId {
+	return o.Id
+}
+
+func (o *Order) GetTradingBotId() *vo.EntityId {
+	return o.tradingBotId
+}
+
+func (o *Order) GetDecisionLogId() string {
+	return o.decisionLogId
+}
+
+func (o *Order) GetClientOrderId() string {
+	return o.clientOrderId
+}
+
+func (o *Order) GetMaxAttempts() int {
+	return o.maxAttempts
+}
+
+func (o *Order) GetSymbol() string {
+	return o.symbol
+}
+
+func (o *Order) GetSide() OrderSide {
+	return o.side
+}
+
+func (o *Order) GetOrderType() string {
+	return o.orderType
+}
+
+func (o *Order) GetRequestedQuantity() float64 {
+	return o.requestedQuantity
+}
+
+func (o *Order) GetRequestedPrice() float64 {
+	return o.requestedPrice
+}
+
+func (o *Order) GetStatus() OrderStatus {
+	return o.status
+}
+
+func (o *Order) GetFill() OrderFill {
+	return o.fill
+}
+
+func (o *Order) GetErrorMessage() string {
+	return o.errorMessage
+}
+
+func (o *Order) GetCreatedAt() time.Time {
+	return o.createdAt
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"errors"
+	"testing"
+)
+
+func TestOrder_StartsPendingAndRecordsTheFill(t *testing.T) {
+	botId := vo.NewEntityId()
+	order := NewOrder(botId, "decision-1", "BTCUSDT", OrderSideBuy, "MARKET", 0.5, 100.0)
+
+	if order.GetStatus() != OrderStatusPending {
+		t.Fatalf("expected a new order to be pending, got %s", order.GetStatus())
+	}
+	if order.GetTradingBotId() != botId || order.GetDecisionLogId() != "decision-1" || order.GetSymbol() != "BTCUSDT" {
+		t.Errorf("expected the order to keep its bot, decision and symbol, got %+v", order)
+	}
+	if order.IsExecuted() {
+		t.Error("expected a pending order not to be executed")
+	}
+
+	order.SetClientOrderId("client-1")
+	order.MarkFilled(OrderFill{ExchangeOrderID: 42, ExecutedQuantity: 0.5, AveragePrice: 101.0})
+
+	if order.GetStatus() != OrderStatusFilled {
+		t.Errorf("expected FILLED without an exchange status, got %s", order.GetStatus())
+	}
+	if order.GetClientOrderId() != "client-1" || order.GetFill().ExchangeOrderID != 42 {
+		t.Errorf("expected the client order ID and fill to be recorded, got %q and %+v", order.GetClientOrderId(), order.GetFill())
+	}
+	if !order.IsExecuted() {
+		t.Error("expected a filled order to be executed")
+	}
+}
+
+func TestOrder_KeepsTheExchangeStatusOfTheFill(t *testing.T) {
+	order := NewOrder(vo.NewEntityId(), "", "BTCUSDT", OrderSideSell, "LIMIT", 1.0, 100.0)
+	order.MarkFilled(OrderFill{Status: "PARTIALLY_FILLED", ExecutedQuantity: 0.4})
+
+	if order.GetStatus() != OrderStatus("PARTIALLY_FILLED") {
+		t.Errorf("expected PARTIALLY_FILLED, got %s", order.GetStatus())
+	}
+}
+
+func TestOrder_RecordsWhyItDidNotExecute(t *testing.T) {
+	rejected := NewOrder(vo.NewEntityId(), "", "BTCUSDT", OrderSideBuy, "MARKET", 0.001, 100.0)
+	rejected.MarkRejected("below min notional")
+	if rejected.GetStatus() != OrderStatusRejected || rejected.GetErrorMessage() != "below min notional" {
+		t.Errorf("expected a rejected order with its reason, got %s %q", rejected.GetStatus(), rejected.GetErrorMessage())
+	}
+
+	failed := NewOrder(vo.NewEntityId(), "", "BTCUSDT", OrderSideBuy, "MARKET", 1.0, 100.0)
+	failed.MarkFailed(errors.New("insufficient balance"))
+	if failed.GetStatus() != OrderStatusFailed || failed.GetErrorMessage() != "insufficient balance" {
+		t.Errorf("expected a failed order with the exchange error, got %s %q", failed.GetStatus(), failed.GetErrorMessage())
+	}
+	if failed.IsUnknown() || failed.IsExecuted() {
+		t.Error("expected a failed order to be neither unknown nor executed")
+	}
+
+	unknown := NewOrder(vo.NewEntityId(), "", "BTCUSDT", OrderSideSell, "MARKET", 1.0, 100.0)
+	unknown.MarkUnknown(errors.New("timeout"))
+	if !unknown.IsUnknown() || unknown.GetErrorMessage() != "timeout" {
+		t.Errorf("expected an unknown order with the timeout, got %s %q", unknown.GetStatus(), unknown.GetErrorMessage())
+	}
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Execution modes a bot can use to place its orders
+const (
+	ExecutionModeMarket   = "market"    // Market orders, paying the spread and slippage
+	ExecutionModeLimit    = "limit"     // Limit orders at the best bid (buys) or ask (sells) with a time in force
+	ExecutionModePostOnly = "post_only" // Maker-only limit orders, refused by the exchange if they would take liquidity
+)
+
+// Time in force of limit orders
+const (
+	TimeInForceGTC = "GTC" // Good till canceled, rests on the book until repriced
+	TimeInForceIOC = "IOC" // Immediate or cancel, the unfilled part expires at once
+	TimeInForceFOK = "FOK" // Fill or kill, fills completely or expires at once
+)
+
+const (
+	DefaultRepriceAfterSeconds = 10
+	DefaultMaxOrderAttempts    = 3
+)
+
+// OrderExecution configures how a bot places its orders. Limit and post-only orders still unfilled after
+// RepriceAfterSeconds are canceled and placed again at the new best price, and whatever is left after
+// MaxAttempts limit orders is sent as a market order. The zero value places market orders.
+type OrderExecution struct {
+	Mode                string
+	TimeInForce         string // Limit mode only, post-only orders always rest on the book
+	RepriceAfterSeconds int
+	MaxAttempts         int
+}
+
+// NewOrderExecution validates the execution parameters, using the defaults for empty or zero values
+func NewOrderExecution(mode string, timeInForce string, repriceAfterSeconds int, maxAttempts int) (OrderExecution, error) {
+	mode = strings.ToLower(mode)
+	timeInForce = strings.ToUpper(timeInForce)
+
+	if repriceAfterSeconds < 0 {
+		return OrderExecution{}, fmt.Errorf("invalid reprice interval: must be greater than or equal to zero")
+	}
+	if maxAttempts < 0 {
+		return OrderExecution{}, fmt.Errorf("invalid max order attempts: must be greater than or equal to zero")
+	}
+
+	switch mode {
+	case "", ExecutionModeMarket:
+		return OrderExecution{Mode: ExecutionModeMarket}, nil
+	case ExecutionModeLimit:
+		switch timeInForce {
+		case "":
+			timeInForce = TimeInForceGTC
+		case TimeInForceGTC, TimeInForceIOC, TimeInForceFOK:
+		default:
+			return OrderExecution{}, fmt.Errorf("invalid time in force: %s (use GTC, IOC or FOK)", timeInForce)
+		}
+	case ExecutionModePostOnly:
+		if timeInForce != "" && timeInForce != TimeInForceGTC {
+			return OrderExecution{}, fmt.Errorf("invalid time in force for post-only orders: %s (they always rest on the book)", timeInForce)
+		}
+		timeInForce = ""
+	default:
+		return OrderExecution{}, fmt.Errorf("invalid order execution mode: %s", mode)
+	}
+
+	if repriceAfterSeconds == 0 {
+		repriceAfterSeconds = DefaultRepriceAfterSeconds
+	}
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxOrderAttempts
+	}
+	return OrderExecution{
+		Mode:                mode,
+		TimeInForce:         timeInForce,
+		RepriceAfterSeconds: repriceAfterSeconds,
+		MaxAttempts:         maxAttempts,
+	}, nil
+}
+
+// IsMarket reports whether orders are placed at market, which is also the case of the zero value
+func (e OrderExecution) IsMarket() bool {
+	return e.Mode == "" || e.Mode == ExecutionModeMarket
+}
+
+// IsPostOnly reports whether orders must only add liquidity to the book
+func (e OrderExecution) IsPostOnly() bool {
+	return e.Mode == ExecutionModePostOnly
+}
+
+// RestsOnBook reports whether limit orders wait on the book to be filled, so they need to be repriced
+func (e OrderExecution) RestsOnBook() bool {
+	return e.IsPostOnly() || (e.Mode == ExecutionModeLimit && (e.TimeInForce == "" || e.TimeInForce == TimeInForceGTC))
+}
+
+// GetMode returns the execution mode, market for the zero value
+func (e OrderExecution) GetMode() string {
+	if e.IsMarket() {
+		return ExecutionModeMarket
+	}
+	return e.Mode
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "testing"
+
+func TestNewOrderExecution_Validation(t *testing.T) {
+	market, err := NewOrderExecution("", "", 0, 0)
+	if err != nil || !market.IsMarket() || market.RestsOnBook() {
+		t.Fatalf("expected an empty mode to place market orders, got %+v (err: %v)", market, err)
+	}
+
+	limit, err := NewOrderExecution("LIMIT", "", 0, 0)
+	if err != nil {
+		t.Fatalf("expected valid limit execution, got %v", err)
+	}
+	if limit.TimeInForce != TimeInForceGTC || limit.RepriceAfterSeconds != DefaultRepriceAfterSeconds || limit.MaxAttempts != DefaultMaxOrderAttempts {
+		t.Errorf("expected GTC and default reprice settings, got %+v", limit)
+	}
+	if !limit.RestsOnBook() {
+		t.Error("expected GTC limit orders to rest on the book")
+	}
+
+	if ioc, _ := NewOrderExecution("limit", "ioc", 5, 2); ioc.TimeInForce != TimeInForceIOC || ioc.RestsOnBook() {
+		t.Errorf("expected IOC limit orders not to rest on the book, got %+v", ioc)
+	}
+
+	postOnly, err := NewOrderExecution("post_only", "", 30, 5)
+	if err != nil || !postOnly.IsPostOnly() || !postOnly.RestsOnBook() || postOnly.TimeInForce != "" {
+		t.Errorf("expected post-only orders resting on the book without time in force, got %+v (err: %v)", postOnly, err)
+	}
+
+	if _, err := NewOrderExecution("post_only", "IOC", 0, 0); err == nil {
+		t.Error("expected error for post-only orders with IOC")
+	}
+	if _, err := NewOrderExecution("limit", "DAY", 0, 0); err == nil {
+		t.Error("expected error for unknown time in force")
+	}
+	if _, err := NewOrderExecution("twap", "", 0, 0); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+	if _, err := NewOrderExecution("limit", "GTC", -1, 0); err == nil {
+		t.Error("expected error for negative reprice interval")
+	}
+	if _, err := NewOrderExecution("limit", "GTC", 0, -1); err == nil {
+		t.Error("expected error for negative max attempts")
+	}
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// OrderFill is what the exchange actually executed for an order, parsed from its fills
+type OrderFill struct {
+	ExchangeOrderID  int64     `json:"exchange_order_id"`
+	Side             string    `json:"side"` // BUY or SELL
+	Status           string    `json:"status"`
+	ExecutedQuantity float64   `json:"executed_quantity"` // Base asset quantity executed
+	AveragePrice     float64   `json:"average_price"`     // Volume-weighted average price of the fills
+	QuoteQuantity    float64   `json:"quote_quantity"`    // Quote currency spent (buy) or received (sell)
+	Commission       float64   `json:"commission"`        // Commission charged, in CommissionAsset (the first one if fills mixed assets)
+	CommissionAsset  string    `json:"commission_asset"`
+	FeesInQuote      float64   `json:"fees_in_quote"` // Commission valued in the quote currency, whatever asset it was paid in
+	NetQuantity      float64   `json:"net_quantity"`  // Executed quantity minus commission charged in the base asset
+	Estimated        bool      `json:"estimated"`     // True when the exchange returned no fills and the values were estimated
+	Fills            []Fill    `json:"fills"`         // Individual executions on the exchange
+	ExecutedAt       time.Time `json:"executed_at"`
+}
+
+// Fill is a single execution of an order on the exchange
+type Fill struct {
+	TradeID         int64   `json:"trade_id"`
+	Price           float64 `json:"price"`
+	Quantity        float64 `json:"quantity"`
+	Commission      float64 `json:"commission"`
+	CommissionAsset string  `json:"commission_asset"`
+}
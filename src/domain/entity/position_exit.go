@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"fmt"
+)
+
+// PositionExit holds the exit rules every strategy shares: the stoploss threshold
+// configured on the strategy and the minimum profit threshold configured on the bot
+type PositionExit struct {
+	tradingBot        *TradingBot
+	currentPrice      float64
+	entryPrice        float64
+	possibleProfit    float64
+	stoplossThreshold float64
+}
+
+func NewPositionExit(tradingBot *TradingBot, currentPrice float64, stoplossThreshold float64) PositionExit {
+	entryPrice := tradingBot.GetEntryPrice()
+	return PositionExit{
+		tradingBot:        tradingBot,
+		currentPrice:      currentPrice,
+		entryPrice:        entryPrice,
+		possibleProfit:    CalculatePossibleProfit(entryPrice, currentPrice),
+		stoplossThreshold: stoplossThreshold,
+	}
+}
+
+// CalculatePossibleProfit returns the % profit of selling at currentPrice a position opened at entryPrice
+func CalculatePossibleProfit(entryPrice, currentPrice float64) float64 {
+	if entryPrice == 0 {
+		return 0.0
+	}
+
+	return ((currentPrice - entryPrice) / entryPrice) * 100
+}
+
+func (p PositionExit) GetPossibleProfit() float64 {
+	return p.possibleProfit
+}
+
+// AnalysisData returns the position keys every strategy reports, merged with the strategy's own indicators
+func (p PositionExit) AnalysisData(indicators map[string]interface{}) map[string]interface{} {
+	analysisData := map[string]interface{}{
+		"currentPrice":           p.currentPrice,
+		"isPositioned":           p.tradingBot.GetIsPositioned(),
+		"entryPrice":             p.entryPrice,
+		"possibleProfit":         p.possibleProfit,
+		"minimumProfitThreshold": p.tradingBot.GetMinimumProfitThreshold(),
+		"stoplossThreshold":      p.stoplossThreshold,
+	}
+	for key, value := range indicators {
+		analysisData[key] = value
+	}
+	return analysisData
+}
+
+// CheckStoploss returns a Sell result when the bot is positioned and the loss reached the stoploss threshold.
+// It returns nil when the stoploss is disabled or not triggered.
+func (p PositionExit) CheckStoploss(label string, analysisData map[string]interface{}) *StrategyAnalysisResult {
+	if !p.tradingBot.GetIsPositioned() || p.stoplossThreshold <= 0 || p.possibleProfit > -p.stoplossThreshold {
+		return nil
+	}
+
+	fmt.Printf("🚨 %s TRIGGERED! Price: %.2f | Entry: %.2f | Loss: %.2f%% | Threshold: %.2f%%\n",
+		label, p.currentPrice, p.entryPrice, p.possibleProfit, p.stoplossThreshold)
+	analysisData["reason"] = ExitReasonStopLoss
+	return NewStrategyAnalysisResult(Sell, analysisData)
+}
+
+// SellIfProfitable turns a strategy sell signal into a Sell only when the minimum profit threshold is met
+func (p PositionExit) SellIfProfitable(analysisData map[string]interface{}, sellReason, holdReason string) TradingDecision {
+	if p.possibleProfit >= p.tradingBot.GetMinimumProfitThreshold() {
+		analysisData["reason"] = sellReason
+		return Sell
+	}
+	analysisData["reason"] = holdReason
+	return Hold
+}
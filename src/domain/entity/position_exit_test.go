@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"testing"
+)
+
+func TestPositionExit_NotPositioned(t *testing.T) {
+	exit := NewPositionExit(createTestTradingBot(false, 0.0, 1.0), 50.0, 5.0)
+
+	if exit.GetPossibleProfit() != 0.0 {
+		t.Errorf("expected no possible profit without entry price, got %.2f", exit.GetPossibleProfit())
+	}
+	if result := exit.CheckStoploss("STOPLOSS", exit.AnalysisData(nil)); result != nil {
+		t.Errorf("expected stoploss to be ignored when not positioned, got %s", result.Decision)
+	}
+}
+
+func TestPositionExit_Stoploss(t *testing.T) {
+	tests := []struct {
+		name      string
+		price     float64
+		threshold float64
+		triggered bool
+	}{
+		{"loss below threshold", 97.0, 5.0, false},
+		{"loss equals threshold", 95.0, 5.0, true},
+		{"stoploss disabled", 80.0, 0.0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exit := NewPositionExit(createTestTradingBot(true, 100.0, 1.0), tt.price, tt.threshold)
+			result := exit.CheckStoploss("STOPLOSS", exit.AnalysisData(nil))
+			if (result != nil) != tt.triggered {
+				t.Fatalf("expected triggered=%v, got %v", tt.triggered, result != nil)
+			}
+			if result != nil && result.AnalysisData["reason"] != "stoploss_triggered" {
+				t.Errorf("expected stoploss_triggered reason, got %v", result.AnalysisData["reason"])
+			}
+		})
+	}
+}
+
+func TestPositionExit_SellIfProfitable(t *testing.T) {
+	exit := NewPositionExit(createTestTradingBot(true, 100.0, 2.0), 101.0, 0.0)
+	analysisData := exit.AnalysisData(map[string]interface{}{"indicator": 1.0})
+
+	if decision := exit.SellIfProfitable(analysisData, "sell", "hold"); decision != Hold || analysisData["reason"] != "hold" {
+		t.Errorf("expected Hold below minimum profit, got %s (%v)", decision, analysisData["reason"])
+	}
+	if analysisData["indicator"] != 1.0 || analysisData["minimumProfitThreshold"] != 2.0 {
+		t.Errorf("expected indicators merged with position keys, got %v", analysisData)
+	}
+
+	exit = NewPositionExit(createTestTradingBot(true, 100.0, 2.0), 102.0, 0.0)
+	if decision := exit.SellIfProfitable(analysisData, "sell", "hold"); decision != Sell || analysisData["reason"] != "sell" {
+		t.Errorf("expected Sell at minimum profit, got %s (%v)", decision, analysisData["reason"])
+	}
+}
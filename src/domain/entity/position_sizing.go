@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/indicator"
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+)
+
+// Sizing modes a bot can use to calculate the quantity of a buy order
+const (
+	SizingModeFixedQuantity = "fixed_quantity"
+	SizingModeTradeAmount   = "trade_amount"
+	SizingModeATRRisk       = "atr_risk"
+)
+
+const (
+	DefaultSizingATRPeriod     = 14
+	DefaultSizingATRMultiplier = 2.0
+)
+
+// PositionSizing risks RiskPercent of the bot's initial capital per trade. The stop distance is
+// ATRMultiplier times the ATR, so volatile symbols get smaller positions. A zero RiskPercent disables it.
+type PositionSizing struct {
+	RiskPercent   float64
+	ATRPeriod     int
+	ATRMultiplier float64
+}
+
+// NewPositionSizing validates the ATR sizing parameters, using the defaults for a zero period or multiplier
+func NewPositionSizing(riskPercent float64, atrPeriod int, atrMultiplier float64) (PositionSizing, error) {
+	if riskPercent < 0 || riskPercent > 100 {
+		return PositionSizing{}, fmt.Errorf("invalid risk per trade: must be between 0 and 100")
+	}
+	if atrPeriod < 0 {
+		return PositionSizing{}, fmt.Errorf("invalid ATR period: must be greater than or equal to zero")
+	}
+	if atrMultiplier < 0 {
+		return PositionSizing{}, fmt.Errorf("invalid ATR multiplier: must be greater than or equal to zero")
+	}
+	if riskPercent == 0 {
+		return PositionSizing{}, nil
+	}
+
+	if atrPeriod == 0 {
+		atrPeriod = DefaultSizingATRPeriod
+	}
+	if atrMultiplier == 0 {
+		atrMultiplier = DefaultSizingATRMultiplier
+	}
+	return PositionSizing{
+		RiskPercent:   riskPercent,
+		ATRPeriod:     atrPeriod,
+		ATRMultiplier: atrMultiplier,
+	}, nil
+}
+
+func (s PositionSizing) IsEnabled() bool {
+	return s.RiskPercent > 0
+}
+
+// ATR returns the latest ATR of the klines, or 0 while there is not enough history
+func (s PositionSizing) ATR(klines []vo.Kline) float64 {
+	if !s.IsEnabled() || len(klines) < s.ATRPeriod {
+		return 0.0
+	}
+	atr := indicator.Last(indicator.ATR(klines, s.ATRPeriod))
+	if !indicator.IsReady(atr) {
+		return 0.0
+	}
+	return atr
+}
+
+// Quantity returns the quantity that loses the risked share of capital if price falls by the ATR stop distance.
+// The order value is capped at maxAmount (0 = no cap). It returns 0 when the ATR is unknown.
+func (s PositionSizing) Quantity(capital, price, atr, maxAmount float64) float64 {
+	if !s.IsEnabled() || atr <= 0 || price <= 0 {
+		return 0.0
+	}
+
+	riskAmount := capital * s.RiskPercent / 100
+	quantity := riskAmount / (atr * s.ATRMultiplier)
+	if maxAmount > 0 && quantity*price > maxAmount {
+		quantity = maxAmount / price
+	}
+	return quantity
+}
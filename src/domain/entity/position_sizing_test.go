@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"math"
+	"testing"
+)
+
+// createTestKlinesWithRange creates klines closing at price with a high-low range of rangeSize
+func createTestKlinesWithRange(count int, price, rangeSize float64) []vo.Kline {
+	klines := make([]vo.Kline, count)
+	for i := range klines {
+		klines[i], _ = vo.NewKline(price, price, price+rangeSize/2, price-rangeSize/2, 1000.0, int64(i+1)*60000)
+	}
+	return klines
+}
+
+func TestNewPositionSizing_Validation(t *testing.T) {
+	sizing, err := NewPositionSizing(1.0, 0, 0)
+	if err != nil {
+		t.Fatalf("expected valid sizing, got %v", err)
+	}
+	if sizing.ATRPeriod != DefaultSizingATRPeriod || sizing.ATRMultiplier != DefaultSizingATRMultiplier {
+		t.Errorf("expected default ATR period and multiplier, got %+v", sizing)
+	}
+
+	if sizing, _ := NewPositionSizing(0, 14, 2.0); sizing.IsEnabled() {
+		t.Error("expected zero risk to disable ATR sizing")
+	}
+	if _, err := NewPositionSizing(101.0, 14, 2.0); err == nil {
+		t.Error("expected error for risk above 100%")
+	}
+	if _, err := NewPositionSizing(1.0, -1, 2.0); err == nil {
+		t.Error("expected error for negative ATR period")
+	}
+	if _, err := NewPositionSizing(1.0, 14, -2.0); err == nil {
+		t.Error("expected error for negative ATR multiplier")
+	}
+}
+
+func TestPositionSizing_Quantity(t *testing.T) {
+	sizing := PositionSizing{RiskPercent: 1.0, ATRPeriod: 14, ATRMultiplier: 2.0}
+
+	// Risking 100 of 10000 with a stop 2 ATRs (10) away buys 10 units
+	if quantity := sizing.Quantity(10000.0, 50.0, 5.0, 0); math.Abs(quantity-10.0) > 1e-9 {
+		t.Errorf("expected quantity 10, got %.6f", quantity)
+	}
+	// Order value is capped at the max amount
+	if quantity := sizing.Quantity(10000.0, 50.0, 5.0, 400.0); math.Abs(quantity-8.0) > 1e-9 {
+		t.Errorf("expected capped quantity 8, got %.6f", quantity)
+	}
+	// A symbol twice as volatile gets half the position
+	if quantity := sizing.Quantity(10000.0, 50.0, 10.0, 0); math.Abs(quantity-5.0) > 1e-9 {
+		t.Errorf("expected quantity 5 for doubled ATR, got %.6f", quantity)
+	}
+	if quantity := sizing.Quantity(10000.0, 50.0, 0, 0); quantity != 0 {
+		t.Errorf("expected 0 without ATR, got %.6f", quantity)
+	}
+}
+
+func TestPositionSizing_ATR(t *testing.T) {
+	sizing := PositionSizing{RiskPercent: 1.0, ATRPeriod: 14, ATRMultiplier: 2.0}
+
+	if atr := sizing.ATR(createTestKlinesWithRange(10, 100.0, 4.0)); atr != 0 {
+		t.Errorf("expected 0 with insufficient klines, got %.4f", atr)
+	}
+	if atr := sizing.ATR(createTestKlinesWithRange(20, 100.0, 4.0)); math.Abs(atr-4.0) > 1e-9 {
+		t.Errorf("expected ATR 4, got %.4f", atr)
+	}
+}
+
+func TestTradingBot_CalculateBuyQuantity(t *testing.T) {
+	symbol, _ := vo.NewSymbol("SOLBRL")
+
+	fixed := NewTradingBot(symbol, 0.5, NewRSIStrategy(14), 60, 10000.0, 1000.0, "BRL", 0.1, 1.0, true)
+	if quantity := fixed.CalculateBuyQuantity(100.0); quantity != 0.5 {
+		t.Errorf("expected fixed quantity 0.5, got %.6f", quantity)
+	}
+	if fixed.GetPositionSizingMode() != SizingModeFixedQuantity {
+		t.Errorf("expected %s mode, got %s", SizingModeFixedQuantity, fixed.GetPositionSizingMode())
+	}
+
+	byAmount := NewTradingBot(symbol, 0.5, NewRSIStrategy(14), 60, 10000.0, 1000.0, "BRL", 0.1, 1.0, false)
+	if quantity := byAmount.CalculateBuyQuantity(100.0); quantity != 10.0 {
+		t.Errorf("expected trade amount quantity 10, got %.6f", quantity)
+	}
+
+	atrRisk := NewTradingBot(symbol, 0.5, NewRSIStrategy(14), 60, 10000.0, 1000.0, "BRL", 0.1, 1.0, false)
+	atrRisk.SetPositionSizing(PositionSizing{RiskPercent: 1.0, ATRPeriod: 14, ATRMultiplier: 2.0})
+	if atrRisk.GetPositionSizingMode() != SizingModeATRRisk {
+		t.Errorf("expected %s mode, got %s", SizingModeATRRisk, atrRisk.GetPositionSizingMode())
+	}
+
+	// Falls back to the trade amount until the ATR is known
+	if quantity := atrRisk.CalculateBuyQuantity(100.0); quantity != 10.0 {
+		t.Errorf("expected trade amount fallback 10, got %.6f", quantity)
+	}
+
+	// Risking 100 with a 2 x 8 ATR stop buys 6.25 units (625 BRL, below the 1000 cap)
+	atrRisk.UpdateATR(createTestKlinesWithRange(20, 100.0, 8.0))
+	if quantity := atrRisk.CalculateBuyQuantity(100.0); math.Abs(quantity-6.25) > 1e-9 {
+		t.Errorf("expected ATR risk quantity 6.25, got %.6f", quantity)
+	}
+}
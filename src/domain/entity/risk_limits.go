@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits enforced by the risk manager across the live bots
+const (
+	RiskLimitKillSwitch    = "kill_switch"
+	RiskLimitDailyLoss     = "daily_loss"
+	RiskLimitOpenPositions = "open_positions"
+	RiskLimitAssetExposure = "asset_exposure"
+)
+
+// Reasons of the decisions the risk limits override, the breach being in the details of the decision
+const (
+	HoldReasonRiskLimit       = "risk_limit_entry_blocked"
+	HoldReasonRiskCheckFailed = "risk_check_failed"
+	ExitReasonRiskFlattened   = "risk_limit_flattened"
+)
+
+// RiskLimits are the limits shared by all live bots, amounts being in their quote currency. While a limit is
+// breached no bot opens a position. When FlattenOnBreach is set, the open positions are also sold while the kill
+// switch is on or the daily loss limit is breached. A zero limit is not enforced.
+type RiskLimits struct {
+	MaxDailyLoss        float64 // Realized loss since midnight UTC, net of the day's profits
+	MaxOpenPositions    int
+	MaxExposurePerAsset float64 // Cost of the open positions in the same base asset
+	FlattenOnBreach     bool
+	KillSwitch          bool
+	UpdatedAt           time.Time
+}
+
+// NewRiskLimits validates the risk limits, the kill switch being set on its own
+func NewRiskLimits(maxDailyLoss float64, maxOpenPositions int, maxExposurePerAsset float64, flattenOnBreach bool) (RiskLimits, error) {
+	if maxDailyLoss < 0 {
+		return RiskLimits{}, fmt.Errorf("invalid max daily loss: must be greater than or equal to zero")
+	}
+	if maxOpenPositions < 0 {
+		return RiskLimits{}, fmt.Errorf("invalid max open positions: must be greater than or equal to zero")
+	}
+	if maxExposurePerAsset < 0 {
+		return RiskLimits{}, fmt.Errorf("invalid max exposure per asset: must be greater than or equal to zero")
+	}
+	return RiskLimits{
+		MaxDailyLoss:        maxDailyLoss,
+		MaxOpenPositions:    maxOpenPositions,
+		MaxExposurePerAsset: maxExposurePerAsset,
+		FlattenOnBreach:     flattenOnBreach,
+	}, nil
+}
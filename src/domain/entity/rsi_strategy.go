@@ -1,9 +1,9 @@
 package entity
 
 import (
+	"crypgo-machine/src/domain/indicator"
 	"crypgo-machine/src/domain/vo"
 	"fmt"
-	"math"
 	"time"
 )
 
@@ -15,6 +15,49 @@ type RSIStrategy struct {
 	StoplossThreshold   float64
 }
 
+func init() {
+	RegisterStrategy(StrategyDefinition{
+		Name:        "RSI",
+		Description: "Buys when RSI is oversold and sells when it becomes overbought",
+		Params: []StrategyParamSpec{
+			{Name: "Period", Type: ParamTypeInt, Default: 14, Min: paramBound(1), Description: "Number of klines used by Wilder's RSI"},
+			{Name: "OversoldThreshold", Type: ParamTypeFloat, Default: 30.0, Min: paramBound(0), Max: paramBound(100), Description: "RSI value below which the market is oversold"},
+			{Name: "OverboughtThreshold", Type: ParamTypeFloat, Default: 70.0, Min: paramBound(0), Max: paramBound(100), Description: "RSI value above which the market is overbought"},
+			{Name: "MinimumSpread", Type: ParamTypeFloat, Default: 0.1, Min: paramBound(0), Max: paramBound(100), Description: "Minimum spread kept for parity with MovingAverage"},
+			{Name: "StoplossThreshold", Type: ParamTypeFloat, Default: 0.0, Min: paramBound(0), Max: paramBound(100), Description: "Loss % that forces a sell (0 disables stoploss)"},
+		},
+		Validate: func(params StrategyParams) error {
+			oversold, overbought := params.Float("OversoldThreshold"), params.Float("OverboughtThreshold")
+			if params.Int("Period") <= 0 {
+				return fmt.Errorf("missing or invalid fields for RSI: Period must be > 0")
+			}
+			if oversold <= 0 || oversold >= 100 {
+				return fmt.Errorf("OversoldThreshold must be between 0 and 100")
+			}
+			if overbought <= 0 || overbought >= 100 {
+				return fmt.Errorf("OverboughtThreshold must be between 0 and 100")
+			}
+			if oversold >= overbought {
+				return fmt.Errorf("OversoldThreshold must be less than OverboughtThreshold")
+			}
+			return nil
+		},
+		Build: func(params StrategyParams) (TradingStrategy, error) {
+			minimumSpread, err := vo.NewMinimumSpread(params.Float("MinimumSpread"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create MinimumSpread: %w", err)
+			}
+			return NewRSIStrategyWithStoploss(
+				params.Int("Period"),
+				params.Float("OversoldThreshold"),
+				params.Float("OverboughtThreshold"),
+				minimumSpread,
+				params.Float("StoplossThreshold"),
+			), nil
+		},
+	})
+}
+
 func NewRSIStrategy(period int) *RSIStrategy {
 	minimumSpread, _ := vo.NewMinimumSpread(0.1)
 	
@@ -81,47 +124,30 @@ func (s *RSIStrategy) Decide(klines []vo.Kline, tradingBot *TradingBot) *Strateg
 	}
 
 	currentPrice := klines[len(klines)-1].Close()
-	entryPrice := tradingBot.GetEntryPrice()
-	possibleProfit := s.calculatePossibleProfit(entryPrice, currentPrice)
-
-	analysisData := map[string]interface{}{
-		"rsi":                     rsiResult.Value,
-		"signal":                  string(rsiResult.Signal),
-		"period":                  rsiResult.Period,
-		"currentPrice":            currentPrice,
-		"isPositioned":            tradingBot.GetIsPositioned(),
-		"oversoldThreshold":       s.OversoldThreshold,
-		"overboughtThreshold":     s.OverboughtThreshold,
-		"entryPrice":              entryPrice,
-		"possibleProfit":          possibleProfit,
-		"minimumProfitThreshold":  tradingBot.GetMinimumProfitThreshold(),
-		"stoplossThreshold":       s.StoplossThreshold,
-	}
+	exit := NewPositionExit(tradingBot, currentPrice, s.StoplossThreshold)
 
-	var decision TradingDecision
+	analysisData := exit.AnalysisData(map[string]interface{}{
+		"rsi":                 rsiResult.Value,
+		"signal":              string(rsiResult.Signal),
+		"period":              rsiResult.Period,
+		"oversoldThreshold":   s.OversoldThreshold,
+		"overboughtThreshold": s.OverboughtThreshold,
+	})
 
 	// Check for stoploss first if positioned and stoploss is enabled
-	if tradingBot.GetIsPositioned() && s.StoplossThreshold > 0 && possibleProfit <= -s.StoplossThreshold {
-		fmt.Printf("🚨 RSI STOPLOSS TRIGGERED! Price: %.2f | Entry: %.2f | Loss: %.2f%% | Threshold: %.2f%%\n", 
-			currentPrice, entryPrice, possibleProfit, s.StoplossThreshold)
-		decision = Sell
-		analysisData["reason"] = "stoploss_triggered"
-		return NewStrategyAnalysisResult(decision, analysisData)
+	if result := exit.CheckStoploss("RSI STOPLOSS", analysisData); result != nil {
+		return result
 	}
 
+	var decision TradingDecision
+
 	// RSI Logic: Buy when oversold (RSI < 30) and not positioned
 	// Sell when overbought (RSI > 70) and positioned with sufficient profit
 	if rsiResult.IsOversold() && !tradingBot.GetIsPositioned() {
 		decision = Buy
 		analysisData["reason"] = "rsi_oversold_buy_signal"
 	} else if rsiResult.IsOverbought() && tradingBot.GetIsPositioned() {
-		if possibleProfit >= tradingBot.GetMinimumProfitThreshold() {
-			decision = Sell
-			analysisData["reason"] = "rsi_overbought_sell_with_profit"
-		} else {
-			decision = Hold
-			analysisData["reason"] = "rsi_overbought_hold_insufficient_profit"
-		}
+		decision = exit.SellIfProfitable(analysisData, "rsi_overbought_sell_with_profit", "rsi_overbought_hold_insufficient_profit")
 	} else {
 		decision = Hold
 		if rsiResult.IsOversold() && tradingBot.GetIsPositioned() {
@@ -140,14 +166,6 @@ func (s *RSIStrategy) Decide(klines []vo.Kline, tradingBot *TradingBot) *Strateg
 	return NewStrategyAnalysisResult(decision, analysisData)
 }
 
-func (s *RSIStrategy) calculatePossibleProfit(entryPrice, currentPrice float64) float64 {
-	if entryPrice == 0 {
-		return 0.0
-	}
-
-	return ((currentPrice - entryPrice) / entryPrice) * 100
-}
-
 func (s *RSIStrategy) calculateRSI(klines []vo.Kline, period int) (*vo.RSIResult, error) {
 	if period <= 0 {
 		return nil, fmt.Errorf("period must be positive, got: %d", period)
@@ -157,42 +175,7 @@ func (s *RSIStrategy) calculateRSI(klines []vo.Kline, period int) (*vo.RSIResult
 		return nil, fmt.Errorf("insufficient data: need at least %d klines, got %d", period+1, len(klines))
 	}
 
-	// Calculate price changes
-	priceChanges := make([]float64, len(klines)-1)
-	for i := 1; i < len(klines); i++ {
-		priceChanges[i-1] = klines[i].Close() - klines[i-1].Close()
-	}
-
-	// Calculate initial average gain and loss for the first RSI value
-	initialGain, initialLoss := s.calculateInitialGainLoss(priceChanges[:period])
-	
-	if initialGain == 0 && initialLoss == 0 {
-		return nil, fmt.Errorf("no price changes detected in the data")
-	}
-
-	// Calculate RSI using the Wilder's smoothing method
-	avgGain := initialGain
-	avgLoss := initialLoss
-	
-	// Apply Wilder's smoothing for remaining periods
-	for i := period; i < len(priceChanges); i++ {
-		change := priceChanges[i]
-		gain := 0.0
-		loss := 0.0
-		
-		if change > 0 {
-			gain = change
-		} else {
-			loss = -change
-		}
-		
-		// Wilder's smoothing formula: new_avg = (prev_avg * (period-1) + new_value) / period
-		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
-		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
-	}
-
-	// Calculate RSI
-	rsiValue := s.calculateRSIValue(avgGain, avgLoss)
+	rsiValue := indicator.Last(indicator.RSI(indicator.Closes(klines), period))
 	
 	// Use the timestamp of the last kline
 	lastKline := klines[len(klines)-1]
@@ -200,32 +183,3 @@ func (s *RSIStrategy) calculateRSI(klines []vo.Kline, period int) (*vo.RSIResult
 	
 	return vo.NewRSIResult(rsiValue, period, timestamp)
 }
-
-func (s *RSIStrategy) calculateInitialGainLoss(changes []float64) (float64, float64) {
-	var totalGain, totalLoss float64
-	
-	for _, change := range changes {
-		if change > 0 {
-			totalGain += change
-		} else if change < 0 {
-			totalLoss += -change
-		}
-	}
-	
-	avgGain := totalGain / float64(len(changes))
-	avgLoss := totalLoss / float64(len(changes))
-	
-	return avgGain, avgLoss
-}
-
-func (s *RSIStrategy) calculateRSIValue(avgGain, avgLoss float64) float64 {
-	if avgLoss == 0 {
-		return 100.0
-	}
-	
-	rs := avgGain / avgLoss
-	rsi := 100.0 - (100.0 / (1.0 + rs))
-	
-	// Ensure RSI is within valid range
-	return math.Max(0, math.Min(100, rsi))
-}
\ No newline at end of file
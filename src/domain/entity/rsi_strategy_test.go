@@ -2,6 +2,7 @@ package entity
 
 import (
 	"crypgo-machine/src/domain/vo"
+	"math"
 	"testing"
 	"time"
 )
@@ -90,6 +91,27 @@ func TestRSIStrategy_Decide_OverboughtHoldInsufficientProfit(t *testing.T) {
 	}
 }
 
+func TestRSIStrategy_Decide_ReferenceValue(t *testing.T) {
+	strategy := NewRSIStrategy(14)
+	bot := createTestTradingBot(false, 0.0, 1.0)
+
+	// Wilder's example series (StockCharts): RSI(14) = 70.46 on the 15th close
+	klines := createTestKlinesForRSI([]float64{
+		44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89, 46.03, 45.61, 46.28, 46.28,
+	})
+
+	result := strategy.Decide(klines, bot)
+
+	rsi := result.AnalysisData["rsi"].(float64)
+	if math.Abs(rsi-70.464135) > 1e-6 {
+		t.Errorf("Expected RSI 70.464135, got: %.6f", rsi)
+	}
+
+	if result.AnalysisData["signal"] != "OVERBOUGHT" {
+		t.Errorf("Expected OVERBOUGHT signal, got: %v", result.AnalysisData["signal"])
+	}
+}
+
 func TestRSIStrategy_GetName(t *testing.T) {
 	strategy := NewRSIStrategy(14)
 
@@ -6,4 +6,10 @@ const (
 	StatusRunning Status = "RUNNING"
 	StatusStopped Status = "STOPPED"
 	StatusError   Status = "ERROR"
+	// StatusPaused keeps managing the open position of a bot without opening new ones
+	StatusPaused Status = "PAUSED"
+	// StatusClosing sells the open position of a bot at the next opportunity, then stops it
+	StatusClosing Status = "CLOSING"
+	// StatusNeedsAttention parks a bot whose position no longer matches the exchange until someone checks it
+	StatusNeedsAttention Status = "NEEDS_ATTENTION"
 )
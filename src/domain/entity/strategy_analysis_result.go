@@ -10,4 +10,19 @@ func NewStrategyAnalysisResult(decision TradingDecision, analysisData map[string
 		Decision:     decision,
 		AnalysisData: analysisData,
 	}
-}
\ No newline at end of file
+}
+
+// Override returns the result with decision taken instead of the strategy's for reason, e.g. a buy held by the
+// cooldown rules. The analysis data is copied with details, the strategy's decision and the reason added.
+func (r *StrategyAnalysisResult) Override(decision TradingDecision, reason string, details map[string]interface{}) *StrategyAnalysisResult {
+	analysisData := make(map[string]interface{}, len(r.AnalysisData)+len(details)+2)
+	for key, value := range r.AnalysisData {
+		analysisData[key] = value
+	}
+	for key, value := range details {
+		analysisData[key] = value
+	}
+	analysisData["strategyDecision"] = string(r.Decision)
+	analysisData["reason"] = reason
+	return NewStrategyAnalysisResult(decision, analysisData)
+}
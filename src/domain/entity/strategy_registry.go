@@ -0,0 +1,288 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// StrategyParamType describes how a strategy parameter value is interpreted
+type StrategyParamType string
+
+const (
+	ParamTypeInt    StrategyParamType = "int"
+	ParamTypeFloat  StrategyParamType = "float"
+	ParamTypeBool   StrategyParamType = "bool"
+	ParamTypeString StrategyParamType = "string"
+	ParamTypeList   StrategyParamType = "list" // List of objects, e.g. nested child strategy configs
+)
+
+// StrategyParamSpec describes a single strategy parameter: its type, default and bounds
+type StrategyParamSpec struct {
+	Name        string            `json:"name"`
+	Type        StrategyParamType `json:"type"`
+	Default     interface{}       `json:"default"`
+	Min         *float64          `json:"min,omitempty"`
+	Max         *float64          `json:"max,omitempty"`
+	Options     []string          `json:"options,omitempty"`
+	Description string            `json:"description"`
+}
+
+// StrategyDefinition is what a TradingStrategy registers so it can be built from raw params
+type StrategyDefinition struct {
+	Name        string
+	Description string
+	Params      []StrategyParamSpec
+	// Validate checks cross-field rules; it runs before the generic bounds check
+	// so strategies can keep their own error messages
+	Validate func(params StrategyParams) error
+	Build    func(params StrategyParams) (TradingStrategy, error)
+}
+
+// StrategyDefinitionDTO is the public representation of a registered strategy schema
+type StrategyDefinitionDTO struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Params      []StrategyParamSpec `json:"params"`
+}
+
+func (d StrategyDefinition) ToDTO() StrategyDefinitionDTO {
+	return StrategyDefinitionDTO{
+		Name:        d.Name,
+		Description: d.Description,
+		Params:      d.Params,
+	}
+}
+
+// StrategyParams holds resolved parameters, already defaulted and converted to the declared types
+type StrategyParams map[string]interface{}
+
+func (p StrategyParams) Int(name string) int {
+	value, _ := p[name].(int)
+	return value
+}
+
+func (p StrategyParams) Float(name string) float64 {
+	value, _ := p[name].(float64)
+	return value
+}
+
+func (p StrategyParams) Bool(name string) bool {
+	value, _ := p[name].(bool)
+	return value
+}
+
+func (p StrategyParams) String(name string) string {
+	value, _ := p[name].(string)
+	return value
+}
+
+func (p StrategyParams) List(name string) []map[string]interface{} {
+	value, _ := p[name].([]map[string]interface{})
+	return value
+}
+
+var (
+	strategyRegistryMu sync.RWMutex
+	strategyRegistry   = make(map[string]StrategyDefinition)
+)
+
+// RegisterStrategy makes a strategy available by name. It panics if the name is
+// registered twice or the definition has no constructor.
+func RegisterStrategy(definition StrategyDefinition) {
+	strategyRegistryMu.Lock()
+	defer strategyRegistryMu.Unlock()
+
+	if definition.Name == "" {
+		panic("strategy registry: strategy name cannot be empty")
+	}
+	if definition.Build == nil {
+		panic(fmt.Sprintf("strategy registry: strategy %s has no constructor", definition.Name))
+	}
+	if _, exists := strategyRegistry[definition.Name]; exists {
+		panic(fmt.Sprintf("strategy registry: strategy %s registered twice", definition.Name))
+	}
+	strategyRegistry[definition.Name] = definition
+}
+
+// GetStrategyDefinition returns the registered definition for the given strategy name
+func GetStrategyDefinition(name string) (StrategyDefinition, bool) {
+	strategyRegistryMu.RLock()
+	defer strategyRegistryMu.RUnlock()
+	definition, exists := strategyRegistry[name]
+	return definition, exists
+}
+
+// IsStrategyRegistered reports whether a strategy with the given name exists
+func IsStrategyRegistered(name string) bool {
+	_, exists := GetStrategyDefinition(name)
+	return exists
+}
+
+// ListStrategyDefinitions returns all registered strategies sorted by name
+func ListStrategyDefinitions() []StrategyDefinition {
+	strategyRegistryMu.RLock()
+	defer strategyRegistryMu.RUnlock()
+
+	definitions := make([]StrategyDefinition, 0, len(strategyRegistry))
+	for _, definition := range strategyRegistry {
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+// NewStrategyFromParams resolves raw params against the strategy schema and builds it
+func NewStrategyFromParams(name string, rawParams map[string]interface{}) (TradingStrategy, error) {
+	definition, exists := GetStrategyDefinition(name)
+	if !exists {
+		return nil, fmt.Errorf("unknown strategy: %s", name)
+	}
+
+	params, err := definition.ResolveParams(rawParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return definition.Build(params)
+}
+
+// ResolveParams applies defaults, converts values to the declared types and validates them
+func (d StrategyDefinition) ResolveParams(rawParams map[string]interface{}) (StrategyParams, error) {
+	params := make(StrategyParams, len(d.Params))
+	for _, spec := range d.Params {
+		raw, exists := rawParams[spec.Name]
+		if !exists || raw == nil {
+			raw = spec.Default
+		}
+		value, err := convertParamValue(spec, raw)
+		if err != nil {
+			return nil, err
+		}
+		params[spec.Name] = value
+	}
+
+	if d.Validate != nil {
+		if err := d.Validate(params); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, spec := range d.Params {
+		if err := checkParamBounds(spec, params[spec.Name]); err != nil {
+			return nil, err
+		}
+	}
+
+	return params, nil
+}
+
+func convertParamValue(spec StrategyParamSpec, raw interface{}) (interface{}, error) {
+	switch spec.Type {
+	case ParamTypeInt:
+		number, ok := toFloat64(raw)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a number", spec.Name)
+		}
+		if number != math.Trunc(number) {
+			return nil, fmt.Errorf("%s must be an integer", spec.Name)
+		}
+		return int(number), nil
+	case ParamTypeFloat:
+		number, ok := toFloat64(raw)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a number", spec.Name)
+		}
+		return number, nil
+	case ParamTypeBool:
+		value, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a boolean", spec.Name)
+		}
+		return value, nil
+	case ParamTypeString:
+		value, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", spec.Name)
+		}
+		return value, nil
+	case ParamTypeList:
+		return toObjectList(spec.Name, raw)
+	default:
+		return nil, fmt.Errorf("%s has unsupported parameter type %s", spec.Name, spec.Type)
+	}
+}
+
+func checkParamBounds(spec StrategyParamSpec, value interface{}) error {
+	if len(spec.Options) > 0 {
+		text, _ := value.(string)
+		for _, option := range spec.Options {
+			if text == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %v", spec.Name, spec.Options)
+	}
+
+	number, ok := toFloat64(value)
+	if !ok {
+		return nil
+	}
+	if spec.Min != nil && spec.Max != nil && (number < *spec.Min || number > *spec.Max) {
+		return fmt.Errorf("%s must be between %g and %g", spec.Name, *spec.Min, *spec.Max)
+	}
+	if spec.Min != nil && number < *spec.Min {
+		return fmt.Errorf("%s must be >= %g", spec.Name, *spec.Min)
+	}
+	if spec.Max != nil && number > *spec.Max {
+		return fmt.Errorf("%s must be <= %g", spec.Name, *spec.Max)
+	}
+	return nil
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// toObjectList accepts both the in-memory form ([]map) and the JSON-decoded form ([]interface{})
+func toObjectList(name string, value interface{}) ([]map[string]interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return []map[string]interface{}{}, nil
+	case []map[string]interface{}:
+		return v, nil
+	case []interface{}:
+		list := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of objects", name)
+			}
+			list = append(list, object)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("%s must be a list of objects", name)
+	}
+}
+
+// paramBound is a small helper to declare Min/Max bounds inline
+func paramBound(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"testing"
+)
+
+func TestStrategyRegistry_ListsBuiltInStrategies(t *testing.T) {
+	for _, name := range []string{"BollingerBands", "Composite", "MACD", "MovingAverage", "RSI"} {
+		if !IsStrategyRegistered(name) {
+			t.Errorf("expected %s to be registered", name)
+		}
+	}
+
+	definitions := ListStrategyDefinitions()
+	for i := 1; i < len(definitions); i++ {
+		if definitions[i-1].Name > definitions[i].Name {
+			t.Fatalf("expected definitions sorted by name, got %s before %s", definitions[i-1].Name, definitions[i].Name)
+		}
+	}
+}
+
+func TestStrategyRegistry_AppliesDefaults(t *testing.T) {
+	strategy, err := NewStrategyFromParams("RSI", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	rsi, ok := strategy.(*RSIStrategy)
+	if !ok {
+		t.Fatalf("expected *RSIStrategy, got %T", strategy)
+	}
+	if rsi.Period != 14 || rsi.OversoldThreshold != 30.0 || rsi.OverboughtThreshold != 70.0 {
+		t.Errorf("expected RSI defaults 14/30/70, got %d/%.1f/%.1f", rsi.Period, rsi.OversoldThreshold, rsi.OverboughtThreshold)
+	}
+	if rsi.MinimumSpread.GetValue() != 0.1 {
+		t.Errorf("expected default MinimumSpread 0.1, got %.2f", rsi.MinimumSpread.GetValue())
+	}
+}
+
+func TestStrategyRegistry_ConvertsJSONNumbers(t *testing.T) {
+	// Params decoded from JSON (API body or strategy_params column) arrive as float64
+	strategy, err := NewStrategyFromParams("MovingAverage", map[string]interface{}{
+		"FastWindow":        5.0,
+		"SlowWindow":        20.0,
+		"MinimumSpread":     0.5,
+		"StoplossThreshold": 3.0,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	ma := strategy.(*MovingAverageStrategy)
+	if ma.FastWindow != 5 || ma.SlowWindow != 20 {
+		t.Errorf("expected windows 5/20, got %d/%d", ma.FastWindow, ma.SlowWindow)
+	}
+	if ma.MinimumSpread.GetValue() != 0.5 {
+		t.Errorf("expected MinimumSpread 0.5 to be honored, got %.2f", ma.MinimumSpread.GetValue())
+	}
+	if ma.StoplossThreshold != 3.0 {
+		t.Errorf("expected StoplossThreshold 3.0, got %.2f", ma.StoplossThreshold)
+	}
+}
+
+func TestStrategyRegistry_RoundTripsGetParams(t *testing.T) {
+	original := NewRSIStrategyWithStoploss(21, 20.0, 80.0, NewRSIStrategy(14).MinimumSpread, 5.0)
+
+	restored, err := NewStrategyFromParams(original.GetName(), original.GetParams())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for name, value := range original.GetParams() {
+		if restored.GetParams()[name] != value {
+			t.Errorf("expected %s=%v after round trip, got %v", name, value, restored.GetParams()[name])
+		}
+	}
+}
+
+func TestStrategyRegistry_RejectsInvalidParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		params   map[string]interface{}
+		expected string
+	}{
+		{"unknown strategy", "Nope", nil, "unknown strategy: Nope"},
+		{"fast not below slow", "MovingAverage", map[string]interface{}{"FastWindow": 20.0, "SlowWindow": 10.0}, "invalid MovingAverage parameters: FastWindow (20) must be less than SlowWindow (10)"},
+		{"fractional window", "MovingAverage", map[string]interface{}{"FastWindow": 2.5}, "FastWindow must be an integer"},
+		{"wrong type", "RSI", map[string]interface{}{"Period": "fourteen"}, "Period must be a number"},
+		{"stoploss out of bounds", "RSI", map[string]interface{}{"StoplossThreshold": 150.0}, "StoplossThreshold must be between 0 and 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStrategyFromParams(tt.strategy, tt.params)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestStrategyRegistry_DuplicateRegistrationPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when registering MovingAverage twice")
+		}
+	}()
+
+	definition, _ := GetStrategyDefinition("MovingAverage")
+	RegisterStrategy(definition)
+}
@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"time"
+)
+
+// Trade is a closed position of a bot, from the order that opened it to the one that closed it
+type Trade struct {
+	Id                *vo.EntityId
+	tradingBotId      *vo.EntityId
+	symbol            string
+	entryOrderId      string // Empty if the opening order is not in the ledger
+	exitOrderId       string
+	quantity          float64
+	entryPrice        float64
+	exitPrice         float64
+	entryFees         float64
+	exitFees          float64
+	profitLoss        float64 // Realized, net of entry and exit fees
+	profitLossPercent float64
+	currency          string
+	openedAt          time.Time
+	closedAt          time.Time
+}
+
+// NewClosedTrade records the position of bot closed by the exit order. It must be called before the bot
+// clears its entry price and fees.
+func NewClosedTrade(bot *TradingBot, entryOrderId string, exit *Order) *Trade {
+	fill := exit.GetFill()
+	profitLoss := bot.CalculateRealizedProfitLoss(fill)
+	profitLossPercent := 0.0
+	if costBasis := bot.GetEntryPrice() * fill.ExecutedQuantity; costBasis > 0 {
+		profitLossPercent = profitLoss / costBasis * 100
+	}
+
+	return &Trade{
+		Id:                vo.NewEntityId(),
+		tradingBotId:      bot.Id,
+		symbol:            bot.GetSymbol().GetValue(),
+		entryOrderId:      entryOrderId,
+		exitOrderId:       exit.GetId().GetValue(),
+		quantity:          fill.ExecutedQuantity,
+		entryPrice:        bot.GetEntryPrice(),
+		exitPrice:         fill.AveragePrice,
+		entryFees:         bot.GetEntryFees(),
+		exitFees:          fill.FeesInQuote,
+		profitLoss:        profitLoss,
+		profitLossPercent: profitLossPercent,
+		currency:          bot.GetCurrency(),
+		openedAt:          bot.GetPositionOpenedAt(),
+		closedAt:          fill.ExecutedAt,
+	}
+}
+
+func RestoreTrade(id *vo.EntityId, tradingBotId *vo.EntityId, symbol string, entryOrderId string, exitOrderId string, quantity float64, entryPrice float64, exitPrice float64, entryFees float64, exitFees float64, profitLoss float64, profitLossPercent float64, currency string, openedAt time.Time, closedAt time.Time) *Trade {
+	return &Trade{
+		Id:                id,
+		tradingBotId:      tradingBotId,
+		symbol:            symbol,
+		entryOrderId:      entryOrderId,
+		exitOrderId:       exitOrderId,
+		quantity:          quantity,
+		entryPrice:        entryPrice,
+		exitPrice:         exitPrice,
+		entryFees:         entryFees,
+		exitFees:          exitFees,
+		profitLoss:        profitLoss,
+		profitLossPercent: profitLossPercent,
+		currency:          currency,
+		openedAt:          openedAt,
+		closedAt:          closedAt,
+	}
+}
+
+func (t *Trade) GetId() *vo.EntityId {
+	return t.Id
+}
+
+func (t *Trade) GetTradingBotId() *vo.EntityId {
+	return t.tradingBotId
+}
+
+func (t *Trade) GetSymbol() string {
+	return t.symbol
+}
+
+func (t *Trade) GetEntryOrderId() string {
+	return t.entryOrderId
+}
+
+func (t *Trade) GetExitOrderId() string {
+	return t.exitOrderId
+}
+
+func (t *Trade) GetQuantity() float64 {
+	return t.quantity
+}
+
+func (t *Trade) GetEntryPrice() float64 {
+	return t.entryPrice
+}
+
+func (t *Trade) GetExitPrice() float64 {
+	return t.exitPrice
+}
+
+func (t *Trade) GetEntryFees() float64 {
+	return t.entryFees
+}
+
+func (t *Trade) GetExitFees() float64 {
+	return t.exitFees
+}
+
+func (t *Trade) GetProfitLoss() float64 {
+	return t.profitLoss
+}
+
+func (t *Trade) GetProfitLossPercent() float64 {
+	return t.profitLossPercent
+}
+
+func (t *Trade) GetCurrency() string {
+	return t.currency
+}
+
+func (t *Trade) GetOpenedAt() time.Time {
+	return t.openedAt
+}
+
+func (t *Trade) GetClosedAt() time.Time {
+	return t.closedAt
+}
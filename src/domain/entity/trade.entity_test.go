@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewClosedTrade_RealizesTheProfitNetOfFees(t *testing.T) {
+	symbol, _ := vo.NewSymbol("BTCUSDT")
+	bot := NewTradingBot(symbol, 1.0, NewRSIStrategy(14), 300, 10000.0, 1000.0, "USDT", 0.1, 2.0, true)
+
+	openedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	bot.RecordEntryFill(OrderFill{AveragePrice: 100.0, ExecutedQuantity: 2.0, NetQuantity: 2.0, FeesInQuote: 0.2})
+	_ = bot.GetIntoPosition()
+	bot.StartPositionTracking(100.0, openedAt)
+
+	closedAt := openedAt.Add(2 * time.Hour)
+	exit := NewOrder(bot.Id, "", "BTCUSDT", OrderSideSell, "MARKET", 2.0, 110.0)
+	exit.MarkFilled(OrderFill{AveragePrice: 110.0, ExecutedQuantity: 2.0, FeesInQuote: 0.22, ExecutedAt: closedAt})
+
+	trade := NewClosedTrade(bot, "entry-order", exit)
+
+	// (110 - 100) * 2 - 0.2 - 0.22
+	expectedProfitLoss := 19.58
+	if math.Abs(trade.GetProfitLoss()-expectedProfitLoss) > 1e-9 {
+		t.Errorf("expected profit %.2f, got %.4f", expectedProfitLoss, trade.GetProfitLoss())
+	}
+	if math.Abs(trade.GetProfitLossPercent()-expectedProfitLoss/200*100) > 1e-9 {
+		t.Errorf("expected profit percent %.3f, got %.4f", expectedProfitLoss/200*100, trade.GetProfitLossPercent())
+	}
+	if trade.GetEntryOrderId() != "entry-order" || trade.GetExitOrderId() != exit.GetId().GetValue() {
+		t.Errorf("expected the entry and exit orders to be linked, got %q and %q", trade.GetEntryOrderId(), trade.GetExitOrderId())
+	}
+	if trade.GetEntryPrice() != 100.0 || trade.GetExitPrice() != 110.0 || trade.GetQuantity() != 2.0 {
+		t.Errorf("expected entry 100, exit 110 and quantity 2, got %+v", trade)
+	}
+	if trade.GetEntryFees() != 0.2 || trade.GetExitFees() != 0.22 || trade.GetCurrency() != "USDT" {
+		t.Errorf("expected fees 0.2/0.22 in USDT, got %+v", trade)
+	}
+	if !trade.GetOpenedAt().Equal(openedAt) || !trade.GetClosedAt().Equal(closedAt) {
+		t.Errorf("expected the trade to span %v - %v, got %v - %v", openedAt, closedAt, trade.GetOpenedAt(), trade.GetClosedAt())
+	}
+}
+
+func TestNewClosedTrade_WithoutEntryPriceHasNoProfitPercent(t *testing.T) {
+	symbol, _ := vo.NewSymbol("BTCUSDT")
+	bot := NewTradingBot(symbol, 1.0, NewRSIStrategy(14), 300, 10000.0, 1000.0, "USDT", 0.1, 2.0, true)
+
+	exit := NewOrder(bot.Id, "", "BTCUSDT", OrderSideSell, "MARKET", 1.0, 110.0)
+	exit.MarkFilled(OrderFill{AveragePrice: 110.0, ExecutedQuantity: 1.0})
+
+	trade := NewClosedTrade(bot, "", exit)
+
+	if trade.GetProfitLossPercent() != 0 {
+		t.Errorf("expected no profit percent without a cost basis, got %.4f", trade.GetProfitLossPercent())
+	}
+	if !trade.GetOpenedAt().IsZero() {
+		t.Errorf("expected no open time without a tracked position, got %v", trade.GetOpenedAt())
+	}
+}
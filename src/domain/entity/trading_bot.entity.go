@@ -16,6 +16,7 @@ type TradingBot struct {
 	isPositioned           bool
 	entryPrice             float64 // Price when position was opened
 	actualQuantityHeld     float64 // Actual quantity held after fees (for sell orders)
+	entryFees              float64 // Fees paid to open the position, in the quote currency
 	intervalSeconds        int
 	initialCapital         float64
 	tradeAmount            float64
@@ -23,6 +24,21 @@ type TradingBot struct {
 	tradingFees            float64
 	minimumProfitThreshold float64
 	useFixedQuantity       bool    // true = use quantity field, false = use tradeAmount to calculate dynamic quantity
+	exitRules              ExitRules
+	cooldownRules          CooldownRules
+	cooldownState          CooldownState
+	positionSizing         PositionSizing
+	orderExecution         OrderExecution
+	exchangeOCO            ExchangeOCO
+	activeOCO              ActiveOCO // OCO protecting the open position on the exchange
+	currentATR             float64   // Latest ATR seen by the bot, not persisted
+	highestPriceSinceEntry float64   // High-water mark of the open position, used by the trailing stop
+	positionOpenedAt       time.Time // When the open position was entered, used by the max holding time
+	useStreaming           bool      // true = decide on each candle close from the kline WebSocket stream, false = poll every interval
+	mode                   string    // live or paper, see TradingModeLive and TradingModePaper
+	exchangeProfile        string    // Exchange account the bot trades on, see DefaultExchangeProfile
+	paperBalance           float64   // Virtual quote balance of a paper bot
+	configVersion          int       // Version of the editable config, bumped by Reconfigure
 	createdAt              time.Time
 }
 
@@ -36,6 +52,7 @@ type TradingBotDTO struct {
 	IsPositioned           bool        `json:"is_positioned"`
 	EntryPrice             *float64    `json:"entry_price"`
 	ActualQuantityHeld     float64     `json:"actual_quantity_held"`
+	EntryFees              float64     `json:"entry_fees"`
 	IntervalSeconds        int         `json:"interval_seconds"`
 	InitialCapital         float64     `json:"initial_capital"`
 	TradeAmount            float64     `json:"trade_amount"`
@@ -43,6 +60,35 @@ type TradingBotDTO struct {
 	TradingFees            float64     `json:"trading_fees"`
 	MinimumProfitThreshold float64     `json:"minimum_profit_threshold"`
 	UseFixedQuantity       bool        `json:"use_fixed_quantity"`
+	PositionSizingMode     string      `json:"position_sizing_mode"`
+	RiskPerTradePercent    float64     `json:"risk_per_trade_percent"`
+	ATRPeriod              int         `json:"atr_period"`
+	ATRMultiplier          float64     `json:"atr_multiplier"`
+	OrderExecutionMode     string      `json:"order_execution_mode"`
+	TimeInForce            string      `json:"time_in_force,omitempty"`
+	RepriceAfterSeconds    int         `json:"reprice_after_seconds,omitempty"`
+	MaxOrderAttempts       int         `json:"max_order_attempts,omitempty"`
+	OCOStopLossPercent     float64     `json:"oco_stop_loss_percent"`
+	OCOTakeProfitPercent   float64     `json:"oco_take_profit_percent"`
+	OCOStopLimitGapPercent float64     `json:"oco_stop_limit_gap_percent"`
+	ActiveOCOOrderListID   *int64      `json:"active_oco_order_list_id"`
+	TrailingStopPercent    float64     `json:"trailing_stop_percent"`
+	TakeProfitPercent      float64     `json:"take_profit_percent"`
+	MaxHoldingSeconds      int         `json:"max_holding_seconds"`
+	CooldownCandles        int         `json:"cooldown_candles"`
+	CooldownMinutes        int         `json:"cooldown_minutes"`
+	StopLossCooldownCandles int        `json:"stop_loss_cooldown_candles"`
+	StopLossCooldownMinutes int        `json:"stop_loss_cooldown_minutes"`
+	MaxTradesPerDay        int         `json:"max_trades_per_day"`
+	LastExitAt             *time.Time  `json:"last_exit_at"`
+	LastExitReason         string      `json:"last_exit_reason,omitempty"`
+	HighestPriceSinceEntry *float64    `json:"highest_price_since_entry"`
+	PositionOpenedAt       *time.Time  `json:"position_opened_at"`
+	UseStreaming           bool        `json:"use_streaming"`
+	Mode                   string      `json:"mode"`
+	ExchangeProfile        string      `json:"exchange_profile"`
+	PaperBalance           *float64    `json:"paper_balance,omitempty"`
+	ConfigVersion          int         `json:"config_version"`
 	CreatedAt              time.Time   `json:"created_at"`
 }
 
@@ -51,6 +97,26 @@ func (b *TradingBot) ToDTO() TradingBotDTO {
 	if b.entryPrice > 0 {
 		entryPrice = &b.entryPrice
 	}
+	var highestPriceSinceEntry *float64
+	if b.highestPriceSinceEntry > 0 {
+		highestPriceSinceEntry = &b.highestPriceSinceEntry
+	}
+	var positionOpenedAt *time.Time
+	if !b.positionOpenedAt.IsZero() {
+		positionOpenedAt = &b.positionOpenedAt
+	}
+	var activeOCOOrderListID *int64
+	if b.activeOCO.IsActive() {
+		activeOCOOrderListID = &b.activeOCO.OrderListID
+	}
+	var paperBalance *float64
+	if b.IsPaper() {
+		paperBalance = &b.paperBalance
+	}
+	var lastExitAt *time.Time
+	if !b.cooldownState.LastExitAt.IsZero() {
+		lastExitAt = &b.cooldownState.LastExitAt
+	}
 	
 	return TradingBotDTO{
 		Id:                     string(b.Id.GetValue()),
@@ -62,6 +128,7 @@ func (b *TradingBot) ToDTO() TradingBotDTO {
 		IsPositioned:           b.isPositioned,
 		EntryPrice:             entryPrice,
 		ActualQuantityHeld:     b.actualQuantityHeld,
+		EntryFees:              b.entryFees,
 		IntervalSeconds:        b.intervalSeconds,
 		InitialCapital:         b.initialCapital,
 		TradeAmount:            b.tradeAmount,
@@ -69,6 +136,35 @@ func (b *TradingBot) ToDTO() TradingBotDTO {
 		TradingFees:            b.tradingFees,
 		MinimumProfitThreshold: b.minimumProfitThreshold,
 		UseFixedQuantity:       b.useFixedQuantity,
+		PositionSizingMode:     b.GetPositionSizingMode(),
+		RiskPerTradePercent:    b.positionSizing.RiskPercent,
+		ATRPeriod:              b.positionSizing.ATRPeriod,
+		ATRMultiplier:          b.positionSizing.ATRMultiplier,
+		OrderExecutionMode:     b.orderExecution.GetMode(),
+		TimeInForce:            b.orderExecution.TimeInForce,
+		RepriceAfterSeconds:    b.orderExecution.RepriceAfterSeconds,
+		MaxOrderAttempts:       b.orderExecution.MaxAttempts,
+		OCOStopLossPercent:     b.exchangeOCO.StopLossPercent,
+		OCOTakeProfitPercent:   b.exchangeOCO.TakeProfitPercent,
+		OCOStopLimitGapPercent: b.exchangeOCO.StopLimitGapPercent,
+		ActiveOCOOrderListID:   activeOCOOrderListID,
+		TrailingStopPercent:    b.exitRules.TrailingStopPercent,
+		TakeProfitPercent:      b.exitRules.TakeProfitPercent,
+		MaxHoldingSeconds:      b.exitRules.MaxHoldingSeconds,
+		CooldownCandles:        b.cooldownRules.CooldownCandles,
+		CooldownMinutes:        b.cooldownRules.CooldownMinutes,
+		StopLossCooldownCandles: b.cooldownRules.StopLossCooldownCandles,
+		StopLossCooldownMinutes: b.cooldownRules.StopLossCooldownMinutes,
+		MaxTradesPerDay:        b.cooldownRules.MaxTradesPerDay,
+		LastExitAt:             lastExitAt,
+		LastExitReason:         b.cooldownState.LastExitReason,
+		HighestPriceSinceEntry: highestPriceSinceEntry,
+		PositionOpenedAt:       positionOpenedAt,
+		UseStreaming:           b.useStreaming,
+		Mode:                   b.GetMode(),
+		ExchangeProfile:        b.GetExchangeProfile(),
+		PaperBalance:           paperBalance,
+		ConfigVersion:          b.configVersion,
 		CreatedAt:              b.createdAt,
 	}
 }
@@ -88,95 +184,87 @@ func NewTradingBot(symbol vo.Symbol, quantity float64, strategy TradingStrategy,
 		tradingFees:            tradingFees,
 		minimumProfitThreshold: minimumProfitThreshold,
 		useFixedQuantity:       useFixedQuantity,
+		mode:                   TradingModeLive,
+		exchangeProfile:        DefaultExchangeProfile,
+		configVersion:          1,
 		createdAt:              time.Now(),
 	}
 }
 
-func Restore(id *vo.EntityId, symbol vo.Symbol, quantity float64, strategy TradingStrategy, status Status, isPositioned bool, intervalSeconds int, initialCapital float64, tradeAmount float64, currency string, tradingFees float64, minimumProfitThreshold float64, entryPrice float64, actualQuantityHeld float64, useFixedQuantity bool, createdAt time.Time) *TradingBot {
+// RestoreParams holds the persisted state a TradingBot is restored from
+type RestoreParams struct {
+	Id                     *vo.EntityId
+	Symbol                 vo.Symbol
+	Quantity               float64
+	Strategy               TradingStrategy
+	Status                 Status
+	IsPositioned           bool
+	IntervalSeconds        int
+	InitialCapital         float64
+	TradeAmount            float64
+	Currency               string
+	TradingFees            float64
+	MinimumProfitThreshold float64
+	EntryPrice             float64
+	ActualQuantityHeld     float64
+	EntryFees              float64
+	UseFixedQuantity       bool
+	PositionSizing         PositionSizing
+	OrderExecution         OrderExecution
+	ExchangeOCO            ExchangeOCO
+	ExitRules              ExitRules
+	HighestPriceSinceEntry float64
+	PositionOpenedAt       time.Time
+	ActiveOCO              ActiveOCO
+	UseStreaming           bool
+	Mode                   string
+	ExchangeProfile        string
+	PaperBalance           float64
+	CooldownRules          CooldownRules
+	CooldownState          CooldownState
+	ConfigVersion          int
+	CreatedAt              time.Time
+}
+
+func Restore(params RestoreParams) *TradingBot {
 	return &TradingBot{
-		Id:                     id,
-		symbol:                 symbol,
-		quantity:               quantity,
-		strategy:               strategy,
-		status:                 status,
-		isPositioned:           isPositioned,
-		intervalSeconds:        intervalSeconds,
-		initialCapital:         initialCapital,
-		tradeAmount:            tradeAmount,
-		currency:               currency,
-		tradingFees:            tradingFees,
-		minimumProfitThreshold: minimumProfitThreshold,
-		entryPrice:             entryPrice,
-		actualQuantityHeld:     actualQuantityHeld,
-		useFixedQuantity:       useFixedQuantity,
-		createdAt:              createdAt,
+		Id:                     params.Id,
+		symbol:                 params.Symbol,
+		quantity:               params.Quantity,
+		strategy:               params.Strategy,
+		status:                 params.Status,
+		isPositioned:           params.IsPositioned,
+		intervalSeconds:        params.IntervalSeconds,
+		initialCapital:         params.InitialCapital,
+		tradeAmount:            params.TradeAmount,
+		currency:               params.Currency,
+		tradingFees:            params.TradingFees,
+		minimumProfitThreshold: params.MinimumProfitThreshold,
+		entryPrice:             params.EntryPrice,
+		actualQuantityHeld:     params.ActualQuantityHeld,
+		entryFees:              params.EntryFees,
+		useFixedQuantity:       params.UseFixedQuantity,
+		positionSizing:         params.PositionSizing,
+		orderExecution:         params.OrderExecution,
+		exchangeOCO:            params.ExchangeOCO,
+		exitRules:              params.ExitRules,
+		highestPriceSinceEntry: params.HighestPriceSinceEntry,
+		positionOpenedAt:       params.PositionOpenedAt,
+		activeOCO:              params.ActiveOCO,
+		useStreaming:           params.UseStreaming,
+		mode:                   params.Mode,
+		exchangeProfile:        params.ExchangeProfile,
+		paperBalance:           params.PaperBalance,
+		cooldownRules:          params.CooldownRules,
+		cooldownState:          params.CooldownState,
+		configVersion:          params.ConfigVersion,
+		createdAt:              params.CreatedAt,
 	}
 }
 
+// BuildStrategy builds the TradingStrategy described by a strategy config through the strategy registry
 func BuildStrategy(config *Strategy) (TradingStrategy, error) {
-	params := config.GetParams()
-	
-	switch config.GetName() {
-	case "MovingAverage":
-		fast, _ := params["FastWindow"].(float64)
-		slow, _ := params["SlowWindow"].(float64)
-		
-		// Check for stoploss threshold
-		stoplossThreshold := 0.0
-		if stoploss, exists := params["StoplossThreshold"]; exists {
-			if stoplossVal, ok := stoploss.(float64); ok {
-				stoplossThreshold = stoplossVal
-			}
-		}
-		
-		// Use stoploss constructor if threshold > 0
-		if stoplossThreshold > 0 {
-			minimumSpread, _ := vo.NewMinimumSpread(0.1)
-			return NewMovingAverageStrategyWithStoploss(int(fast), int(slow), minimumSpread, stoplossThreshold), nil
-		}
-		
-		return NewMovingAverageStrategy(int(fast), int(slow)), nil
-		
-	case "RSI":
-		period, _ := params["Period"].(float64)
-		
-		// Get thresholds with defaults
-		oversoldThreshold := 30.0
-		if oversold, exists := params["OversoldThreshold"]; exists {
-			if oversoldVal, ok := oversold.(float64); ok {
-				oversoldThreshold = oversoldVal
-			}
-		}
-		
-		overboughtThreshold := 70.0
-		if overbought, exists := params["OverboughtThreshold"]; exists {
-			if overboughtVal, ok := overbought.(float64); ok {
-				overboughtThreshold = overboughtVal
-			}
-		}
-		
-		// Check for stoploss threshold
-		stoplossThreshold := 0.0
-		if stoploss, exists := params["StoplossThreshold"]; exists {
-			if stoplossVal, ok := stoploss.(float64); ok {
-				stoplossThreshold = stoplossVal
-			}
-		}
-		
-		minimumSpread, _ := vo.NewMinimumSpread(0.1)
-		
-		// Use appropriate constructor based on parameters
-		if stoplossThreshold > 0 {
-			return NewRSIStrategyWithStoploss(int(period), oversoldThreshold, overboughtThreshold, minimumSpread, stoplossThreshold), nil
-		} else if oversoldThreshold != 30.0 || overboughtThreshold != 70.0 {
-			return NewRSIStrategyWithCustomThresholds(int(period), oversoldThreshold, overboughtThreshold, minimumSpread), nil
-		} else {
-			return NewRSIStrategy(int(period)), nil
-		}
-
-	default:
-		return nil, fmt.Errorf("unknown strategy: %s", config.GetName())
-	}
+	return NewStrategyFromParams(config.GetName(), config.GetParams())
 }
 
 func (b *TradingBot) Start() error {
@@ -202,6 +290,7 @@ func (b *TradingBot) GetOutOfPosition() error {
 	}
 
 	b.isPositioned = false
+	b.ClearPositionTracking()
 	return nil
 }
 
@@ -281,6 +370,49 @@ func (b *TradingBot) ClearActualQuantityHeld() {
 	b.actualQuantityHeld = 0.0
 }
 
+func (b *TradingBot) GetEntryFees() float64 {
+	return b.entryFees
+}
+
+func (b *TradingBot) ClearEntryFees() {
+	b.entryFees = 0.0
+}
+
+// RecordEntryFill opens the position bookkeeping from what the exchange executed:
+// the average fill price, the quantity left after commission and the fees paid
+func (b *TradingBot) RecordEntryFill(fill OrderFill) {
+	b.entryPrice = fill.AveragePrice
+	b.actualQuantityHeld = fill.NetQuantity
+	b.entryFees = fill.FeesInQuote
+}
+
+// OpenPosition takes the bot into the position filled by an entry order
+func (b *TradingBot) OpenPosition(fill OrderFill, openedAt time.Time) error {
+	if err := b.GetIntoPosition(); err != nil {
+		return err
+	}
+	b.RecordEntryFill(fill)
+	b.StartPositionTracking(fill.AveragePrice, openedAt)
+	return nil
+}
+
+// ClosePosition takes the bot out of its position, clearing the entry bookkeeping and the OCO protecting it
+func (b *TradingBot) ClosePosition() error {
+	if err := b.GetOutOfPosition(); err != nil {
+		return err
+	}
+	b.ClearEntryPrice()
+	b.ClearActualQuantityHeld()
+	b.ClearEntryFees()
+	b.ClearActiveOCO()
+	return nil
+}
+
+// CalculateRealizedProfitLoss returns the profit of the exit fill against the entry, net of entry and exit fees
+func (b *TradingBot) CalculateRealizedProfitLoss(exit OrderFill) float64 {
+	return (exit.AveragePrice-b.entryPrice)*exit.ExecutedQuantity - b.entryFees - exit.FeesInQuote
+}
+
 func (b *TradingBot) GetUseFixedQuantity() bool {
 	return b.useFixedQuantity
 }
@@ -289,6 +421,171 @@ func (b *TradingBot) SetUseFixedQuantity(useFixed bool) {
 	b.useFixedQuantity = useFixed
 }
 
+func (b *TradingBot) GetUseStreaming() bool {
+	return b.useStreaming
+}
+
+func (b *TradingBot) SetUseStreaming(useStreaming bool) {
+	b.useStreaming = useStreaming
+}
+
+// GetMode returns the trading mode of the bot, bots created before paper trading trade live
+func (b *TradingBot) GetMode() string {
+	if b.mode == "" {
+		return TradingModeLive
+	}
+	return b.mode
+}
+
+// SetMode sets the trading mode, a paper bot starts with its initial capital as virtual balance
+func (b *TradingBot) SetMode(mode string) {
+	b.mode = mode
+	if mode == TradingModePaper {
+		b.paperBalance = b.initialCapital
+	}
+}
+
+// GetExchangeProfile returns the exchange account the bot trades on, bots created before profiles trade on the default one
+func (b *TradingBot) GetExchangeProfile() string {
+	if b.exchangeProfile == "" {
+		return DefaultExchangeProfile
+	}
+	return b.exchangeProfile
+}
+
+func (b *TradingBot) SetExchangeProfile(exchangeProfile string) {
+	b.exchangeProfile = exchangeProfile
+}
+
+func (b *TradingBot) IsPaper() bool {
+	return b.mode == TradingModePaper
+}
+
+func (b *TradingBot) GetPaperBalance() float64 {
+	return b.paperBalance
+}
+
+// AdjustPaperBalance credits (positive delta) or debits (negative delta) the virtual balance of a paper bot
+func (b *TradingBot) AdjustPaperBalance(delta float64) {
+	b.paperBalance += delta
+}
+
+func (b *TradingBot) GetPositionSizing() PositionSizing {
+	return b.positionSizing
+}
+
+func (b *TradingBot) SetPositionSizing(sizing PositionSizing) {
+	b.positionSizing = sizing
+}
+
+func (b *TradingBot) GetOrderExecution() OrderExecution {
+	return b.orderExecution
+}
+
+func (b *TradingBot) SetOrderExecution(execution OrderExecution) {
+	b.orderExecution = execution
+}
+
+func (b *TradingBot) GetExchangeOCO() ExchangeOCO {
+	return b.exchangeOCO
+}
+
+func (b *TradingBot) SetExchangeOCO(oco ExchangeOCO) {
+	b.exchangeOCO = oco
+}
+
+func (b *TradingBot) GetActiveOCO() ActiveOCO {
+	return b.activeOCO
+}
+
+// SetActiveOCO records the OCO placed on the exchange to protect the open position
+func (b *TradingBot) SetActiveOCO(oco ActiveOCO) {
+	b.activeOCO = oco
+}
+
+// ClearActiveOCO forgets the OCO once it was filled, canceled or expired
+func (b *TradingBot) ClearActiveOCO() {
+	b.activeOCO = ActiveOCO{}
+}
+
+// GetPositionSizingMode returns how buy quantities are calculated: ATR risk, fixed quantity or trade amount
+func (b *TradingBot) GetPositionSizingMode() string {
+	return positionSizingModeOf(b.positionSizing, b.useFixedQuantity)
+}
+
+func positionSizingModeOf(sizing PositionSizing, useFixedQuantity bool) string {
+	if sizing.IsEnabled() {
+		return SizingModeATRRisk
+	}
+	if useFixedQuantity {
+		return SizingModeFixedQuantity
+	}
+	return SizingModeTradeAmount
+}
+
+// UpdateATR records the latest ATR of the klines for ATR risk sizing
+func (b *TradingBot) UpdateATR(klines []vo.Kline) {
+	b.currentATR = b.positionSizing.ATR(klines)
+}
+
+func (b *TradingBot) GetCurrentATR() float64 {
+	return b.currentATR
+}
+
+// CalculateBuyQuantity returns the quantity to buy at currentPrice according to the bot's sizing mode.
+// ATR risk sizing is capped at tradeAmount and falls back to it while the ATR is not known yet.
+func (b *TradingBot) CalculateBuyQuantity(currentPrice float64) float64 {
+	if b.positionSizing.IsEnabled() {
+		if quantity := b.positionSizing.Quantity(b.initialCapital, currentPrice, b.currentATR, b.tradeAmount); quantity > 0 {
+			return quantity
+		}
+		return b.tradeAmount / currentPrice
+	}
+	if b.useFixedQuantity {
+		return b.quantity
+	}
+	return b.tradeAmount / currentPrice
+}
+
+func (b *TradingBot) GetExitRules() ExitRules {
+	return b.exitRules
+}
+
+func (b *TradingBot) SetExitRules(rules ExitRules) {
+	b.exitRules = rules
+}
+
+func (b *TradingBot) GetCooldownRules() CooldownRules {
+	return b.cooldownRules
+}
+
+func (b *TradingBot) SetCooldownRules(rules CooldownRules) {
+	b.cooldownRules = rules
+}
+
+func (b *TradingBot) GetCooldownState() CooldownState {
+	return b.cooldownState
+}
+
+func (b *TradingBot) GetHighestPriceSinceEntry() float64 {
+	return b.highestPriceSinceEntry
+}
+
+func (b *TradingBot) GetPositionOpenedAt() time.Time {
+	return b.positionOpenedAt
+}
+
+// StartPositionTracking resets the high-water mark and holding time for a position entered at entryPrice
+func (b *TradingBot) StartPositionTracking(entryPrice float64, openedAt time.Time) {
+	b.highestPriceSinceEntry = entryPrice
+	b.positionOpenedAt = openedAt
+}
+
+func (b *TradingBot) ClearPositionTracking() {
+	b.highestPriceSinceEntry = 0.0
+	b.positionOpenedAt = time.Time{}
+}
+
 // CalculateQuantityForSell calculates the quantity available for selling after considering trading fees
 func (b *TradingBot) CalculateQuantityForSell() float64 {
 	if b.actualQuantityHeld > 0 {
@@ -307,3 +604,8 @@ func (b *TradingBot) Stop() error {
 	b.status = StatusStopped
 	return nil
 }
+
+// MarkNeedsAttention parks the bot until its position is checked by hand, stopping it brings it back
+func (b *TradingBot) MarkNeedsAttention() {
+	b.status = StatusNeedsAttention
+}
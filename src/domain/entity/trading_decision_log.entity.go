@@ -14,6 +14,7 @@ type TradingDecisionLog struct {
 	marketData           []vo.Kline
 	currentPrice         float64
 	currentPossibleProfit float64 // Potential profit if position were closed now
+	configVersion        int     // Version of the bot config the decision was made with (0 = unknown)
 	timestamp            time.Time
 }
 
@@ -48,6 +49,7 @@ func RestoreTradingDecisionLog(
 	marketData []vo.Kline,
 	currentPrice float64,
 	currentPossibleProfit float64,
+	configVersion int,
 	timestamp time.Time,
 ) *TradingDecisionLog {
 	return &TradingDecisionLog{
@@ -59,6 +61,7 @@ func RestoreTradingDecisionLog(
 		marketData:           marketData,
 		currentPrice:         currentPrice,
 		currentPossibleProfit: currentPossibleProfit,
+		configVersion:        configVersion,
 		timestamp:            timestamp,
 	}
 }
@@ -97,4 +100,13 @@ func (t *TradingDecisionLog) GetTimestamp() time.Time {
 
 func (t *TradingDecisionLog) GetCurrentPossibleProfit() float64 {
 	return t.currentPossibleProfit
-}
\ No newline at end of file
+}
+
+func (t *TradingDecisionLog) GetConfigVersion() int {
+	return t.configVersion
+}
+
+// SetConfigVersion attributes the decision to the version of the bot config in force
+func (t *TradingDecisionLog) SetConfigVersion(version int) {
+	t.configVersion = version
+}
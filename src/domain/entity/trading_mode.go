@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Trading modes of a bot, chosen at creation
+const (
+	TradingModeLive  = "live"  // Orders are placed on the exchange
+	TradingModePaper = "paper" // Fills are simulated at the live price against a virtual balance
+)
+
+// NewTradingMode validates a trading mode, an empty mode trades live
+func NewTradingMode(mode string) (string, error) {
+	switch strings.ToLower(mode) {
+	case "", TradingModeLive:
+		return TradingModeLive, nil
+	case TradingModePaper:
+		return TradingModePaper, nil
+	}
+	return "", fmt.Errorf("invalid trading mode: %s (use live or paper)", mode)
+}
@@ -33,3 +33,31 @@ type TradingStrategy interface {
 	GetParams() map[string]interface{}
 	Decide(klines []vo.Kline, tradingBot *TradingBot) *StrategyAnalysisResult
 }
+
+// Timeframes holds klines of additional intervals, keyed by interval in seconds
+type Timeframes map[int][]vo.Kline
+
+// MultiTimeframeStrategy is implemented by strategies that also read klines of other intervals than the bot's own,
+// e.g. a 30m crossover filtered by the 4h trend
+type MultiTimeframeStrategy interface {
+	TradingStrategy
+	// GetTimeframes returns the additional intervals in seconds the strategy needs
+	GetTimeframes() []int
+	DecideWithTimeframes(klines []vo.Kline, timeframes Timeframes, tradingBot *TradingBot) *StrategyAnalysisResult
+}
+
+// RequiredTimeframes returns the additional intervals a strategy needs, or nil for single-timeframe strategies
+func RequiredTimeframes(strategy TradingStrategy) []int {
+	if mtf, ok := strategy.(MultiTimeframeStrategy); ok {
+		return mtf.GetTimeframes()
+	}
+	return nil
+}
+
+// DecideStrategy passes the additional timeframes to strategies that use them and calls Decide otherwise
+func DecideStrategy(strategy TradingStrategy, klines []vo.Kline, timeframes Timeframes, tradingBot *TradingBot) *StrategyAnalysisResult {
+	if mtf, ok := strategy.(MultiTimeframeStrategy); ok {
+		return mtf.DecideWithTimeframes(klines, timeframes, tradingBot)
+	}
+	return strategy.Decide(klines, tradingBot)
+}
@@ -0,0 +1,27 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOrderNotFound is returned, wrapped, when the exchange answers that an order does not exist
+var ErrOrderNotFound = errors.New("order does not exist")
+
+// RejectedError is the exchange refusing a request, e.g. an order failing its rules or a post-only order that
+// would take liquidity. Nothing was placed. Errors that are not a RejectedError leave the outcome unknown, like
+// network errors and timeouts.
+type RejectedError struct {
+	Code    int // The exchange's own error code
+	Message string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("rejected by the exchange (code %d): %s", e.Code, e.Message)
+}
+
+// IsRejected reports whether err is the exchange refusing the request, which then had no effect
+func IsRejected(err error) bool {
+	var rejected *RejectedError
+	return errors.As(err, &rejected) || errors.Is(err, ErrOrderNotFound)
+}
@@ -0,0 +1,82 @@
+package exchange
+
+import (
+	"context"
+	"crypgo-machine/src/domain/vo"
+)
+
+// Exchange is the port the bots trade through, implemented by one adapter per venue. It speaks the domain's
+// candles, orders, fills, balances and symbol rules, so a new venue needs an adapter and nothing else.
+type Exchange interface {
+	// GetKlines returns the candles of a symbol, oldest first
+	GetKlines(ctx context.Context, query KlineQuery) ([]vo.Kline, error)
+	// PlaceOrder sends an order and returns it as the exchange accepted it, with its fills when they are known
+	PlaceOrder(ctx context.Context, request OrderRequest) (*Order, error)
+	// GetOrder returns an order by its exchange ID, failing with ErrOrderNotFound when the exchange does not know it
+	GetOrder(ctx context.Context, symbol string, orderID int64) (*Order, error)
+	// GetOrderByClientID returns the latest order placed with a client order ID, failing with ErrOrderNotFound when
+	// the exchange does not know it
+	GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*Order, error)
+	// CancelOrder cancels an order resting on the book and returns it with what it executed before
+	CancelOrder(ctx context.Context, symbol string, orderID int64) (*Order, error)
+	// ListOrders returns the latest orders of a symbol, oldest first
+	ListOrders(ctx context.Context, symbol string, limit int) ([]*Order, error)
+	// GetBookTicker returns the best bid and ask of a symbol
+	GetBookTicker(ctx context.Context, symbol string) (*BookTicker, error)
+	// PlaceOCO sends a take-profit limit and a stop-loss limit order, the one filling canceling the other
+	PlaceOCO(ctx context.Context, request OCORequest) (*OCO, error)
+	// CancelOCO cancels both legs of an OCO
+	CancelOCO(ctx context.Context, symbol string, orderListID int64) error
+	// GetBalances returns the balance of every asset of the account
+	GetBalances(ctx context.Context) ([]Balance, error)
+	// GetSymbolRules returns the trading rules of every symbol of the exchange
+	GetSymbolRules(ctx context.Context) ([]SymbolRules, error)
+}
+
+// KlineQuery selects candles by symbol and interval, e.g. 1m, 15m, 1h or 1d
+type KlineQuery struct {
+	Symbol    string
+	Interval  string
+	StartTime int64 // Unix milliseconds, 0 = no lower bound
+	EndTime   int64 // Unix milliseconds, 0 = up to the forming candle
+	Limit     int   // 0 = the exchange default
+}
+
+// BookTicker is the best bid and ask of a symbol
+type BookTicker struct {
+	Symbol      string
+	BidPrice    float64
+	BidQuantity float64
+	AskPrice    float64
+	AskQuantity float64
+}
+
+// Balance is what the account holds of an asset, locked funds being held by open orders
+type Balance struct {
+	Asset  string
+	Free   float64
+	Locked float64
+}
+
+// SymbolRules are the quantity, price and notional constraints of the orders of a symbol. A rule the exchange does
+// not set is left at zero.
+type SymbolRules struct {
+	Symbol      string
+	MinQuantity float64
+	MaxQuantity float64
+	StepSize    float64
+	MinPrice    float64
+	MaxPrice    float64
+	TickSize    float64
+	MinNotional float64
+}
+
+// HasQuantityRule reports whether the exchange constrains the quantity of the orders
+func (r SymbolRules) HasQuantityRule() bool {
+	return r.MinQuantity > 0 || r.MaxQuantity > 0 || r.StepSize > 0
+}
+
+// HasPriceRule reports whether the exchange constrains the price of the orders
+func (r SymbolRules) HasPriceRule() bool {
+	return r.MinPrice > 0 || r.MaxPrice > 0 || r.TickSize > 0
+}
@@ -1,8 +1,8 @@
-package external
+package exchange
 
 import "fmt"
 
-// SecondsToInterval converts interval in seconds to Binance API interval format
+// SecondsToInterval converts interval in seconds to the kline interval format, e.g. 1m, 4h or 1d
 func SecondsToInterval(seconds int) (string, error) {
 	switch seconds {
 	case 60:
@@ -40,7 +40,7 @@ func SecondsToInterval(seconds int) (string, error) {
 	}
 }
 
-// IntervalToSeconds converts Binance API interval format to seconds
+// IntervalToSeconds converts a kline interval, e.g. 1m, 4h or 1d, to seconds
 func IntervalToSeconds(interval string) (int, error) {
 	switch interval {
 	case "1m":
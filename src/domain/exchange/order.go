@@ -0,0 +1,83 @@
+package exchange
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"time"
+)
+
+type OrderType string
+
+const (
+	OrderTypeMarket        OrderType = "MARKET"
+	OrderTypeLimit         OrderType = "LIMIT"
+	OrderTypeLimitMaker    OrderType = "LIMIT_MAKER" // Post-only limit order, refused when it would take liquidity
+	OrderTypeStopLossLimit OrderType = "STOP_LOSS_LIMIT"
+)
+
+type TimeInForce string
+
+const (
+	TimeInForceGTC TimeInForce = "GTC" // Rests on the book until filled or canceled
+	TimeInForceIOC TimeInForce = "IOC" // Fills what it can at once, the rest expires
+	TimeInForceFOK TimeInForce = "FOK" // Fills entirely at once or expires
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusNew             OrderStatus = "NEW"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusCanceled        OrderStatus = "CANCELED"
+	OrderStatusExpired         OrderStatus = "EXPIRED"
+	OrderStatusRejected        OrderStatus = "REJECTED"
+)
+
+// OrderRequest is an order to send. Quantity and price are already formatted to the symbol's step and tick size.
+type OrderRequest struct {
+	Symbol        string
+	ClientOrderID string
+	Side          entity.OrderSide
+	Type          OrderType
+	Quantity      string
+	Price         string      // Limit orders only
+	TimeInForce   TimeInForce // Limit orders only, empty for post-only ones
+}
+
+// Order is an order as the exchange reports it
+type Order struct {
+	Symbol           string
+	OrderID          int64
+	ClientOrderID    string
+	OrderListID      int64 // OCO the order is a leg of, 0 for none
+	Side             entity.OrderSide
+	Type             OrderType
+	Status           OrderStatus
+	TimeInForce      TimeInForce
+	Price            float64
+	StopPrice        float64
+	Quantity         float64
+	ExecutedQuantity float64
+	QuoteQuantity    float64       // Quote spent or received by what was executed
+	Fills            []entity.Fill // Trades that executed the order, only known in the response of its placement
+	UpdatedAt        time.Time     // Latest change, e.g. its last fill; zero when unknown
+}
+
+// OCORequest is an OCO sell (or buy) of Quantity, taking profit at Price and stopping at StopPrice with a limit
+// order at StopLimitPrice
+type OCORequest struct {
+	Symbol               string
+	Side                 entity.OrderSide
+	Quantity             string
+	Price                string
+	StopPrice            string
+	StopLimitPrice       string
+	StopLimitTimeInForce TimeInForce
+}
+
+// OCO identifies an OCO placed on the exchange and its two legs
+type OCO struct {
+	OrderListID       int64
+	TakeProfitOrderID int64
+	StopLossOrderID   int64
+}
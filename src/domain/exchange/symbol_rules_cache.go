@@ -1,13 +1,10 @@
-package external
+package exchange
 
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"sync"
 	"time"
-
-	"github.com/adshao/go-binance/v2"
 )
 
 // SymbolFilters contains trading rules for a specific symbol
@@ -38,26 +35,26 @@ type MinNotionalFilter struct {
 	MinNotional float64
 }
 
-// ExchangeInfoService manages symbol trading rules from Binance
-type ExchangeInfoService struct {
-	client         BinanceClientInterface
+// SymbolRulesCache caches the trading rules of the symbols of an exchange
+type SymbolRulesCache struct {
+	exchange       Exchange
 	symbolFilters  map[string]*SymbolFilters
 	mu             sync.RWMutex
 	cacheTimeout   time.Duration
 	lastFullUpdate time.Time
 }
 
-// NewExchangeInfoService creates a new exchange info service
-func NewExchangeInfoService(client BinanceClientInterface) *ExchangeInfoService {
-	return &ExchangeInfoService{
-		client:        client,
+// NewSymbolRulesCache creates a cache of the symbol rules of exchangeClient
+func NewSymbolRulesCache(exchangeClient Exchange) *SymbolRulesCache {
+	return &SymbolRulesCache{
+		exchange:      exchangeClient,
 		symbolFilters: make(map[string]*SymbolFilters),
 		cacheTimeout:  30 * time.Minute, // Cache for 30 minutes
 	}
 }
 
 // GetSymbolFilters returns trading rules for a specific symbol
-func (s *ExchangeInfoService) GetSymbolFilters(symbol string) (*SymbolFilters, error) {
+func (s *SymbolRulesCache) GetSymbolFilters(symbol string) (*SymbolFilters, error) {
 	s.mu.RLock()
 	filters, exists := s.symbolFilters[symbol]
 	s.mu.RUnlock()
@@ -85,9 +82,9 @@ func (s *ExchangeInfoService) GetSymbolFilters(symbol string) (*SymbolFilters, e
 	return filters, nil
 }
 
-// refreshSymbolInfo updates symbol information from Binance API
-func (s *ExchangeInfoService) refreshSymbolInfo(symbol string) error {
-	exchangeInfo, err := s.client.NewGetExchangeInfoService().Do(context.Background())
+// refreshSymbolInfo updates symbol information from the exchange
+func (s *SymbolRulesCache) refreshSymbolInfo(symbol string) error {
+	symbolRules, err := s.exchange.GetSymbolRules(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get exchange info: %v", err)
 	}
@@ -97,11 +94,10 @@ func (s *ExchangeInfoService) refreshSymbolInfo(symbol string) error {
 
 	// Find the specific symbol or update all if doing full refresh
 	symbolFound := false
-	for _, symbolInfo := range exchangeInfo.Symbols {
-		if symbolInfo.Symbol == symbol || symbol == "" {
-			filters := s.parseSymbolFilters(&symbolInfo)
-			s.symbolFilters[symbolInfo.Symbol] = filters
-			if symbolInfo.Symbol == symbol {
+	for _, rules := range symbolRules {
+		if rules.Symbol == symbol || symbol == "" {
+			s.symbolFilters[rules.Symbol] = symbolFiltersOf(rules)
+			if rules.Symbol == symbol {
 				symbolFound = true
 			}
 		}
@@ -115,61 +111,32 @@ func (s *ExchangeInfoService) refreshSymbolInfo(symbol string) error {
 	return nil
 }
 
-// parseSymbolFilters extracts trading rules from Binance symbol info
-func (s *ExchangeInfoService) parseSymbolFilters(symbolInfo *binance.Symbol) *SymbolFilters {
+// symbolFiltersOf converts the rules of a symbol, leaving out the filters the exchange does not set
+func symbolFiltersOf(rules SymbolRules) *SymbolFilters {
 	filters := &SymbolFilters{
-		Symbol:      symbolInfo.Symbol,
+		Symbol:      rules.Symbol,
 		LastUpdated: time.Now(),
 	}
-
-	for _, filter := range symbolInfo.Filters {
-		switch filter["filterType"] {
-		case "LOT_SIZE":
-			filters.LotSizeFilter = &LotSizeFilter{
-				MinQty:   s.parseFloat(filter["minQty"]),
-				MaxQty:   s.parseFloat(filter["maxQty"]),
-				StepSize: s.parseFloat(filter["stepSize"]),
-			}
-		case "PRICE_FILTER":
-			filters.PriceFilter = &PriceFilter{
-				MinPrice: s.parseFloat(filter["minPrice"]),
-				MaxPrice: s.parseFloat(filter["maxPrice"]),
-				TickSize: s.parseFloat(filter["tickSize"]),
-			}
-		case "MIN_NOTIONAL":
-			filters.MinNotional = &MinNotionalFilter{
-				MinNotional: s.parseFloat(filter["minNotional"]),
-			}
-		case "NOTIONAL":
-			// Some symbols use NOTIONAL instead of MIN_NOTIONAL
-			if filters.MinNotional == nil {
-				filters.MinNotional = &MinNotionalFilter{
-					MinNotional: s.parseFloat(filter["minNotional"]),
-				}
-			}
+	if rules.HasQuantityRule() {
+		filters.LotSizeFilter = &LotSizeFilter{
+			MinQty:   rules.MinQuantity,
+			MaxQty:   rules.MaxQuantity,
+			StepSize: rules.StepSize,
 		}
 	}
-
-	return filters
-}
-
-// parseFloat safely converts string to float64
-func (s *ExchangeInfoService) parseFloat(value interface{}) float64 {
-	if value == nil {
-		return 0
-	}
-	
-	str, ok := value.(string)
-	if !ok {
-		return 0
+	if rules.HasPriceRule() {
+		filters.PriceFilter = &PriceFilter{
+			MinPrice: rules.MinPrice,
+			MaxPrice: rules.MaxPrice,
+			TickSize: rules.TickSize,
+		}
 	}
-	
-	result, err := strconv.ParseFloat(str, 64)
-	if err != nil {
-		return 0
+	if rules.MinNotional > 0 {
+		filters.MinNotional = &MinNotionalFilter{
+			MinNotional: rules.MinNotional,
+		}
 	}
-	
-	return result
+	return filters
 }
 
 // ValidateQuantity checks if quantity is valid for the symbol
@@ -329,12 +296,12 @@ func (filters *SymbolFilters) ValidateNotional(quantity, price float64) error {
 }
 
 // RefreshAllSymbols updates all symbol information (should be called periodically)
-func (s *ExchangeInfoService) RefreshAllSymbols() error {
+func (s *SymbolRulesCache) RefreshAllSymbols() error {
 	return s.refreshSymbolInfo("") // Empty string triggers full refresh
 }
 
 // GetCacheStatus returns cache information for monitoring
-func (s *ExchangeInfoService) GetCacheStatus() map[string]interface{} {
+func (s *SymbolRulesCache) GetCacheStatus() map[string]interface{} {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
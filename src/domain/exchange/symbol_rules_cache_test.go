@@ -1,14 +1,16 @@
-package external
+package exchange_test
 
 import (
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/infra/external"
 	"testing"
 	"time"
 )
 
-func TestExchangeInfoService_GetSymbolFilters(t *testing.T) {
+func TestSymbolRulesCache_GetSymbolFilters(t *testing.T) {
 	// Create fake client and service
-	fakeClient := NewBinanceClientFake()
-	service := NewExchangeInfoService(fakeClient)
+	fakeClient := external.NewFakeExchange()
+	service := exchange.NewSymbolRulesCache(fakeClient)
 
 	// Test getting XRPBRL filters (which should be in our fake data)
 	filters, err := service.GetSymbolFilters("XRPBRL")
@@ -58,9 +60,9 @@ func TestExchangeInfoService_GetSymbolFilters(t *testing.T) {
 		filters.LotSizeFilter.MinQty, filters.LotSizeFilter.StepSize, filters.MinNotional.MinNotional)
 }
 
-func TestExchangeInfoService_QuantityValidation(t *testing.T) {
-	fakeClient := NewBinanceClientFake()
-	service := NewExchangeInfoService(fakeClient)
+func TestSymbolRulesCache_QuantityValidation(t *testing.T) {
+	fakeClient := external.NewFakeExchange()
+	service := exchange.NewSymbolRulesCache(fakeClient)
 
 	filters, err := service.GetSymbolFilters("XRPBRL")
 	if err != nil {
@@ -126,9 +128,9 @@ func TestExchangeInfoService_QuantityValidation(t *testing.T) {
 	}
 }
 
-func TestExchangeInfoService_QuantityAdjustment(t *testing.T) {
-	fakeClient := NewBinanceClientFake()
-	service := NewExchangeInfoService(fakeClient)
+func TestSymbolRulesCache_QuantityAdjustment(t *testing.T) {
+	fakeClient := external.NewFakeExchange()
+	service := exchange.NewSymbolRulesCache(fakeClient)
 
 	filters, err := service.GetSymbolFilters("XRPBRL")
 	if err != nil {
@@ -185,9 +187,9 @@ func TestExchangeInfoService_QuantityAdjustment(t *testing.T) {
 	}
 }
 
-func TestExchangeInfoService_FormatQuantity(t *testing.T) {
-	fakeClient := NewBinanceClientFake()
-	service := NewExchangeInfoService(fakeClient)
+func TestSymbolRulesCache_FormatQuantity(t *testing.T) {
+	fakeClient := external.NewFakeExchange()
+	service := exchange.NewSymbolRulesCache(fakeClient)
 
 	testCases := []struct {
 		symbol           string
@@ -233,9 +235,9 @@ func TestExchangeInfoService_FormatQuantity(t *testing.T) {
 	}
 }
 
-func TestExchangeInfoService_NotionalValidation(t *testing.T) {
-	fakeClient := NewBinanceClientFake()
-	service := NewExchangeInfoService(fakeClient)
+func TestSymbolRulesCache_NotionalValidation(t *testing.T) {
+	fakeClient := external.NewFakeExchange()
+	service := exchange.NewSymbolRulesCache(fakeClient)
 
 	filters, err := service.GetSymbolFilters("XRPBRL")
 	if err != nil {
@@ -295,9 +297,9 @@ func TestExchangeInfoService_NotionalValidation(t *testing.T) {
 	}
 }
 
-func TestExchangeInfoService_Cache(t *testing.T) {
-	fakeClient := NewBinanceClientFake()
-	service := NewExchangeInfoService(fakeClient)
+func TestSymbolRulesCache_Cache(t *testing.T) {
+	fakeClient := external.NewFakeExchange()
+	service := exchange.NewSymbolRulesCache(fakeClient)
 
 	// First call should fetch from API
 	start := time.Now()
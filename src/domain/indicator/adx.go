@@ -0,0 +1,121 @@
+package indicator
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"math"
+)
+
+// ADXValue is one point of the Average Directional Index
+type ADXValue struct {
+	ADX     float64 // Trend strength, 0-100, regardless of direction
+	PlusDI  float64 // Upward directional indicator
+	MinusDI float64 // Downward directional indicator
+}
+
+// ADXStream is Wilder's Average Directional Index. True range and directional movement
+// start on the second kline; +DI/-DI are defined after period movements and ADX after
+// period more DX values.
+type ADXStream struct {
+	period      int
+	previous    vo.Kline
+	hasPrevious bool
+	movements   int
+	smoothedTR  float64
+	smoothedPDM float64
+	smoothedMDM float64
+	dxCount     int
+	dxSum       float64
+	value       ADXValue
+}
+
+func NewADXStream(period int) *ADXStream {
+	return &ADXStream{
+		period: period,
+		value:  ADXValue{ADX: math.NaN(), PlusDI: math.NaN(), MinusDI: math.NaN()},
+	}
+}
+
+// Update adds a kline and returns the current ADX/+DI/-DI, NaN while they are not defined
+func (s *ADXStream) Update(kline vo.Kline) ADXValue {
+	if !s.hasPrevious {
+		s.previous = kline
+		s.hasPrevious = true
+		return s.value
+	}
+
+	tr := trueRange(kline, s.previous.Close(), true)
+	upMove := kline.High() - s.previous.High()
+	downMove := s.previous.Low() - kline.Low()
+	plusDM, minusDM := 0.0, 0.0
+	if upMove > downMove && upMove > 0 {
+		plusDM = upMove
+	}
+	if downMove > upMove && downMove > 0 {
+		minusDM = downMove
+	}
+	s.previous = kline
+	s.movements++
+
+	period := float64(s.period)
+	if s.movements <= s.period {
+		s.smoothedTR += tr
+		s.smoothedPDM += plusDM
+		s.smoothedMDM += minusDM
+		if s.movements < s.period {
+			return s.value
+		}
+	} else {
+		// Wilder's smoothing of running sums: new = prev - prev/period + current
+		s.smoothedTR = s.smoothedTR - s.smoothedTR/period + tr
+		s.smoothedPDM = s.smoothedPDM - s.smoothedPDM/period + plusDM
+		s.smoothedMDM = s.smoothedMDM - s.smoothedMDM/period + minusDM
+	}
+
+	plusDI, minusDI := 0.0, 0.0
+	if s.smoothedTR != 0 {
+		plusDI = 100 * s.smoothedPDM / s.smoothedTR
+		minusDI = 100 * s.smoothedMDM / s.smoothedTR
+	}
+	dx := 0.0
+	if plusDI+minusDI != 0 {
+		dx = 100 * math.Abs(plusDI-minusDI) / (plusDI + minusDI)
+	}
+
+	adx := s.value.ADX
+	s.dxCount++
+	if s.dxCount < s.period {
+		s.dxSum += dx
+	} else if s.dxCount == s.period {
+		adx = (s.dxSum + dx) / period
+	} else {
+		adx = (adx*(period-1) + dx) / period
+	}
+
+	s.value = ADXValue{ADX: adx, PlusDI: plusDI, MinusDI: minusDI}
+	return s.value
+}
+
+func (s *ADXStream) Value() ADXValue { return s.value }
+func (s *ADXStream) Ready() bool     { return IsReady(s.value.ADX) }
+
+// ADXSeries holds the full-series ADX outputs, aligned with the input
+type ADXSeries struct {
+	ADX     []float64
+	PlusDI  []float64
+	MinusDI []float64
+}
+
+// ADX returns the Average Directional Index of klines
+func ADX(klines []vo.Kline, period int) ADXSeries {
+	stream := NewADXStream(period)
+	series := ADXSeries{
+		ADX:     make([]float64, len(klines)),
+		PlusDI:  make([]float64, len(klines)),
+		MinusDI: make([]float64, len(klines)),
+	}
+	for i, kline := range klines {
+		point := stream.Update(kline)
+		series.ADX[i], series.PlusDI[i], series.MinusDI[i] = point.ADX, point.PlusDI, point.MinusDI
+	}
+	return series
+}
@@ -0,0 +1,58 @@
+package indicator
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"math"
+)
+
+// trueRange is the largest of high-low and the gaps from the previous close
+func trueRange(kline vo.Kline, previousClose float64, hasPrevious bool) float64 {
+	rangeHL := kline.High() - kline.Low()
+	if !hasPrevious {
+		return rangeHL
+	}
+	return math.Max(rangeHL, math.Max(math.Abs(kline.High()-previousClose), math.Abs(kline.Low()-previousClose)))
+}
+
+// ATRStream is Wilder's Average True Range. The first kline's true range is its high-low;
+// the first ATR is the average of the first period true ranges.
+type ATRStream struct {
+	period        int
+	previousClose float64
+	hasPrevious   bool
+	count         int
+	sum           float64
+	value         float64
+}
+
+func NewATRStream(period int) *ATRStream {
+	return &ATRStream{period: period, value: math.NaN()}
+}
+
+// Update adds a kline and returns the current ATR, or NaN until period klines were seen
+func (s *ATRStream) Update(kline vo.Kline) float64 {
+	tr := trueRange(kline, s.previousClose, s.hasPrevious)
+	s.previousClose = kline.Close()
+	s.hasPrevious = true
+	s.count++
+
+	if s.count < s.period {
+		s.sum += tr
+		return s.value
+	}
+	if s.count == s.period {
+		s.value = (s.sum + tr) / float64(s.period)
+		return s.value
+	}
+
+	s.value = (s.value*float64(s.period-1) + tr) / float64(s.period)
+	return s.value
+}
+
+func (s *ATRStream) Value() float64 { return s.value }
+func (s *ATRStream) Ready() bool    { return IsReady(s.value) }
+
+// ATR returns the Average True Range of klines
+func ATR(klines []vo.Kline, period int) []float64 {
+	return mapKlines(klines, NewATRStream(period).Update)
+}
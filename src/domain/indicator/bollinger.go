@@ -0,0 +1,92 @@
+package indicator
+
+import (
+	"math"
+)
+
+// BollingerValue is one point of the Bollinger Bands indicator
+type BollingerValue struct {
+	Upper     float64
+	Middle    float64
+	Lower     float64
+	PercentB  float64 // Position of the value inside the bands: 0 = lower band, 1 = upper band
+	Bandwidth float64 // Distance between the bands as % of the middle band
+}
+
+// BollingerStream computes Bollinger Bands: an SMA middle band with bands multiplier
+// population standard deviations above and below it
+type BollingerStream struct {
+	period     int
+	multiplier float64
+	window     *window
+	sum        float64
+	value      BollingerValue
+}
+
+func NewBollingerStream(period int, multiplier float64) *BollingerStream {
+	nan := math.NaN()
+	return &BollingerStream{
+		period:     period,
+		multiplier: multiplier,
+		window:     newWindow(period),
+		value:      BollingerValue{Upper: nan, Middle: nan, Lower: nan, PercentB: nan, Bandwidth: nan},
+	}
+}
+
+// Update adds a close price and returns the bands, or NaN values until period prices were seen
+func (s *BollingerStream) Update(value float64) BollingerValue {
+	s.sum += value - s.window.push(value)
+	if s.window.len() < s.period {
+		return s.value
+	}
+
+	middle := s.sum / float64(s.period)
+	variance := 0.0
+	s.window.each(func(_ int, v float64) {
+		variance += (v - middle) * (v - middle)
+	})
+	deviation := s.multiplier * math.Sqrt(variance/float64(s.period))
+	upper, lower := middle+deviation, middle-deviation
+
+	percentB := 0.0
+	if upper != lower {
+		percentB = (value - lower) / (upper - lower)
+	}
+	bandwidth := 0.0
+	if middle != 0 {
+		bandwidth = ((upper - lower) / middle) * 100
+	}
+
+	s.value = BollingerValue{Upper: upper, Middle: middle, Lower: lower, PercentB: percentB, Bandwidth: bandwidth}
+	return s.value
+}
+
+func (s *BollingerStream) Value() BollingerValue { return s.value }
+func (s *BollingerStream) Ready() bool           { return IsReady(s.value.Middle) }
+
+// BollingerSeries holds the full-series Bollinger Bands outputs, aligned with the input
+type BollingerSeries struct {
+	Upper     []float64
+	Middle    []float64
+	Lower     []float64
+	PercentB  []float64
+	Bandwidth []float64
+}
+
+// Bollinger returns the Bollinger Bands of values
+func Bollinger(values []float64, period int, multiplier float64) BollingerSeries {
+	stream := NewBollingerStream(period, multiplier)
+	series := BollingerSeries{
+		Upper:     make([]float64, len(values)),
+		Middle:    make([]float64, len(values)),
+		Lower:     make([]float64, len(values)),
+		PercentB:  make([]float64, len(values)),
+		Bandwidth: make([]float64, len(values)),
+	}
+	for i, value := range values {
+		point := stream.Update(value)
+		series.Upper[i], series.Middle[i], series.Lower[i] = point.Upper, point.Middle, point.Lower
+		series.PercentB[i], series.Bandwidth[i] = point.PercentB, point.Bandwidth
+	}
+	return series
+}
@@ -0,0 +1,113 @@
+// Package indicator implements technical indicators shared by the trading strategies.
+//
+// Every indicator comes in two modes:
+//   - full-series functions (SMA, RSI, ...) take the whole history and return one value per
+//     input, with math.NaN() for the warm-up positions where the indicator is not defined yet
+//   - streaming types (SMAStream, RSIStream, ...) are fed one value or kline at a time and keep
+//     only the state they need, for callers that receive data incrementally
+//
+// The full-series functions are implemented on top of the streams, so both modes always agree.
+package indicator
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"math"
+)
+
+// Closes extracts the close prices of the given klines
+func Closes(klines []vo.Kline) []float64 {
+	closes := make([]float64, len(klines))
+	for i, kline := range klines {
+		closes[i] = kline.Close()
+	}
+	return closes
+}
+
+// Last returns the last value of a series, or NaN if the series is empty
+func Last(series []float64) float64 {
+	if len(series) == 0 {
+		return math.NaN()
+	}
+	return series[len(series)-1]
+}
+
+// IsReady reports whether v is a defined indicator value (not a warm-up NaN)
+func IsReady(v float64) bool {
+	return !math.IsNaN(v)
+}
+
+// window is a fixed-size ring buffer of the most recent values
+type window struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+func newWindow(size int) *window {
+	return &window{values: make([]float64, size)}
+}
+
+// push adds a value and returns the value it evicted (0 while the window is filling)
+func (w *window) push(value float64) float64 {
+	evicted := w.values[w.next]
+	w.values[w.next] = value
+	w.next++
+	if w.next == len(w.values) {
+		w.next = 0
+		w.full = true
+	}
+	return evicted
+}
+
+func (w *window) len() int {
+	if w.full {
+		return len(w.values)
+	}
+	return w.next
+}
+
+// each calls fn with the buffered values from oldest to newest
+func (w *window) each(fn func(position int, value float64)) {
+	count := w.len()
+	start := 0
+	if w.full {
+		start = w.next
+	}
+	for i := 0; i < count; i++ {
+		fn(i, w.values[(start+i)%len(w.values)])
+	}
+}
+
+func (w *window) max() float64 {
+	result := math.Inf(-1)
+	w.each(func(_ int, value float64) {
+		result = math.Max(result, value)
+	})
+	return result
+}
+
+func (w *window) min() float64 {
+	result := math.Inf(1)
+	w.each(func(_ int, value float64) {
+		result = math.Min(result, value)
+	})
+	return result
+}
+
+// mapSeries runs a float stream over values and collects one output per input
+func mapSeries(values []float64, update func(float64) float64) []float64 {
+	series := make([]float64, len(values))
+	for i, value := range values {
+		series[i] = update(value)
+	}
+	return series
+}
+
+// mapKlines runs a kline stream over klines and collects one output per input
+func mapKlines(klines []vo.Kline, update func(vo.Kline) float64) []float64 {
+	series := make([]float64, len(klines))
+	for i, kline := range klines {
+		series[i] = update(kline)
+	}
+	return series
+}
@@ -0,0 +1,97 @@
+package indicator
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"math"
+	"testing"
+)
+
+// referenceCloses is the classic Wilder RSI example series (StockCharts), reused for every indicator.
+// Reference values below were computed independently with the textbook formulas.
+var referenceCloses = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89,
+	46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64, 46.21, 46.25,
+	45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57, 43.42, 42.66, 43.13,
+}
+
+// referenceKlines derives deterministic OHLCV klines from referenceCloses: each kline opens at the
+// previous close and the wicks/volume follow a small repeating pattern
+func referenceKlines(t *testing.T) []vo.Kline {
+	t.Helper()
+	klines := make([]vo.Kline, len(referenceCloses))
+	for i, close := range referenceCloses {
+		open := close
+		if i > 0 {
+			open = referenceCloses[i-1]
+		}
+		high := math.Max(open, close) + 0.3 + 0.05*float64(i%4)
+		low := math.Min(open, close) - 0.25 - 0.05*float64(i%3)
+		volume := 1000.0 + 100*float64(i%5)
+		kline, err := vo.NewKline(open, close, high, low, volume, int64(i+1)*60000)
+		if err != nil {
+			t.Fatalf("invalid reference kline %d: %v", i, err)
+		}
+		klines[i] = kline
+	}
+	return klines
+}
+
+func assertClose(t *testing.T, name string, expected, actual float64) {
+	t.Helper()
+	if math.Abs(expected-actual) > 1e-6 {
+		t.Errorf("%s: expected %.6f, got %.6f", name, expected, actual)
+	}
+}
+
+// assertTail compares the last len(expected) values of series
+func assertTail(t *testing.T, name string, expected, series []float64) {
+	t.Helper()
+	offset := len(series) - len(expected)
+	for i, value := range expected {
+		assertClose(t, name, value, series[offset+i])
+	}
+}
+
+// assertWarmup checks the first ready index of a series: NaN before it, defined from it on
+func assertWarmup(t *testing.T, name string, series []float64, firstReady int) {
+	t.Helper()
+	for i, value := range series {
+		if i < firstReady && IsReady(value) {
+			t.Fatalf("%s: expected NaN at %d during warm-up, got %.6f", name, i, value)
+		}
+		if i >= firstReady && !IsReady(value) {
+			t.Fatalf("%s: expected value at %d, got NaN", name, i)
+		}
+	}
+}
+
+func TestLast(t *testing.T) {
+	if !math.IsNaN(Last(nil)) {
+		t.Error("expected NaN for empty series")
+	}
+	if Last([]float64{1, 2, 3}) != 3 {
+		t.Error("expected last value 3")
+	}
+}
+
+func TestWindow_KeepsMostRecentValues(t *testing.T) {
+	w := newWindow(3)
+	for _, value := range []float64{1, 5, 2, 4} {
+		w.push(value)
+	}
+
+	var values []float64
+	w.each(func(_ int, value float64) {
+		values = append(values, value)
+	})
+
+	expected := []float64{5, 2, 4}
+	for i := range expected {
+		if values[i] != expected[i] {
+			t.Fatalf("expected %v oldest to newest, got %v", expected, values)
+		}
+	}
+	if w.max() != 5 || w.min() != 2 {
+		t.Errorf("expected max 5 / min 2, got %.0f / %.0f", w.max(), w.min())
+	}
+}
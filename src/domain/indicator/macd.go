@@ -0,0 +1,70 @@
+package indicator
+
+import (
+	"math"
+)
+
+// MACDValue is one point of the MACD indicator
+type MACDValue struct {
+	MACD      float64
+	Signal    float64
+	Histogram float64
+}
+
+// MACDStream is the Moving Average Convergence Divergence: the fast EMA minus the slow EMA,
+// an EMA of that difference as signal line, and their difference as histogram
+type MACDStream struct {
+	fast   *EMAStream
+	slow   *EMAStream
+	signal *EMAStream
+	value  MACDValue
+}
+
+func NewMACDStream(fastPeriod, slowPeriod, signalPeriod int) *MACDStream {
+	return &MACDStream{
+		fast:   NewEMAStream(fastPeriod),
+		slow:   NewEMAStream(slowPeriod),
+		signal: NewEMAStream(signalPeriod),
+		value:  MACDValue{MACD: math.NaN(), Signal: math.NaN(), Histogram: math.NaN()},
+	}
+}
+
+// Update adds a close price. MACD is defined once the slow EMA is; Signal and Histogram
+// need signalPeriod more MACD values.
+func (s *MACDStream) Update(value float64) MACDValue {
+	fast := s.fast.Update(value)
+	slow := s.slow.Update(value)
+	if !IsReady(fast) || !IsReady(slow) {
+		return s.value
+	}
+
+	macd := fast - slow
+	signal := s.signal.Update(macd)
+	s.value = MACDValue{MACD: macd, Signal: signal, Histogram: macd - signal}
+	return s.value
+}
+
+func (s *MACDStream) Value() MACDValue { return s.value }
+func (s *MACDStream) Ready() bool      { return IsReady(s.value.Histogram) }
+
+// MACDSeries holds the full-series MACD outputs, aligned with the input
+type MACDSeries struct {
+	MACD      []float64
+	Signal    []float64
+	Histogram []float64
+}
+
+// MACD returns the MACD line, signal line and histogram of values
+func MACD(values []float64, fastPeriod, slowPeriod, signalPeriod int) MACDSeries {
+	stream := NewMACDStream(fastPeriod, slowPeriod, signalPeriod)
+	series := MACDSeries{
+		MACD:      make([]float64, len(values)),
+		Signal:    make([]float64, len(values)),
+		Histogram: make([]float64, len(values)),
+	}
+	for i, value := range values {
+		point := stream.Update(value)
+		series.MACD[i], series.Signal[i], series.Histogram[i] = point.MACD, point.Signal, point.Histogram
+	}
+	return series
+}
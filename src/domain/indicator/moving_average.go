@@ -0,0 +1,109 @@
+package indicator
+
+import (
+	"math"
+)
+
+// SMAStream is a simple moving average over the last period values
+type SMAStream struct {
+	period int
+	window *window
+	sum    float64
+	value  float64
+}
+
+func NewSMAStream(period int) *SMAStream {
+	return &SMAStream{period: period, window: newWindow(period), value: math.NaN()}
+}
+
+// Update adds a value and returns the current average, or NaN until period values were seen
+func (s *SMAStream) Update(value float64) float64 {
+	s.sum += value - s.window.push(value)
+	if s.window.len() == s.period {
+		s.value = s.sum / float64(s.period)
+	}
+	return s.value
+}
+
+func (s *SMAStream) Value() float64 { return s.value }
+func (s *SMAStream) Ready() bool    { return IsReady(s.value) }
+
+// SMA returns the simple moving average of values
+func SMA(values []float64, period int) []float64 {
+	return mapSeries(values, NewSMAStream(period).Update)
+}
+
+// EMAStream is an exponential moving average seeded with the SMA of the first period values
+type EMAStream struct {
+	period     int
+	multiplier float64
+	seed       *SMAStream
+	value      float64
+}
+
+func NewEMAStream(period int) *EMAStream {
+	return &EMAStream{
+		period:     period,
+		multiplier: 2.0 / float64(period+1),
+		seed:       NewSMAStream(period),
+		value:      math.NaN(),
+	}
+}
+
+// Update adds a value and returns the current EMA, or NaN until period values were seen
+func (s *EMAStream) Update(value float64) float64 {
+	if !s.Ready() {
+		s.value = s.seed.Update(value)
+		return s.value
+	}
+	s.value = (value-s.value)*s.multiplier + s.value
+	return s.value
+}
+
+func (s *EMAStream) Value() float64 { return s.value }
+func (s *EMAStream) Ready() bool    { return IsReady(s.value) }
+
+// EMA returns the exponential moving average of values
+func EMA(values []float64, period int) []float64 {
+	return mapSeries(values, NewEMAStream(period).Update)
+}
+
+// WMAStream is a linearly weighted moving average: the newest value weighs period, the oldest 1
+type WMAStream struct {
+	period  int
+	window  *window
+	divisor float64
+	value   float64
+}
+
+func NewWMAStream(period int) *WMAStream {
+	return &WMAStream{
+		period:  period,
+		window:  newWindow(period),
+		divisor: float64(period*(period+1)) / 2,
+		value:   math.NaN(),
+	}
+}
+
+// Update adds a value and returns the current WMA, or NaN until period values were seen
+func (s *WMAStream) Update(value float64) float64 {
+	s.window.push(value)
+	if s.window.len() < s.period {
+		return s.value
+	}
+
+	weighted := 0.0
+	s.window.each(func(position int, v float64) {
+		weighted += v * float64(position+1)
+	})
+	s.value = weighted / s.divisor
+	return s.value
+}
+
+func (s *WMAStream) Value() float64 { return s.value }
+func (s *WMAStream) Ready() bool    { return IsReady(s.value) }
+
+// WMA returns the weighted moving average of values
+func WMA(values []float64, period int) []float64 {
+	return mapSeries(values, NewWMAStream(period).Update)
+}
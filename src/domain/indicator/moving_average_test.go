@@ -0,0 +1,47 @@
+package indicator
+
+import (
+	"testing"
+)
+
+func TestSMA_ReferenceValues(t *testing.T) {
+	series := SMA(referenceCloses, 5)
+
+	assertWarmup(t, "SMA", series, 4)
+	assertTail(t, "SMA", []float64{44.084, 43.81, 43.6}, series)
+}
+
+func TestEMA_ReferenceValues(t *testing.T) {
+	series := EMA(referenceCloses, 5)
+
+	assertWarmup(t, "EMA", series, 4)
+	assertClose(t, "EMA seed", SMA(referenceCloses, 5)[4], series[4])
+	assertTail(t, "EMA", []float64{44.222427, 43.701618, 43.511078}, series)
+}
+
+func TestWMA_ReferenceValues(t *testing.T) {
+	series := WMA(referenceCloses, 5)
+
+	assertWarmup(t, "WMA", series, 4)
+	assertTail(t, "WMA", []float64{44.028667, 43.554, 43.327333}, series)
+}
+
+func TestMovingAverages_StreamMatchesSeries(t *testing.T) {
+	sma, ema, wma := NewSMAStream(7), NewEMAStream(7), NewWMAStream(7)
+	smaSeries, emaSeries, wmaSeries := SMA(referenceCloses, 7), EMA(referenceCloses, 7), WMA(referenceCloses, 7)
+
+	for i, value := range referenceCloses {
+		sma.Update(value)
+		ema.Update(value)
+		wma.Update(value)
+		if sma.Ready() != IsReady(smaSeries[i]) || (sma.Ready() && sma.Value() != smaSeries[i]) {
+			t.Fatalf("SMA stream diverged at %d", i)
+		}
+		if ema.Ready() != IsReady(emaSeries[i]) || (ema.Ready() && ema.Value() != emaSeries[i]) {
+			t.Fatalf("EMA stream diverged at %d", i)
+		}
+		if wma.Ready() != IsReady(wmaSeries[i]) || (wma.Ready() && wma.Value() != wmaSeries[i]) {
+			t.Fatalf("WMA stream diverged at %d", i)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package indicator
+
+import (
+	"testing"
+)
+
+func TestRSI_ReferenceValues(t *testing.T) {
+	series := RSI(referenceCloses, 14)
+
+	assertWarmup(t, "RSI", series, 14)
+	// First values of the StockCharts example
+	assertClose(t, "RSI[14]", 70.464135, series[14])
+	assertClose(t, "RSI[15]", 66.249619, series[15])
+	assertClose(t, "RSI[16]", 66.480942, series[16])
+	assertTail(t, "RSI", []float64{37.322778, 33.090483, 37.788772}, series)
+}
+
+func TestRSI_EdgeCases(t *testing.T) {
+	if value := Last(RSI([]float64{1, 2, 3, 4}, 3)); value != 100 {
+		t.Errorf("expected 100 when prices only rise, got %.2f", value)
+	}
+	if value := Last(RSI([]float64{4, 3, 2, 1}, 3)); value != 0 {
+		t.Errorf("expected 0 when prices only fall, got %.2f", value)
+	}
+	if value := Last(RSI([]float64{5, 5, 5, 5}, 3)); value != 50 {
+		t.Errorf("expected 50 without price changes, got %.2f", value)
+	}
+}
+
+func TestMACD_ReferenceValues(t *testing.T) {
+	series := MACD(referenceCloses, 5, 10, 4)
+
+	assertWarmup(t, "MACD line", series.MACD, 9)
+	assertWarmup(t, "MACD signal", series.Signal, 12)
+	assertTail(t, "MACD line", []float64{-0.489860, -0.637526, -0.608221}, series.MACD)
+	assertTail(t, "MACD signal", []float64{-0.401991, -0.496205, -0.541011}, series.Signal)
+	assertClose(t, "MACD histogram", -0.608221-(-0.541011), Last(series.Histogram))
+}
+
+func TestBollinger_ReferenceValues(t *testing.T) {
+	series := Bollinger(referenceCloses, 20, 2)
+
+	assertWarmup(t, "Bollinger", series.Middle, 19)
+	assertClose(t, "upper", 47.620150, Last(series.Upper))
+	assertClose(t, "middle", 45.241, Last(series.Middle))
+	assertClose(t, "lower", 42.861850, Last(series.Lower))
+	assertClose(t, "%B", 0.056354, Last(series.PercentB))
+	assertClose(t, "bandwidth", 10.517673, Last(series.Bandwidth))
+}
+
+func TestStochastic_ReferenceValues(t *testing.T) {
+	series := Stochastic(referenceKlines(t), 14, 3)
+
+	assertWarmup(t, "%K", series.K, 13)
+	assertWarmup(t, "%D", series.D, 15)
+	assertTail(t, "%K", []float64{6.702413, 6.607930, 17.864924}, series.K)
+	assertTail(t, "%D", []float64{17.037450, 13.650031, 10.391755}, series.D)
+}
+
+func TestMACDStream_MatchesSeries(t *testing.T) {
+	stream := NewMACDStream(5, 10, 4)
+	series := MACD(referenceCloses, 5, 10, 4)
+
+	for i, value := range referenceCloses {
+		point := stream.Update(value)
+		if stream.Ready() != IsReady(series.Histogram[i]) || (stream.Ready() && point.Histogram != series.Histogram[i]) {
+			t.Fatalf("MACD stream diverged at %d", i)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package indicator
+
+import (
+	"math"
+)
+
+// RSIStream is Wilder's Relative Strength Index. The first average gain/loss is the simple
+// average of the first period changes; later ones use Wilder's smoothing.
+type RSIStream struct {
+	period      int
+	previous    float64
+	hasPrevious bool
+	changes     int
+	avgGain     float64
+	avgLoss     float64
+	value       float64
+}
+
+func NewRSIStream(period int) *RSIStream {
+	return &RSIStream{period: period, value: math.NaN()}
+}
+
+// Update adds a close price and returns the current RSI, or NaN until period changes were seen
+func (s *RSIStream) Update(value float64) float64 {
+	if !s.hasPrevious {
+		s.previous = value
+		s.hasPrevious = true
+		return s.value
+	}
+
+	change := value - s.previous
+	s.previous = value
+	s.changes++
+
+	gain, loss := 0.0, 0.0
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	period := float64(s.period)
+	if s.changes <= s.period {
+		s.avgGain += gain / period
+		s.avgLoss += loss / period
+		if s.changes < s.period {
+			return s.value
+		}
+	} else {
+		// Wilder's smoothing formula: new_avg = (prev_avg * (period-1) + new_value) / period
+		s.avgGain = (s.avgGain*(period-1) + gain) / period
+		s.avgLoss = (s.avgLoss*(period-1) + loss) / period
+	}
+
+	s.value = rsiValue(s.avgGain, s.avgLoss)
+	return s.value
+}
+
+func (s *RSIStream) Value() float64 { return s.value }
+func (s *RSIStream) Ready() bool    { return IsReady(s.value) }
+
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgGain == 0 && avgLoss == 0 {
+		return 50.0 // No movement at all: neither side dominates
+	}
+	if avgLoss == 0 {
+		return 100.0
+	}
+
+	rs := avgGain / avgLoss
+	return math.Max(0, math.Min(100, 100.0-(100.0/(1.0+rs))))
+}
+
+// RSI returns the Relative Strength Index of values
+func RSI(values []float64, period int) []float64 {
+	return mapSeries(values, NewRSIStream(period).Update)
+}
@@ -0,0 +1,69 @@
+package indicator
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"math"
+)
+
+// StochasticValue is one point of the Stochastic Oscillator
+type StochasticValue struct {
+	K float64 // Close position in the high-low range of the last kPeriod klines, 0-100
+	D float64 // SMA of K over dPeriod values
+}
+
+// StochasticStream is the Stochastic Oscillator %K/%D
+type StochasticStream struct {
+	kPeriod int
+	highs   *window
+	lows    *window
+	d       *SMAStream
+	value   StochasticValue
+}
+
+func NewStochasticStream(kPeriod, dPeriod int) *StochasticStream {
+	return &StochasticStream{
+		kPeriod: kPeriod,
+		highs:   newWindow(kPeriod),
+		lows:    newWindow(kPeriod),
+		d:       NewSMAStream(dPeriod),
+		value:   StochasticValue{K: math.NaN(), D: math.NaN()},
+	}
+}
+
+// Update adds a kline. K is defined after kPeriod klines, D after dPeriod K values.
+func (s *StochasticStream) Update(kline vo.Kline) StochasticValue {
+	s.highs.push(kline.High())
+	s.lows.push(kline.Low())
+	if s.highs.len() < s.kPeriod {
+		return s.value
+	}
+
+	highest, lowest := s.highs.max(), s.lows.min()
+	k := 50.0 // Flat range: the close is neither near the high nor the low
+	if highest != lowest {
+		k = 100 * (kline.Close() - lowest) / (highest - lowest)
+	}
+
+	s.value = StochasticValue{K: k, D: s.d.Update(k)}
+	return s.value
+}
+
+func (s *StochasticStream) Value() StochasticValue { return s.value }
+func (s *StochasticStream) Ready() bool            { return IsReady(s.value.D) }
+
+// StochasticSeries holds the full-series Stochastic outputs, aligned with the input
+type StochasticSeries struct {
+	K []float64
+	D []float64
+}
+
+// Stochastic returns the Stochastic Oscillator of klines
+func Stochastic(klines []vo.Kline, kPeriod, dPeriod int) StochasticSeries {
+	stream := NewStochasticStream(kPeriod, dPeriod)
+	series := StochasticSeries{K: make([]float64, len(klines)), D: make([]float64, len(klines))}
+	for i, kline := range klines {
+		point := stream.Update(kline)
+		series.K[i], series.D[i] = point.K, point.D
+	}
+	return series
+}
@@ -0,0 +1,66 @@
+package indicator
+
+import (
+	"testing"
+)
+
+func TestATR_ReferenceValues(t *testing.T) {
+	series := ATR(referenceKlines(t), 14)
+
+	assertWarmup(t, "ATR", series, 13)
+	assertClose(t, "ATR[13]", 1.002857, series[13])
+	assertClose(t, "ATR[14]", 0.984796, series[14])
+	assertClose(t, "ATR", 1.146481, Last(series))
+}
+
+func TestADX_ReferenceValues(t *testing.T) {
+	series := ADX(referenceKlines(t), 7)
+
+	assertWarmup(t, "+DI", series.PlusDI, 7)
+	assertWarmup(t, "ADX", series.ADX, 13)
+	assertTail(t, "ADX", []float64{41.927704, 45.393912, 48.455698}, series.ADX)
+	assertClose(t, "+DI", 5.141223, Last(series.PlusDI))
+	assertClose(t, "-DI", 25.854664, Last(series.MinusDI))
+}
+
+func TestOBV_ReferenceValues(t *testing.T) {
+	series := OBV(referenceKlines(t))
+
+	if series[0] != 0 {
+		t.Errorf("expected OBV to start at 0, got %.0f", series[0])
+	}
+	assertTail(t, "OBV", []float64{6600, 5500, 6700}, series)
+}
+
+func TestVWAP_ReferenceValues(t *testing.T) {
+	klines := referenceKlines(t)
+
+	cumulative := VWAP(klines, 0)
+	assertWarmup(t, "cumulative VWAP", cumulative, 0)
+	assertTail(t, "cumulative VWAP", []float64{45.276929, 45.206081}, cumulative)
+
+	rolling := VWAP(klines, 10)
+	assertWarmup(t, "rolling VWAP", rolling, 9)
+	assertTail(t, "rolling VWAP", []float64{44.828444, 44.531778}, rolling)
+}
+
+func TestKlineStreams_MatchSeries(t *testing.T) {
+	klines := referenceKlines(t)
+	atr, adx, stochastic := NewATRStream(14), NewADXStream(7), NewStochasticStream(14, 3)
+	atrSeries, adxSeries, stochasticSeries := ATR(klines, 14), ADX(klines, 7), Stochastic(klines, 14, 3)
+
+	for i, kline := range klines {
+		if value := atr.Update(kline); atr.Ready() && value != atrSeries[i] {
+			t.Fatalf("ATR stream diverged at %d", i)
+		}
+		if value := adx.Update(kline); adx.Ready() && value.ADX != adxSeries.ADX[i] {
+			t.Fatalf("ADX stream diverged at %d", i)
+		}
+		if value := stochastic.Update(kline); stochastic.Ready() && value.D != stochasticSeries.D[i] {
+			t.Fatalf("Stochastic stream diverged at %d", i)
+		}
+	}
+	if !atr.Ready() || !adx.Ready() || !stochastic.Ready() {
+		t.Error("expected all streams to be ready after the reference series")
+	}
+}
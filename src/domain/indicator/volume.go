@@ -0,0 +1,96 @@
+package indicator
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"math"
+)
+
+// OBVStream is the On-Balance Volume: volume is added on up closes and subtracted on down closes.
+// It starts at 0 on the first kline.
+type OBVStream struct {
+	previousClose float64
+	hasPrevious   bool
+	value         float64
+}
+
+func NewOBVStream() *OBVStream {
+	return &OBVStream{}
+}
+
+// Update adds a kline and returns the running OBV
+func (s *OBVStream) Update(kline vo.Kline) float64 {
+	if s.hasPrevious {
+		if kline.Close() > s.previousClose {
+			s.value += kline.Volume()
+		} else if kline.Close() < s.previousClose {
+			s.value -= kline.Volume()
+		}
+	}
+	s.previousClose = kline.Close()
+	s.hasPrevious = true
+	return s.value
+}
+
+func (s *OBVStream) Value() float64 { return s.value }
+func (s *OBVStream) Ready() bool    { return s.hasPrevious }
+
+// OBV returns the On-Balance Volume of klines
+func OBV(klines []vo.Kline) []float64 {
+	return mapKlines(klines, NewOBVStream().Update)
+}
+
+// VWAPStream is the Volume Weighted Average Price of the typical price (high+low+close)/3.
+// With period > 0 it covers the last period klines; with period <= 0 it is cumulative
+// since the first kline fed to the stream.
+type VWAPStream struct {
+	period       int
+	priceVolumes *window
+	volumes      *window
+	sumPV        float64
+	sumVolume    float64
+	count        int
+	value        float64
+}
+
+func NewVWAPStream(period int) *VWAPStream {
+	stream := &VWAPStream{period: period, value: math.NaN()}
+	if period > 0 {
+		stream.priceVolumes = newWindow(period)
+		stream.volumes = newWindow(period)
+	}
+	return stream
+}
+
+// Update adds a kline and returns the current VWAP, or NaN until the window is full
+// (or while no volume was traded)
+func (s *VWAPStream) Update(kline vo.Kline) float64 {
+	typical := (kline.High() + kline.Low() + kline.Close()) / 3
+	priceVolume := typical * kline.Volume()
+	s.count++
+
+	if s.period > 0 {
+		s.sumPV += priceVolume - s.priceVolumes.push(priceVolume)
+		s.sumVolume += kline.Volume() - s.volumes.push(kline.Volume())
+		if s.count < s.period {
+			return s.value
+		}
+	} else {
+		s.sumPV += priceVolume
+		s.sumVolume += kline.Volume()
+	}
+
+	if s.sumVolume == 0 {
+		s.value = math.NaN()
+		return s.value
+	}
+	s.value = s.sumPV / s.sumVolume
+	return s.value
+}
+
+func (s *VWAPStream) Value() float64 { return s.value }
+func (s *VWAPStream) Ready() bool    { return IsReady(s.value) }
+
+// VWAP returns the Volume Weighted Average Price of klines (period <= 0 for cumulative)
+func VWAP(klines []vo.Kline, period int) []float64 {
+	return mapKlines(klines, NewVWAPStream(period).Update)
+}
@@ -1,8 +1,8 @@
 package service
 
 import (
+	"crypgo-machine/src/domain/exchange"
 	"crypgo-machine/src/domain/vo"
-	"crypgo-machine/src/infra/external"
 	"fmt"
 )
 
@@ -17,15 +17,15 @@ type OrderValidationResult struct {
 	SymbolFilters     *vo.SymbolFilter
 }
 
-// OrderValidatorService validates orders against Binance symbol constraints
+// OrderValidatorService validates orders against the symbol rules of the exchange
 type OrderValidatorService struct {
-	exchangeInfoService *external.ExchangeInfoService
+	symbolRules *exchange.SymbolRulesCache
 }
 
 // NewOrderValidatorService creates a new order validator
-func NewOrderValidatorService(exchangeInfoService *external.ExchangeInfoService) *OrderValidatorService {
+func NewOrderValidatorService(symbolRules *exchange.SymbolRulesCache) *OrderValidatorService {
 	return &OrderValidatorService{
-		exchangeInfoService: exchangeInfoService,
+		symbolRules: symbolRules,
 	}
 }
 
@@ -39,7 +39,7 @@ func (s *OrderValidatorService) ValidateOrder(symbol string, quantity, price flo
 	}
 
 	// Get symbol filters from exchange info
-	symbolFilters, err := s.exchangeInfoService.GetSymbolFilters(symbol)
+	symbolFilters, err := s.symbolRules.GetSymbolFilters(symbol)
 	if err != nil {
 		result.ValidationErrors = append(result.ValidationErrors, fmt.Sprintf("Failed to get exchange info for %s: %v", symbol, err))
 		result.IsValid = false
@@ -108,6 +108,25 @@ func (s *OrderValidatorService) ValidateOrder(symbol string, quantity, price flo
 	return result, nil
 }
 
+// AdjustLimitPrice rounds a limit order price to the symbol's tick size, down for buys and up for sells,
+// and returns it formatted for the exchange
+func (s *OrderValidatorService) AdjustLimitPrice(symbol string, price float64, isBuy bool) (float64, string, error) {
+	symbolFilters, err := s.symbolRules.GetSymbolFilters(symbol)
+	if err != nil {
+		return price, "", fmt.Errorf("failed to get exchange info for %s: %v", symbol, err)
+	}
+	domainFilter, err := s.convertToSymbolFilter(symbolFilters)
+	if err != nil {
+		return price, "", fmt.Errorf("failed to process filters for %s: %v", symbol, err)
+	}
+
+	adjustedPrice := domainFilter.AdjustPriceToTickSize(price, !isBuy)
+	if err := domainFilter.ValidatePrice(adjustedPrice); err != nil {
+		return price, "", err
+	}
+	return adjustedPrice, domainFilter.FormatPriceString(adjustedPrice), nil
+}
+
 // ValidateAndAdjustQuantity performs validation and returns the best quantity to use
 func (s *OrderValidatorService) ValidateAndAdjustQuantity(symbol string, quantity, price float64) (float64, string, error) {
 	result, err := s.ValidateOrder(symbol, quantity, price)
@@ -123,8 +142,8 @@ func (s *OrderValidatorService) ValidateAndAdjustQuantity(symbol string, quantit
 	return result.AdjustedQuantity, result.FormattedQuantity, nil
 }
 
-// convertToSymbolFilter converts external symbol filters to domain value object
-func (s *OrderValidatorService) convertToSymbolFilter(filters *external.SymbolFilters) (*vo.SymbolFilter, error) {
+// convertToSymbolFilter converts the cached symbol filters to domain value object
+func (s *OrderValidatorService) convertToSymbolFilter(filters *exchange.SymbolFilters) (*vo.SymbolFilter, error) {
 	if filters == nil {
 		return nil, fmt.Errorf("symbol filters is nil")
 	}
@@ -160,7 +179,7 @@ func (s *OrderValidatorService) convertToSymbolFilter(filters *external.SymbolFi
 
 // GetSymbolInfo returns symbol information for debugging
 func (s *OrderValidatorService) GetSymbolInfo(symbol string) (map[string]interface{}, error) {
-	filters, err := s.exchangeInfoService.GetSymbolFilters(symbol)
+	filters, err := s.symbolRules.GetSymbolFilters(symbol)
 	if err != nil {
 		return nil, err
 	}
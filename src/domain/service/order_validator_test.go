@@ -1,15 +1,16 @@
 package service
 
 import (
+	"crypgo-machine/src/domain/exchange"
 	"crypgo-machine/src/infra/external"
 	"testing"
 )
 
 func TestOrderValidatorService_ValidateOrder(t *testing.T) {
 	// Setup
-	fakeClient := external.NewBinanceClientFake()
-	exchangeInfoService := external.NewExchangeInfoService(fakeClient)
-	validator := NewOrderValidatorService(exchangeInfoService)
+	fakeClient := external.NewFakeExchange()
+	symbolRules := exchange.NewSymbolRulesCache(fakeClient)
+	validator := NewOrderValidatorService(symbolRules)
 
 	testCases := []struct {
 		name                string
@@ -122,9 +123,9 @@ func TestOrderValidatorService_ValidateOrder(t *testing.T) {
 
 func TestOrderValidatorService_ValidateAndAdjustQuantity(t *testing.T) {
 	// Setup
-	fakeClient := external.NewBinanceClientFake()
-	exchangeInfoService := external.NewExchangeInfoService(fakeClient)
-	validator := NewOrderValidatorService(exchangeInfoService)
+	fakeClient := external.NewFakeExchange()
+	symbolRules := exchange.NewSymbolRulesCache(fakeClient)
+	validator := NewOrderValidatorService(symbolRules)
 
 	testCases := []struct {
 		name            string
@@ -198,9 +199,9 @@ func TestOrderValidatorService_ValidateAndAdjustQuantity(t *testing.T) {
 
 func TestOrderValidatorService_ValidateOrderBeforePlacement(t *testing.T) {
 	// Setup
-	fakeClient := external.NewBinanceClientFake()
-	exchangeInfoService := external.NewExchangeInfoService(fakeClient)
-	validator := NewOrderValidatorService(exchangeInfoService)
+	fakeClient := external.NewFakeExchange()
+	symbolRules := exchange.NewSymbolRulesCache(fakeClient)
+	validator := NewOrderValidatorService(symbolRules)
 
 	testCases := []struct {
 		name              string
@@ -285,9 +286,9 @@ func TestOrderValidatorService_ValidateOrderBeforePlacement(t *testing.T) {
 
 func TestOrderValidatorService_GetSymbolInfo(t *testing.T) {
 	// Setup
-	fakeClient := external.NewBinanceClientFake()
-	exchangeInfoService := external.NewExchangeInfoService(fakeClient)
-	validator := NewOrderValidatorService(exchangeInfoService)
+	fakeClient := external.NewFakeExchange()
+	symbolRules := exchange.NewSymbolRulesCache(fakeClient)
+	validator := NewOrderValidatorService(symbolRules)
 
 	symbols := []string{"XRPBRL", "BTCUSDT", "ETHUSDT", "SOLBRL"}
 
@@ -334,9 +335,9 @@ func TestOrderValidatorService_GetSymbolInfo(t *testing.T) {
 // Integration test simulating the exact scenario from production logs
 func TestOrderValidatorService_ProductionScenario(t *testing.T) {
 	// Setup
-	fakeClient := external.NewBinanceClientFake()
-	exchangeInfoService := external.NewExchangeInfoService(fakeClient)
-	validator := NewOrderValidatorService(exchangeInfoService)
+	fakeClient := external.NewFakeExchange()
+	symbolRules := exchange.NewSymbolRulesCache(fakeClient)
+	validator := NewOrderValidatorService(symbolRules)
 
 	// Simulate the exact scenario from production logs
 	symbol := "XRPBRL"
@@ -2,7 +2,6 @@ package service
 
 import (
 	"crypgo-machine/src/domain/entity"
-	"crypgo-machine/src/domain/vo"
 	"fmt"
 )
 
@@ -19,70 +18,57 @@ type RSIParams struct {
 	StoplossThreshold   float64
 }
 
+// typedParamsNames names the params structs of the strategies built before the strategy registry
+var typedParamsNames = map[string]string{
+	"MovingAverage": "MovingAverageParams",
+	"RSI":           "RSIParams",
+}
+
+// NewTradeStrategyFactory builds a strategy through the strategy registry.
+// Params may be a raw map (as decoded from JSON) or, for the strategies of typedParamsNames, their params struct.
 func NewTradeStrategyFactory(strategyType string, Params interface{}) (entity.TradingStrategy, error) {
-	var strategy entity.TradingStrategy
+	if !entity.IsStrategyRegistered(strategyType) {
+		return nil, fmt.Errorf("unknown or invalid strategy: %s", strategyType)
+	}
 
-	switch strategyType {
-	case "MovingAverage":
-		params, ok := Params.(MovingAverageParams)
-		if !ok {
-			return nil, fmt.Errorf("params must be MovingAverageParams for MovingAverage strategy")
-		}
-		if params.FastWindow <= 0 || params.SlowWindow <= 0 {
-			return nil, fmt.Errorf("missing or invalid fields for MovingAverage: FastWindow and SlowWindow must be > 0")
-		}
-		
-		minimumSpread, _ := vo.NewMinimumSpread(0.1)
-		
-		// Create with stoploss if provided, otherwise use default constructor
-		if params.StoplossThreshold > 0 {
-			strategy = entity.NewMovingAverageStrategyWithStoploss(params.FastWindow, params.SlowWindow, minimumSpread, params.StoplossThreshold)
-		} else {
-			strategy = entity.NewMovingAverageStrategy(params.FastWindow, params.SlowWindow)
-		}
+	params, err := toRawStrategyParams(strategyType, Params)
+	if err != nil {
+		return nil, err
+	}
 
-	case "RSI":
-		params, ok := Params.(RSIParams)
-		if !ok {
-			return nil, fmt.Errorf("params must be RSIParams for RSI strategy")
-		}
-		if params.Period <= 0 {
-			return nil, fmt.Errorf("missing or invalid fields for RSI: Period must be > 0")
-		}
-		if params.OversoldThreshold <= 0 || params.OversoldThreshold >= 100 {
-			return nil, fmt.Errorf("OversoldThreshold must be between 0 and 100")
-		}
-		if params.OverboughtThreshold <= 0 || params.OverboughtThreshold >= 100 {
-			return nil, fmt.Errorf("OverboughtThreshold must be between 0 and 100")
-		}
-		if params.OversoldThreshold >= params.OverboughtThreshold {
-			return nil, fmt.Errorf("OversoldThreshold must be less than OverboughtThreshold")
-		}
-		
-		// Use default thresholds if not provided
-		oversold := params.OversoldThreshold
-		overbought := params.OverboughtThreshold
-		if oversold == 0 {
-			oversold = 30.0
-		}
-		if overbought == 0 {
-			overbought = 70.0
+	return entity.NewStrategyFromParams(strategyType, params)
+}
+
+func toRawStrategyParams(strategyType string, Params interface{}) (map[string]interface{}, error) {
+	switch params := Params.(type) {
+	case map[string]interface{}:
+		return params, nil
+	case entity.StrategyParams:
+		return params, nil
+	case nil:
+		return map[string]interface{}{}, nil
+	case MovingAverageParams:
+		if strategyType != "MovingAverage" {
+			break
 		}
-		
-		minimumSpread, _ := vo.NewMinimumSpread(0.1)
-		
-		// Create with stoploss if provided, otherwise use custom thresholds or defaults
-		if params.StoplossThreshold > 0 {
-			strategy = entity.NewRSIStrategyWithStoploss(params.Period, oversold, overbought, minimumSpread, params.StoplossThreshold)
-		} else if oversold != 30.0 || overbought != 70.0 {
-			strategy = entity.NewRSIStrategyWithCustomThresholds(params.Period, oversold, overbought, minimumSpread)
-		} else {
-			strategy = entity.NewRSIStrategy(params.Period)
+		return map[string]interface{}{
+			"FastWindow":        params.FastWindow,
+			"SlowWindow":        params.SlowWindow,
+			"StoplossThreshold": params.StoplossThreshold,
+		}, nil
+	case RSIParams:
+		if strategyType != "RSI" {
+			break
 		}
-
-	default:
-		return nil, fmt.Errorf("unknown or invalid strategy: %s", strategyType)
+		return map[string]interface{}{
+			"Period":              params.Period,
+			"OversoldThreshold":   params.OversoldThreshold,
+			"OverboughtThreshold": params.OverboughtThreshold,
+			"StoplossThreshold":   params.StoplossThreshold,
+		}, nil
 	}
-
-	return strategy, nil
+	if typedParamsName, ok := typedParamsNames[strategyType]; ok {
+		return nil, fmt.Errorf("params must be %s for %s strategy", typedParamsName, strategyType)
+	}
+	return nil, fmt.Errorf("params must be a parameter map for %s strategy", strategyType)
 }
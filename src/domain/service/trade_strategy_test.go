@@ -163,4 +163,42 @@ func TestNewTradeStrategyFactory_UnknownStrategy(t *testing.T) {
 	if err.Error() != expectedMessage {
 		t.Errorf("Expected error message '%s', got: %s", expectedMessage, err.Error())
 	}
-}
\ No newline at end of file
+}
+func TestNewTradeStrategyFactory_MACD_Success(t *testing.T) {
+	params := map[string]interface{}{
+		"FastPeriod":            12,
+		"SlowPeriod":            26,
+		"SignalPeriod":          9,
+		"HistogramConfirmation": true,
+	}
+
+	strategy, err := NewTradeStrategyFactory("MACD", params)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	strategyParams := strategy.GetParams()
+	if strategyParams["SignalPeriod"] != 9 || strategyParams["HistogramConfirmation"] != true {
+		t.Errorf("Expected SignalPeriod 9 with confirmation, got: %v", strategyParams)
+	}
+}
+
+func TestNewTradeStrategyFactory_MACD_FastNotBelowSlow(t *testing.T) {
+	params := map[string]interface{}{
+		"FastPeriod":   26,
+		"SlowPeriod":   12,
+		"SignalPeriod": 9,
+	}
+
+	_, err := NewTradeStrategyFactory("MACD", params)
+
+	if err == nil {
+		t.Fatal("Expected error for FastPeriod >= SlowPeriod, got nil")
+	}
+
+	expectedMessage := "invalid MACD parameters: FastPeriod (26) must be less than SlowPeriod (12)"
+	if err.Error() != expectedMessage {
+		t.Errorf("Expected error message '%s', got: %s", expectedMessage, err.Error())
+	}
+}
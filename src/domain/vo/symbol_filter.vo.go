@@ -205,6 +205,34 @@ func (sf *SymbolFilter) calculateDecimalPlaces(stepSize float64) int {
 	return decimalPlaces
 }
 
+// AdjustPriceToTickSize rounds price to the tick size, down for buys and up for sells so a limit order
+// never pays more or receives less than the given price
+func (sf *SymbolFilter) AdjustPriceToTickSize(price float64, roundUp bool) float64 {
+	if sf.tickSize <= 0 {
+		return price
+	}
+
+	// Nudge by a fraction of a tick so prices already on a tick are not moved by float error
+	steps := price / sf.tickSize
+	if roundUp {
+		steps = math.Ceil(steps - 1e-6)
+	} else {
+		steps = math.Floor(steps + 1e-6)
+	}
+	return steps * sf.tickSize
+}
+
+// FormatPriceString formats price with appropriate precision based on tick size
+func (sf *SymbolFilter) FormatPriceString(price float64) string {
+	if sf.tickSize <= 0 {
+		return fmt.Sprintf("%.8f", price)
+	}
+
+	decimalPlaces := sf.calculateDecimalPlaces(sf.tickSize)
+	formatStr := fmt.Sprintf("%%.%df", decimalPlaces)
+	return fmt.Sprintf(formatStr, price)
+}
+
 // ValidatePrice validates if price meets the symbol requirements
 func (sf *SymbolFilter) ValidatePrice(price float64) error {
 	if price < sf.minPrice {
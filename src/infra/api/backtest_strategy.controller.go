@@ -35,6 +35,17 @@ type BacktestRequest struct {
 	EndDate                 string                 `json:"end_date,omitempty"`               // Optional - will use yesterday if not provided
 	TradingFees             float64                `json:"trading_fees"`                     // Percentage (e.g., 0.1 for 0.1%)
 	MinimumProfitThreshold  float64                `json:"minimum_profit_threshold,omitempty"` // Minimum profit % to sell (default: 0 = sell at any profit)
+	TrailingStopPercent     float64                `json:"trailing_stop_percent,omitempty"`    // Trailing stop % from the highest price since entry (0 = disabled)
+	TakeProfitPercent       float64                `json:"take_profit_percent,omitempty"`      // Take profit % (0 = disabled)
+	MaxHoldingSeconds       int                    `json:"max_holding_seconds,omitempty"`      // Max holding time in seconds (0 = disabled)
+	RiskPerTradePercent     float64                `json:"risk_per_trade_percent,omitempty"`   // ATR sizing: % of initial capital risked per trade (0 = disabled)
+	ATRPeriod               int                    `json:"atr_period,omitempty"`               // ATR sizing: klines in the ATR (default: 14)
+	ATRMultiplier           float64                `json:"atr_multiplier,omitempty"`           // ATR sizing: stop distance in ATRs (default: 2)
+	CooldownCandles         int                    `json:"cooldown_candles,omitempty"`           // Candles to wait after any exit (0 = disabled)
+	CooldownMinutes         int                    `json:"cooldown_minutes,omitempty"`           // Minutes to wait after any exit (0 = disabled)
+	StopLossCooldownCandles int                    `json:"stop_loss_cooldown_candles,omitempty"` // Candles to wait after a stop-loss exit (0 = disabled)
+	StopLossCooldownMinutes int                    `json:"stop_loss_cooldown_minutes,omitempty"` // Minutes to wait after a stop-loss exit (0 = disabled)
+	MaxTradesPerDay         int                    `json:"max_trades_per_day,omitempty"`         // Positions opened per UTC day (0 = unlimited)
 	UseYesterday            bool                   `json:"use_yesterday,omitempty"`          // If true, fetch yesterday's data from Binance
 	UseLastWeek             bool                   `json:"use_last_week,omitempty"`          // If true, fetch last week's data from Binance
 	UseBinanceData          bool                   `json:"use_binance_data,omitempty"`       // If true, fetch data from start_date to today
@@ -220,6 +231,17 @@ func (c *BacktestStrategyController) Handle(w http.ResponseWriter, r *http.Reque
 		EndDate:                endDate,
 		TradingFees:            req.TradingFees,
 		MinimumProfitThreshold: req.MinimumProfitThreshold,
+		TrailingStopPercent:    req.TrailingStopPercent,
+		TakeProfitPercent:      req.TakeProfitPercent,
+		MaxHoldingSeconds:      req.MaxHoldingSeconds,
+		RiskPerTradePercent:    req.RiskPerTradePercent,
+		ATRPeriod:              req.ATRPeriod,
+		ATRMultiplier:          req.ATRMultiplier,
+		CooldownCandles:         req.CooldownCandles,
+		CooldownMinutes:         req.CooldownMinutes,
+		StopLossCooldownCandles: req.StopLossCooldownCandles,
+		StopLossCooldownMinutes: req.StopLossCooldownMinutes,
+		MaxTradesPerDay:         req.MaxTradesPerDay,
 	}
 
 	// Execute backtest
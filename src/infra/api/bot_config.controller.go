@@ -0,0 +1,63 @@
+package api
+
+import (
+	"crypgo-machine/src/application/usecase"
+	"encoding/json"
+	"net/http"
+)
+
+// BotConfigController handles the endpoints editing the config of a bot and listing its versions
+type BotConfigController struct {
+	updateTradingBotUseCase    *usecase.UpdateTradingBotUseCase
+	getBotConfigHistoryUseCase *usecase.GetBotConfigHistoryUseCase
+}
+
+// NewBotConfigController creates a new bot config controller
+func NewBotConfigController(updateTradingBotUseCase *usecase.UpdateTradingBotUseCase, getBotConfigHistoryUseCase *usecase.GetBotConfigHistoryUseCase) *BotConfigController {
+	return &BotConfigController{
+		updateTradingBotUseCase:    updateTradingBotUseCase,
+		getBotConfigHistoryUseCase: getBotConfigHistoryUseCase,
+	}
+}
+
+// Update handles PATCH /api/v1/trading/bots/{id}, the body holding the config fields to change
+func (c *BotConfigController) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var changes map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.updateTradingBotUseCase.Execute(usecase.InputUpdateTradingBot{BotId: r.PathValue("id"), Changes: changes})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// History handles GET /api/v1/trading/bots/{id}/config-history
+func (c *BotConfigController) History(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	output, err := c.getBotConfigHistoryUseCase.Execute(usecase.InputGetBotConfigHistory{BotId: r.PathValue("id")})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
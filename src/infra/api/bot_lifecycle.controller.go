@@ -0,0 +1,103 @@
+package api
+
+import (
+	"crypgo-machine/src/application/usecase"
+	"encoding/json"
+	"net/http"
+)
+
+// BotLifecycleController handles the endpoints moving a bot between running, paused and closing
+type BotLifecycleController struct {
+	pauseTradingBotUseCase         *usecase.PauseTradingBotUseCase
+	resumeTradingBotUseCase        *usecase.ResumeTradingBotUseCase
+	closeTradingBotPositionUseCase *usecase.CloseTradingBotPositionUseCase
+	liquidateTradingBotUseCase     *usecase.LiquidateTradingBotUseCase
+}
+
+// NewBotLifecycleController creates a new bot lifecycle controller
+func NewBotLifecycleController(
+	pauseTradingBotUseCase *usecase.PauseTradingBotUseCase,
+	resumeTradingBotUseCase *usecase.ResumeTradingBotUseCase,
+	closeTradingBotPositionUseCase *usecase.CloseTradingBotPositionUseCase,
+	liquidateTradingBotUseCase *usecase.LiquidateTradingBotUseCase,
+) *BotLifecycleController {
+	return &BotLifecycleController{
+		pauseTradingBotUseCase:         pauseTradingBotUseCase,
+		resumeTradingBotUseCase:        resumeTradingBotUseCase,
+		closeTradingBotPositionUseCase: closeTradingBotPositionUseCase,
+		liquidateTradingBotUseCase:     liquidateTradingBotUseCase,
+	}
+}
+
+// Pause handles POST /api/v1/trading/pause
+func (c *BotLifecycleController) Pause(w http.ResponseWriter, r *http.Request) {
+	var input usecase.InputPauseTradingBot
+	if !decodeLifecycleInput(w, r, &input) {
+		return
+	}
+	if err := c.pauseTradingBotUseCase.Execute(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeLifecycleResponse(w, "Trading bot paused successfully", input.BotId)
+}
+
+// Resume handles POST /api/v1/trading/resume
+func (c *BotLifecycleController) Resume(w http.ResponseWriter, r *http.Request) {
+	var input usecase.InputResumeTradingBot
+	if !decodeLifecycleInput(w, r, &input) {
+		return
+	}
+	if err := c.resumeTradingBotUseCase.Execute(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeLifecycleResponse(w, "Trading bot resumed successfully", input.BotId)
+}
+
+// Close handles POST /api/v1/trading/close
+func (c *BotLifecycleController) Close(w http.ResponseWriter, r *http.Request) {
+	var input usecase.InputCloseTradingBotPosition
+	if !decodeLifecycleInput(w, r, &input) {
+		return
+	}
+	if err := c.closeTradingBotPositionUseCase.Execute(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeLifecycleResponse(w, "Trading bot is closing its position, it stops once the position is sold", input.BotId)
+}
+
+// Liquidate handles POST /api/v1/trading/liquidate
+func (c *BotLifecycleController) Liquidate(w http.ResponseWriter, r *http.Request) {
+	var input usecase.InputLiquidateTradingBot
+	if !decodeLifecycleInput(w, r, &input) {
+		return
+	}
+	if err := c.liquidateTradingBotUseCase.Execute(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeLifecycleResponse(w, "Trading bot position liquidated and bot stopped", input.BotId)
+}
+
+func decodeLifecycleInput(w http.ResponseWriter, r *http.Request, input interface{}) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(input); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeLifecycleResponse(w http.ResponseWriter, message, botId string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": message,
+		"bot_id":  botId,
+	})
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"crypgo-machine/src/application/usecase"
+	"encoding/json"
+	"net/http"
+)
+
+type BotRuntimeController struct {
+	GetBotRuntime *usecase.GetBotRuntimeUseCase
+}
+
+func NewBotRuntimeController(getBotRuntime *usecase.GetBotRuntimeUseCase) *BotRuntimeController {
+	return &BotRuntimeController{
+		GetBotRuntime: getBotRuntime,
+	}
+}
+
+// Handle handles GET /api/v1/trading/runtime
+func (c *BotRuntimeController) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(c.GetBotRuntime.Execute()); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -2,7 +2,7 @@ package api
 
 import (
 	"crypgo-machine/src/application/usecase"
-	"crypgo-machine/src/domain/service"
+	"crypgo-machine/src/domain/entity"
 	"encoding/json"
 	"net/http"
 )
@@ -24,21 +24,16 @@ func (c *CreateTradingBotController) Handle(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	var params interface{}
-	switch rawInput.Strategy {
-	case "MovingAverage":
-		var m service.MovingAverageParams
-		// Marshal/Unmarshal: transforma map em struct
-		b, _ := json.Marshal(rawInput.Params)
-		if err := json.Unmarshal(b, &m); err != nil {
-			http.Error(w, "invalid params for MovingAverage", http.StatusBadRequest)
-			return
-		}
-		params = m
-	default:
+	// Strategy params are resolved and validated by the strategy registry
+	if !entity.IsStrategyRegistered(rawInput.Strategy) {
 		http.Error(w, "unknown strategy", http.StatusBadRequest)
 		return
 	}
+	params, ok := rawInput.Params.(map[string]interface{})
+	if rawInput.Params != nil && !ok {
+		http.Error(w, "invalid params for "+rawInput.Strategy, http.StatusBadRequest)
+		return
+	}
 
 	input := usecase.InputCreateTradingBot{
 		Symbol:                   rawInput.Symbol,
@@ -51,6 +46,30 @@ func (c *CreateTradingBotController) Handle(w http.ResponseWriter, r *http.Reque
 		Currency:                 rawInput.Currency,
 		TradingFees:              rawInput.TradingFees,
 		MinimumProfitThreshold:   rawInput.MinimumProfitThreshold,
+		UseFixedQuantity:         rawInput.UseFixedQuantity,
+		PositionSizingMode:       rawInput.PositionSizingMode,
+		RiskPerTradePercent:      rawInput.RiskPerTradePercent,
+		ATRPeriod:                rawInput.ATRPeriod,
+		ATRMultiplier:            rawInput.ATRMultiplier,
+		TrailingStopPercent:      rawInput.TrailingStopPercent,
+		TakeProfitPercent:        rawInput.TakeProfitPercent,
+		MaxHoldingSeconds:        rawInput.MaxHoldingSeconds,
+		UseStreaming:             rawInput.UseStreaming,
+		OrderExecutionMode:       rawInput.OrderExecutionMode,
+		TimeInForce:              rawInput.TimeInForce,
+		RepriceAfterSeconds:      rawInput.RepriceAfterSeconds,
+		MaxOrderAttempts:         rawInput.MaxOrderAttempts,
+		UseExchangeOCO:           rawInput.UseExchangeOCO,
+		OCOStopLossPercent:       rawInput.OCOStopLossPercent,
+		OCOTakeProfitPercent:     rawInput.OCOTakeProfitPercent,
+		OCOStopLimitGapPercent:   rawInput.OCOStopLimitGapPercent,
+		Mode:                     rawInput.Mode,
+		ExchangeProfile:          rawInput.ExchangeProfile,
+		CooldownCandles:          rawInput.CooldownCandles,
+		CooldownMinutes:          rawInput.CooldownMinutes,
+		StopLossCooldownCandles:  rawInput.StopLossCooldownCandles,
+		StopLossCooldownMinutes:  rawInput.StopLossCooldownMinutes,
+		MaxTradesPerDay:          rawInput.MaxTradesPerDay,
 	}
 
 	if err := c.CreateTradingBot.Execute(input); err != nil {
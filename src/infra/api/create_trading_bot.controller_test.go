@@ -5,10 +5,10 @@ import (
 	"crypgo-machine/src/application/usecase"
 	"crypgo-machine/src/infra/api"
 	"crypgo-machine/src/infra/database"
+	"crypgo-machine/src/infra/external"
 	"crypgo-machine/src/infra/queue"
 	"crypgo-machine/src/infra/repository"
 	"encoding/json"
-	"github.com/adshao/go-binance/v2"
 	"github.com/joho/godotenv"
 	"io"
 	"net/http"
@@ -105,7 +105,7 @@ func TestCreateTradingBotController_MovingAverage_Success(t *testing.T) {
 	tradeBotRepo := repository.NewTradeBotRepositoryInMemory()
 	//tradeBotRepo := repository.NewTradingBotRepositoryDatabase(dbConn.DB)
 	mockMessageBroker := &MockMessageBroker{}
-	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 	controller := api.NewCreateTradingBotController(useCase)
 
 	// Prepare request body
@@ -157,7 +157,7 @@ func TestCreateTradingBotController_InvalidParams(t *testing.T) {
 	}()
 	tradeBotRepo := repository.NewTradeBotRepositoryInMemory()
 	mockMessageBroker := &MockMessageBroker{}
-	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 	controller := api.NewCreateTradingBotController(useCase)
 
 	body := map[string]interface{}{
@@ -203,7 +203,7 @@ func TestCreateTradingBotController_SmallWindowDifference(t *testing.T) {
 	}()
 	tradeBotRepo := repository.NewTradeBotRepositoryInMemory()
 	mockMessageBroker := &MockMessageBroker{}
-	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 	controller := api.NewCreateTradingBotController(useCase)
 
 	body := map[string]interface{}{
@@ -250,7 +250,7 @@ func TestCreateTradingBotController_MissingFinancialFields(t *testing.T) {
 	}()
 	tradeBotRepo := repository.NewTradeBotRepositoryInMemory()
 	mockMessageBroker := &MockMessageBroker{}
-	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 	controller := api.NewCreateTradingBotController(useCase)
 
 	body := map[string]interface{}{
@@ -292,7 +292,7 @@ func TestCreateTradingBotController_InvalidInitialCapital(t *testing.T) {
 	}()
 	tradeBotRepo := repository.NewTradeBotRepositoryInMemory()
 	mockMessageBroker := &MockMessageBroker{}
-	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, binance.Client{}, mockMessageBroker, "test-exchange")
+	useCase := usecase.NewCreateTradingBotUseCase(tradeBotRepo, external.NewFakeExchange(), mockMessageBroker, "test-exchange")
 	controller := api.NewCreateTradingBotController(useCase)
 
 	body := map[string]interface{}{
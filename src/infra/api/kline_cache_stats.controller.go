@@ -0,0 +1,31 @@
+package api
+
+import (
+	"crypgo-machine/src/application/usecase"
+	"encoding/json"
+	"net/http"
+)
+
+type KlineCacheStatsController struct {
+	GetKlineCacheStats *usecase.GetKlineCacheStatsUseCase
+}
+
+func NewKlineCacheStatsController(getKlineCacheStats *usecase.GetKlineCacheStatsUseCase) *KlineCacheStatsController {
+	return &KlineCacheStatsController{
+		GetKlineCacheStats: getKlineCacheStats,
+	}
+}
+
+// Handle handles GET /api/v1/market-data/cache/stats
+func (c *KlineCacheStatsController) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(c.GetKlineCacheStats.Execute()); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
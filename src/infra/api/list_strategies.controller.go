@@ -0,0 +1,31 @@
+package api
+
+import (
+	"crypgo-machine/src/application/usecase"
+	"encoding/json"
+	"net/http"
+)
+
+type ListStrategiesController struct {
+	ListStrategies *usecase.ListStrategiesUseCase
+}
+
+func NewListStrategiesController(listStrategies *usecase.ListStrategiesUseCase) *ListStrategiesController {
+	return &ListStrategiesController{
+		ListStrategies: listStrategies,
+	}
+}
+
+// Handle handles GET /api/v1/strategies
+func (c *ListStrategiesController) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(c.ListStrategies.Execute()); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
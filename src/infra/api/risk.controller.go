@@ -0,0 +1,96 @@
+package api
+
+import (
+	"crypgo-machine/src/application/usecase"
+	"crypgo-machine/src/domain/entity"
+	"encoding/json"
+	"net/http"
+)
+
+// RiskController handles the endpoints of the risk limits shared by the live bots
+type RiskController struct {
+	getRiskStatusUseCase    *usecase.GetRiskStatusUseCase
+	updateRiskLimitsUseCase *usecase.UpdateRiskLimitsUseCase
+	setKillSwitchUseCase    *usecase.SetKillSwitchUseCase
+}
+
+// NewRiskController creates a new risk controller
+func NewRiskController(getRiskStatusUseCase *usecase.GetRiskStatusUseCase, updateRiskLimitsUseCase *usecase.UpdateRiskLimitsUseCase, setKillSwitchUseCase *usecase.SetKillSwitchUseCase) *RiskController {
+	return &RiskController{
+		getRiskStatusUseCase:    getRiskStatusUseCase,
+		updateRiskLimitsUseCase: updateRiskLimitsUseCase,
+		setKillSwitchUseCase:    setKillSwitchUseCase,
+	}
+}
+
+// Status handles GET /api/v1/risk/status?exchange_profile=testnet, the default profile being reported without it
+func (c *RiskController) Status(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	input := usecase.InputGetRiskStatus{ExchangeProfile: r.URL.Query().Get("exchange_profile")}
+	if _, err := entity.NewExchangeProfileName(input.ExchangeProfile); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.getRiskStatusUseCase.Execute(input)
+	if err != nil {
+		http.Error(w, `{"error":"Failed to retrieve risk status"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// UpdateLimits handles PUT /api/v1/risk/limits
+func (c *RiskController) UpdateLimits(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var input usecase.InputUpdateRiskLimits
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.updateRiskLimitsUseCase.Execute(input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// KillSwitch handles POST /api/v1/risk/kill-switch
+func (c *RiskController) KillSwitch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var input usecase.InputSetKillSwitch
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.setKillSwitchUseCase.Execute(input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
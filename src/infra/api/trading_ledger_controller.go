@@ -0,0 +1,136 @@
+package api
+
+import (
+	"crypgo-machine/src/application/usecase"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TradingLedgerController handles the order and trade ledger endpoints
+type TradingLedgerController struct {
+	listOrdersUseCase *usecase.ListOrdersUseCase
+	listTradesUseCase *usecase.ListTradesUseCase
+}
+
+// NewTradingLedgerController creates a new trading ledger controller
+func NewTradingLedgerController(listOrdersUseCase *usecase.ListOrdersUseCase, listTradesUseCase *usecase.ListTradesUseCase) *TradingLedgerController {
+	return &TradingLedgerController{
+		listOrdersUseCase: listOrdersUseCase,
+		listTradesUseCase: listTradesUseCase,
+	}
+}
+
+// ListOrders handles GET /api/v1/trading/orders
+func (c *TradingLedgerController) ListOrders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	from, to, ok := parsePeriod(w, r)
+	if !ok {
+		return
+	}
+	limit, offset := parsePagination(r)
+
+	input := usecase.ListOrdersInput{
+		BotID:  query.Get("bot_id"),
+		Symbol: strings.ToUpper(query.Get("symbol")),
+		Side:   strings.ToUpper(query.Get("side")),
+		Status: strings.ToUpper(query.Get("status")),
+		From:   from,
+		To:     to,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	output, err := c.listOrdersUseCase.Execute(input)
+	if err != nil {
+		http.Error(w, `{"error":"Failed to retrieve orders"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// ListTrades handles GET /api/v1/trading/trades
+func (c *TradingLedgerController) ListTrades(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	from, to, ok := parsePeriod(w, r)
+	if !ok {
+		return
+	}
+	limit, offset := parsePagination(r)
+
+	input := usecase.ListTradesInput{
+		BotID:  query.Get("bot_id"),
+		Symbol: strings.ToUpper(query.Get("symbol")),
+		From:   from,
+		To:     to,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	output, err := c.listTradesUseCase.Execute(input)
+	if err != nil {
+		http.Error(w, `{"error":"Failed to retrieve trades"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// parsePagination reads limit (1-100, default 20) and offset (default 0), ignoring invalid values
+func parsePagination(r *http.Request) (int, int) {
+	limit := 20
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsed > 0 && parsed <= 100 {
+		limit = parsed
+	}
+
+	offset := 0
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && parsed >= 0 {
+		offset = parsed
+	}
+
+	return limit, offset
+}
+
+// parsePeriod reads the optional from/to query parameters as RFC3339 or YYYY-MM-DD, answering 400 when invalid
+func parsePeriod(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	from, err := parseDateParam(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, `{"error":"Invalid 'from' date, use RFC3339 or YYYY-MM-DD"}`, http.StatusBadRequest)
+		return time.Time{}, time.Time{}, false
+	}
+
+	to, err := parseDateParam(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, `{"error":"Invalid 'to' date, use RFC3339 or YYYY-MM-DD"}`, http.StatusBadRequest)
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
+
+func parseDateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -0,0 +1,144 @@
+package api_test
+
+import (
+	"crypgo-machine/src/application/usecase"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"crypgo-machine/src/infra/api"
+	"crypgo-machine/src/infra/repository"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupLedgerController(t *testing.T) (*api.TradingLedgerController, *vo.EntityId) {
+	t.Helper()
+	orderRepo := repository.NewOrderRepositoryInMemory()
+	tradeRepo := repository.NewTradeRepositoryInMemory()
+	botId := vo.NewEntityId()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_ = orderRepo.Save(entity.RestoreOrder(vo.NewEntityId(), botId, "", "", 0, "BTCUSDT", entity.OrderSideBuy, "MARKET", 1.0, 100.0, entity.OrderStatusFilled, entity.OrderFill{}, "", base))
+	_ = orderRepo.Save(entity.RestoreOrder(vo.NewEntityId(), botId, "", "", 0, "BTCUSDT", entity.OrderSideSell, "MARKET", 1.0, 110.0, entity.OrderStatusFilled, entity.OrderFill{}, "", base.Add(24*time.Hour)))
+	_ = orderRepo.Save(entity.RestoreOrder(vo.NewEntityId(), vo.NewEntityId(), "", "", 0, "SOLBRL", entity.OrderSideBuy, "MARKET", 2.0, 50.0, entity.OrderStatusRejected, entity.OrderFill{}, "below min notional", base.Add(48*time.Hour)))
+
+	_ = tradeRepo.Save(entity.RestoreTrade(vo.NewEntityId(), botId, "BTCUSDT", "", "exit-1", 1.0, 100.0, 110.0, 0, 0, 10.0, 10.0, "USDT", base, base.Add(24*time.Hour)))
+	_ = tradeRepo.Save(entity.RestoreTrade(vo.NewEntityId(), vo.NewEntityId(), "SOLBRL", "", "exit-2", 2.0, 50.0, 49.0, 0, 0, -2.0, -2.0, "BRL", base, base.Add(48*time.Hour)))
+
+	controller := api.NewTradingLedgerController(usecase.NewListOrdersUseCase(orderRepo), usecase.NewListTradesUseCase(tradeRepo))
+	return controller, botId
+}
+
+func TestTradingLedgerController_ListOrders(t *testing.T) {
+	controller, botId := setupLedgerController(t)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedTotal int
+	}{
+		{"all orders", "", 3},
+		{"by bot", "?bot_id=" + botId.GetValue(), 2},
+		{"by lowercase symbol, side and status", "?symbol=btcusdt&side=sell&status=filled", 1},
+		{"by rejected status", "?status=REJECTED", 1},
+		{"by date range", "?from=2025-01-02&to=2025-01-03", 1},
+		{"by RFC3339 range", "?from=2025-01-01T12:00:00Z", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/trading/orders"+tt.query, nil)
+			w := httptest.NewRecorder()
+			controller.ListOrders(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var output usecase.ListOrdersOutput
+			if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if output.Total != tt.expectedTotal || len(output.Orders) != tt.expectedTotal {
+				t.Errorf("expected %d orders, got %d listed of %d", tt.expectedTotal, len(output.Orders), output.Total)
+			}
+		})
+	}
+}
+
+func TestTradingLedgerController_ListOrdersPaginates(t *testing.T) {
+	controller, _ := setupLedgerController(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/trading/orders?limit=1&offset=1", nil)
+	w := httptest.NewRecorder()
+	controller.ListOrders(w, req)
+
+	var output usecase.ListOrdersOutput
+	if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if output.Total != 3 || len(output.Orders) != 1 || output.Orders[0].Side != "SELL" {
+		t.Errorf("expected the second most recent of 3 orders, got %+v", output)
+	}
+}
+
+func TestTradingLedgerController_ListTrades(t *testing.T) {
+	controller, botId := setupLedgerController(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/trading/trades?bot_id="+botId.GetValue(), nil)
+	w := httptest.NewRecorder()
+	controller.ListTrades(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var output usecase.ListTradesOutput
+	if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if output.Total != 1 || output.Trades[0].Symbol != "BTCUSDT" || output.TotalProfitLoss != 10.0 {
+		t.Errorf("expected the BTCUSDT trade with a 10.0 profit, got %+v", output)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/trading/trades?symbol=solbrl&from=2025-01-02T12:00:00Z", nil)
+	w = httptest.NewRecorder()
+	controller.ListTrades(w, req)
+
+	output = usecase.ListTradesOutput{}
+	if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if output.Total != 1 || output.TotalProfitLoss != -2.0 {
+		t.Errorf("expected the SOLBRL trade with a -2.0 loss, got %+v", output)
+	}
+}
+
+func TestTradingLedgerController_RejectsInvalidRequests(t *testing.T) {
+	controller, _ := setupLedgerController(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		handler        func(http.ResponseWriter, *http.Request)
+		expectedStatus int
+	}{
+		{"orders with POST", http.MethodPost, "/api/v1/trading/orders", controller.ListOrders, http.StatusMethodNotAllowed},
+		{"trades with POST", http.MethodPost, "/api/v1/trading/trades", controller.ListTrades, http.StatusMethodNotAllowed},
+		{"orders with invalid from", http.MethodGet, "/api/v1/trading/orders?from=yesterday", controller.ListOrders, http.StatusBadRequest},
+		{"trades with invalid to", http.MethodGet, "/api/v1/trading/trades?to=01/02/2025", controller.ListTrades, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			w := httptest.NewRecorder()
+			tt.handler(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+-- Add bot-level exit rules to trade_bots table
+-- Trailing stop, take profit and max holding time are evaluated before the strategy on every tick
+
+ALTER TABLE trade_bots 
+ADD COLUMN trailing_stop_percent DOUBLE PRECISION DEFAULT 0.0,
+ADD COLUMN take_profit_percent DOUBLE PRECISION DEFAULT 0.0,
+ADD COLUMN max_holding_seconds INTEGER DEFAULT 0,
+ADD COLUMN highest_price_since_entry DOUBLE PRECISION DEFAULT 0.0,
+ADD COLUMN position_opened_at TIMESTAMP NULL;
+
+-- Add comments for documentation
+COMMENT ON COLUMN trade_bots.trailing_stop_percent IS 'Sell when price drops this percentage below the highest price since entry (0 = disabled)';
+COMMENT ON COLUMN trade_bots.take_profit_percent IS 'Sell when profit from the entry price reaches this percentage (0 = disabled)';
+COMMENT ON COLUMN trade_bots.max_holding_seconds IS 'Sell when the position has been open for this many seconds (0 = disabled)';
+COMMENT ON COLUMN trade_bots.highest_price_since_entry IS 'High-water mark of the open position, kept across restarts for the trailing stop';
+COMMENT ON COLUMN trade_bots.position_opened_at IS 'When the open position was entered, kept across restarts for the max holding time';
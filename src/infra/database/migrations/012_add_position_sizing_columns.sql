@@ -0,0 +1,13 @@
+-- Add ATR risk position sizing to trade_bots table
+-- When risk_per_trade_percent is greater than zero, buy quantities risk that share of initial_capital
+-- with a stop distance of atr_multiplier times the ATR
+
+ALTER TABLE trade_bots 
+ADD COLUMN risk_per_trade_percent DOUBLE PRECISION DEFAULT 0.0,
+ADD COLUMN atr_period INTEGER DEFAULT 0,
+ADD COLUMN atr_multiplier DOUBLE PRECISION DEFAULT 0.0;
+
+-- Add comments for documentation
+COMMENT ON COLUMN trade_bots.risk_per_trade_percent IS 'Percentage of initial capital risked per trade with ATR sizing (0 = use use_fixed_quantity sizing)';
+COMMENT ON COLUMN trade_bots.atr_period IS 'Number of klines in the ATR used for position sizing';
+COMMENT ON COLUMN trade_bots.atr_multiplier IS 'Stop distance in ATRs used for position sizing';
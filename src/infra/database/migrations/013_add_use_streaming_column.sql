@@ -0,0 +1,8 @@
+-- Add kline WebSocket streaming opt-in to trade_bots table
+-- Streaming bots decide on each candle close instead of polling every interval_seconds
+
+ALTER TABLE trade_bots 
+ADD COLUMN use_streaming BOOLEAN DEFAULT FALSE;
+
+-- Add comment for documentation
+COMMENT ON COLUMN trade_bots.use_streaming IS 'If true, the bot decides on each candle close from the kline WebSocket stream; if false, it polls klines every interval_seconds';
@@ -0,0 +1,8 @@
+-- Add entry fees to trade_bots table
+-- Fees paid on the buy fill, valued in the quote currency, so realized P&L is net of fees
+
+ALTER TABLE trade_bots 
+ADD COLUMN entry_fees DECIMAL(20, 8) DEFAULT 0.0;
+
+-- Add comment for documentation
+COMMENT ON COLUMN trade_bots.entry_fees IS 'Commission paid to open the current position, valued in the quote currency (including fees paid in BNB)';
@@ -0,0 +1,38 @@
+-- Migration: 015_create_orders_table
+-- Description: Create the order ledger, one row per order placement attempt of a bot
+
+CREATE TABLE orders
+(
+    id                 VARCHAR(36)      PRIMARY KEY,
+    trading_bot_id     VARCHAR(36)      NOT NULL,
+    decision_log_id    VARCHAR(36),
+    symbol             VARCHAR(20)      NOT NULL,
+    side               VARCHAR(4)       NOT NULL,
+    order_type         VARCHAR(20)      NOT NULL,
+    requested_quantity DECIMAL(20, 8)   NOT NULL,
+    requested_price    DECIMAL(20, 8)   NOT NULL,
+    status             VARCHAR(20)      NOT NULL,
+    exchange_order_id  BIGINT,
+    executed_quantity  DECIMAL(20, 8)   DEFAULT 0.0,
+    average_price      DECIMAL(20, 8)   DEFAULT 0.0,
+    quote_quantity     DECIMAL(20, 8)   DEFAULT 0.0,
+    commission         DECIMAL(20, 8)   DEFAULT 0.0,
+    commission_asset   VARCHAR(20),
+    fees_in_quote      DECIMAL(20, 8)   DEFAULT 0.0,
+    net_quantity       DECIMAL(20, 8)   DEFAULT 0.0,
+    estimated          BOOLEAN          DEFAULT FALSE,
+    fills              TEXT             NOT NULL DEFAULT '[]',
+    executed_at        TIMESTAMP,
+    error_message      TEXT,
+    created_at         TIMESTAMP        NOT NULL,
+
+    FOREIGN KEY (trading_bot_id) REFERENCES trade_bots(id)
+);
+
+CREATE INDEX idx_orders_trading_bot_id ON orders(trading_bot_id);
+CREATE INDEX idx_orders_symbol_created_at ON orders(symbol, created_at);
+
+COMMENT ON COLUMN orders.decision_log_id IS 'Trading decision log that caused the order';
+COMMENT ON COLUMN orders.status IS 'Exchange status (FILLED, PARTIALLY_FILLED, ...), REJECTED by validation or FAILED on an exchange error';
+COMMENT ON COLUMN orders.fees_in_quote IS 'Commission valued in the quote currency, including fees paid in BNB';
+COMMENT ON COLUMN orders.fills IS 'Individual executions returned by the exchange, as JSON';
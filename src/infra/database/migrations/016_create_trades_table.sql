@@ -0,0 +1,28 @@
+-- Migration: 016_create_trades_table
+-- Description: Create the trade ledger, one row per closed position of a bot with its realized P&L
+
+CREATE TABLE trades
+(
+    id                  VARCHAR(36)      PRIMARY KEY,
+    trading_bot_id      VARCHAR(36)      NOT NULL,
+    symbol              VARCHAR(20)      NOT NULL,
+    entry_order_id      VARCHAR(36),
+    exit_order_id       VARCHAR(36)      NOT NULL,
+    quantity            DECIMAL(20, 8)   NOT NULL,
+    entry_price         DECIMAL(20, 8)   NOT NULL,
+    exit_price          DECIMAL(20, 8)   NOT NULL,
+    entry_fees          DECIMAL(20, 8)   DEFAULT 0.0,
+    exit_fees           DECIMAL(20, 8)   DEFAULT 0.0,
+    profit_loss         DECIMAL(20, 8)   NOT NULL,
+    profit_loss_percent DECIMAL(10, 4)   NOT NULL,
+    currency            VARCHAR(10)      NOT NULL,
+    opened_at           TIMESTAMP,
+    closed_at           TIMESTAMP        NOT NULL,
+
+    FOREIGN KEY (trading_bot_id) REFERENCES trade_bots(id)
+);
+
+CREATE INDEX idx_trades_trading_bot_id ON trades(trading_bot_id);
+CREATE INDEX idx_trades_symbol_closed_at ON trades(symbol, closed_at);
+
+COMMENT ON COLUMN trades.profit_loss IS 'Realized profit or loss in the quote currency, net of entry and exit fees';
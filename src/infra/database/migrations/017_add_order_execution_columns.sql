@@ -0,0 +1,15 @@
+-- Add order execution mode to trade_bots table
+-- Limit and post-only bots place orders at the best bid/ask, cancel and reprice them after reprice_after_seconds
+-- and send what is left as a market order after max_order_attempts
+
+ALTER TABLE trade_bots 
+ADD COLUMN order_execution_mode VARCHAR(20) DEFAULT 'market',
+ADD COLUMN time_in_force VARCHAR(3) DEFAULT '',
+ADD COLUMN reprice_after_seconds INTEGER DEFAULT 0,
+ADD COLUMN max_order_attempts INTEGER DEFAULT 0;
+
+-- Add comments for documentation
+COMMENT ON COLUMN trade_bots.order_execution_mode IS 'How orders are placed: market, limit (at best bid/ask) or post_only (maker only)';
+COMMENT ON COLUMN trade_bots.time_in_force IS 'Time in force of limit orders: GTC, IOC or FOK (empty for market and post_only)';
+COMMENT ON COLUMN trade_bots.reprice_after_seconds IS 'Seconds a resting limit order waits before being canceled and repriced';
+COMMENT ON COLUMN trade_bots.max_order_attempts IS 'Limit orders placed before the remaining quantity is sent as a market order';
@@ -0,0 +1,19 @@
+-- Add exchange-side OCO protection to trade_bots table
+-- After each entry, bots with oco_stop_loss_percent and oco_take_profit_percent place an OCO sell on the exchange
+-- and keep its order IDs until it fills, is canceled by a strategy exit or expires
+
+ALTER TABLE trade_bots 
+ADD COLUMN oco_stop_loss_percent DOUBLE PRECISION DEFAULT 0.0,
+ADD COLUMN oco_take_profit_percent DOUBLE PRECISION DEFAULT 0.0,
+ADD COLUMN oco_stop_limit_gap_percent DOUBLE PRECISION DEFAULT 0.0,
+ADD COLUMN oco_order_list_id BIGINT DEFAULT 0,
+ADD COLUMN oco_take_profit_order_id BIGINT DEFAULT 0,
+ADD COLUMN oco_stop_loss_order_id BIGINT DEFAULT 0;
+
+-- Add comments for documentation
+COMMENT ON COLUMN trade_bots.oco_stop_loss_percent IS 'Loss % below the entry price that triggers the OCO stop-loss leg (0 = no OCO)';
+COMMENT ON COLUMN trade_bots.oco_take_profit_percent IS 'Profit % above the entry price of the OCO take-profit leg (0 = no OCO)';
+COMMENT ON COLUMN trade_bots.oco_stop_limit_gap_percent IS 'How far below the stop trigger the stop-loss limit price is placed';
+COMMENT ON COLUMN trade_bots.oco_order_list_id IS 'Exchange order list ID of the OCO protecting the open position (0 = none)';
+COMMENT ON COLUMN trade_bots.oco_take_profit_order_id IS 'Exchange order ID of the take-profit leg of the active OCO';
+COMMENT ON COLUMN trade_bots.oco_stop_loss_order_id IS 'Exchange order ID of the stop-loss leg of the active OCO';
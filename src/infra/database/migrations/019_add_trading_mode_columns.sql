@@ -0,0 +1,10 @@
+-- Add paper trading to trade_bots table
+-- Paper bots simulate their fills at the live price against a virtual balance instead of placing orders
+
+ALTER TABLE trade_bots 
+ADD COLUMN mode VARCHAR(10) DEFAULT 'live',
+ADD COLUMN paper_balance DOUBLE PRECISION DEFAULT 0.0;
+
+-- Add comments for documentation
+COMMENT ON COLUMN trade_bots.mode IS 'Trading mode chosen at creation: live (exchange orders) or paper (simulated fills)';
+COMMENT ON COLUMN trade_bots.paper_balance IS 'Virtual quote balance of a paper bot, starting at its initial capital';
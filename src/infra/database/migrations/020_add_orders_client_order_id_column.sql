@@ -0,0 +1,11 @@
+-- Add the client order ID to the order ledger
+-- Orders are sent with a client order ID derived from the bot and the decision, so a request that timed out can
+-- be looked up on the exchange before it is retried instead of being sent twice
+
+ALTER TABLE orders 
+ADD COLUMN client_order_id VARCHAR(36);
+
+CREATE INDEX idx_orders_client_order_id ON orders(client_order_id);
+
+-- Add comments for documentation
+COMMENT ON COLUMN orders.client_order_id IS 'newClientOrderId sent to the exchange, shared by the retries of the order';
@@ -0,0 +1,19 @@
+-- Migration: 021_create_risk_limits_table
+-- Description: Create the risk limits shared by all live bots, kept in a single row
+
+CREATE TABLE risk_limits
+(
+    id                     INTEGER          PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    max_daily_loss         DECIMAL(20, 8)   NOT NULL DEFAULT 0.0,
+    max_open_positions     INTEGER          NOT NULL DEFAULT 0,
+    max_exposure_per_asset DECIMAL(20, 8)   NOT NULL DEFAULT 0.0,
+    flatten_on_breach      BOOLEAN          NOT NULL DEFAULT FALSE,
+    kill_switch            BOOLEAN          NOT NULL DEFAULT FALSE,
+    updated_at             TIMESTAMP
+);
+
+COMMENT ON COLUMN risk_limits.max_daily_loss IS 'Realized loss since midnight UTC, in the quote currency, that blocks new entries (0 = no limit)';
+COMMENT ON COLUMN risk_limits.max_open_positions IS 'Maximum number of positions open at once across the live bots (0 = no limit)';
+COMMENT ON COLUMN risk_limits.max_exposure_per_asset IS 'Cost of the open positions in one base asset, in the quote currency, that new entries cannot exceed (0 = no limit)';
+COMMENT ON COLUMN risk_limits.flatten_on_breach IS 'Sell every open position while the kill switch is on or the daily loss limit is breached';
+COMMENT ON COLUMN risk_limits.kill_switch IS 'Manually blocks every new entry';
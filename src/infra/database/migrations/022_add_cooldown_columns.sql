@@ -0,0 +1,25 @@
+-- Add cooldown and re-entry rules to trade_bots table
+-- After an exit the bot holds its buys for a cooldown, longer after a stop-loss, and opens at most a number of
+-- positions per UTC day. The last exit and the day's entries are kept so the rules survive a restart
+
+ALTER TABLE trade_bots 
+ADD COLUMN cooldown_candles INTEGER DEFAULT 0,
+ADD COLUMN cooldown_minutes INTEGER DEFAULT 0,
+ADD COLUMN stop_loss_cooldown_candles INTEGER DEFAULT 0,
+ADD COLUMN stop_loss_cooldown_minutes INTEGER DEFAULT 0,
+ADD COLUMN max_trades_per_day INTEGER DEFAULT 0,
+ADD COLUMN last_exit_at TIMESTAMP,
+ADD COLUMN last_exit_reason VARCHAR(50) DEFAULT '',
+ADD COLUMN trades_day DATE,
+ADD COLUMN trades_today INTEGER DEFAULT 0;
+
+-- Add comments for documentation
+COMMENT ON COLUMN trade_bots.cooldown_candles IS 'Candles the bot waits after any exit before buying again (0 = disabled)';
+COMMENT ON COLUMN trade_bots.cooldown_minutes IS 'Minutes the bot waits after any exit before buying again (0 = disabled)';
+COMMENT ON COLUMN trade_bots.stop_loss_cooldown_candles IS 'Candles the bot waits after a stop-loss exit (0 = disabled)';
+COMMENT ON COLUMN trade_bots.stop_loss_cooldown_minutes IS 'Minutes the bot waits after a stop-loss exit (0 = disabled)';
+COMMENT ON COLUMN trade_bots.max_trades_per_day IS 'Maximum positions opened per UTC day (0 = unlimited)';
+COMMENT ON COLUMN trade_bots.last_exit_at IS 'When the last position was closed, start of the cooldown';
+COMMENT ON COLUMN trade_bots.last_exit_reason IS 'Reason of the last exit, a stop-loss applying the longer cooldown';
+COMMENT ON COLUMN trade_bots.trades_day IS 'UTC day counted by trades_today';
+COMMENT ON COLUMN trade_bots.trades_today IS 'Positions opened on trades_day';
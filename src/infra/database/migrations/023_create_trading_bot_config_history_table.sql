@@ -0,0 +1,29 @@
+-- Migration: 023_create_trading_bot_config_history_table
+-- Description: Version the editable config of the bots, so decisions can be attributed to the config in force
+-- Editing a bot bumps its config_version and records the new config. The decision logs keep the version they were
+-- made with. Bots and logs from before this migration are on version 1 and 0 (unknown) respectively
+
+CREATE TABLE trading_bot_config_history
+(
+    id             VARCHAR(36)      PRIMARY KEY,
+    trading_bot_id VARCHAR(36)      NOT NULL,
+    version        INTEGER          NOT NULL,
+    config         TEXT             NOT NULL,
+    changed_fields TEXT             NOT NULL DEFAULT '[]',
+    created_at     TIMESTAMP        NOT NULL,
+
+    FOREIGN KEY (trading_bot_id) REFERENCES trade_bots(id),
+    UNIQUE (trading_bot_id, version)
+);
+
+ALTER TABLE trade_bots 
+ADD COLUMN config_version INTEGER DEFAULT 1;
+
+ALTER TABLE trading_decision_logs 
+ADD COLUMN config_version INTEGER DEFAULT 0;
+
+COMMENT ON COLUMN trading_bot_config_history.config IS 'Strategy, strategy params and trading parameters of the version, as JSON';
+COMMENT ON COLUMN trading_bot_config_history.changed_fields IS 'Fields changed from the previous version, as a JSON array';
+COMMENT ON COLUMN trading_bot_config_history.created_at IS 'When the version came into force';
+COMMENT ON COLUMN trade_bots.config_version IS 'Version of the config in force, see trading_bot_config_history';
+COMMENT ON COLUMN trading_decision_logs.config_version IS 'Bot config version the decision was made with (0 = before the config history)';
@@ -0,0 +1,8 @@
+-- Add exchange profiles to trade_bots table
+-- A bot trades on the exchange account of its profile, e.g. production or testnet, each with its own API keys
+
+ALTER TABLE trade_bots 
+ADD COLUMN exchange_profile VARCHAR(50) DEFAULT 'production';
+
+-- Add comments for documentation
+COMMENT ON COLUMN trade_bots.exchange_profile IS 'Exchange profile chosen at creation: the account, environment (production, testnet or custom URL) and API keys the bot trades with';
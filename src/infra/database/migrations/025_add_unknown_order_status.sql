@@ -0,0 +1,2 @@
+-- Orders sent without knowing whether the exchange placed them are recorded as UNKNOWN until looked up
+COMMENT ON COLUMN orders.status IS 'Exchange status (FILLED, PARTIALLY_FILLED, ...), REJECTED by validation, FAILED on an exchange error or UNKNOWN while the exchange has not confirmed nor denied the order';
@@ -0,0 +1,6 @@
+-- Store the full reason of the last exit
+-- Exits forced by the lifecycle actions or the risk limits carry free text reasons longer than 50 characters,
+-- which made every later update of the bot fail
+
+ALTER TABLE trade_bots
+ALTER COLUMN last_exit_reason TYPE TEXT;
@@ -0,0 +1,9 @@
+-- Add the number of exchange orders a bot order may be sent as to the order ledger
+-- A limit order is repriced under a client order ID per attempt, so an order of unknown status is looked up by the
+-- attempts it was sent with rather than those of the bot's current order execution
+
+ALTER TABLE orders
+ADD COLUMN max_attempts INTEGER NOT NULL DEFAULT 0;
+
+-- Add comments for documentation
+COMMENT ON COLUMN orders.max_attempts IS 'Exchange orders the order may be sent as: the limit attempts and the market fallback, 0 if unknown';
@@ -0,0 +1,325 @@
+package external
+
+import (
+	"context"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// BinanceExchange is the Binance adapter of the exchange port
+type BinanceExchange struct {
+	client *binance.Client
+}
+
+func NewBinanceExchange(client *binance.Client) *BinanceExchange {
+	return &BinanceExchange{
+		client: client,
+	}
+}
+
+func (b *BinanceExchange) GetKlines(ctx context.Context, query exchange.KlineQuery) ([]vo.Kline, error) {
+	service := b.client.NewKlinesService().
+		Symbol(query.Symbol).
+		Interval(query.Interval)
+	if query.StartTime > 0 {
+		service = service.StartTime(query.StartTime)
+	}
+	if query.EndTime > 0 {
+		service = service.EndTime(query.EndTime)
+	}
+	if query.Limit > 0 {
+		service = service.Limit(query.Limit)
+	}
+
+	binanceKlines, err := service.Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+	klines := make([]vo.Kline, len(binanceKlines))
+	for i, bkline := range binanceKlines {
+		kline, err := vo.NewKline(
+			parseBinanceFloat(bkline.Open),
+			parseBinanceFloat(bkline.Close),
+			parseBinanceFloat(bkline.High),
+			parseBinanceFloat(bkline.Low),
+			parseBinanceFloat(bkline.Volume),
+			bkline.CloseTime,
+		)
+		if err != nil {
+			return nil, err
+		}
+		klines[i] = kline
+	}
+	return klines, nil
+}
+
+func (b *BinanceExchange) PlaceOrder(ctx context.Context, request exchange.OrderRequest) (*exchange.Order, error) {
+	service := b.client.NewCreateOrderService().
+		Symbol(request.Symbol).
+		Side(binance.SideType(request.Side)).
+		Type(binance.OrderType(request.Type)).
+		Quantity(request.Quantity)
+	if request.ClientOrderID != "" {
+		service = service.NewClientOrderID(request.ClientOrderID)
+	}
+	if request.Price != "" {
+		service = service.Price(request.Price)
+	}
+	if request.TimeInForce != "" {
+		service = service.TimeInForce(binance.TimeInForceType(request.TimeInForce))
+	}
+
+	response, err := service.Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+	order := &exchange.Order{
+		Symbol:           response.Symbol,
+		OrderID:          response.OrderID,
+		ClientOrderID:    response.ClientOrderID,
+		Side:             entity.OrderSide(response.Side),
+		Type:             exchange.OrderType(response.Type),
+		Status:           exchange.OrderStatus(response.Status),
+		TimeInForce:      exchange.TimeInForce(response.TimeInForce),
+		Price:            parseBinanceFloat(response.Price),
+		Quantity:         parseBinanceFloat(response.OrigQuantity),
+		ExecutedQuantity: parseBinanceFloat(response.ExecutedQuantity),
+		QuoteQuantity:    parseBinanceFloat(response.CummulativeQuoteQuantity),
+		UpdatedAt:        binanceTime(response.TransactTime),
+	}
+	for _, fill := range response.Fills {
+		order.Fills = append(order.Fills, entity.Fill{
+			TradeID:         fill.TradeID,
+			Price:           parseBinanceFloat(fill.Price),
+			Quantity:        parseBinanceFloat(fill.Quantity),
+			Commission:      parseBinanceFloat(fill.Commission),
+			CommissionAsset: fill.CommissionAsset,
+		})
+	}
+	return order, nil
+}
+
+func (b *BinanceExchange) GetOrder(ctx context.Context, symbol string, orderID int64) (*exchange.Order, error) {
+	order, err := b.client.NewGetOrderService().Symbol(symbol).OrderID(orderID).Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+	return binanceOrderOf(order), nil
+}
+
+func (b *BinanceExchange) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*exchange.Order, error) {
+	order, err := b.client.NewGetOrderService().Symbol(symbol).OrigClientOrderID(clientOrderID).Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+	return binanceOrderOf(order), nil
+}
+
+func (b *BinanceExchange) CancelOrder(ctx context.Context, symbol string, orderID int64) (*exchange.Order, error) {
+	response, err := b.client.NewCancelOrderService().Symbol(symbol).OrderID(orderID).Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+	return &exchange.Order{
+		Symbol:           response.Symbol,
+		OrderID:          response.OrderID,
+		ClientOrderID:    response.OrigClientOrderID,
+		OrderListID:      response.OrderListID,
+		Side:             entity.OrderSide(response.Side),
+		Type:             exchange.OrderType(response.Type),
+		Status:           exchange.OrderStatus(response.Status),
+		TimeInForce:      exchange.TimeInForce(response.TimeInForce),
+		Price:            parseBinanceFloat(response.Price),
+		Quantity:         parseBinanceFloat(response.OrigQuantity),
+		ExecutedQuantity: parseBinanceFloat(response.ExecutedQuantity),
+		QuoteQuantity:    parseBinanceFloat(response.CummulativeQuoteQuantity),
+		UpdatedAt:        binanceTime(response.TransactTime),
+	}, nil
+}
+
+func (b *BinanceExchange) ListOrders(ctx context.Context, symbol string, limit int) ([]*exchange.Order, error) {
+	service := b.client.NewListOrdersService().Symbol(symbol)
+	if limit > 0 {
+		service = service.Limit(limit)
+	}
+	binanceOrders, err := service.Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+	orders := make([]*exchange.Order, len(binanceOrders))
+	for i, order := range binanceOrders {
+		orders[i] = binanceOrderOf(order)
+	}
+	return orders, nil
+}
+
+func (b *BinanceExchange) GetBookTicker(ctx context.Context, symbol string) (*exchange.BookTicker, error) {
+	tickers, err := b.client.NewListBookTickersService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+	if len(tickers) == 0 {
+		return nil, fmt.Errorf("no book ticker for %s", symbol)
+	}
+	return &exchange.BookTicker{
+		Symbol:      tickers[0].Symbol,
+		BidPrice:    parseBinanceFloat(tickers[0].BidPrice),
+		BidQuantity: parseBinanceFloat(tickers[0].BidQuantity),
+		AskPrice:    parseBinanceFloat(tickers[0].AskPrice),
+		AskQuantity: parseBinanceFloat(tickers[0].AskQuantity),
+	}, nil
+}
+
+func (b *BinanceExchange) PlaceOCO(ctx context.Context, request exchange.OCORequest) (*exchange.OCO, error) {
+	response, err := b.client.NewCreateOCOService().
+		Symbol(request.Symbol).
+		Side(binance.SideType(request.Side)).
+		Quantity(request.Quantity).
+		Price(request.Price).
+		StopPrice(request.StopPrice).
+		StopLimitPrice(request.StopLimitPrice).
+		StopLimitTimeInForce(binance.TimeInForceType(request.StopLimitTimeInForce)).
+		Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+
+	// The take profit is the LIMIT_MAKER leg, the stop loss the STOP_LOSS_LIMIT one
+	oco := &exchange.OCO{OrderListID: response.OrderListID}
+	for _, report := range response.OrderReports {
+		if report.Type == binance.OrderTypeLimitMaker {
+			oco.TakeProfitOrderID = report.OrderID
+		} else {
+			oco.StopLossOrderID = report.OrderID
+		}
+	}
+	return oco, nil
+}
+
+func (b *BinanceExchange) CancelOCO(ctx context.Context, symbol string, orderListID int64) error {
+	_, err := b.client.NewCancelOCOService().Symbol(symbol).OrderListID(orderListID).Do(ctx)
+	if err != nil {
+		return binanceErrorOf(err)
+	}
+	return nil
+}
+
+func (b *BinanceExchange) GetBalances(ctx context.Context) ([]exchange.Balance, error) {
+	account, err := b.client.NewGetAccountService().Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+	balances := make([]exchange.Balance, len(account.Balances))
+	for i, balance := range account.Balances {
+		balances[i] = exchange.Balance{
+			Asset:  balance.Asset,
+			Free:   parseBinanceFloat(balance.Free),
+			Locked: parseBinanceFloat(balance.Locked),
+		}
+	}
+	return balances, nil
+}
+
+func (b *BinanceExchange) GetSymbolRules(ctx context.Context) ([]exchange.SymbolRules, error) {
+	exchangeInfo, err := b.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, binanceErrorOf(err)
+	}
+	rules := make([]exchange.SymbolRules, len(exchangeInfo.Symbols))
+	for i, symbolInfo := range exchangeInfo.Symbols {
+		rules[i] = binanceSymbolRulesOf(symbolInfo.Symbol, symbolInfo.Filters)
+	}
+	return rules, nil
+}
+
+// binanceSymbolRulesOf reads the LOT_SIZE, PRICE_FILTER and MIN_NOTIONAL (or NOTIONAL) filters of a symbol
+func binanceSymbolRulesOf(symbol string, filters []map[string]interface{}) exchange.SymbolRules {
+	rules := exchange.SymbolRules{Symbol: symbol}
+	hasMinNotional := false
+	for _, filter := range filters {
+		switch filter["filterType"] {
+		case "LOT_SIZE":
+			rules.MinQuantity = parseBinanceFilter(filter["minQty"])
+			rules.MaxQuantity = parseBinanceFilter(filter["maxQty"])
+			rules.StepSize = parseBinanceFilter(filter["stepSize"])
+		case "PRICE_FILTER":
+			rules.MinPrice = parseBinanceFilter(filter["minPrice"])
+			rules.MaxPrice = parseBinanceFilter(filter["maxPrice"])
+			rules.TickSize = parseBinanceFilter(filter["tickSize"])
+		case "MIN_NOTIONAL":
+			rules.MinNotional = parseBinanceFilter(filter["minNotional"])
+			hasMinNotional = true
+		case "NOTIONAL":
+			// Some symbols use NOTIONAL instead of MIN_NOTIONAL
+			if !hasMinNotional {
+				rules.MinNotional = parseBinanceFilter(filter["minNotional"])
+			}
+		}
+	}
+	return rules
+}
+
+// binanceOrderOf converts an order queried on Binance
+func binanceOrderOf(order *binance.Order) *exchange.Order {
+	return &exchange.Order{
+		Symbol:           order.Symbol,
+		OrderID:          order.OrderID,
+		ClientOrderID:    order.ClientOrderID,
+		OrderListID:      order.OrderListId,
+		Side:             entity.OrderSide(order.Side),
+		Type:             exchange.OrderType(order.Type),
+		Status:           exchange.OrderStatus(order.Status),
+		TimeInForce:      exchange.TimeInForce(order.TimeInForce),
+		Price:            parseBinanceFloat(order.Price),
+		StopPrice:        parseBinanceFloat(order.StopPrice),
+		Quantity:         parseBinanceFloat(order.OrigQuantity),
+		ExecutedQuantity: parseBinanceFloat(order.ExecutedQuantity),
+		QuoteQuantity:    parseBinanceFloat(order.CummulativeQuoteQuantity),
+		UpdatedAt:        binanceTime(order.UpdateTime),
+	}
+}
+
+// binanceErrorOf maps the errors of the Binance API to the port's: unknown orders to ErrOrderNotFound and other
+// refusals to RejectedError. Network errors and the exchange's internal, timeout and rate limit errors are kept
+// as they are, the outcome of the request being unknown.
+func binanceErrorOf(err error) error {
+	var apiErr *common.APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsValid() {
+		return err
+	}
+	switch apiErr.Code {
+	case -1000, -1001, -1003, -1006, -1007, -1008:
+		return err
+	case -2013:
+		return fmt.Errorf("%w: %s", exchange.ErrOrderNotFound, apiErr.Message)
+	}
+	return &exchange.RejectedError{Code: int(apiErr.Code), Message: apiErr.Message}
+}
+
+func parseBinanceFloat(value string) float64 {
+	parsed, _ := strconv.ParseFloat(value, 64)
+	return parsed
+}
+
+func parseBinanceFilter(value interface{}) float64 {
+	str, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	return parseBinanceFloat(str)
+}
+
+func binanceTime(milliseconds int64) time.Time {
+	if milliseconds <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(milliseconds)
+}
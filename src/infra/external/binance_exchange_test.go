@@ -0,0 +1,64 @@
+package external
+
+import (
+	"crypgo-machine/src/domain/exchange"
+	"errors"
+	"testing"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+func TestBinanceErrorOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		rejected bool
+		notFound bool
+	}{
+		{"network error", errors.New("connection reset by peer"), false, false},
+		{"internal error", &common.APIError{Code: -1001, Message: "Internal error"}, false, false},
+		{"rate limit", &common.APIError{Code: -1003, Message: "Too many requests"}, false, false},
+		{"unknown order", &common.APIError{Code: -2013, Message: "Order does not exist."}, true, true},
+		{"insufficient balance", &common.APIError{Code: -2010, Message: "Account has insufficient balance"}, true, false},
+		{"filter failure", &common.APIError{Code: -1013, Message: "Filter failure: LOT_SIZE"}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := binanceErrorOf(tt.err)
+			if exchange.IsRejected(err) != tt.rejected {
+				t.Errorf("expected rejected=%v, got %v (%v)", tt.rejected, !tt.rejected, err)
+			}
+			if errors.Is(err, exchange.ErrOrderNotFound) != tt.notFound {
+				t.Errorf("expected not found=%v, got %v (%v)", tt.notFound, !tt.notFound, err)
+			}
+		})
+	}
+}
+
+func TestBinanceSymbolRulesOf(t *testing.T) {
+	rules := binanceSymbolRulesOf("SOLBRL", []map[string]interface{}{
+		{"filterType": "PRICE_FILTER", "minPrice": "0.01", "maxPrice": "100000.00", "tickSize": "0.01"},
+		{"filterType": "LOT_SIZE", "minQty": "0.01", "maxQty": "10000.00", "stepSize": "0.01"},
+		{"filterType": "NOTIONAL", "minNotional": "10.00"},
+	})
+
+	if rules.Symbol != "SOLBRL" || !rules.HasQuantityRule() || !rules.HasPriceRule() {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+	if rules.StepSize != 0.01 || rules.TickSize != 0.01 || rules.MaxQuantity != 10000.0 {
+		t.Errorf("unexpected lot size or price rules %+v", rules)
+	}
+	if rules.MinNotional != 10.0 {
+		t.Errorf("expected min notional from NOTIONAL 10.00, got %.2f", rules.MinNotional)
+	}
+
+	// MIN_NOTIONAL wins over NOTIONAL
+	rules = binanceSymbolRulesOf("BTCUSDT", []map[string]interface{}{
+		{"filterType": "MIN_NOTIONAL", "minNotional": "5.00"},
+		{"filterType": "NOTIONAL", "minNotional": "10.00"},
+	})
+	if rules.MinNotional != 5.0 || rules.HasQuantityRule() {
+		t.Errorf("unexpected rules %+v", rules)
+	}
+}
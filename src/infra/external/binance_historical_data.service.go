@@ -2,22 +2,21 @@ package external
 
 import (
 	"context"
+	"crypgo-machine/src/domain/exchange"
 	"crypgo-machine/src/domain/vo"
 	"fmt"
-	"github.com/adshao/go-binance/v2"
 	"sort"
-	"strconv"
 	"time"
 )
 
-// BinanceHistoricalDataService fetches historical data from Binance API
+// BinanceHistoricalDataService fetches historical data from the exchange, in batches of Binance's 1000 klines
 type BinanceHistoricalDataService struct {
-	client BinanceClientInterface
+	exchange exchange.Exchange
 }
 
-func NewBinanceHistoricalDataService(client BinanceClientInterface) *BinanceHistoricalDataService {
+func NewBinanceHistoricalDataService(exchangeClient exchange.Exchange) *BinanceHistoricalDataService {
 	return &BinanceHistoricalDataService{
-		client: client,
+		exchange: exchangeClient,
 	}
 }
 
@@ -83,54 +82,40 @@ func (s *BinanceHistoricalDataService) GetKlinesForPeriod(symbol string, startTi
 
 	// First batch: get 1000 klines
 	fmt.Printf("📊 Requesting first 1000 klines for %s with interval %s\n", symbol, interval)
-	binanceKlines1, err := s.client.NewKlinesService().
-		Symbol(symbol).
-		Interval(interval).
-		StartTime(startTime.UnixMilli()).
-		EndTime(endTime.UnixMilli()).
-		Limit(1000).
-		Do(ctx)
+	klines1, err := s.exchange.GetKlines(ctx, exchange.KlineQuery{
+		Symbol:    symbol,
+		Interval:  interval,
+		StartTime: startTime.UnixMilli(),
+		EndTime:   endTime.UnixMilli(),
+		Limit:     1000,
+	})
 
 	if err != nil {
 		fmt.Printf("❌ Binance API error (first batch): %v\n", err)
 		return nil, fmt.Errorf("failed to fetch first batch of klines from Binance: %w", err)
 	}
-	fmt.Printf("✅ Received %d klines from Binance (first batch)\n", len(binanceKlines1))
+	fmt.Printf("✅ Received %d klines from Binance (first batch)\n", len(klines1))
 
-	// Convert first batch
-	for _, bk := range binanceKlines1 {
-		kline, err := s.convertBinanceKlineToVOKline(bk)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert kline: %w", err)
-		}
-		allKlines = append(allKlines, kline)
-	}
+	allKlines = append(allKlines, klines1...)
 
 	// Second batch: get remaining klines (up to 440 more)
-	if len(binanceKlines1) == 1000 {
+	if len(klines1) == 1000 {
 		fmt.Printf("📊 Requesting remaining klines for %s with interval %s\n", symbol, interval)
-		binanceKlines2, err := s.client.NewKlinesService().
-			Symbol(symbol).
-			Interval(interval).
-			StartTime(startTime.UnixMilli()).
-			EndTime(endTime.UnixMilli()).
-			Limit(440).
-			Do(ctx)
+		klines2, err := s.exchange.GetKlines(ctx, exchange.KlineQuery{
+			Symbol:    symbol,
+			Interval:  interval,
+			StartTime: startTime.UnixMilli(),
+			EndTime:   endTime.UnixMilli(),
+			Limit:     440,
+		})
 
 		if err != nil {
 			fmt.Printf("❌ Binance API error (second batch): %v\n", err)
 			return nil, fmt.Errorf("failed to fetch second batch of klines from Binance: %w", err)
 		}
-		fmt.Printf("✅ Received %d klines from Binance (second batch)\n", len(binanceKlines2))
+		fmt.Printf("✅ Received %d klines from Binance (second batch)\n", len(klines2))
 
-		// Convert second batch
-		for _, bk := range binanceKlines2 {
-			kline, err := s.convertBinanceKlineToVOKline(bk)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert kline: %w", err)
-			}
-			allKlines = append(allKlines, kline)
-		}
+		allKlines = append(allKlines, klines2...)
 	}
 
 	// Sort klines by close time to ensure chronological order
@@ -160,46 +145,39 @@ func (s *BinanceHistoricalDataService) GetKlinesForWeekPeriod(symbol string, sta
 
 		fmt.Printf("📊 Requesting klines batch %d (limit: %d) for %s with interval %s\n", requestCount, limit, symbol, interval)
 
-		binanceKlines, err := s.client.NewKlinesService().
-			Symbol(symbol).
-			Interval(interval).
-			StartTime(startTime.UnixMilli()).
-			EndTime(endTime.UnixMilli()).
-			Limit(limit).
-			Do(ctx)
+		klines, err := s.exchange.GetKlines(ctx, exchange.KlineQuery{
+			Symbol:    symbol,
+			Interval:  interval,
+			StartTime: startTime.UnixMilli(),
+			EndTime:   endTime.UnixMilli(),
+			Limit:     limit,
+		})
 
 		if err != nil {
 			fmt.Printf("❌ Binance API error (batch %d): %v\n", requestCount, err)
 			return nil, fmt.Errorf("failed to fetch batch %d of klines from Binance: %w", requestCount, err)
 		}
 		// Log first and last kline dates for debugging
-		if len(binanceKlines) > 0 {
-			firstKline := binanceKlines[0]
-			lastKline := binanceKlines[len(binanceKlines)-1]
-			firstTime := time.Unix(firstKline.CloseTime/1000, 0)
-			lastTime := time.Unix(lastKline.CloseTime/1000, 0)
+		if len(klines) > 0 {
+			firstKline := klines[0]
+			lastKline := klines[len(klines)-1]
+			firstTime := time.Unix(firstKline.CloseTime()/1000, 0)
+			lastTime := time.Unix(lastKline.CloseTime()/1000, 0)
 			fmt.Printf("✅ Received %d klines from Binance (batch %d) | %s to %s\n", 
-				len(binanceKlines), requestCount, firstTime.Format("2006-01-02 15:04"), lastTime.Format("2006-01-02 15:04"))
+				len(klines), requestCount, firstTime.Format("2006-01-02 15:04"), lastTime.Format("2006-01-02 15:04"))
 		} else {
-			fmt.Printf("✅ Received %d klines from Binance (batch %d)\n", len(binanceKlines), requestCount)
+			fmt.Printf("✅ Received %d klines from Binance (batch %d)\n", len(klines), requestCount)
 		}
 
-		if len(binanceKlines) == 0 {
+		if len(klines) == 0 {
 			fmt.Printf("⚠️ No more data available from Binance after %d requests\n", requestCount)
 			break // No more data
 		}
 
-		// Convert batch
-		for _, bk := range binanceKlines {
-			kline, err := s.convertBinanceKlineToVOKline(bk)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert kline: %w", err)
-			}
-			allKlines = append(allKlines, kline)
-		}
+		allKlines = append(allKlines, klines...)
 
 		// If we got less than the limit, we've reached the end
-		if len(binanceKlines) < limit {
+		if len(klines) < limit {
 			break
 		}
 
@@ -219,36 +197,6 @@ func (s *BinanceHistoricalDataService) GetKlinesForWeekPeriod(symbol string, sta
 	return allKlines, nil
 }
 
-// convertBinanceKlineToVOKline converts a Binance Kline to domain vo.Kline
-func (s *BinanceHistoricalDataService) convertBinanceKlineToVOKline(bk *binance.Kline) (vo.Kline, error) {
-	open, err := strconv.ParseFloat(bk.Open, 64)
-	if err != nil {
-		return vo.Kline{}, fmt.Errorf("invalid open price: %w", err)
-	}
-
-	close, err := strconv.ParseFloat(bk.Close, 64)
-	if err != nil {
-		return vo.Kline{}, fmt.Errorf("invalid close price: %w", err)
-	}
-
-	high, err := strconv.ParseFloat(bk.High, 64)
-	if err != nil {
-		return vo.Kline{}, fmt.Errorf("invalid high price: %w", err)
-	}
-
-	low, err := strconv.ParseFloat(bk.Low, 64)
-	if err != nil {
-		return vo.Kline{}, fmt.Errorf("invalid low price: %w", err)
-	}
-
-	volume, err := strconv.ParseFloat(bk.Volume, 64)
-	if err != nil {
-		return vo.Kline{}, fmt.Errorf("invalid volume: %w", err)
-	}
-
-	return vo.NewKline(open, close, high, low, volume, bk.CloseTime)
-}
-
 // GetKlinesForCustomPeriod fetches klines for any custom period with configurable interval
 func (s *BinanceHistoricalDataService) GetKlinesForCustomPeriod(symbol string, startTime, endTime time.Time, interval string) ([]vo.Kline, error) {
 	ctx := context.Background()
@@ -294,46 +242,39 @@ func (s *BinanceHistoricalDataService) GetKlinesForCustomPeriod(symbol string, s
 
 		fmt.Printf("📊 Requesting klines batch %d (limit: %d) for %s with interval %s\n", requestCount, limit, symbol, interval)
 
-		binanceKlines, err := s.client.NewKlinesService().
-			Symbol(symbol).
-			Interval(interval).
-			StartTime(startTime.UnixMilli()).
-			EndTime(endTime.UnixMilli()).
-			Limit(limit).
-			Do(ctx)
+		klines, err := s.exchange.GetKlines(ctx, exchange.KlineQuery{
+			Symbol:    symbol,
+			Interval:  interval,
+			StartTime: startTime.UnixMilli(),
+			EndTime:   endTime.UnixMilli(),
+			Limit:     limit,
+		})
 
 		if err != nil {
 			fmt.Printf("❌ Binance API error (batch %d): %v\n", requestCount, err)
 			return nil, fmt.Errorf("failed to fetch batch %d of klines from Binance: %w", requestCount, err)
 		}
 		// Log first and last kline dates for debugging
-		if len(binanceKlines) > 0 {
-			firstKline := binanceKlines[0]
-			lastKline := binanceKlines[len(binanceKlines)-1]
-			firstTime := time.Unix(firstKline.CloseTime/1000, 0)
-			lastTime := time.Unix(lastKline.CloseTime/1000, 0)
+		if len(klines) > 0 {
+			firstKline := klines[0]
+			lastKline := klines[len(klines)-1]
+			firstTime := time.Unix(firstKline.CloseTime()/1000, 0)
+			lastTime := time.Unix(lastKline.CloseTime()/1000, 0)
 			fmt.Printf("✅ Received %d klines from Binance (batch %d) | %s to %s\n", 
-				len(binanceKlines), requestCount, firstTime.Format("2006-01-02 15:04"), lastTime.Format("2006-01-02 15:04"))
+				len(klines), requestCount, firstTime.Format("2006-01-02 15:04"), lastTime.Format("2006-01-02 15:04"))
 		} else {
-			fmt.Printf("✅ Received %d klines from Binance (batch %d)\n", len(binanceKlines), requestCount)
+			fmt.Printf("✅ Received %d klines from Binance (batch %d)\n", len(klines), requestCount)
 		}
 
-		if len(binanceKlines) == 0 {
+		if len(klines) == 0 {
 			fmt.Printf("⚠️ No more data available from Binance after %d requests\n", requestCount)
 			break // No more data
 		}
 
-		// Convert batch
-		for _, bk := range binanceKlines {
-			kline, err := s.convertBinanceKlineToVOKline(bk)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert kline: %w", err)
-			}
-			allKlines = append(allKlines, kline)
-		}
+		allKlines = append(allKlines, klines...)
 
 		// If we got less than the limit, we've reached the end
-		if len(binanceKlines) < limit {
+		if len(klines) < limit {
 			break
 		}
 
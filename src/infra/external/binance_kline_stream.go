@@ -0,0 +1,112 @@
+package external
+
+import (
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// KlineStreamServeFunc opens a kline WebSocket stream, see binance.WsKlineServe
+type KlineStreamServeFunc func(symbol, interval string, handler binance.WsKlineHandler, errHandler binance.ErrHandler) (doneC, stopC chan struct{}, err error)
+
+// BinanceKlineStream keeps Binance kline WebSocket streams open, reconnecting with exponential backoff
+type BinanceKlineStream struct {
+	serve      KlineStreamServeFunc
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// NewBinanceKlineStream creates a kline stream on the Binance WebSocket API, retrying from 1s up to 1m apart
+func NewBinanceKlineStream() *BinanceKlineStream {
+	return NewBinanceKlineStreamWithOptions(binance.WsKlineServe, time.Second, time.Minute)
+}
+
+// NewBinanceKlineStreamWithOptions creates a kline stream with a custom WebSocket dialer and backoff
+func NewBinanceKlineStreamWithOptions(serve KlineStreamServeFunc, minBackoff, maxBackoff time.Duration) *BinanceKlineStream {
+	return &BinanceKlineStream{
+		serve:      serve,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+	}
+}
+
+// Subscribe streams every kline update of symbol and interval to onKline until stop is called.
+// isFinal is true for the update that closes a candle. onConnect runs after every (re)connection,
+// so callers can resync the candles missed while disconnected.
+func (s *BinanceKlineStream) Subscribe(symbol, interval string, onKline func(kline vo.Kline, isFinal bool), onConnect func()) (stop func()) {
+	stopC := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		backoff := s.minBackoff
+		for {
+			doneC, wsStopC, err := s.serve(symbol, interval, func(event *binance.WsKlineEvent) {
+				kline, err := convertWsKline(event.Kline)
+				if err != nil {
+					fmt.Printf("⚠️ [%s] Invalid kline from stream: %v\n", symbol, err)
+					return
+				}
+				onKline(kline, event.Kline.IsFinal)
+			}, func(err error) {
+				fmt.Printf("⚠️ [%s] Kline stream error: %v\n", symbol, err)
+			})
+
+			if err != nil {
+				fmt.Printf("⚠️ [%s] Failed to connect kline stream %s, retrying in %s: %v\n", symbol, interval, backoff, err)
+			} else {
+				backoff = s.minBackoff
+				if onConnect != nil {
+					onConnect()
+				}
+
+				select {
+				case <-stopC:
+					close(wsStopC)
+					<-doneC
+					return
+				case <-doneC:
+					fmt.Printf("🔌 [%s] Kline stream %s disconnected, reconnecting in %s\n", symbol, interval, backoff)
+				}
+			}
+
+			select {
+			case <-stopC:
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+			if backoff > s.maxBackoff {
+				backoff = s.maxBackoff
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(stopC) })
+	}
+}
+
+// convertWsKline converts a stream kline, failing when one of its values is not a number
+func convertWsKline(wsKline binance.WsKline) (vo.Kline, error) {
+	values := make([]float64, 5)
+	for i, field := range []struct{ name, value string }{
+		{"open", wsKline.Open},
+		{"close", wsKline.Close},
+		{"high", wsKline.High},
+		{"low", wsKline.Low},
+		{"volume", wsKline.Volume},
+	} {
+		parsed, err := strconv.ParseFloat(field.value, 64)
+		if err != nil {
+			return vo.Kline{}, fmt.Errorf("invalid %s %q: %v", field.name, field.value, err)
+		}
+		values[i] = parsed
+	}
+
+	return vo.NewKline(values[0], values[1], values[2], values[3], values[4], wsKline.EndTime)
+}
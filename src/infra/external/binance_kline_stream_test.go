@@ -0,0 +1,27 @@
+package external
+
+import (
+	"testing"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+func TestConvertWsKline(t *testing.T) {
+	kline, err := convertWsKline(binance.WsKline{Open: "100.5", Close: "101.25", High: "102", Low: "99.75", Volume: "12.5", EndTime: 1704110459999})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kline.Open() != 100.5 || kline.Close() != 101.25 || kline.High() != 102 || kline.Low() != 99.75 || kline.Volume() != 12.5 || kline.CloseTime() != 1704110459999 {
+		t.Errorf("expected the stream values to be kept, got %+v", kline)
+	}
+
+	for _, invalid := range []binance.WsKline{
+		{Open: "", Close: "101", High: "102", Low: "99", Volume: "1"},
+		{Open: "100", Close: "abc", High: "102", Low: "99", Volume: "1"},
+		{Open: "100", Close: "101", High: "102", Low: "99", Volume: "NaN?"},
+	} {
+		if _, err := convertWsKline(invalid); err == nil {
+			t.Errorf("expected error for kline %+v", invalid)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package external
+
+import (
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/gorilla/websocket"
+)
+
+// BinanceWsServerFake is a local WebSocket server speaking the Binance kline stream protocol, for testing
+type BinanceWsServerFake struct {
+	server      *httptest.Server
+	upgrader    websocket.Upgrader
+	mu          sync.Mutex
+	conns       []*websocket.Conn
+	connections int
+	streams     []string
+}
+
+func NewBinanceWsServerFake() *BinanceWsServerFake {
+	f := &BinanceWsServerFake{}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+// URL returns the base WebSocket endpoint, to be used as binance.BaseWsMainURL
+func (f *BinanceWsServerFake) URL() string {
+	return "ws" + strings.TrimPrefix(f.server.URL, "http") + "/ws"
+}
+
+func (f *BinanceWsServerFake) handle(w http.ResponseWriter, r *http.Request) {
+	conn, err := f.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	f.conns = append(f.conns, conn)
+	f.connections++
+	f.streams = append(f.streams, strings.TrimPrefix(r.URL.Path, "/ws/"))
+	f.mu.Unlock()
+
+	// Drain client frames so control messages (close, pong) are handled
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// SendKline pushes a kline event for the candle closing at kline.CloseTime() to every open connection
+func (f *BinanceWsServerFake) SendKline(symbol, interval string, kline vo.Kline, isFinal bool) error {
+	event := binance.WsKlineEvent{
+		Event:  "kline",
+		Time:   time.Now().UnixMilli(),
+		Symbol: symbol,
+		Kline: binance.WsKline{
+			StartTime: kline.CloseTime() - intervalMillis(interval) + 1,
+			EndTime:   kline.CloseTime(),
+			Symbol:    symbol,
+			Interval:  interval,
+			Open:      fmt.Sprintf("%f", kline.Open()),
+			Close:     fmt.Sprintf("%f", kline.Close()),
+			High:      fmt.Sprintf("%f", kline.High()),
+			Low:       fmt.Sprintf("%f", kline.Low()),
+			Volume:    fmt.Sprintf("%f", kline.Volume()),
+			IsFinal:   isFinal,
+		},
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, conn := range f.conns {
+		if err := conn.WriteJSON(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropConnections closes every open connection, simulating a network failure
+func (f *BinanceWsServerFake) DropConnections() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, conn := range f.conns {
+		conn.Close()
+	}
+	f.conns = nil
+}
+
+// WaitForConnections waits until the server accepted count connections in total
+func (f *BinanceWsServerFake) WaitForConnections(count int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if f.GetConnectionCount() >= count {
+			return nil
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return fmt.Errorf("expected %d connections, got %d", count, f.GetConnectionCount())
+}
+
+// GetConnectionCount returns how many connections the server accepted
+func (f *BinanceWsServerFake) GetConnectionCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connections
+}
+
+// GetStreams returns the stream names requested by each connection (e.g. solbrl@kline_1m)
+func (f *BinanceWsServerFake) GetStreams() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.streams...)
+}
+
+func (f *BinanceWsServerFake) Close() {
+	f.DropConnections()
+	f.server.Close()
+}
+
+func intervalMillis(interval string) int64 {
+	for _, seconds := range []int{60, 180, 300, 900, 1800, 3600, 7200, 14400, 21600, 28800, 43200, 86400} {
+		if candidate, _ := exchange.SecondsToInterval(seconds); candidate == interval {
+			return int64(seconds) * 1000
+		}
+	}
+	return 60000
+}
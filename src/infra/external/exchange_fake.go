@@ -0,0 +1,565 @@
+package external
+
+import (
+	"context"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/exchange"
+	"crypgo-machine/src/domain/vo"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// FakeExchange simulates an exchange behind the exchange port for testing, with Binance's rules for limit,
+// post-only and OCO orders
+type FakeExchange struct {
+	predefinedKlines []vo.Kline
+	shouldFailKlines bool
+	shouldFailOrder  bool
+	orderCounter     int64
+	orderFills       [][]entity.Fill
+	bookTickers      map[string]*exchange.BookTicker
+	limitFillRatios  []float64
+	limitOrders      map[int64]*fakeLimitOrder
+	placedOrders     []FakePlacedOrder
+	canceledOrderIDs []int64
+	ocoCounter       int64
+	ocoLegs          map[int64][]int64
+	placedOCOs       []FakePlacedOCO
+	canceledOCOIDs   []int64
+	orderHistory     []*exchange.Order
+	balances         map[string]float64
+	orderFailures    []FakeOrderFailure
+}
+
+// FakePlacedOrder records an order sent to the fake exchange
+type FakePlacedOrder struct {
+	OrderID int64
+	exchange.OrderRequest
+}
+
+// FakeOrderFailure makes an order request fail with Err. When Placed is true the order still reaches the
+// exchange and only its response is lost, like a request timing out after the exchange accepted it.
+type FakeOrderFailure struct {
+	Err    error
+	Placed bool
+}
+
+// FakePlacedOCO records an OCO order sent to the fake exchange
+type FakePlacedOCO struct {
+	TakeProfitOrderID int64
+	StopLossOrderID   int64
+	OrderListID       int64
+	exchange.OCORequest
+}
+
+// fakeLimitOrder is a limit order of the fake exchange, filling fillRatio of its quantity while on the book
+type fakeLimitOrder struct {
+	order     *exchange.Order
+	fillRatio float64
+}
+
+func NewFakeExchange() *FakeExchange {
+	return &FakeExchange{
+		predefinedKlines: make([]vo.Kline, 0),
+		orderCounter:     1,
+		bookTickers:      make(map[string]*exchange.BookTicker),
+		limitOrders:      make(map[int64]*fakeLimitOrder),
+		ocoLegs:          make(map[int64][]int64),
+		balances:         make(map[string]float64),
+	}
+}
+
+// SetPredefinedKlines sets the klines that will be returned by the fake exchange
+func (f *FakeExchange) SetPredefinedKlines(klines []vo.Kline) {
+	f.predefinedKlines = klines
+}
+
+// SetShouldFailKlines makes the klines requests fail
+func (f *FakeExchange) SetShouldFailKlines(shouldFail bool) {
+	f.shouldFailKlines = shouldFail
+}
+
+// SetShouldFailOrder makes the order requests fail
+func (f *FakeExchange) SetShouldFailOrder(shouldFail bool) {
+	f.shouldFailOrder = shouldFail
+}
+
+// AddOrderFills queues the fills returned by the next market order, one call per order.
+// Orders placed with no fills queued return no fills and no cost, only their executed quantity.
+func (f *FakeExchange) AddOrderFills(fills ...entity.Fill) {
+	f.orderFills = append(f.orderFills, fills)
+}
+
+// SetBookTicker sets the best bid and ask returned for symbol
+func (f *FakeExchange) SetBookTicker(symbol string, bid, ask float64) {
+	f.bookTickers[symbol] = &exchange.BookTicker{
+		Symbol:      symbol,
+		BidPrice:    bid,
+		BidQuantity: 100,
+		AskPrice:    ask,
+		AskQuantity: 100,
+	}
+}
+
+// AddLimitFillRatios queues how much of the next limit orders fills, one ratio of the order quantity per order:
+// 0 leaves it unfilled, 0.5 fills half of it and 1 fills it. GTC and post-only orders fill while resting on the
+// book, seen when they are queried or canceled; IOC and FOK orders fill at once. Orders with no ratio queued do
+// not fill.
+func (f *FakeExchange) AddLimitFillRatios(ratios ...float64) {
+	f.limitFillRatios = append(f.limitFillRatios, ratios...)
+}
+
+// AddOrderFailures makes the next order requests fail, one failure per request
+func (f *FakeExchange) AddOrderFailures(failures ...FakeOrderFailure) {
+	f.orderFailures = append(f.orderFailures, failures...)
+}
+
+// GetPlacedOrders returns the orders sent to the fake exchange, in order
+func (f *FakeExchange) GetPlacedOrders() []FakePlacedOrder {
+	return f.placedOrders
+}
+
+// GetCanceledOrderIDs returns the IDs of the orders canceled on the fake exchange, in order
+func (f *FakeExchange) GetCanceledOrderIDs() []int64 {
+	return f.canceledOrderIDs
+}
+
+// SetBalance sets the free balance of an asset, replacing the default balances
+func (f *FakeExchange) SetBalance(asset string, free float64) {
+	f.balances[asset] = free
+}
+
+// AddOrderHistory adds orders, e.g. trades made by hand, to the order history of their symbol
+func (f *FakeExchange) AddOrderHistory(orders ...*exchange.Order) {
+	f.orderHistory = append(f.orderHistory, orders...)
+}
+
+// GetPlacedOCOs returns the OCO orders sent to the fake exchange
+func (f *FakeExchange) GetPlacedOCOs() []FakePlacedOCO {
+	return f.placedOCOs
+}
+
+// GetCanceledOCOIDs returns the order list IDs of the OCO orders canceled on the fake exchange
+func (f *FakeExchange) GetCanceledOCOIDs() []int64 {
+	return f.canceledOCOIDs
+}
+
+// TriggerOCO simulates the market reaching one leg of an OCO: that leg fills and the other one expires
+func (f *FakeExchange) TriggerOCO(orderListID int64, takeProfit bool) {
+	legs, ok := f.ocoLegs[orderListID]
+	if !ok {
+		return
+	}
+	for _, orderID := range legs {
+		order := f.limitOrders[orderID].order
+		if (order.Type == exchange.OrderTypeLimitMaker) == takeProfit {
+			fillFakeOrder(order, 1)
+		} else {
+			order.Status = exchange.OrderStatusExpired
+		}
+	}
+}
+
+func (f *FakeExchange) GetKlines(ctx context.Context, query exchange.KlineQuery) ([]vo.Kline, error) {
+	if f.shouldFailKlines {
+		return nil, fmt.Errorf("simulated klines error")
+	}
+
+	// Return predefined klines or generate default ones
+	if len(f.predefinedKlines) > 0 {
+		return f.predefinedKlines, nil
+	}
+	return generateDefaultKlines(query.Limit), nil
+}
+
+func generateDefaultKlines(limit int) []vo.Kline {
+	if limit == 0 {
+		limit = 50 // Default limit
+	}
+
+	klines := make([]vo.Kline, limit)
+	basePrice := 100.0
+	for i := 0; i < limit; i++ {
+		price := basePrice + float64(i)*0.5
+		klines[i] = fakeKline(price, price+0.1, price+0.3, price-0.2, 100.0, 1640995200000+int64(i*3600000)) // Each hour
+	}
+	return klines
+}
+
+func (f *FakeExchange) PlaceOrder(ctx context.Context, request exchange.OrderRequest) (*exchange.Order, error) {
+	if f.shouldFailOrder {
+		return nil, &exchange.RejectedError{Code: -2010, Message: "simulated order error"}
+	}
+	if request.ClientOrderID != "" {
+		for _, order := range f.orderHistory {
+			if order.ClientOrderID == request.ClientOrderID && (order.Status == exchange.OrderStatusNew || order.Status == exchange.OrderStatusPartiallyFilled) {
+				return nil, &exchange.RejectedError{Code: -2010, Message: "Duplicate order sent."}
+			}
+		}
+	}
+
+	if len(f.orderFailures) > 0 {
+		failure := f.orderFailures[0]
+		f.orderFailures = f.orderFailures[1:]
+		if failure.Placed {
+			if _, err := f.place(request); err != nil {
+				return nil, err
+			}
+		}
+		return nil, failure.Err
+	}
+	return f.place(request)
+}
+
+func (f *FakeExchange) place(request exchange.OrderRequest) (*exchange.Order, error) {
+	if request.Type == exchange.OrderTypeLimit || request.Type == exchange.OrderTypeLimitMaker {
+		return f.placeLimitOrder(request)
+	}
+
+	f.orderCounter++
+	f.recordPlacedOrder(request)
+
+	quantity, _ := strconv.ParseFloat(request.Quantity, 64)
+	order := &exchange.Order{
+		Symbol:           request.Symbol,
+		OrderID:          f.orderCounter,
+		ClientOrderID:    request.ClientOrderID,
+		Side:             request.Side,
+		Type:             request.Type,
+		Status:           exchange.OrderStatusFilled,
+		Quantity:         quantity,
+		ExecutedQuantity: quantity,
+	}
+
+	var fills []entity.Fill
+	if len(f.orderFills) > 0 {
+		fills = f.orderFills[0]
+		f.orderFills = f.orderFills[1:]
+		order.ExecutedQuantity = 0
+		for _, fill := range fills {
+			order.ExecutedQuantity += fill.Quantity
+			order.QuoteQuantity += fill.Price * fill.Quantity
+		}
+		order.UpdatedAt = time.Now()
+	}
+
+	f.orderHistory = append(f.orderHistory, order)
+	response := *order
+	response.Fills = fills
+	return &response, nil
+}
+
+func (f *FakeExchange) recordPlacedOrder(request exchange.OrderRequest) {
+	f.placedOrders = append(f.placedOrders, FakePlacedOrder{OrderID: f.orderCounter, OrderRequest: request})
+}
+
+// placeLimitOrder simulates a limit order: post-only orders crossing the book are refused, IOC and FOK orders
+// fill at once and GTC or post-only orders rest on the book until queried or canceled
+func (f *FakeExchange) placeLimitOrder(request exchange.OrderRequest) (*exchange.Order, error) {
+	price, _ := strconv.ParseFloat(request.Price, 64)
+	quantity, _ := strconv.ParseFloat(request.Quantity, 64)
+	if price <= 0 || quantity <= 0 {
+		return nil, fmt.Errorf("invalid limit order: price %q, quantity %q", request.Price, request.Quantity)
+	}
+
+	if ticker, ok := f.bookTickers[request.Symbol]; ok && request.Type == exchange.OrderTypeLimitMaker {
+		if (request.Side == entity.OrderSideBuy && price >= ticker.AskPrice) || (request.Side == entity.OrderSideSell && price <= ticker.BidPrice) {
+			return nil, &exchange.RejectedError{Code: -2010, Message: "Order would immediately match and take."}
+		}
+	}
+
+	fillRatio := 0.0
+	if len(f.limitFillRatios) > 0 {
+		fillRatio = f.limitFillRatios[0]
+		f.limitFillRatios = f.limitFillRatios[1:]
+	}
+	if fillRatio > 1 {
+		fillRatio = 1
+	}
+
+	f.orderCounter++
+	f.recordPlacedOrder(request)
+	order := &exchange.Order{
+		Symbol:        request.Symbol,
+		OrderID:       f.orderCounter,
+		ClientOrderID: request.ClientOrderID,
+		Side:          request.Side,
+		Type:          request.Type,
+		Status:        exchange.OrderStatusNew,
+		TimeInForce:   request.TimeInForce,
+		Price:         price,
+		Quantity:      quantity,
+		UpdatedAt:     time.Now(),
+	}
+
+	var fills []entity.Fill
+	switch request.TimeInForce {
+	case exchange.TimeInForceIOC, exchange.TimeInForceFOK:
+		if request.TimeInForce == exchange.TimeInForceFOK && fillRatio < 1 {
+			fillRatio = 0
+		}
+		fillFakeOrder(order, fillRatio)
+		if order.Status != exchange.OrderStatusFilled {
+			order.Status = exchange.OrderStatusExpired
+		}
+		if fillRatio > 0 {
+			fills = []entity.Fill{{TradeID: order.OrderID, Price: price, Quantity: order.ExecutedQuantity}}
+		}
+	default:
+		f.limitOrders[order.OrderID] = &fakeLimitOrder{order: order, fillRatio: fillRatio}
+	}
+	f.orderHistory = append(f.orderHistory, order)
+
+	response := *order
+	response.Fills = fills
+	return &response, nil
+}
+
+// fillFakeOrder fills ratio of the order quantity at its limit price
+func fillFakeOrder(order *exchange.Order, ratio float64) {
+	order.ExecutedQuantity = roundFakeQuantity(order.Quantity * ratio)
+	order.QuoteQuantity = roundFakeQuantity(order.ExecutedQuantity * order.Price)
+	order.UpdatedAt = time.Now()
+	switch {
+	case ratio >= 1:
+		order.Status = exchange.OrderStatusFilled
+	case ratio > 0:
+		order.Status = exchange.OrderStatusPartiallyFilled
+	}
+}
+
+// roundFakeQuantity rounds to the 8 decimals the exchange reports
+func roundFakeQuantity(value float64) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'f', 8, 64), 64)
+	return rounded
+}
+
+// restingOrder returns a resting limit order after it filled what it was going to fill on the book
+func (f *FakeExchange) restingOrder(orderID int64) (*exchange.Order, error) {
+	resting, ok := f.limitOrders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", exchange.ErrOrderNotFound, orderID)
+	}
+	if resting.order.Status == exchange.OrderStatusNew && resting.fillRatio > 0 {
+		fillFakeOrder(resting.order, resting.fillRatio)
+	}
+	return resting.order, nil
+}
+
+func (f *FakeExchange) GetOrder(ctx context.Context, symbol string, orderID int64) (*exchange.Order, error) {
+	order, err := f.restingOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	copied := *order
+	return &copied, nil
+}
+
+// GetOrderByClientID returns the latest order placed with the client order ID
+func (f *FakeExchange) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*exchange.Order, error) {
+	for i := len(f.orderHistory) - 1; i >= 0; i-- {
+		order := f.orderHistory[i]
+		if order.ClientOrderID != clientOrderID {
+			continue
+		}
+		if _, resting := f.limitOrders[order.OrderID]; resting {
+			if _, err := f.restingOrder(order.OrderID); err != nil {
+				return nil, err
+			}
+		}
+		copied := *order
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("%w: %s", exchange.ErrOrderNotFound, clientOrderID)
+}
+
+func (f *FakeExchange) CancelOrder(ctx context.Context, symbol string, orderID int64) (*exchange.Order, error) {
+	order, err := f.restingOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == exchange.OrderStatusFilled || order.Status == exchange.OrderStatusCanceled {
+		return nil, &exchange.RejectedError{Code: -2011, Message: "Unknown order sent."}
+	}
+
+	order.Status = exchange.OrderStatusCanceled
+	order.UpdatedAt = time.Now()
+	f.canceledOrderIDs = append(f.canceledOrderIDs, order.OrderID)
+	copied := *order
+	return &copied, nil
+}
+
+// ListOrders returns the orders of the symbol, oldest first like the exchange
+func (f *FakeExchange) ListOrders(ctx context.Context, symbol string, limit int) ([]*exchange.Order, error) {
+	var orders []*exchange.Order
+	for _, order := range f.orderHistory {
+		if order.Symbol == symbol {
+			copied := *order
+			orders = append(orders, &copied)
+		}
+	}
+	if limit > 0 && len(orders) > limit {
+		orders = orders[len(orders)-limit:]
+	}
+	return orders, nil
+}
+
+func (f *FakeExchange) GetBookTicker(ctx context.Context, symbol string) (*exchange.BookTicker, error) {
+	ticker, ok := f.bookTickers[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no book ticker for %s", symbol)
+	}
+	copied := *ticker
+	return &copied, nil
+}
+
+// PlaceOCO places both legs resting on the book until TriggerOCO
+func (f *FakeExchange) PlaceOCO(ctx context.Context, request exchange.OCORequest) (*exchange.OCO, error) {
+	if f.shouldFailOrder {
+		return nil, fmt.Errorf("simulated OCO order failure")
+	}
+	price, _ := strconv.ParseFloat(request.Price, 64)
+	stopPrice, _ := strconv.ParseFloat(request.StopPrice, 64)
+	stopLimitPrice, _ := strconv.ParseFloat(request.StopLimitPrice, 64)
+	quantity, _ := strconv.ParseFloat(request.Quantity, 64)
+	if request.Side == entity.OrderSideSell && (stopPrice >= price || stopLimitPrice > stopPrice) {
+		return nil, &exchange.RejectedError{Code: -1013, Message: "The relationship of the prices for the orders is not correct."}
+	}
+
+	f.ocoCounter++
+	oco := &exchange.OCO{OrderListID: f.ocoCounter}
+	now := time.Now()
+
+	// The stop-loss leg is placed first, as Binance reports it
+	legs := []*exchange.Order{
+		{Type: exchange.OrderTypeStopLossLimit, Price: stopLimitPrice, StopPrice: stopPrice, TimeInForce: request.StopLimitTimeInForce},
+		{Type: exchange.OrderTypeLimitMaker, Price: price},
+	}
+	for _, order := range legs {
+		f.orderCounter++
+		order.Symbol = request.Symbol
+		order.OrderID = f.orderCounter
+		order.OrderListID = oco.OrderListID
+		order.Side = request.Side
+		order.Status = exchange.OrderStatusNew
+		order.Quantity = quantity
+		order.UpdatedAt = now
+		f.limitOrders[order.OrderID] = &fakeLimitOrder{order: order}
+		f.orderHistory = append(f.orderHistory, order)
+		f.ocoLegs[oco.OrderListID] = append(f.ocoLegs[oco.OrderListID], order.OrderID)
+	}
+	oco.StopLossOrderID = legs[0].OrderID
+	oco.TakeProfitOrderID = legs[1].OrderID
+
+	f.placedOCOs = append(f.placedOCOs, FakePlacedOCO{
+		OrderListID:       oco.OrderListID,
+		TakeProfitOrderID: oco.TakeProfitOrderID,
+		StopLossOrderID:   oco.StopLossOrderID,
+		OCORequest:        request,
+	})
+	return oco, nil
+}
+
+func (f *FakeExchange) CancelOCO(ctx context.Context, symbol string, orderListID int64) error {
+	legs, ok := f.ocoLegs[orderListID]
+	if !ok {
+		return &exchange.RejectedError{Code: -2011, Message: "Order list does not exist."}
+	}
+	for _, orderID := range legs {
+		if f.limitOrders[orderID].order.Status != exchange.OrderStatusNew {
+			return &exchange.RejectedError{Code: -2011, Message: "Unknown order sent."}
+		}
+	}
+
+	for _, orderID := range legs {
+		order := f.limitOrders[orderID].order
+		order.Status = exchange.OrderStatusCanceled
+		order.UpdatedAt = time.Now()
+	}
+	f.canceledOCOIDs = append(f.canceledOCOIDs, orderListID)
+	return nil
+}
+
+func (f *FakeExchange) GetBalances(ctx context.Context) ([]exchange.Balance, error) {
+	if len(f.balances) > 0 {
+		var balances []exchange.Balance
+		for asset, free := range f.balances {
+			balances = append(balances, exchange.Balance{Asset: asset, Free: free})
+		}
+		return balances, nil
+	}
+	return []exchange.Balance{
+		{Asset: "BTC", Free: 1.0},
+		{Asset: "ETH", Free: 10.0},
+	}, nil
+}
+
+// GetSymbolRules returns the rules of common symbols
+func (f *FakeExchange) GetSymbolRules(ctx context.Context) ([]exchange.SymbolRules, error) {
+	return []exchange.SymbolRules{
+		{Symbol: "BTCUSDT", MinQuantity: 0.00001, MaxQuantity: 9000, StepSize: 0.00001, MinPrice: 0.01, MaxPrice: 1000000, TickSize: 0.01, MinNotional: 10},
+		{Symbol: "ETHUSDT", MinQuantity: 0.0001, MaxQuantity: 9000, StepSize: 0.0001, MinPrice: 0.01, MaxPrice: 1000000, TickSize: 0.01, MinNotional: 10},
+		{Symbol: "XRPBRL", MinQuantity: 0.1, MaxQuantity: 90000, StepSize: 0.1, MinPrice: 0.001, MaxPrice: 1000, TickSize: 0.001, MinNotional: 10},
+		{Symbol: "SOLBRL", MinQuantity: 0.01, MaxQuantity: 9000, StepSize: 0.01, MinPrice: 0.1, MaxPrice: 10000, TickSize: 0.1, MinNotional: 10},
+	}, nil
+}
+
+// fakeKline builds a kline of the test data, valid by construction
+func fakeKline(open, close, high, low, volume float64, closeTime int64) vo.Kline {
+	kline, err := vo.NewKline(open, close, high, low, volume, closeTime)
+	if err != nil {
+		panic(fmt.Sprintf("invalid fake kline: %v", err))
+	}
+	return kline
+}
+
+// CreateWhipsawKlines creates klines that will cause whipsaw signals
+func CreateWhipsawKlines() []vo.Kline {
+	baseKlines := []vo.Kline{}
+
+	// Generate 40 base klines with stable trend
+	basePrice := 800.0
+	for i := 0; i < 40; i++ {
+		price := basePrice + float64(i)*0.5
+		baseKlines = append(baseKlines, fakeKline(price, price+0.3, price+0.5, price-0.2, 100, 1640995200000+int64(i*3600000)))
+	}
+
+	// Add whipsaw klines at the end
+	baseKlines = append(baseKlines,
+		fakeKline(830.0, 831.0, 832.0, 829.0, 100, 1641139200000),
+		fakeKline(831.0, 832.0, 833.0, 830.0, 100, 1641142800000),
+		fakeKline(832.0, 833.0, 834.0, 831.0, 100, 1641146400000),
+		fakeKline(833.0, 835.0, 836.0, 832.0, 100, 1641150000000),
+		fakeKline(835.0, 834.5, 836.0, 833.0, 100, 1641153600000),
+		fakeKline(834.5, 834.0, 835.0, 833.0, 100, 1641157200000),
+		fakeKline(834.0, 833.5, 834.5, 832.0, 100, 1641160800000),
+	)
+
+	return baseKlines
+}
+
+// CreateStrongTrendKlines creates klines with strong trend and sufficient spread
+func CreateStrongTrendKlines() []vo.Kline {
+	baseKlines := []vo.Kline{}
+
+	// Generate 40 base klines with moderate trend
+	basePrice := 700.0
+	for i := 0; i < 40; i++ {
+		price := basePrice + float64(i)*1.5 // Gradual uptrend
+		baseKlines = append(baseKlines, fakeKline(price, price+1.2, price+1.5, price-0.5, 100, 1640995200000+int64(i*3600000)))
+	}
+
+	// Add strong trend klines at the end
+	baseKlines = append(baseKlines,
+		fakeKline(760.0, 770.0, 771.0, 759.0, 100, 1641139200000),
+		fakeKline(770.0, 780.0, 781.0, 769.0, 100, 1641142800000),
+		fakeKline(780.0, 790.0, 791.0, 779.0, 100, 1641146400000),
+		fakeKline(790.0, 800.0, 801.0, 789.0, 100, 1641150000000),
+		fakeKline(800.0, 810.0, 811.0, 799.0, 100, 1641153600000),
+		fakeKline(810.0, 820.0, 821.0, 809.0, 100, 1641157200000),
+		fakeKline(820.0, 830.0, 831.0, 819.0, 100, 1641160800000),
+	)
+
+	return baseKlines
+}
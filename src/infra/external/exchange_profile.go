@@ -0,0 +1,119 @@
+package external
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"fmt"
+	"strings"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// Environments an exchange profile connects to
+const (
+	ExchangeEnvironmentProduction = "production"
+	ExchangeEnvironmentTestnet    = "testnet"
+	ExchangeEnvironmentCustom     = "custom" // Any base URL, e.g. a local mock of the exchange
+)
+
+// ExchangeProfile is an exchange account bots trade on: the environment it lives in and its API keys
+type ExchangeProfile struct {
+	Name        string
+	Environment string
+	BaseURL     string // Custom environment only
+	APIKey      string
+	SecretKey   string
+}
+
+// APIBaseURL returns the REST endpoint of the profile's environment
+func (p ExchangeProfile) APIBaseURL() string {
+	switch p.Environment {
+	case ExchangeEnvironmentTestnet:
+		return binance.BaseAPITestnetURL
+	case ExchangeEnvironmentCustom:
+		return p.BaseURL
+	}
+	return binance.BaseAPIMainURL
+}
+
+// LoadExchangeProfiles reads the exchange profiles from the environment. The default profile always exists, with
+// the BINANCE_API_KEY and BINANCE_SECRET_KEY keys on production unless overridden; further profiles are listed in
+// EXCHANGE_PROFILES (e.g. "testnet,mock"). Each profile NAME reads:
+//
+//	EXCHANGE_PROFILE_<NAME>_ENVIRONMENT  production, testnet or custom (default production)
+//	EXCHANGE_PROFILE_<NAME>_BASE_URL     REST endpoint of a custom environment
+//	EXCHANGE_PROFILE_<NAME>_API_KEY      API key of the account
+//	EXCHANGE_PROFILE_<NAME>_SECRET_KEY   Secret key of the account
+//
+// The default profile comes first.
+func LoadExchangeProfiles(getenv func(string) string) ([]ExchangeProfile, error) {
+	names := []string{entity.DefaultExchangeProfile}
+	for _, listed := range strings.Split(getenv("EXCHANGE_PROFILES"), ",") {
+		if strings.TrimSpace(listed) == "" {
+			continue
+		}
+		name, err := entity.NewExchangeProfileName(listed)
+		if err != nil {
+			return nil, err
+		}
+		if !containsProfileName(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	profiles := make([]ExchangeProfile, 0, len(names))
+	for _, name := range names {
+		profile, err := loadExchangeProfile(name, getenv)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func loadExchangeProfile(name string, getenv func(string) string) (ExchangeProfile, error) {
+	prefix := "EXCHANGE_PROFILE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+	profile := ExchangeProfile{
+		Name:        name,
+		Environment: strings.ToLower(getenv(prefix + "ENVIRONMENT")),
+		BaseURL:     strings.TrimSuffix(getenv(prefix+"BASE_URL"), "/"),
+		APIKey:      getenv(prefix + "API_KEY"),
+		SecretKey:   getenv(prefix + "SECRET_KEY"),
+	}
+	isDefault := name == entity.DefaultExchangeProfile
+	if isDefault && profile.APIKey == "" && profile.SecretKey == "" {
+		profile.APIKey = getenv("BINANCE_API_KEY")
+		profile.SecretKey = getenv("BINANCE_SECRET_KEY")
+	}
+
+	switch profile.Environment {
+	case "":
+		profile.Environment = ExchangeEnvironmentProduction
+	case ExchangeEnvironmentProduction, ExchangeEnvironmentTestnet, ExchangeEnvironmentCustom:
+	default:
+		return ExchangeProfile{}, fmt.Errorf("invalid environment %q for exchange profile %s (use production, testnet or custom)", profile.Environment, name)
+	}
+	if profile.Environment == ExchangeEnvironmentCustom && profile.BaseURL == "" {
+		return ExchangeProfile{}, fmt.Errorf("exchange profile %s is custom but has no %sBASE_URL", name, prefix)
+	}
+	if !isDefault && (profile.APIKey == "" || profile.SecretKey == "") {
+		return ExchangeProfile{}, fmt.Errorf("exchange profile %s has no %sAPI_KEY or %sSECRET_KEY", name, prefix, prefix)
+	}
+	return profile, nil
+}
+
+func containsProfileName(names []string, name string) bool {
+	for _, existing := range names {
+		if existing == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewBinanceExchangeForProfile creates the Binance adapter trading on the profile's account and environment
+func NewBinanceExchangeForProfile(profile ExchangeProfile) *BinanceExchange {
+	client := binance.NewClient(profile.APIKey, profile.SecretKey)
+	client.BaseURL = profile.APIBaseURL()
+	return NewBinanceExchange(client)
+}
@@ -0,0 +1,113 @@
+package external
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+func envOf(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestLoadExchangeProfiles_DefaultsToProductionWithBinanceKeys(t *testing.T) {
+	profiles, err := LoadExchangeProfiles(envOf(map[string]string{
+		"BINANCE_API_KEY":    "prod-key",
+		"BINANCE_SECRET_KEY": "prod-secret",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(profiles) != 1 {
+		t.Fatalf("expected only the default profile, got %+v", profiles)
+	}
+	production := profiles[0]
+	if production.Name != "production" || production.Environment != ExchangeEnvironmentProduction ||
+		production.APIKey != "prod-key" || production.SecretKey != "prod-secret" {
+		t.Errorf("unexpected default profile %+v", production)
+	}
+	if production.APIBaseURL() != binance.BaseAPIMainURL {
+		t.Errorf("expected production URL, got %s", production.APIBaseURL())
+	}
+}
+
+func TestLoadExchangeProfiles_TestnetAndCustomProfiles(t *testing.T) {
+	profiles, err := LoadExchangeProfiles(envOf(map[string]string{
+		"BINANCE_API_KEY":                         "prod-key",
+		"BINANCE_SECRET_KEY":                      "prod-secret",
+		"EXCHANGE_PROFILES":                       "Testnet, local-mock,testnet",
+		"EXCHANGE_PROFILE_TESTNET_ENVIRONMENT":    "testnet",
+		"EXCHANGE_PROFILE_TESTNET_API_KEY":        "test-key",
+		"EXCHANGE_PROFILE_TESTNET_SECRET_KEY":     "test-secret",
+		"EXCHANGE_PROFILE_LOCAL_MOCK_ENVIRONMENT": "custom",
+		"EXCHANGE_PROFILE_LOCAL_MOCK_BASE_URL":    "http://localhost:8081/",
+		"EXCHANGE_PROFILE_LOCAL_MOCK_API_KEY":     "mock-key",
+		"EXCHANGE_PROFILE_LOCAL_MOCK_SECRET_KEY":  "mock-secret",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(profiles) != 3 || profiles[0].Name != "production" || profiles[1].Name != "testnet" || profiles[2].Name != "local-mock" {
+		t.Fatalf("expected production, testnet and local-mock, got %+v", profiles)
+	}
+	if profiles[1].APIBaseURL() != binance.BaseAPITestnetURL || profiles[1].APIKey != "test-key" {
+		t.Errorf("unexpected testnet profile %+v", profiles[1])
+	}
+	if profiles[2].APIBaseURL() != "http://localhost:8081" || profiles[2].SecretKey != "mock-secret" {
+		t.Errorf("unexpected custom profile %+v", profiles[2])
+	}
+
+	exchangeClient := NewBinanceExchangeForProfile(profiles[2])
+	if exchangeClient.client.BaseURL != "http://localhost:8081" || exchangeClient.client.APIKey != "mock-key" {
+		t.Errorf("expected a client on the mock, got %s with key %s", exchangeClient.client.BaseURL, exchangeClient.client.APIKey)
+	}
+}
+
+func TestLoadExchangeProfiles_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{
+			name:     "invalid name",
+			env:      map[string]string{"EXCHANGE_PROFILES": "test net"},
+			expected: "invalid exchange profile",
+		},
+		{
+			name:     "unknown environment",
+			env:      map[string]string{"EXCHANGE_PROFILE_PRODUCTION_ENVIRONMENT": "staging"},
+			expected: "invalid environment",
+		},
+		{
+			name: "custom without base URL",
+			env: map[string]string{
+				"EXCHANGE_PROFILES":                 "mock",
+				"EXCHANGE_PROFILE_MOCK_ENVIRONMENT": "custom",
+				"EXCHANGE_PROFILE_MOCK_API_KEY":     "key",
+				"EXCHANGE_PROFILE_MOCK_SECRET_KEY":  "secret",
+			},
+			expected: "has no EXCHANGE_PROFILE_MOCK_BASE_URL",
+		},
+		{
+			name: "profile without keys",
+			env: map[string]string{
+				"EXCHANGE_PROFILES":                    "testnet",
+				"EXCHANGE_PROFILE_TESTNET_ENVIRONMENT": "testnet",
+			},
+			expected: "has no EXCHANGE_PROFILE_TESTNET_API_KEY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadExchangeProfiles(envOf(tt.env))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
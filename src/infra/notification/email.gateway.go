@@ -120,6 +120,7 @@ func (e *EmailNotificationConsumer) payloadToTradingEventData(payload map[string
 		Strategy:    getStringValue(payload, "strategy"),
 		TradingFees: getFloatValue(payload, "trading_fees"),
 		Currency:    getStringValue(payload, "currency"),
+		Mode:        getStringValue(payload, "mode"),
 		Timestamp:   time.Now(), // Default fallback
 	}
 
@@ -19,10 +19,19 @@ type TradingEventData struct {
 	ProfitLossPerc  float64   `json:"profit_loss_perc,omitempty"` // Para SELL
 	TradingFees     float64   `json:"trading_fees"`
 	Currency        string    `json:"currency"`
+	Mode            string    `json:"mode"` // "live" ou "paper"
+}
+
+// PaperTag marca as notificações de bots em paper trading, cujas ordens são simuladas
+func (d TradingEventData) PaperTag() string {
+	if d.Mode == "paper" {
+		return "🧪 [PAPER] "
+	}
+	return ""
 }
 
 func GenerateBuyEmailTemplate(data TradingEventData) (string, string) {
-	subject := fmt.Sprintf("🟢 CrypGo: Compra Executada - %s", data.Symbol)
+	subject := fmt.Sprintf("%s🟢 CrypGo: Compra Executada - %s", data.PaperTag(), data.Symbol)
 	
 	body := fmt.Sprintf(`
 <!DOCTYPE html>
@@ -132,7 +141,7 @@ func GenerateSellEmailTemplate(data TradingEventData) (string, string) {
 		profitColor = "#f44336"
 	}
 	
-	subject := fmt.Sprintf("🔴 CrypGo: Venda Executada - %s (%.2f%%)", data.Symbol, data.ProfitLossPerc)
+	subject := fmt.Sprintf("%s🔴 CrypGo: Venda Executada - %s (%.2f%%)", data.PaperTag(), data.Symbol, data.ProfitLossPerc)
 	
 	body := fmt.Sprintf(`
 <!DOCTYPE html>
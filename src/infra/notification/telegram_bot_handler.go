@@ -2,6 +2,7 @@ package notification
 
 import (
 	"crypgo-machine/src/application/service"
+	"crypgo-machine/src/application/usecase"
 	"fmt"
 	"log"
 
@@ -46,6 +47,19 @@ func NewTelegramBotHandler(telegramService *TelegramService, marketService *serv
 	}
 }
 
+// WithBotLifecycle lets the chat pause, resume, close and liquidate bots
+func (h *TelegramBotHandler) WithBotLifecycle(
+	pauseTradingBot *usecase.PauseTradingBotUseCase,
+	resumeTradingBot *usecase.ResumeTradingBotUseCase,
+	closeTradingBotPosition *usecase.CloseTradingBotPositionUseCase,
+	liquidateTradingBot *usecase.LiquidateTradingBotUseCase,
+) *TelegramBotHandler {
+	if h.commandProcessor != nil {
+		h.commandProcessor.WithBotLifecycle(pauseTradingBot, resumeTradingBot, closeTradingBotPosition, liquidateTradingBot)
+	}
+	return h
+}
+
 // Start begins processing Telegram updates
 func (h *TelegramBotHandler) Start() error {
 	if !h.enabled {
@@ -164,7 +178,10 @@ func (h *TelegramBotHandler) sendHelpMessage(chatID int64) {
 		"⚡ <code>/quick</code> - Verificação rápida (Fear & Greed)\n" +
 		"📊 <code>/status</code> - Status dos bots e sistema\n" +
 		"❓ <code>/help</code> - Esta mensagem de ajuda\n" +
-		"👋 <code>/oi</code> - Teste de conectividade\n\n" +
+		"👋 <code>/oi</code> - Teste de conectividade\n" +
+		"⏸️ <code>/pause</code>, <code>/resume</code> &lt;bot_id&gt; - Pausa ou retoma o bot\n" +
+		"🏁 <code>/close</code> &lt;bot_id&gt; - Vende a posição e para o bot\n" +
+		"🚨 <code>/liquidate</code> &lt;bot_id&gt; - Vende a mercado agora e para o bot\n\n" +
 		"💡 <i>Digite um comando para começar!</i>"
 	h.sendMessage(chatID, message)
 }
@@ -2,6 +2,7 @@ package notification
 
 import (
 	"crypgo-machine/src/application/service"
+	"crypgo-machine/src/application/usecase"
 	"crypgo-machine/src/infra/external"
 	"fmt"
 	"log"
@@ -12,6 +13,12 @@ import (
 type TelegramCommandProcessor struct {
 	marketService   *service.MarketSentimentService
 	telegramService *TelegramService
+
+	// Bot lifecycle commands, unavailable while nil
+	pauseTradingBot         *usecase.PauseTradingBotUseCase
+	resumeTradingBot        *usecase.ResumeTradingBotUseCase
+	closeTradingBotPosition *usecase.CloseTradingBotPositionUseCase
+	liquidateTradingBot     *usecase.LiquidateTradingBotUseCase
 }
 
 func NewTelegramCommandProcessor(marketService *service.MarketSentimentService, telegramService *TelegramService) *TelegramCommandProcessor {
@@ -21,6 +28,20 @@ func NewTelegramCommandProcessor(marketService *service.MarketSentimentService,
 	}
 }
 
+// WithBotLifecycle enables the commands pausing, resuming, closing and liquidating bots
+func (p *TelegramCommandProcessor) WithBotLifecycle(
+	pauseTradingBot *usecase.PauseTradingBotUseCase,
+	resumeTradingBot *usecase.ResumeTradingBotUseCase,
+	closeTradingBotPosition *usecase.CloseTradingBotPositionUseCase,
+	liquidateTradingBot *usecase.LiquidateTradingBotUseCase,
+) *TelegramCommandProcessor {
+	p.pauseTradingBot = pauseTradingBot
+	p.resumeTradingBot = resumeTradingBot
+	p.closeTradingBotPosition = closeTradingBotPosition
+	p.liquidateTradingBot = liquidateTradingBot
+	return p
+}
+
 // ProcessCommand handles command logic and returns response message
 func (p *TelegramCommandProcessor) ProcessCommand(command string, args string, chatID int64) string {
 	log.Printf("🔄 Processing command: /%s", command)
@@ -36,6 +57,8 @@ func (p *TelegramCommandProcessor) ProcessCommand(command string, args string, c
 		return p.handleHelpCommand()
 	case "oi":
 		return p.handleOiCommand()
+	case "pause", "resume", "close", "liquidate":
+		return p.handleBotLifecycleCommand(strings.ToLower(command), args)
 	default:
 		return p.handleUnknownCommand(command)
 	}
@@ -191,6 +214,13 @@ func (p *TelegramCommandProcessor) handleHelpCommand() string {
 		"   • Teste de fontes de dados\n\n" +
 		"👋 <b>/oi</b>\n" +
 		"   Teste de conectividade básico\n\n" +
+		"🤖 <b>/pause, /resume &lt;bot_id&gt;</b>\n" +
+		"   Pausa ou retoma novas entradas do bot\n" +
+		"   • Pausado, o bot ainda gerencia a posição aberta\n\n" +
+		"🏁 <b>/close &lt;bot_id&gt;</b>\n" +
+		"   Vende a posição na próxima oportunidade e para o bot\n\n" +
+		"🚨 <b>/liquidate &lt;bot_id&gt;</b>\n" +
+		"   Vende a posição imediatamente a mercado e para o bot\n\n" +
 		"💡 <b>IMPORTANTE</b>:\n" +
 		"• Todas as sugestões são consultivas\n" +
 		"• Sempre revise antes de aplicar\n" +
@@ -214,6 +244,43 @@ func (p *TelegramCommandProcessor) handleOiCommand() string {
 	return response
 }
 
+// handleBotLifecycleCommand pauses, resumes, closes or liquidates the bot whose ID is given as argument
+func (p *TelegramCommandProcessor) handleBotLifecycleCommand(command string, args string) string {
+	if p.pauseTradingBot == nil {
+		return "⚠️ <b>Comando Indisponível</b>\n\nO controle dos bots não está habilitado."
+	}
+
+	botId := strings.TrimSpace(args)
+	if botId == "" {
+		return fmt.Sprintf("⚠️ <b>Bot Não Informado</b>\n\nUse <code>/%s &lt;bot_id&gt;</code>.", command)
+	}
+
+	var err error
+	var done string
+	switch command {
+	case "pause":
+		err = p.pauseTradingBot.Execute(usecase.InputPauseTradingBot{BotId: botId})
+		done = "⏸️ <b>Bot Pausado</b>\n\nO bot não abre novas posições, mas continua gerenciando a posição aberta."
+	case "resume":
+		err = p.resumeTradingBot.Execute(usecase.InputResumeTradingBot{BotId: botId})
+		done = "▶️ <b>Bot Retomado</b>\n\nO bot voltou a operar normalmente."
+	case "close":
+		err = p.closeTradingBotPosition.Execute(usecase.InputCloseTradingBotPosition{BotId: botId})
+		done = "🏁 <b>Encerrando Posição</b>\n\nO bot vende a posição na próxima oportunidade e depois para."
+	case "liquidate":
+		err = p.liquidateTradingBot.Execute(usecase.InputLiquidateTradingBot{BotId: botId})
+		done = "🚨 <b>Posição Liquidada</b>\n\nA posição foi vendida a mercado e o bot parou."
+	}
+
+	if err != nil {
+		log.Printf("❌ Error in /%s for bot %s: %v", command, botId, err)
+		return fmt.Sprintf("❌ <b>Falha no Comando</b>\n\n<code>/%s</code> não pôde ser executado.\n\n<i>Erro: %s</i>", command, err.Error())
+	}
+
+	log.Printf("✅ /%s completed for bot %s", command, botId)
+	return fmt.Sprintf("%s\n\n🆔 <code>%s</code>\n\n#CrypGo #BotControl", done, botId)
+}
+
 // handleUnknownCommand responds to unrecognized commands
 func (p *TelegramCommandProcessor) handleUnknownCommand(command string) string {
 	response := fmt.Sprintf(
@@ -224,7 +291,8 @@ func (p *TelegramCommandProcessor) handleUnknownCommand(command string) string {
 			"• <code>/quick</code> - Verificação rápida\n"+
 			"• <code>/status</code> - Status do sistema\n"+
 			"• <code>/help</code> - Ajuda detalhada\n"+
-			"• <code>/oi</code> - Teste de conectividade\n\n"+
+			"• <code>/oi</code> - Teste de conectividade\n"+
+			"• <code>/pause</code>, <code>/resume</code>, <code>/close</code>, <code>/liquidate</code> - Controle dos bots\n\n"+
 			"💡 Use <code>/help</code> para mais informações.\n\n"+
 			"#CrypGo #ComandoInválido",
 		command,
@@ -40,8 +40,12 @@ func (t *TelegramNotificationConsumer) Start() error {
 		"trading_bot.created",
 		"trading_bot.started",
 		"trading_bot.stopped",
+		"trading_bot.needs_attention",
+		"trading_bot.panic_recovered",
 		"trading.buy_executed",
 		"trading.sell_executed",
+		"trading.buy_refused",
+		"risk.limit_breached",
 	}
 
 	return t.broker.Subscribe(t.exchangeName, t.queueName, routingKeys, t.handleMessage)
@@ -75,6 +79,31 @@ func (t *TelegramNotificationConsumer) handleMessage(msg queue.Message) error {
 			payload["id"])
 		return t.sendSimpleMessage(message)
 
+	case "trading_bot.needs_attention":
+		message := fmt.Sprintf("🚨 <b>CrypGo: Trading Bot Precisa de Atenção</b>\n\nBot %v (<b>%v</b>) foi parado porque a posição não confere com a exchange:\n%v",
+			payload["id"], payload["symbol"], payload["reason"])
+		return t.sendSimpleMessage(message)
+
+	case "trading_bot.panic_recovered":
+		message := fmt.Sprintf("💥 <b>CrypGo: Erro Inesperado no Trading Bot</b>\n\nBot %v (<b>%v</b>) teve um panic, recuperado pelo supervisor:\n%v",
+			payload["id"], payload["symbol"], payload["error"])
+		return t.sendSimpleMessage(message)
+
+	case "trading.buy_refused":
+		message := fmt.Sprintf("🚫 <b>CrypGo: Compra Recusada</b>\n\nBot %v (<b>%v</b>) não comprou por falta de capital: %v\nNecessário: %.2f %v\nDisponível: %.2f %v",
+			payload["bot_id"], payload["symbol"], payload["reason"],
+			payload["required"], payload["currency"], payload["available"], payload["currency"])
+		return t.sendSimpleMessage(message)
+
+	case "risk.limit_breached":
+		action := "Novas entradas bloqueadas"
+		if flatten, _ := payload["flatten"].(bool); flatten {
+			action = "Novas entradas bloqueadas e posições abertas sendo zeradas"
+		}
+		message := fmt.Sprintf("🛑 <b>CrypGo: Limite de Risco Atingido</b>\n\n%v\n%s",
+			payload["reason"], action)
+		return t.sendSimpleMessage(message)
+
 	case "trading.buy_executed":
 		return t.handleTradingEvent(payload, true)
 
@@ -107,7 +136,7 @@ func (t *TelegramNotificationConsumer) handleTradingEvent(payload map[string]int
 
 func (t *TelegramNotificationConsumer) generateBuyMessage(data TradingEventData) string {
 	return fmt.Sprintf(
-		"💰 <b>COMPRA EXECUTADA</b>\n\n"+
+		"%s💰 <b>COMPRA EXECUTADA</b>\n\n"+
 			"🤖 Bot: <code>%s</code>\n"+
 			"💱 Par: <b>%s</b>\n"+
 			"💵 Preço: <b>%.8f %s</b>\n"+
@@ -115,6 +144,7 @@ func (t *TelegramNotificationConsumer) generateBuyMessage(data TradingEventData)
 			"💸 Total: <b>%.2f %s</b>\n"+
 			"🎯 Estratégia: <code>%s</code>\n"+
 			"⏰ %s",
+		data.PaperTag(),
 		data.BotID,
 		data.Symbol,
 		data.Price, data.Currency,
@@ -132,7 +162,7 @@ func (t *TelegramNotificationConsumer) generateSellMessage(data TradingEventData
 	}
 
 	return fmt.Sprintf(
-		"💸 <b>VENDA EXECUTADA</b>\n\n"+
+		"%s💸 <b>VENDA EXECUTADA</b>\n\n"+
 			"🤖 Bot: <code>%s</code>\n"+
 			"💱 Par: <b>%s</b>\n"+
 			"💵 Preço Venda: <b>%.8f %s</b>\n"+
@@ -142,6 +172,7 @@ func (t *TelegramNotificationConsumer) generateSellMessage(data TradingEventData
 			"%s P&L: <b>%.2f %s (%.2f%%)</b>\n"+
 			"🎯 Estratégia: <code>%s</code>\n"+
 			"⏰ %s",
+		data.PaperTag(),
 		data.BotID,
 		data.Symbol,
 		data.Price, data.Currency,
@@ -165,6 +196,7 @@ func (t *TelegramNotificationConsumer) payloadToTradingEventData(payload map[str
 		Strategy:    getStringValue(payload, "strategy"),
 		TradingFees: getFloatValue(payload, "trading_fees"),
 		Currency:    getStringValue(payload, "currency"),
+		Mode:        getStringValue(payload, "mode"),
 		Timestamp:   time.Now(), // Default fallback
 	}
 
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// BotConfigHistoryRepositoryDatabase keeps the config versions of the bots in the trading_bot_config_history table
+type BotConfigHistoryRepositoryDatabase struct {
+	db *sql.DB
+}
+
+func NewBotConfigHistoryRepositoryDatabase(db *sql.DB) *BotConfigHistoryRepositoryDatabase {
+	return &BotConfigHistoryRepositoryDatabase{db: db}
+}
+
+var _ repository.BotConfigHistoryRepository = (*BotConfigHistoryRepositoryDatabase)(nil)
+
+func (r *BotConfigHistoryRepositoryDatabase) Save(version *entity.BotConfigVersion) error {
+	return saveBotConfigVersion(r.db, version)
+}
+
+// SaveWithBot saves the versions and updates the bot in one transaction, so no version is recorded for a bot whose
+// update failed and no bot is left on a config missing from its history
+func (r *BotConfigHistoryRepositoryDatabase) SaveWithBot(bot *entity.TradingBot, versions ...*entity.BotConfigVersion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, version := range versions {
+		if err := saveBotConfigVersion(tx, version); err != nil {
+			return err
+		}
+	}
+	if err := updateTradingBot(tx, bot); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveBotConfigVersion writes a config version, with exec being the database or a transaction
+func saveBotConfigVersion(exec sqlExecer, version *entity.BotConfigVersion) error {
+	config, err := json.Marshal(version.GetConfig())
+	if err != nil {
+		return err
+	}
+	changedFields, err := json.Marshal(version.ToDTO().ChangedFields)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO trading_bot_config_history (id, trading_bot_id, version, config, changed_fields, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (trading_bot_id, version) DO UPDATE SET
+			config = EXCLUDED.config,
+			changed_fields = EXCLUDED.changed_fields,
+			created_at = EXCLUDED.created_at
+	`
+
+	_, err = exec.Exec(query,
+		version.Id.GetValue(),
+		version.GetTradingBotId().GetValue(),
+		version.GetVersion(),
+		string(config),
+		string(changedFields),
+		version.GetCreatedAt(),
+	)
+	return err
+}
+
+func (r *BotConfigHistoryRepositoryDatabase) GetByTradingBotId(tradingBotId string) ([]*entity.BotConfigVersion, error) {
+	query := `
+		SELECT id, trading_bot_id, version, config, changed_fields, created_at
+		FROM trading_bot_config_history
+		WHERE trading_bot_id = $1
+		ORDER BY version ASC
+	`
+
+	rows, err := r.db.Query(query, tradingBotId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []*entity.BotConfigVersion
+	for rows.Next() {
+		var (
+			id               string
+			botId            string
+			version          int
+			configJson       string
+			changedFieldsStr string
+			createdAt        time.Time
+		)
+		if err := rows.Scan(&id, &botId, &version, &configJson, &changedFieldsStr, &createdAt); err != nil {
+			return nil, err
+		}
+
+		var config entity.BotConfigDTO
+		if err := json.Unmarshal([]byte(configJson), &config); err != nil {
+			return nil, err
+		}
+		var changedFields []string
+		if err := json.Unmarshal([]byte(changedFieldsStr), &changedFields); err != nil {
+			return nil, err
+		}
+
+		versionId, err := vo.RestoreEntityId(id)
+		if err != nil {
+			return nil, err
+		}
+		tradingBotEntityId, err := vo.RestoreEntityId(botId)
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, entity.RestoreBotConfigVersion(versionId, tradingBotEntityId, version, config, changedFields, createdAt))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"sort"
+	"sync"
+)
+
+type BotConfigHistoryRepositoryInMemory struct {
+	versions             map[string]map[int]*entity.BotConfigVersion // Per bot ID and version
+	tradingBotRepository repository.TradingBotRepository             // Updated by SaveWithBot when set
+	mu                   sync.RWMutex
+}
+
+func NewBotConfigHistoryRepositoryInMemory() *BotConfigHistoryRepositoryInMemory {
+	return &BotConfigHistoryRepositoryInMemory{
+		versions: make(map[string]map[int]*entity.BotConfigVersion),
+	}
+}
+
+var _ repository.BotConfigHistoryRepository = (*BotConfigHistoryRepositoryInMemory)(nil)
+
+// WithTradingBotRepository sets the repository SaveWithBot updates the bots in
+func (r *BotConfigHistoryRepositoryInMemory) WithTradingBotRepository(tradingBotRepository repository.TradingBotRepository) *BotConfigHistoryRepositoryInMemory {
+	r.tradingBotRepository = tradingBotRepository
+	return r
+}
+
+// SaveWithBot updates the bot first and saves the versions only when it succeeded
+func (r *BotConfigHistoryRepositoryInMemory) SaveWithBot(bot *entity.TradingBot, versions ...*entity.BotConfigVersion) error {
+	if r.tradingBotRepository != nil {
+		if err := r.tradingBotRepository.Update(bot); err != nil {
+			return err
+		}
+	}
+	for _, version := range versions {
+		if err := r.Save(version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *BotConfigHistoryRepositoryInMemory) Save(version *entity.BotConfigVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	botId := version.GetTradingBotId().GetValue()
+	if r.versions[botId] == nil {
+		r.versions[botId] = make(map[int]*entity.BotConfigVersion)
+	}
+	r.versions[botId][version.GetVersion()] = version
+	return nil
+}
+
+func (r *BotConfigHistoryRepositoryInMemory) GetByTradingBotId(tradingBotId string) ([]*entity.BotConfigVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]*entity.BotConfigVersion, 0, len(r.versions[tradingBotId]))
+	for _, version := range r.versions[tradingBotId] {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].GetVersion() < versions[j].GetVersion()
+	})
+	return versions, nil
+}
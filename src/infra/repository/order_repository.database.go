@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type OrderRepositoryDatabase struct {
+	db *sql.DB
+}
+
+func NewOrderRepositoryDatabase(db *sql.DB) *OrderRepositoryDatabase {
+	return &OrderRepositoryDatabase{db: db}
+}
+
+var _ repository.OrderRepository = (*OrderRepositoryDatabase)(nil)
+
+func (r *OrderRepositoryDatabase) Save(order *entity.Order) error {
+	fill := order.GetFill()
+	fillsJson, err := json.Marshal(fill.Fills)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO orders (
+			id, trading_bot_id, decision_log_id, symbol, side, order_type, requested_quantity, requested_price,
+			status, exchange_order_id, executed_quantity, average_price, quote_quantity, commission, commission_asset,
+			fees_in_quote, net_quantity, estimated, fills, executed_at, error_message, created_at, client_order_id,
+			max_attempts
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+	`
+
+	_, err = r.db.Exec(query,
+		order.GetId().GetValue(),
+		order.GetTradingBotId().GetValue(),
+		nullableString(order.GetDecisionLogId()),
+		order.GetSymbol(),
+		string(order.GetSide()),
+		order.GetOrderType(),
+		order.GetRequestedQuantity(),
+		order.GetRequestedPrice(),
+		string(order.GetStatus()),
+		sql.NullInt64{Int64: fill.ExchangeOrderID, Valid: fill.ExchangeOrderID != 0},
+		fill.ExecutedQuantity,
+		fill.AveragePrice,
+		fill.QuoteQuantity,
+		fill.Commission,
+		nullableString(fill.CommissionAsset),
+		fill.FeesInQuote,
+		fill.NetQuantity,
+		fill.Estimated,
+		string(fillsJson),
+		nullableTime(fill.ExecutedAt),
+		nullableString(order.GetErrorMessage()),
+		order.GetCreatedAt(),
+		nullableString(order.GetClientOrderId()),
+		order.GetMaxAttempts(),
+	)
+
+	return err
+}
+
+// Update records the outcome of an order: its status, what the exchange filled and why it failed
+func (r *OrderRepositoryDatabase) Update(order *entity.Order) error {
+	fill := order.GetFill()
+	fillsJson, err := json.Marshal(fill.Fills)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE orders
+		SET status = $2, exchange_order_id = $3, executed_quantity = $4, average_price = $5, quote_quantity = $6,
+		    commission = $7, commission_asset = $8, fees_in_quote = $9, net_quantity = $10, estimated = $11,
+		    fills = $12, executed_at = $13, error_message = $14
+		WHERE id = $1
+	`
+
+	_, err = r.db.Exec(query,
+		order.GetId().GetValue(),
+		string(order.GetStatus()),
+		sql.NullInt64{Int64: fill.ExchangeOrderID, Valid: fill.ExchangeOrderID != 0},
+		fill.ExecutedQuantity,
+		fill.AveragePrice,
+		fill.QuoteQuantity,
+		fill.Commission,
+		nullableString(fill.CommissionAsset),
+		fill.FeesInQuote,
+		fill.NetQuantity,
+		fill.Estimated,
+		string(fillsJson),
+		nullableTime(fill.ExecutedAt),
+		nullableString(order.GetErrorMessage()),
+	)
+
+	return err
+}
+
+// GetOrdersWithFilters retrieves orders with optional filters and pagination, most recent first
+func (r *OrderRepositoryDatabase) GetOrdersWithFilters(filter repository.OrderFilter) ([]*entity.Order, int, error) {
+	var whereConditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		whereConditions = append(whereConditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.TradingBotId != "" {
+		addCondition("trading_bot_id = $%d", filter.TradingBotId)
+	}
+	if filter.Symbol != "" {
+		addCondition("symbol = $%d", filter.Symbol)
+	}
+	if filter.Side != "" {
+		addCondition("side = $%d", filter.Side)
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if filter.ExchangeOrderId != 0 {
+		addCondition("exchange_order_id = $%d", filter.ExchangeOrderId)
+	}
+	if filter.ClientOrderId != "" {
+		addCondition("client_order_id = $%d", filter.ClientOrderId)
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < $%d", filter.To)
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM orders %s`, whereClause)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, trading_bot_id, decision_log_id, symbol, side, order_type, requested_quantity, requested_price,
+			   status, exchange_order_id, executed_quantity, average_price, quote_quantity, commission, commission_asset,
+			   fees_in_quote, net_quantity, estimated, fills, executed_at, error_message, created_at, client_order_id,
+			   max_attempts
+		FROM orders
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, len(args)+1, len(args)+2)
+	args = append(args, nullableLimit(filter.Limit), filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var orders []*entity.Order
+	for rows.Next() {
+		var (
+			id                string
+			botId             string
+			decisionLogId     sql.NullString
+			symbol            string
+			side              string
+			orderType         string
+			requestedQuantity float64
+			requestedPrice    float64
+			status            string
+			exchangeOrderId   sql.NullInt64
+			fill              entity.OrderFill
+			commissionAsset   sql.NullString
+			fillsStr          string
+			executedAt        sql.NullTime
+			errorMessage      sql.NullString
+			createdAt         time.Time
+			clientOrderId     sql.NullString
+			maxAttempts       int
+		)
+
+		if err := rows.Scan(&id, &botId, &decisionLogId, &symbol, &side, &orderType, &requestedQuantity, &requestedPrice,
+			&status, &exchangeOrderId, &fill.ExecutedQuantity, &fill.AveragePrice, &fill.QuoteQuantity, &fill.Commission, &commissionAsset,
+			&fill.FeesInQuote, &fill.NetQuantity, &fill.Estimated, &fillsStr, &executedAt, &errorMessage, &createdAt, &clientOrderId,
+			&maxAttempts); err != nil {
+			return nil, 0, err
+		}
+
+		if err := json.Unmarshal([]byte(fillsStr), &fill.Fills); err != nil {
+			return nil, 0, err
+		}
+		fill.ExchangeOrderID = exchangeOrderId.Int64
+		fill.Side = side
+		fill.Status = status
+		fill.CommissionAsset = commissionAsset.String
+		fill.ExecutedAt = executedAt.Time
+
+		orderId, err := vo.RestoreEntityId(id)
+		if err != nil {
+			return nil, 0, err
+		}
+		tradingBotId, err := vo.RestoreEntityId(botId)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		orders = append(orders, entity.RestoreOrder(
+			orderId,
+			tradingBotId,
+			decisionLogId.String,
+			clientOrderId.String,
+			maxAttempts,
+			symbol,
+			entity.OrderSide(side),
+			orderType,
+			requestedQuantity,
+			requestedPrice,
+			entity.OrderStatus(status),
+			fill,
+			errorMessage.String,
+			createdAt,
+		))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return orders, total, nil
+}
+
+// nullableString stores an empty string as NULL
+func nullableString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullableLimit passes a non-positive limit as NULL, which Postgres treats as no limit
+func nullableLimit(limit int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(limit), Valid: limit > 0}
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"errors"
+	"sort"
+	"sync"
+)
+
+type OrderRepositoryInMemory struct {
+	orders []*entity.Order
+	mu     sync.RWMutex
+}
+
+func NewOrderRepositoryInMemory() *OrderRepositoryInMemory {
+	return &OrderRepositoryInMemory{}
+}
+
+var _ repository.OrderRepository = (*OrderRepositoryInMemory)(nil)
+
+func (r *OrderRepositoryInMemory) Save(order *entity.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders = append(r.orders, order)
+	return nil
+}
+
+func (r *OrderRepositoryInMemory) Update(order *entity.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.orders {
+		if existing.GetId().GetValue() == order.GetId().GetValue() {
+			r.orders[i] = order
+			return nil
+		}
+	}
+	return errors.New("order not found")
+}
+
+// GetOrdersWithFilters retrieves orders with optional filters and pagination, most recent first
+func (r *OrderRepositoryInMemory) GetOrdersWithFilters(filter repository.OrderFilter) ([]*entity.Order, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Walk from the newest so orders created at the same time stay most recent first
+	var filtered []*entity.Order
+	for i := len(r.orders) - 1; i >= 0; i-- {
+		order := r.orders[i]
+		if filter.TradingBotId != "" && order.GetTradingBotId().GetValue() != filter.TradingBotId {
+			continue
+		}
+		if filter.Symbol != "" && order.GetSymbol() != filter.Symbol {
+			continue
+		}
+		if filter.Side != "" && string(order.GetSide()) != filter.Side {
+			continue
+		}
+		if filter.Status != "" && string(order.GetStatus()) != filter.Status {
+			continue
+		}
+		if filter.ExchangeOrderId != 0 && order.GetFill().ExchangeOrderID != filter.ExchangeOrderId {
+			continue
+		}
+		if filter.ClientOrderId != "" && order.GetClientOrderId() != filter.ClientOrderId {
+			continue
+		}
+		if !filter.From.IsZero() && order.GetCreatedAt().Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !order.GetCreatedAt().Before(filter.To) {
+			continue
+		}
+		filtered = append(filtered, order)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].GetCreatedAt().After(filtered[j].GetCreatedAt())
+	})
+
+	start, end := pageBounds(len(filtered), filter.Limit, filter.Offset)
+	return filtered[start:end], len(filtered), nil
+}
+
+// pageBounds returns the slice bounds of a page, a non-positive limit meaning no limit
+func pageBounds(total, limit, offset int) (int, int) {
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return offset, end
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func newLedgerOrder(botId *vo.EntityId, symbol string, side entity.OrderSide, status entity.OrderStatus, createdAt time.Time) *entity.Order {
+	return entity.RestoreOrder(vo.NewEntityId(), botId, "", "", 0, symbol, side, "MARKET", 1.0, 100.0, status, entity.OrderFill{}, "", createdAt)
+}
+
+func TestOrderRepositoryInMemory_GetOrdersWithFilters(t *testing.T) {
+	repo := NewOrderRepositoryInMemory()
+	botA := vo.NewEntityId()
+	botB := vo.NewEntityId()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := newLedgerOrder(botA, "BTCUSDT", entity.OrderSideBuy, entity.OrderStatusFilled, base)
+	second := newLedgerOrder(botA, "BTCUSDT", entity.OrderSideSell, entity.OrderStatusFailed, base.Add(time.Hour))
+	third := newLedgerOrder(botB, "SOLBRL", entity.OrderSideBuy, entity.OrderStatusFilled, base.Add(2*time.Hour))
+	for _, order := range []*entity.Order{first, second, third} {
+		_ = repo.Save(order)
+	}
+
+	orders, total, _ := repo.GetOrdersWithFilters(repository.OrderFilter{})
+	if total != 3 || orders[0] != third || orders[2] != first {
+		t.Fatalf("expected all 3 orders most recent first, got %d", total)
+	}
+
+	if orders, total, _ := repo.GetOrdersWithFilters(repository.OrderFilter{TradingBotId: botA.GetValue()}); total != 2 || orders[0] != second {
+		t.Errorf("expected the 2 orders of bot A, got %d", total)
+	}
+	if orders, total, _ := repo.GetOrdersWithFilters(repository.OrderFilter{Symbol: "SOLBRL"}); total != 1 || orders[0] != third {
+		t.Errorf("expected the SOLBRL order, got %d", total)
+	}
+	if orders, total, _ := repo.GetOrdersWithFilters(repository.OrderFilter{Side: "SELL"}); total != 1 || orders[0] != second {
+		t.Errorf("expected the sell order, got %d", total)
+	}
+	if _, total, _ := repo.GetOrdersWithFilters(repository.OrderFilter{Status: "FILLED"}); total != 2 {
+		t.Errorf("expected 2 filled orders, got %d", total)
+	}
+	if orders, total, _ := repo.GetOrdersWithFilters(repository.OrderFilter{From: base.Add(time.Hour), To: base.Add(2 * time.Hour)}); total != 1 || orders[0] != second {
+		t.Errorf("expected the period to include its start and exclude its end, got %d", total)
+	}
+
+	orders, total, _ = repo.GetOrdersWithFilters(repository.OrderFilter{Limit: 1, Offset: 1})
+	if total != 3 || len(orders) != 1 || orders[0] != second {
+		t.Errorf("expected the second page of one order out of 3, got %d of %d", len(orders), total)
+	}
+	if orders, _, _ := repo.GetOrdersWithFilters(repository.OrderFilter{Offset: 5}); len(orders) != 0 {
+		t.Errorf("expected no orders past the end, got %d", len(orders))
+	}
+}
+
+func TestOrderRepositoryInMemory_Update(t *testing.T) {
+	repo := NewOrderRepositoryInMemory()
+	order := entity.NewOrder(vo.NewEntityId(), "", "BTCUSDT", entity.OrderSideBuy, "MARKET", 1.0, 100.0)
+	_ = repo.Save(order)
+
+	order.MarkRejected("below min notional")
+	if err := repo.Update(order); err != nil {
+		t.Fatalf("failed to update order: %v", err)
+	}
+	if orders, _, _ := repo.GetOrdersWithFilters(repository.OrderFilter{Status: "REJECTED"}); len(orders) != 1 {
+		t.Errorf("expected the rejected order, got %d", len(orders))
+	}
+
+	missing := entity.NewOrder(vo.NewEntityId(), "", "BTCUSDT", entity.OrderSideBuy, "MARKET", 1.0, 100.0)
+	if err := repo.Update(missing); err == nil {
+		t.Error("expected error when updating an order that was never saved")
+	}
+}
+
+func cleanupTestLedger(t *testing.T, db *sql.DB, botID string) {
+	if _, err := db.Exec("DELETE FROM trades WHERE trading_bot_id = $1", botID); err != nil {
+		t.Logf("Warning: failed to cleanup trades: %v", err)
+	}
+	if _, err := db.Exec("DELETE FROM orders WHERE trading_bot_id = $1", botID); err != nil {
+		t.Logf("Warning: failed to cleanup orders: %v", err)
+	}
+	cleanupTestBot(t, db, botID)
+}
+
+func TestOrderRepositoryDatabase_Persistence(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	symbol, _ := vo.NewSymbol("BTCUSDT")
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 300, 10000.0, 1000.0, "USDT", 0.1, 2.0, true)
+	if err := NewTradingBotRepositoryDatabase(db).Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+	botID := bot.Id.GetValue()
+	defer cleanupTestLedger(t, db, botID)
+
+	repo := NewOrderRepositoryDatabase(db)
+
+	buy := entity.NewOrder(bot.Id, "", "BTCUSDT", entity.OrderSideBuy, "MARKET", 0.001, 100000.0)
+	buy.SetClientOrderId("test-client-order")
+	buy.SetMaxAttempts(4)
+	if err := repo.Save(buy); err != nil {
+		t.Fatalf("Failed to save order: %v", err)
+	}
+	executedAt := time.Now().UTC().Truncate(time.Second)
+	buy.MarkFilled(entity.OrderFill{
+		ExchangeOrderID:  4242,
+		Status:           "FILLED",
+		ExecutedQuantity: 0.001,
+		AveragePrice:     100050.0,
+		QuoteQuantity:    100.05,
+		Commission:       0.000001,
+		CommissionAsset:  "BTC",
+		FeesInQuote:      0.1,
+		NetQuantity:      0.000999,
+		Fills:            []entity.Fill{{TradeID: 1, Price: 100050.0, Quantity: 0.001}},
+		ExecutedAt:       executedAt,
+	})
+	if err := repo.Update(buy); err != nil {
+		t.Fatalf("Failed to update order: %v", err)
+	}
+
+	sell := entity.NewOrder(bot.Id, "", "BTCUSDT", entity.OrderSideSell, "MARKET", 0.001, 99000.0)
+	if err := repo.Save(sell); err != nil {
+		t.Fatalf("Failed to save order: %v", err)
+	}
+
+	orders, total, err := repo.GetOrdersWithFilters(repository.OrderFilter{TradingBotId: botID, Side: "BUY", Status: "FILLED"})
+	if err != nil || total != 1 || len(orders) != 1 {
+		t.Fatalf("Expected the filled buy order, got %d (err: %v)", total, err)
+	}
+	retrieved := orders[0]
+	if retrieved.GetClientOrderId() != "test-client-order" {
+		t.Errorf("Expected client order ID to be persisted, got %q", retrieved.GetClientOrderId())
+	}
+	if retrieved.GetMaxAttempts() != 4 {
+		t.Errorf("Expected max attempts 4 to be persisted, got %d", retrieved.GetMaxAttempts())
+	}
+	fill := retrieved.GetFill()
+	if fill.ExchangeOrderID != 4242 || fill.AveragePrice != 100050.0 || fill.CommissionAsset != "BTC" || len(fill.Fills) != 1 {
+		t.Errorf("Expected the fill to be persisted, got %+v", fill)
+	}
+	if !fill.ExecutedAt.Equal(executedAt) {
+		t.Errorf("Expected executed at %v, got %v", executedAt, fill.ExecutedAt)
+	}
+
+	if orders, _, _ := repo.GetOrdersWithFilters(repository.OrderFilter{ClientOrderId: "test-client-order"}); len(orders) != 1 {
+		t.Errorf("Expected to find the order by client order ID, got %d", len(orders))
+	}
+	if orders, _, _ := repo.GetOrdersWithFilters(repository.OrderFilter{ExchangeOrderId: 4242}); len(orders) != 1 {
+		t.Errorf("Expected to find the order by exchange order ID, got %d", len(orders))
+	}
+
+	orders, total, _ = repo.GetOrdersWithFilters(repository.OrderFilter{TradingBotId: botID, Limit: 1})
+	if total != 2 || len(orders) != 1 || orders[0].GetId().GetValue() != sell.GetId().GetValue() {
+		t.Errorf("Expected the most recent of 2 orders, got %d of %d", len(orders), total)
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"database/sql"
+)
+
+// RiskLimitsRepositoryDatabase keeps the risk limits in the single row of the risk_limits table
+type RiskLimitsRepositoryDatabase struct {
+	db *sql.DB
+}
+
+func NewRiskLimitsRepositoryDatabase(db *sql.DB) *RiskLimitsRepositoryDatabase {
+	return &RiskLimitsRepositoryDatabase{db: db}
+}
+
+var _ repository.RiskLimitsRepository = (*RiskLimitsRepositoryDatabase)(nil)
+
+func (r *RiskLimitsRepositoryDatabase) Get() (entity.RiskLimits, error) {
+	query := `
+		SELECT max_daily_loss, max_open_positions, max_exposure_per_asset, flatten_on_breach, kill_switch, updated_at
+		FROM risk_limits
+		WHERE id = 1
+	`
+
+	var limits entity.RiskLimits
+	var updatedAt sql.NullTime
+	err := r.db.QueryRow(query).Scan(
+		&limits.MaxDailyLoss,
+		&limits.MaxOpenPositions,
+		&limits.MaxExposurePerAsset,
+		&limits.FlattenOnBreach,
+		&limits.KillSwitch,
+		&updatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return entity.RiskLimits{}, nil
+	}
+	if err != nil {
+		return entity.RiskLimits{}, err
+	}
+	limits.UpdatedAt = updatedAt.Time
+	return limits, nil
+}
+
+func (r *RiskLimitsRepositoryDatabase) Save(limits entity.RiskLimits) error {
+	query := `
+		INSERT INTO risk_limits (id, max_daily_loss, max_open_positions, max_exposure_per_asset, flatten_on_breach, kill_switch, updated_at)
+		VALUES (1, $1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			max_daily_loss = EXCLUDED.max_daily_loss,
+			max_open_positions = EXCLUDED.max_open_positions,
+			max_exposure_per_asset = EXCLUDED.max_exposure_per_asset,
+			flatten_on_breach = EXCLUDED.flatten_on_breach,
+			kill_switch = EXCLUDED.kill_switch,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		limits.MaxDailyLoss,
+		limits.MaxOpenPositions,
+		limits.MaxExposurePerAsset,
+		limits.FlattenOnBreach,
+		limits.KillSwitch,
+		nullableTime(limits.UpdatedAt),
+	)
+	return err
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"sync"
+)
+
+type RiskLimitsRepositoryInMemory struct {
+	limits entity.RiskLimits
+	mu     sync.RWMutex
+}
+
+func NewRiskLimitsRepositoryInMemory() *RiskLimitsRepositoryInMemory {
+	return &RiskLimitsRepositoryInMemory{}
+}
+
+var _ repository.RiskLimitsRepository = (*RiskLimitsRepositoryInMemory)(nil)
+
+func (r *RiskLimitsRepositoryInMemory) Get() (entity.RiskLimits, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.limits, nil
+}
+
+func (r *RiskLimitsRepositoryInMemory) Save(limits entity.RiskLimits) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limits = limits
+	return nil
+}
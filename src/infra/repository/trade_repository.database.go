@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type TradeRepositoryDatabase struct {
+	db *sql.DB
+}
+
+func NewTradeRepositoryDatabase(db *sql.DB) *TradeRepositoryDatabase {
+	return &TradeRepositoryDatabase{db: db}
+}
+
+var _ repository.TradeRepository = (*TradeRepositoryDatabase)(nil)
+
+func (r *TradeRepositoryDatabase) Save(trade *entity.Trade) error {
+	query := `
+		INSERT INTO trades (
+			id, trading_bot_id, symbol, entry_order_id, exit_order_id, quantity, entry_price, exit_price,
+			entry_fees, exit_fees, profit_loss, profit_loss_percent, currency, opened_at, closed_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := r.db.Exec(query,
+		trade.GetId().GetValue(),
+		trade.GetTradingBotId().GetValue(),
+		trade.GetSymbol(),
+		nullableString(trade.GetEntryOrderId()),
+		trade.GetExitOrderId(),
+		trade.GetQuantity(),
+		trade.GetEntryPrice(),
+		trade.GetExitPrice(),
+		trade.GetEntryFees(),
+		trade.GetExitFees(),
+		trade.GetProfitLoss(),
+		trade.GetProfitLossPercent(),
+		trade.GetCurrency(),
+		nullableTime(trade.GetOpenedAt()),
+		trade.GetClosedAt(),
+	)
+
+	return err
+}
+
+// GetTradesWithFilters retrieves closed trades with optional filters and pagination, most recent first
+func (r *TradeRepositoryDatabase) GetTradesWithFilters(filter repository.TradeFilter) ([]*entity.Trade, int, error) {
+	var whereConditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		whereConditions = append(whereConditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.TradingBotId != "" {
+		addCondition("trading_bot_id = $%d", filter.TradingBotId)
+	}
+	if filter.Symbol != "" {
+		addCondition("symbol = $%d", filter.Symbol)
+	}
+	if !filter.From.IsZero() {
+		addCondition("closed_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("closed_at < $%d", filter.To)
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM trades %s`, whereClause)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, trading_bot_id, symbol, entry_order_id, exit_order_id, quantity, entry_price, exit_price,
+			   entry_fees, exit_fees, profit_loss, profit_loss_percent, currency, opened_at, closed_at
+		FROM trades
+		%s
+		ORDER BY closed_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, len(args)+1, len(args)+2)
+	args = append(args, nullableLimit(filter.Limit), filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var trades []*entity.Trade
+	for rows.Next() {
+		var (
+			id                string
+			botId             string
+			symbol            string
+			entryOrderId      sql.NullString
+			exitOrderId       string
+			quantity          float64
+			entryPrice        float64
+			exitPrice         float64
+			entryFees         float64
+			exitFees          float64
+			profitLoss        float64
+			profitLossPercent float64
+			currency          string
+			openedAt          sql.NullTime
+			closedAt          time.Time
+		)
+
+		if err := rows.Scan(&id, &botId, &symbol, &entryOrderId, &exitOrderId, &quantity, &entryPrice, &exitPrice,
+			&entryFees, &exitFees, &profitLoss, &profitLossPercent, &currency, &openedAt, &closedAt); err != nil {
+			return nil, 0, err
+		}
+
+		tradeId, err := vo.RestoreEntityId(id)
+		if err != nil {
+			return nil, 0, err
+		}
+		tradingBotId, err := vo.RestoreEntityId(botId)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		trades = append(trades, entity.RestoreTrade(
+			tradeId,
+			tradingBotId,
+			symbol,
+			entryOrderId.String,
+			exitOrderId,
+			quantity,
+			entryPrice,
+			exitPrice,
+			entryFees,
+			exitFees,
+			profitLoss,
+			profitLossPercent,
+			currency,
+			openedAt.Time,
+			closedAt,
+		))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return trades, total, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"sort"
+	"sync"
+)
+
+type TradeRepositoryInMemory struct {
+	trades []*entity.Trade
+	mu     sync.RWMutex
+}
+
+func NewTradeRepositoryInMemory() *TradeRepositoryInMemory {
+	return &TradeRepositoryInMemory{}
+}
+
+var _ repository.TradeRepository = (*TradeRepositoryInMemory)(nil)
+
+func (r *TradeRepositoryInMemory) Save(trade *entity.Trade) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trades = append(r.trades, trade)
+	return nil
+}
+
+// GetTradesWithFilters retrieves closed trades with optional filters and pagination, most recent first
+func (r *TradeRepositoryInMemory) GetTradesWithFilters(filter repository.TradeFilter) ([]*entity.Trade, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Walk from the newest so trades closed at the same time stay most recent first
+	var filtered []*entity.Trade
+	for i := len(r.trades) - 1; i >= 0; i-- {
+		trade := r.trades[i]
+		if filter.TradingBotId != "" && trade.GetTradingBotId().GetValue() != filter.TradingBotId {
+			continue
+		}
+		if filter.Symbol != "" && trade.GetSymbol() != filter.Symbol {
+			continue
+		}
+		if !filter.From.IsZero() && trade.GetClosedAt().Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !trade.GetClosedAt().Before(filter.To) {
+			continue
+		}
+		filtered = append(filtered, trade)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].GetClosedAt().After(filtered[j].GetClosedAt())
+	})
+
+	start, end := pageBounds(len(filtered), filter.Limit, filter.Offset)
+	return filtered[start:end], len(filtered), nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"crypgo-machine/src/application/repository"
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"testing"
+	"time"
+)
+
+func newLedgerTrade(botId *vo.EntityId, symbol string, profitLoss float64, closedAt time.Time) *entity.Trade {
+	return entity.RestoreTrade(vo.NewEntityId(), botId, symbol, "", vo.NewEntityId().GetValue(), 1.0, 100.0, 100.0+profitLoss, 0, 0, profitLoss, profitLoss, "USDT", closedAt.Add(-time.Hour), closedAt)
+}
+
+func TestTradeRepositoryInMemory_GetTradesWithFilters(t *testing.T) {
+	repo := NewTradeRepositoryInMemory()
+	botA := vo.NewEntityId()
+	botB := vo.NewEntityId()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := newLedgerTrade(botA, "BTCUSDT", 10.0, base)
+	second := newLedgerTrade(botA, "BTCUSDT", -5.0, base.Add(time.Hour))
+	third := newLedgerTrade(botB, "SOLBRL", 2.0, base.Add(2*time.Hour))
+	for _, trade := range []*entity.Trade{first, second, third} {
+		_ = repo.Save(trade)
+	}
+
+	trades, total, _ := repo.GetTradesWithFilters(repository.TradeFilter{})
+	if total != 3 || trades[0] != third || trades[2] != first {
+		t.Fatalf("expected all 3 trades most recent first, got %d", total)
+	}
+
+	if _, total, _ := repo.GetTradesWithFilters(repository.TradeFilter{TradingBotId: botA.GetValue()}); total != 2 {
+		t.Errorf("expected the 2 trades of bot A, got %d", total)
+	}
+	if trades, total, _ := repo.GetTradesWithFilters(repository.TradeFilter{Symbol: "SOLBRL"}); total != 1 || trades[0] != third {
+		t.Errorf("expected the SOLBRL trade, got %d", total)
+	}
+	if trades, total, _ := repo.GetTradesWithFilters(repository.TradeFilter{From: base.Add(time.Hour), To: base.Add(2 * time.Hour)}); total != 1 || trades[0] != second {
+		t.Errorf("expected the period to include its start and exclude its end, got %d", total)
+	}
+
+	trades, total, _ = repo.GetTradesWithFilters(repository.TradeFilter{Limit: 2})
+	if total != 3 || len(trades) != 2 || trades[1] != second {
+		t.Errorf("expected the first page of 2 trades out of 3, got %d of %d", len(trades), total)
+	}
+}
+
+func TestTradeRepositoryDatabase_Persistence(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	symbol, _ := vo.NewSymbol("BTCUSDT")
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 300, 10000.0, 1000.0, "USDT", 0.1, 2.0, true)
+	if err := NewTradingBotRepositoryDatabase(db).Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+	botID := bot.Id.GetValue()
+	defer cleanupTestLedger(t, db, botID)
+
+	repo := NewTradeRepositoryDatabase(db)
+
+	closedAt := time.Now().UTC().Truncate(time.Second)
+	older := newLedgerTrade(bot.Id, "BTCUSDT", 10.0, closedAt.Add(-24*time.Hour))
+	newer := newLedgerTrade(bot.Id, "BTCUSDT", -5.0, closedAt)
+	for _, trade := range []*entity.Trade{older, newer} {
+		if err := repo.Save(trade); err != nil {
+			t.Fatalf("Failed to save trade: %v", err)
+		}
+	}
+
+	trades, total, err := repo.GetTradesWithFilters(repository.TradeFilter{TradingBotId: botID})
+	if err != nil || total != 2 || len(trades) != 2 {
+		t.Fatalf("Expected the 2 trades of the bot, got %d (err: %v)", total, err)
+	}
+	retrieved := trades[0]
+	if retrieved.GetId().GetValue() != newer.GetId().GetValue() {
+		t.Errorf("Expected the most recent trade first, got %s", retrieved.GetId().GetValue())
+	}
+	if retrieved.GetProfitLoss() != -5.0 || retrieved.GetExitPrice() != 95.0 || retrieved.GetCurrency() != "USDT" {
+		t.Errorf("Expected the trade values to be persisted, got %+v", retrieved)
+	}
+	if !retrieved.GetClosedAt().Equal(closedAt) || !retrieved.GetOpenedAt().Equal(closedAt.Add(-time.Hour)) {
+		t.Errorf("Expected the trade to span %v - %v, got %v - %v", closedAt.Add(-time.Hour), closedAt, retrieved.GetOpenedAt(), retrieved.GetClosedAt())
+	}
+
+	if trades, _, _ := repo.GetTradesWithFilters(repository.TradeFilter{TradingBotId: botID, From: closedAt.Add(-time.Hour)}); len(trades) != 1 {
+		t.Errorf("Expected only the trade closed in the period, got %d", len(trades))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypgo-machine/src/domain/vo"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -27,8 +28,8 @@ func (r *TradingBotRepositoryDatabase) Save(bot *entity.TradingBot) error {
 	}
 
 	query := `
-		INSERT INTO trade_bots (id, symbol, quantity, strategy_name, strategy_params, status, is_positioned, interval_seconds, initial_capital, trade_amount, currency, trading_fees, minimum_profit_threshold, entry_price, actual_quantity_held, use_fixed_quantity, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		INSERT INTO trade_bots (id, symbol, quantity, strategy_name, strategy_params, status, is_positioned, interval_seconds, initial_capital, trade_amount, currency, trading_fees, minimum_profit_threshold, entry_price, actual_quantity_held, use_fixed_quantity, risk_per_trade_percent, atr_period, atr_multiplier, trailing_stop_percent, take_profit_percent, max_holding_seconds, highest_price_since_entry, position_opened_at, use_streaming, entry_fees, order_execution_mode, time_in_force, reprice_after_seconds, max_order_attempts, oco_stop_loss_percent, oco_take_profit_percent, oco_stop_limit_gap_percent, oco_order_list_id, oco_take_profit_order_id, oco_stop_loss_order_id, mode, paper_balance, cooldown_candles, cooldown_minutes, stop_loss_cooldown_candles, stop_loss_cooldown_minutes, max_trades_per_day, last_exit_at, last_exit_reason, trades_day, trades_today, created_at, config_version, exchange_profile)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46, $47, $48, $49, $50)
 	`
 	_, err = r.db.Exec(query,
 		string(bot.Id.GetValue()),
@@ -47,12 +48,55 @@ func (r *TradingBotRepositoryDatabase) Save(bot *entity.TradingBot) error {
 		bot.GetEntryPrice(),
 		bot.GetActualQuantityHeld(),
 		bot.GetUseFixedQuantity(),
+		bot.GetPositionSizing().RiskPercent,
+		bot.GetPositionSizing().ATRPeriod,
+		bot.GetPositionSizing().ATRMultiplier,
+		bot.GetExitRules().TrailingStopPercent,
+		bot.GetExitRules().TakeProfitPercent,
+		bot.GetExitRules().MaxHoldingSeconds,
+		bot.GetHighestPriceSinceEntry(),
+		nullableTime(bot.GetPositionOpenedAt()),
+		bot.GetUseStreaming(),
+		bot.GetEntryFees(),
+		bot.GetOrderExecution().GetMode(),
+		bot.GetOrderExecution().TimeInForce,
+		bot.GetOrderExecution().RepriceAfterSeconds,
+		bot.GetOrderExecution().MaxAttempts,
+		bot.GetExchangeOCO().StopLossPercent,
+		bot.GetExchangeOCO().TakeProfitPercent,
+		bot.GetExchangeOCO().StopLimitGapPercent,
+		bot.GetActiveOCO().OrderListID,
+		bot.GetActiveOCO().TakeProfitOrderID,
+		bot.GetActiveOCO().StopLossOrderID,
+		bot.GetMode(),
+		bot.GetPaperBalance(),
+		bot.GetCooldownRules().CooldownCandles,
+		bot.GetCooldownRules().CooldownMinutes,
+		bot.GetCooldownRules().StopLossCooldownCandles,
+		bot.GetCooldownRules().StopLossCooldownMinutes,
+		bot.GetCooldownRules().MaxTradesPerDay,
+		nullableTime(bot.GetCooldownState().LastExitAt),
+		bot.GetCooldownState().LastExitReason,
+		nullableTime(bot.GetCooldownState().TradesDay),
+		bot.GetCooldownState().TradesToday,
 		bot.GetCreatedAt(),
+		bot.GetConfigVersion(),
+		bot.GetExchangeProfile(),
 	)
 	return err
 }
 
+// sqlExecer runs statements on the database or within a transaction
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
 func (r *TradingBotRepositoryDatabase) Update(bot *entity.TradingBot) error {
+	return updateTradingBot(r.db, bot)
+}
+
+// updateTradingBot writes every field of the bot, with exec being the database or a transaction
+func updateTradingBot(exec sqlExecer, bot *entity.TradingBot) error {
 	strategyParams, err := json.Marshal(bot.GetStrategy().GetParams())
 	if err != nil {
 		return err
@@ -60,10 +104,10 @@ func (r *TradingBotRepositoryDatabase) Update(bot *entity.TradingBot) error {
 
 	query := `
 		UPDATE trade_bots
-		SET symbol = $2, quantity = $3, strategy_name = $4, strategy_params = $5, status = $6, is_positioned = $7, interval_seconds = $8, initial_capital = $9, trade_amount = $10, currency = $11, trading_fees = $12, minimum_profit_threshold = $13, entry_price = $14, actual_quantity_held = $15, use_fixed_quantity = $16, created_at = $17
+		SET symbol = $2, quantity = $3, strategy_name = $4, strategy_params = $5, status = $6, is_positioned = $7, interval_seconds = $8, initial_capital = $9, trade_amount = $10, currency = $11, trading_fees = $12, minimum_profit_threshold = $13, entry_price = $14, actual_quantity_held = $15, use_fixed_quantity = $16, risk_per_trade_percent = $17, atr_period = $18, atr_multiplier = $19, trailing_stop_percent = $20, take_profit_percent = $21, max_holding_seconds = $22, highest_price_since_entry = $23, position_opened_at = $24, use_streaming = $25, entry_fees = $26, order_execution_mode = $27, time_in_force = $28, reprice_after_seconds = $29, max_order_attempts = $30, oco_stop_loss_percent = $31, oco_take_profit_percent = $32, oco_stop_limit_gap_percent = $33, oco_order_list_id = $34, oco_take_profit_order_id = $35, oco_stop_loss_order_id = $36, mode = $37, paper_balance = $38, cooldown_candles = $39, cooldown_minutes = $40, stop_loss_cooldown_candles = $41, stop_loss_cooldown_minutes = $42, max_trades_per_day = $43, last_exit_at = $44, last_exit_reason = $45, trades_day = $46, trades_today = $47, created_at = $48, config_version = $49, exchange_profile = $50
 		WHERE id = $1
 	`
-	_, err = r.db.Exec(query,
+	_, err = exec.Exec(query,
 		string(bot.Id.GetValue()),
 		string(bot.GetSymbol().GetValue()),
 		bot.GetQuantity(),
@@ -80,7 +124,40 @@ func (r *TradingBotRepositoryDatabase) Update(bot *entity.TradingBot) error {
 		bot.GetEntryPrice(),
 		bot.GetActualQuantityHeld(),
 		bot.GetUseFixedQuantity(),
+		bot.GetPositionSizing().RiskPercent,
+		bot.GetPositionSizing().ATRPeriod,
+		bot.GetPositionSizing().ATRMultiplier,
+		bot.GetExitRules().TrailingStopPercent,
+		bot.GetExitRules().TakeProfitPercent,
+		bot.GetExitRules().MaxHoldingSeconds,
+		bot.GetHighestPriceSinceEntry(),
+		nullableTime(bot.GetPositionOpenedAt()),
+		bot.GetUseStreaming(),
+		bot.GetEntryFees(),
+		bot.GetOrderExecution().GetMode(),
+		bot.GetOrderExecution().TimeInForce,
+		bot.GetOrderExecution().RepriceAfterSeconds,
+		bot.GetOrderExecution().MaxAttempts,
+		bot.GetExchangeOCO().StopLossPercent,
+		bot.GetExchangeOCO().TakeProfitPercent,
+		bot.GetExchangeOCO().StopLimitGapPercent,
+		bot.GetActiveOCO().OrderListID,
+		bot.GetActiveOCO().TakeProfitOrderID,
+		bot.GetActiveOCO().StopLossOrderID,
+		bot.GetMode(),
+		bot.GetPaperBalance(),
+		bot.GetCooldownRules().CooldownCandles,
+		bot.GetCooldownRules().CooldownMinutes,
+		bot.GetCooldownRules().StopLossCooldownCandles,
+		bot.GetCooldownRules().StopLossCooldownMinutes,
+		bot.GetCooldownRules().MaxTradesPerDay,
+		nullableTime(bot.GetCooldownState().LastExitAt),
+		bot.GetCooldownState().LastExitReason,
+		nullableTime(bot.GetCooldownState().TradesDay),
+		bot.GetCooldownState().TradesToday,
 		bot.GetCreatedAt(),
+		bot.GetConfigVersion(),
+		bot.GetExchangeProfile(),
 	)
 	return err
 }
@@ -92,52 +169,18 @@ func (r *TradingBotRepositoryDatabase) Exists(id string) (bool, error) {
 	return exists, err
 }
 
-func (r *TradingBotRepositoryDatabase) GetTradeByID(id string) (*entity.TradingBot, error) {
-	query := `
-		SELECT id, symbol, quantity, strategy_name, strategy_params, status, is_positioned, interval_seconds, initial_capital, trade_amount, currency, trading_fees, minimum_profit_threshold, entry_price, actual_quantity_held, use_fixed_quantity, created_at
-		FROM trade_bots
-		WHERE id = $1
-	`
+// tradingBotColumns are the trade_bots columns scanTradingBot reads, in order
+const tradingBotColumns = `id, symbol, quantity, strategy_name, strategy_params, status, is_positioned, interval_seconds, initial_capital, trade_amount, currency, trading_fees, minimum_profit_threshold, entry_price, actual_quantity_held, use_fixed_quantity, risk_per_trade_percent, atr_period, atr_multiplier, trailing_stop_percent, take_profit_percent, max_holding_seconds, highest_price_since_entry, position_opened_at, use_streaming, entry_fees, order_execution_mode, time_in_force, reprice_after_seconds, max_order_attempts, oco_stop_loss_percent, oco_take_profit_percent, oco_stop_limit_gap_percent, oco_order_list_id, oco_take_profit_order_id, oco_stop_loss_order_id, mode, exchange_profile, paper_balance, cooldown_candles, cooldown_minutes, stop_loss_cooldown_candles, stop_loss_cooldown_minutes, max_trades_per_day, last_exit_at, last_exit_reason, trades_day, trades_today, config_version, created_at`
 
-	var (
-		botId                  string
-		symbol                 string
-		quantity               float64
-		strategyName           string
-		strategyParams         string
-		status                 string
-		isPositioned           bool
-		intervalSeconds        int
-		initialCapital         float64
-		tradeAmount            float64
-		currency               string
-		tradingFees            float64
-		minimumProfitThreshold float64
-		entryPrice             float64
-		actualQuantityHeld     float64
-		useFixedQuantity       bool
-		createdAt              time.Time
-	)
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	err := r.db.QueryRow(query, id).Scan(
-		&botId,
-		&symbol,
-		&quantity,
-		&strategyName,
-		&strategyParams,
-		&status,
-		&isPositioned,
-		&intervalSeconds,
-		&initialCapital,
-		&tradeAmount,
-		&currency,
-		&tradingFees,
-		&minimumProfitThreshold,
-		&entryPrice,
-		&actualQuantityHeld,
-		&useFixedQuantity,
-		&createdAt,
-	)
+func (r *TradingBotRepositoryDatabase) GetTradeByID(id string) (*entity.TradingBot, error) {
+	query := `SELECT ` + tradingBotColumns + ` FROM trade_bots WHERE id = $1`
+
+	tradeBot, err := r.scanTradingBot(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -145,32 +188,6 @@ func (r *TradingBotRepositoryDatabase) GetTradeByID(id string) (*entity.TradingB
 		return nil, err
 	}
 
-	strategy, err := r.buildStrategyFromParams(strategyName, strategyParams)
-	if err != nil {
-		return nil, err
-	}
-
-	symbolInstance, _ := vo.NewSymbol(symbol)
-	restoredId, _ := vo.RestoreEntityId(botId)
-	tradeBot := entity.Restore(
-		restoredId,
-		symbolInstance,
-		quantity,
-		strategy,
-		entity.Status(status),
-		isPositioned,
-		intervalSeconds,
-		initialCapital,
-		tradeAmount,
-		currency,
-		tradingFees,
-		minimumProfitThreshold,
-		entryPrice,
-		actualQuantityHeld,
-		useFixedQuantity,
-		createdAt,
-	)
-
 	return tradeBot, nil
 }
 
@@ -180,137 +197,61 @@ func (r *TradingBotRepositoryDatabase) buildStrategyFromParams(strategyName, str
 		return nil, err
 	}
 
-	switch strategyName {
-	case "MovingAverage":
-		fast := int(params["FastWindow"].(float64))
-		slow := int(params["SlowWindow"].(float64))
-		
-		// Check for stoploss threshold
-		stoplossThreshold := 0.0
-		if stoploss, exists := params["StoplossThreshold"]; exists {
-			if stoplossVal, ok := stoploss.(float64); ok {
-				stoplossThreshold = stoplossVal
-			}
-		}
-		
-		// Use stoploss constructor if threshold > 0
-		if stoplossThreshold > 0 {
-			minimumSpread, _ := vo.NewMinimumSpread(0.1)
-			return entity.NewMovingAverageStrategyWithStoploss(fast, slow, minimumSpread, stoplossThreshold), nil
-		}
-		
-		return entity.NewMovingAverageStrategy(fast, slow), nil
-		
-	case "RSI":
-		period := int(params["Period"].(float64))
-		
-		// Get thresholds with defaults
-		oversoldThreshold := 30.0
-		if oversold, exists := params["OversoldThreshold"]; exists {
-			if oversoldVal, ok := oversold.(float64); ok {
-				oversoldThreshold = oversoldVal
-			}
-		}
-		
-		overboughtThreshold := 70.0
-		if overbought, exists := params["OverboughtThreshold"]; exists {
-			if overboughtVal, ok := overbought.(float64); ok {
-				overboughtThreshold = overboughtVal
-			}
-		}
-		
-		// Check for stoploss threshold
-		stoplossThreshold := 0.0
-		if stoploss, exists := params["StoplossThreshold"]; exists {
-			if stoplossVal, ok := stoploss.(float64); ok {
-				stoplossThreshold = stoplossVal
-			}
-		}
-		
-		minimumSpread, _ := vo.NewMinimumSpread(0.1)
-		
-		// Use appropriate constructor based on parameters
-		if stoplossThreshold > 0 {
-			return entity.NewRSIStrategyWithStoploss(period, oversoldThreshold, overboughtThreshold, minimumSpread, stoplossThreshold), nil
-		} else if oversoldThreshold != 30.0 || overboughtThreshold != 70.0 {
-			return entity.NewRSIStrategyWithCustomThresholds(period, oversoldThreshold, overboughtThreshold, minimumSpread), nil
-		} else {
-			return entity.NewRSIStrategy(period), nil
-		}
-		
-	default:
+	if !entity.IsStrategyRegistered(strategyName) {
 		return nil, fmt.Errorf("estratégia desconhecida: %s", strategyName)
 	}
+	return entity.NewStrategyFromParams(strategyName, params)
 }
 
 func (r *TradingBotRepositoryDatabase) GetAllTradingBots() ([]*entity.TradingBot, error) {
-	query := `
-		SELECT id, symbol, quantity, strategy_name, strategy_params, status, is_positioned, interval_seconds, initial_capital, trade_amount, currency, trading_fees, minimum_profit_threshold, entry_price, actual_quantity_held, use_fixed_quantity, created_at
-		FROM trade_bots
-	`
+	query := `SELECT ` + tradingBotColumns + ` FROM trade_bots`
 	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+	return r.scanTradingBots(rows)
+}
+
+func (r *TradingBotRepositoryDatabase) GetTradingBotsByStatus(status entity.Status) ([]*entity.TradingBot, error) {
+	query := `SELECT ` + tradingBotColumns + ` FROM trade_bots WHERE status = $1`
+	rows, err := r.db.Query(query, string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanTradingBots(rows)
+}
+
+// invalidTradingBotError is returned for a stored bot that cannot be restored, e.g. a legacy row whose strategy
+// params the strategy registry rejects
+type invalidTradingBotError struct {
+	botID string
+	err   error
+}
+
+func (e *invalidTradingBotError) Error() string {
+	return fmt.Sprintf("trading bot %s cannot be restored: %v", e.botID, e.err)
+}
+
+func (e *invalidTradingBotError) Unwrap() error {
+	return e.err
+}
+
+// scanTradingBots restores the bots of the rows, skipping the ones that cannot be restored so that a single invalid
+// row does not hide every other bot
+func (r *TradingBotRepositoryDatabase) scanTradingBots(rows *sql.Rows) ([]*entity.TradingBot, error) {
 	var bots []*entity.TradingBot
 	for rows.Next() {
-		var (
-			botID                  string
-			symbol                 string
-			quantity               float64
-			strategyName           string
-			strategyParams         string
-			status                 string
-			isPositioned           bool
-			intervalSeconds        int
-			initialCapital         float64
-			tradeAmount            float64
-			currency               string
-			tradingFees            float64
-			minimumProfitThreshold float64
-			entryPrice             float64
-			actualQuantityHeld     float64
-			useFixedQuantity       bool
-			createdAt              time.Time
-		)
-		if err := rows.Scan(&botID, &symbol, &quantity, &strategyName, &strategyParams, &status, &isPositioned, &intervalSeconds, &initialCapital, &tradeAmount, &currency, &tradingFees, &minimumProfitThreshold, &entryPrice, &actualQuantityHeld, &useFixedQuantity, &createdAt); err != nil {
-			return nil, err
+		bot, err := r.scanTradingBot(rows)
+		var invalid *invalidTradingBotError
+		if errors.As(err, &invalid) {
+			fmt.Printf("⚠️ Skipping stored bot: %v\n", err)
+			continue
 		}
-
-		strategy, err := r.buildStrategyFromParams(strategyName, strategyParams)
 		if err != nil {
 			return nil, err
 		}
-
-		symbolInstance, errSymbol := vo.NewSymbol(symbol)
-		if errSymbol != nil {
-			return nil, errSymbol
-		}
-
-		restoredId, errRestoredId := vo.RestoreEntityId(botID)
-		if errRestoredId != nil {
-			return nil, errRestoredId
-		}
-		bot := entity.Restore(
-			restoredId,
-			symbolInstance,
-			quantity,
-			strategy,
-			entity.Status(status),
-			isPositioned,
-			intervalSeconds,
-			initialCapital,
-			tradeAmount,
-			currency,
-			tradingFees,
-			minimumProfitThreshold,
-			entryPrice,
-			actualQuantityHeld,
-			useFixedQuantity,
-			createdAt,
-		)
-
 		bots = append(bots, bot)
 	}
 
@@ -320,82 +261,100 @@ func (r *TradingBotRepositoryDatabase) GetAllTradingBots() ([]*entity.TradingBot
 	return bots, nil
 }
 
-func (r *TradingBotRepositoryDatabase) GetTradingBotsByStatus(status entity.Status) ([]*entity.TradingBot, error) {
-	query := `
-		SELECT id, symbol, quantity, strategy_name, strategy_params, status, is_positioned, interval_seconds, initial_capital, trade_amount, currency, trading_fees, minimum_profit_threshold, entry_price, actual_quantity_held, use_fixed_quantity, created_at
-		FROM trade_bots
-		WHERE status = $1
-	`
-	rows, err := r.db.Query(query, string(status))
+// scanTradingBot restores a bot from a row selecting tradingBotColumns
+func (r *TradingBotRepositoryDatabase) scanTradingBot(row rowScanner) (*entity.TradingBot, error) {
+	var (
+		params           entity.RestoreParams
+		botID            string
+		symbol           string
+		strategyName     string
+		strategyParams   string
+		status           string
+		positionOpenedAt sql.NullTime
+		lastExitAt       sql.NullTime
+		tradesDay        sql.NullTime
+	)
+
+	err := row.Scan(
+		&botID,
+		&symbol,
+		&params.Quantity,
+		&strategyName,
+		&strategyParams,
+		&status,
+		&params.IsPositioned,
+		&params.IntervalSeconds,
+		&params.InitialCapital,
+		&params.TradeAmount,
+		&params.Currency,
+		&params.TradingFees,
+		&params.MinimumProfitThreshold,
+		&params.EntryPrice,
+		&params.ActualQuantityHeld,
+		&params.UseFixedQuantity,
+		&params.PositionSizing.RiskPercent,
+		&params.PositionSizing.ATRPeriod,
+		&params.PositionSizing.ATRMultiplier,
+		&params.ExitRules.TrailingStopPercent,
+		&params.ExitRules.TakeProfitPercent,
+		&params.ExitRules.MaxHoldingSeconds,
+		&params.HighestPriceSinceEntry,
+		&positionOpenedAt,
+		&params.UseStreaming,
+		&params.EntryFees,
+		&params.OrderExecution.Mode,
+		&params.OrderExecution.TimeInForce,
+		&params.OrderExecution.RepriceAfterSeconds,
+		&params.OrderExecution.MaxAttempts,
+		&params.ExchangeOCO.StopLossPercent,
+		&params.ExchangeOCO.TakeProfitPercent,
+		&params.ExchangeOCO.StopLimitGapPercent,
+		&params.ActiveOCO.OrderListID,
+		&params.ActiveOCO.TakeProfitOrderID,
+		&params.ActiveOCO.StopLossOrderID,
+		&params.Mode,
+		&params.ExchangeProfile,
+		&params.PaperBalance,
+		&params.CooldownRules.CooldownCandles,
+		&params.CooldownRules.CooldownMinutes,
+		&params.CooldownRules.StopLossCooldownCandles,
+		&params.CooldownRules.StopLossCooldownMinutes,
+		&params.CooldownRules.MaxTradesPerDay,
+		&lastExitAt,
+		&params.CooldownState.LastExitReason,
+		&tradesDay,
+		&params.CooldownState.TradesToday,
+		&params.ConfigVersion,
+		&params.CreatedAt,
+	)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-	
-	var bots []*entity.TradingBot
-	for rows.Next() {
-		var (
-			botID                  string
-			symbol                 string
-			quantity               float64
-			strategyName           string
-			strategyParams         string
-			statusStr              string
-			isPositioned           bool
-			intervalSeconds        int
-			initialCapital         float64
-			tradeAmount            float64
-			currency               string
-			tradingFees            float64
-			minimumProfitThreshold float64
-			entryPrice             float64
-			actualQuantityHeld     float64
-			useFixedQuantity       bool
-			createdAt              time.Time
-		)
-		if err := rows.Scan(&botID, &symbol, &quantity, &strategyName, &strategyParams, &statusStr, &isPositioned, &intervalSeconds, &initialCapital, &tradeAmount, &currency, &tradingFees, &minimumProfitThreshold, &entryPrice, &actualQuantityHeld, &useFixedQuantity, &createdAt); err != nil {
-			return nil, err
-		}
-
-		strategy, err := r.buildStrategyFromParams(strategyName, strategyParams)
-		if err != nil {
-			return nil, err
-		}
-
-		symbolInstance, errSymbol := vo.NewSymbol(symbol)
-		if errSymbol != nil {
-			return nil, errSymbol
-		}
 
-		restoredId, errRestoredId := vo.RestoreEntityId(botID)
-		if errRestoredId != nil {
-			return nil, errRestoredId
-		}
-		
-		bot := entity.Restore(
-			restoredId,
-			symbolInstance,
-			quantity,
-			strategy,
-			entity.Status(statusStr),
-			isPositioned,
-			intervalSeconds,
-			initialCapital,
-			tradeAmount,
-			currency,
-			tradingFees,
-			minimumProfitThreshold,
-			entryPrice,
-			actualQuantityHeld,
-			useFixedQuantity,
-			createdAt,
-		)
+	params.Strategy, err = r.buildStrategyFromParams(strategyName, strategyParams)
+	if err != nil {
+		return nil, &invalidTradingBotError{botID: botID, err: err}
+	}
 
-		bots = append(bots, bot)
+	params.Symbol, err = vo.NewSymbol(symbol)
+	if err != nil {
+		return nil, &invalidTradingBotError{botID: botID, err: err}
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+	params.Id, err = vo.RestoreEntityId(botID)
+	if err != nil {
+		return nil, &invalidTradingBotError{botID: botID, err: err}
 	}
-	return bots, nil
+
+	params.Status = entity.Status(status)
+	params.PositionOpenedAt = positionOpenedAt.Time
+	params.CooldownState.LastExitAt = lastExitAt.Time
+	params.CooldownState.TradesDay = tradesDay.Time
+
+	return entity.Restore(params), nil
+}
+
+// nullableTime stores a zero time as NULL
+func nullableTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
 }
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"testing"
+)
+
+func TestTradingBotRepository_ListsSkipBotsThatCannotBeRestored(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewTradingBotRepositoryDatabase(db)
+
+	symbol, _ := vo.NewSymbol("BTCUSDT")
+	var botIDs []string
+	for i := 0; i < 3; i++ {
+		bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 300, 10000.0, 1000.0, "USDT", 0.1, 2.0, true)
+		botID := string(bot.Id.GetValue())
+		defer cleanupTestBot(t, db, botID)
+		if err := repo.Save(bot); err != nil {
+			t.Fatalf("Failed to save bot: %v", err)
+		}
+		botIDs = append(botIDs, botID)
+	}
+
+	// Corrupt the last bot with strategy params the registry rejects
+	invalidID := botIDs[2]
+	if _, err := db.Exec(`UPDATE trade_bots SET strategy_params = '{"Period": "fourteen"}' WHERE id = $1`, invalidID); err != nil {
+		t.Fatalf("Failed to corrupt bot: %v", err)
+	}
+
+	allBots, err := repo.GetAllTradingBots()
+	if err != nil {
+		t.Fatalf("Expected GetAllTradingBots to skip the invalid bot, got error: %v", err)
+	}
+	stoppedBots, err := repo.GetTradingBotsByStatus(entity.StatusStopped)
+	if err != nil {
+		t.Fatalf("Expected GetTradingBotsByStatus to skip the invalid bot, got error: %v", err)
+	}
+
+	for name, bots := range map[string][]*entity.TradingBot{"GetAllTradingBots": allBots, "GetTradingBotsByStatus": stoppedBots} {
+		found := make(map[string]bool)
+		for _, bot := range bots {
+			found[string(bot.Id.GetValue())] = true
+		}
+		if !found[botIDs[0]] || !found[botIDs[1]] {
+			t.Errorf("Expected %s to return both valid bots", name)
+		}
+		if found[invalidID] {
+			t.Errorf("Expected %s to skip the invalid bot", name)
+		}
+	}
+
+	if _, err := repo.GetTradeByID(invalidID); err == nil {
+		t.Error("Expected GetTradeByID to report the invalid bot")
+	}
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"crypgo-machine/src/domain/entity"
+	"crypgo-machine/src/domain/vo"
+	"testing"
+	"time"
+)
+
+func TestTradingBotRiskSettingsPersistence(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewTradingBotRepositoryDatabase(db)
+
+	symbol, _ := vo.NewSymbol("BTCUSDT")
+	bot := entity.NewTradingBot(symbol, 0.001, entity.NewRSIStrategy(14), 300, 10000.0, 1000.0, "USDT", 0.1, 2.0, true)
+	bot.SetExitRules(entity.ExitRules{TrailingStopPercent: 3.0, TakeProfitPercent: 8.0, MaxHoldingSeconds: 172800})
+	bot.SetPositionSizing(entity.PositionSizing{RiskPercent: 1.0, ATRPeriod: 14, ATRMultiplier: 2.0})
+	bot.SetUseStreaming(true)
+	bot.SetOrderExecution(entity.OrderExecution{Mode: entity.ExecutionModeLimit, TimeInForce: entity.TimeInForceGTC, RepriceAfterSeconds: 20, MaxAttempts: 4})
+	bot.SetExchangeOCO(entity.ExchangeOCO{StopLossPercent: 2.0, TakeProfitPercent: 5.0, StopLimitGapPercent: 0.5})
+	bot.SetMode(entity.TradingModePaper)
+	bot.AdjustPaperBalance(-250.0)
+	bot.SetExchangeProfile("testnet")
+	bot.SetCooldownRules(entity.CooldownRules{CooldownCandles: 3, CooldownMinutes: 10, StopLossCooldownCandles: 12, StopLossCooldownMinutes: 60, MaxTradesPerDay: 4})
+
+	botID := string(bot.Id.GetValue())
+	defer cleanupTestBot(t, db, botID)
+
+	if err := repo.Save(bot); err != nil {
+		t.Fatalf("Failed to save bot: %v", err)
+	}
+
+	// Open a position and move the high-water mark
+	openedAt := time.Now().UTC().Truncate(time.Second)
+	bot.RecordEntryFill(entity.OrderFill{AveragePrice: 100.0, ExecutedQuantity: 1.0, NetQuantity: 0.999, FeesInQuote: 0.1})
+	_ = bot.GetIntoPosition()
+	bot.StartPositionTracking(100.0, openedAt)
+	bot.TrackPosition(112.5, openedAt.Add(time.Hour))
+	bot.SetActiveOCO(entity.ActiveOCO{OrderListID: 42, TakeProfitOrderID: 1001, StopLossOrderID: 1000})
+	if err := repo.Update(bot); err != nil {
+		t.Fatalf("Failed to update bot: %v", err)
+	}
+
+	retrievedBot, err := repo.GetTradeByID(botID)
+	if err != nil || retrievedBot == nil {
+		t.Fatalf("Failed to retrieve bot: %v", err)
+	}
+
+	if retrievedBot.GetExitRules() != bot.GetExitRules() {
+		t.Errorf("Expected exit rules %+v, got %+v", bot.GetExitRules(), retrievedBot.GetExitRules())
+	}
+	if retrievedBot.GetPositionSizing() != bot.GetPositionSizing() {
+		t.Errorf("Expected position sizing %+v, got %+v", bot.GetPositionSizing(), retrievedBot.GetPositionSizing())
+	}
+	if !retrievedBot.GetUseStreaming() {
+		t.Error("Expected use_streaming to be persisted")
+	}
+	if retrievedBot.GetOrderExecution() != bot.GetOrderExecution() {
+		t.Errorf("Expected order execution %+v, got %+v", bot.GetOrderExecution(), retrievedBot.GetOrderExecution())
+	}
+	if retrievedBot.GetExchangeOCO() != bot.GetExchangeOCO() {
+		t.Errorf("Expected exchange OCO %+v, got %+v", bot.GetExchangeOCO(), retrievedBot.GetExchangeOCO())
+	}
+	if retrievedBot.GetActiveOCO() != bot.GetActiveOCO() {
+		t.Errorf("Expected active OCO %+v, got %+v", bot.GetActiveOCO(), retrievedBot.GetActiveOCO())
+	}
+	if !retrievedBot.IsPaper() || retrievedBot.GetPaperBalance() != bot.GetPaperBalance() {
+		t.Errorf("Expected paper mode with balance %.2f, got %s with %.2f", bot.GetPaperBalance(), retrievedBot.GetMode(), retrievedBot.GetPaperBalance())
+	}
+	if retrievedBot.GetExchangeProfile() != "testnet" {
+		t.Errorf("Expected exchange profile testnet, got %s", retrievedBot.GetExchangeProfile())
+	}
+	if retrievedBot.GetEntryFees() != 0.1 || retrievedBot.GetActualQuantityHeld() != 0.999 {
+		t.Errorf("Expected entry fees 0.1 and quantity held 0.999, got %.4f and %.4f", retrievedBot.GetEntryFees(), retrievedBot.GetActualQuantityHeld())
+	}
+	if retrievedBot.GetHighestPriceSinceEntry() != 112.5 {
+		t.Errorf("Expected high-water mark 112.5, got %.2f", retrievedBot.GetHighestPriceSinceEntry())
+	}
+	if !retrievedBot.GetPositionOpenedAt().Equal(openedAt) {
+		t.Errorf("Expected position opened at %v, got %v", openedAt, retrievedBot.GetPositionOpenedAt())
+	}
+
+	// Leaving the position clears the tracking columns
+	_ = bot.GetOutOfPosition()
+	bot.RecordExit(entity.ExitReasonTrailingStop, openedAt.Add(2*time.Hour))
+	if err := repo.Update(bot); err != nil {
+		t.Fatalf("Failed to update bot: %v", err)
+	}
+	retrievedBot, _ = repo.GetTradeByID(botID)
+	if retrievedBot.GetHighestPriceSinceEntry() != 0 || !retrievedBot.GetPositionOpenedAt().IsZero() {
+		t.Error("Expected position tracking to be cleared")
+	}
+	if retrievedBot.GetCooldownRules() != bot.GetCooldownRules() {
+		t.Errorf("Expected cooldown rules %+v, got %+v", bot.GetCooldownRules(), retrievedBot.GetCooldownRules())
+	}
+	state := retrievedBot.GetCooldownState()
+	if !state.LastExitAt.Equal(openedAt.Add(2*time.Hour)) || state.LastExitReason != entity.ExitReasonTrailingStop {
+		t.Errorf("Expected the last exit to be persisted, got %+v", state)
+	}
+
+	// Free text exit reasons are not truncated by the column
+	longReason := "position flattened by risk limits: daily loss 512.40 USDT exceeds the limit of 500.00 USDT"
+	bot.RecordExit(longReason, openedAt.Add(3*time.Hour))
+	if err := repo.Update(bot); err != nil {
+		t.Fatalf("Failed to update bot with a long exit reason: %v", err)
+	}
+	retrievedBot, _ = repo.GetTradeByID(botID)
+	if retrievedBot.GetCooldownState().LastExitReason != longReason {
+		t.Errorf("Expected exit reason %q, got %q", longReason, retrievedBot.GetCooldownState().LastExitReason)
+	}
+}
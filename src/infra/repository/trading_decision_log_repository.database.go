@@ -35,9 +35,9 @@ func (r *TradingDecisionLogRepositoryDatabase) Save(log *entity.TradingDecisionL
 	query := `
 		INSERT INTO trading_decision_logs (
 			id, trading_bot_id, decision, strategy_name, 
-			analysis_data, market_data, current_price, current_possible_profit, timestamp
+			analysis_data, market_data, current_price, current_possible_profit, config_version, timestamp
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	_, err = r.db.Exec(query,
@@ -49,6 +49,7 @@ func (r *TradingDecisionLogRepositoryDatabase) Save(log *entity.TradingDecisionL
 		string(marketDataJson),
 		log.GetCurrentPrice(),
 		log.GetCurrentPossibleProfit(),
+		log.GetConfigVersion(),
 		log.GetTimestamp(),
 	)
 
@@ -62,7 +63,7 @@ func (r *TradingDecisionLogRepositoryDatabase) GetByTradingBotId(tradingBotId st
 func (r *TradingDecisionLogRepositoryDatabase) GetByTradingBotIdWithLimit(tradingBotId string, limit int) ([]*entity.TradingDecisionLog, error) {
 	query := `
 		SELECT id, trading_bot_id, decision, strategy_name, 
-			   analysis_data, market_data, current_price, current_possible_profit, timestamp
+			   analysis_data, market_data, current_price, current_possible_profit, config_version, timestamp
 		FROM trading_decision_logs 
 		WHERE trading_bot_id = $1 
 		ORDER BY timestamp DESC
@@ -89,11 +90,12 @@ func (r *TradingDecisionLogRepositoryDatabase) GetByTradingBotIdWithLimit(tradin
 			marketDataStr        string
 			currentPrice         float64
 			currentPossibleProfit float64
+			configVersion        int
 			timestamp            time.Time
 		)
 
 		if err := rows.Scan(&id, &botId, &decision, &strategyName,
-			&analysisDataStr, &marketDataStr, &currentPrice, &currentPossibleProfit, &timestamp); err != nil {
+			&analysisDataStr, &marketDataStr, &currentPrice, &currentPossibleProfit, &configVersion, &timestamp); err != nil {
 			return nil, err
 		}
 
@@ -129,6 +131,7 @@ func (r *TradingDecisionLogRepositoryDatabase) GetByTradingBotIdWithLimit(tradin
 			marketData,
 			currentPrice,
 			currentPossibleProfit,
+			configVersion,
 			timestamp,
 		)
 
@@ -145,7 +148,7 @@ func (r *TradingDecisionLogRepositoryDatabase) GetByTradingBotIdWithLimit(tradin
 func (r *TradingDecisionLogRepositoryDatabase) GetRecentLogs(limit int) ([]*entity.TradingDecisionLog, error) {
 	query := `
 		SELECT id, trading_bot_id, decision, strategy_name, analysis_data, market_data, 
-		       current_price, current_possible_profit, timestamp
+		       current_price, current_possible_profit, config_version, timestamp
 		FROM trading_decision_logs 
 		ORDER BY timestamp DESC 
 		LIMIT $1
@@ -163,7 +166,7 @@ func (r *TradingDecisionLogRepositoryDatabase) GetRecentLogs(limit int) ([]*enti
 func (r *TradingDecisionLogRepositoryDatabase) GetRecentLogsByDecision(decision string, limit int) ([]*entity.TradingDecisionLog, error) {
 	query := `
 		SELECT id, trading_bot_id, decision, strategy_name, analysis_data, market_data, 
-		       current_price, current_possible_profit, timestamp
+		       current_price, current_possible_profit, config_version, timestamp
 		FROM trading_decision_logs 
 		WHERE decision = $1
 		ORDER BY timestamp DESC 
@@ -191,11 +194,12 @@ func (r *TradingDecisionLogRepositoryDatabase) scanRows(rows *sql.Rows) ([]*enti
 			marketDataStr        string
 			currentPrice         float64
 			currentPossibleProfit float64
+			configVersion        int
 			timestamp            time.Time
 		)
 
 		if err := rows.Scan(&id, &botId, &decision, &strategyName,
-			&analysisDataStr, &marketDataStr, &currentPrice, &currentPossibleProfit, &timestamp); err != nil {
+			&analysisDataStr, &marketDataStr, &currentPrice, &currentPossibleProfit, &configVersion, &timestamp); err != nil {
 			return nil, err
 		}
 
@@ -236,6 +240,7 @@ func (r *TradingDecisionLogRepositoryDatabase) scanRows(rows *sql.Rows) ([]*enti
 			marketData,
 			currentPrice,
 			currentPossibleProfit,
+			configVersion,
 			timestamp,
 		)
 
@@ -289,7 +294,7 @@ func (r *TradingDecisionLogRepositoryDatabase) GetLogsWithFilters(decision strin
 	// Then get the actual logs with pagination
 	query := fmt.Sprintf(`
 		SELECT l.id, l.trading_bot_id, l.decision, l.strategy_name, l.analysis_data, 
-			   l.market_data, l.current_price, l.current_possible_profit, l.timestamp
+			   l.market_data, l.current_price, l.current_possible_profit, l.config_version, l.timestamp
 		FROM trading_decision_logs l
 		LEFT JOIN trade_bots b ON l.trading_bot_id = b.id
 		%s
@@ -63,6 +63,13 @@ class ApiClient {
         return await this.request('/trading/list');
     }
 
+    /**
+     * Lista as estratégias registradas com seus parâmetros
+     */
+    async listStrategies() {
+        return await this.request('/strategies');
+    }
+
     /**
      * Verifica saúde da API
      */
@@ -52,6 +52,7 @@ class Dashboard {
         await checkConnection();
         
         // Carrega dados iniciais
+        await this.loadStrategies();
         await this.loadData();
         await this.loadLogs();
         
@@ -186,6 +187,32 @@ class Dashboard {
         }
     }
 
+    /**
+     * Carrega as estratégias disponíveis para o filtro
+     */
+    async loadStrategies() {
+        const strategyFilter = document.getElementById('strategyFilter');
+        if (!strategyFilter) {
+            return;
+        }
+
+        const result = await apiClient.listStrategies();
+        if (!result.success || !Array.isArray(result.data)) {
+            debugLog('Não foi possível carregar as estratégias');
+            return;
+        }
+
+        strategyFilter.innerHTML = '<option value="">Todas</option>';
+        result.data.forEach(strategy => {
+            const option = document.createElement('option');
+            option.value = strategy.name;
+            option.textContent = translateStrategy(strategy.name);
+            option.title = strategy.description;
+            strategyFilter.appendChild(option);
+        });
+        strategyFilter.value = this.filters.strategy;
+    }
+
     /**
      * Carrega dados da API
      */
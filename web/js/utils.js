@@ -92,6 +92,8 @@ function translateStrategy(strategy) {
         'MovingAverage': 'Média Móvel',
         'Breakout': 'Rompimento',
         'RSI': 'RSI',
+        'BollingerBands': 'Bandas de Bollinger',
+        'Composite': 'Composta',
         'MACD': 'MACD'
     };
     return translations[strategy] || strategy;